@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package client bundles the SDK services around a single Core client, so
+// they share authentication (including token refresh), transport, retries
+// and rate limiter.
+package client
+
+import (
+	"context"
+	"slices"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/auth"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/crud"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/dataitems"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/functions"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/models"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/projects"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/run"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/secrets"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/transfer"
+)
+
+// Client is a facade over all services. It is safe for concurrent use.
+type Client struct {
+	// Core is the Core client shared by the services
+	Core config.CoreHTTP
+
+	Auth      *auth.AuthService
+	Crud      *crud.CrudService
+	DataItems *dataitems.DataItemsService
+	Functions *functions.FunctionsService
+	Models    *models.ModelsService
+	Projects  *projects.ProjectsService
+	Run       *run.RunService
+	Secrets   *secrets.SecretsService
+	Transfer  *transfer.TransferService
+}
+
+// New builds the shared Core client from conf and opts (see
+// config.ServiceOption), then the services on top of it.
+func New(ctx context.Context, conf config.Config, opts ...config.ServiceOption) (*Client, error) {
+	o := config.NewServiceOptions(opts...).ForConfig(conf)
+	core := config.NewHTTPCoreWithOptions(conf.Core, o)
+	opts = append(slices.Clone(opts), config.WithCoreHTTP(core))
+
+	c := &Client{Core: core}
+	var err error
+	if c.Auth, err = auth.NewAuthService(ctx, conf, opts...); err != nil {
+		return nil, err
+	}
+	if c.Crud, err = crud.NewCrudService(ctx, conf, opts...); err != nil {
+		return nil, err
+	}
+	if c.DataItems, err = dataitems.NewDataItemsService(ctx, conf, opts...); err != nil {
+		return nil, err
+	}
+	if c.Functions, err = functions.NewFunctionsService(ctx, conf, opts...); err != nil {
+		return nil, err
+	}
+	if c.Models, err = models.NewModelsService(ctx, conf, opts...); err != nil {
+		return nil, err
+	}
+	if c.Projects, err = projects.NewProjectsService(ctx, conf, opts...); err != nil {
+		return nil, err
+	}
+	if c.Run, err = run.NewRunService(ctx, conf, opts...); err != nil {
+		return nil, err
+	}
+	if c.Secrets, err = secrets.NewSecretsService(ctx, conf, opts...); err != nil {
+		return nil, err
+	}
+	if c.Transfer, err = transfer.NewTransferService(ctx, conf, opts...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/client"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/dhcoretest"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/crud"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/run"
+)
+
+// countingCore counts the calls made through a shared CoreHTTP.
+type countingCore struct {
+	config.CoreHTTP
+	n atomic.Int32
+}
+
+func (c *countingCore) Do(ctx context.Context, method, url string, data []byte) ([]byte, int, error) {
+	c.n.Add(1)
+	return c.CoreHTTP.Do(ctx, method, url, data)
+}
+
+func TestServicesShareCoreHTTP(t *testing.T) {
+	srv := dhcoretest.NewServer()
+	defer srv.Close()
+	id := srv.Add("demo", "runs", map[string]interface{}{"kind": "python+job:run"})
+
+	conf := srv.Config()
+	core := &countingCore{CoreHTTP: config.NewHTTPCore(nil, conf.Core)}
+	ctx := context.Background()
+
+	crudSvc, err := crud.NewCrudService(ctx, conf, config.WithCoreHTTP(core))
+	if err != nil {
+		t.Fatal(err)
+	}
+	runSvc, err := run.NewRunService(ctx, conf, config.WithCoreHTTP(core))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := crudSvc.Get(ctx, crud.GetRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "runs"}, ID: id,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := runSvc.GetResource(ctx, run.LogRequest{
+		RunResourceRequest: run.RunResourceRequest{Project: "demo", Resource: "runs", ID: id},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n := core.n.Load(); n != 2 {
+		t.Fatalf("expected 2 calls through the shared client, got %d", n)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	srv := dhcoretest.NewServer()
+	defer srv.Close()
+
+	c, err := client.New(context.Background(), srv.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Core == nil || c.Crud == nil || c.DataItems == nil || c.Functions == nil || c.Models == nil || c.Run == nil || c.Transfer == nil {
+		t.Fatal("expected all services")
+	}
+	caps, err := c.Crud.Capabilities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if caps.APILevel == 0 {
+		t.Fatalf("unexpected capabilities %+v", caps)
+	}
+}
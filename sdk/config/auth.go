@@ -0,0 +1,292 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthProvider authenticates the requests sent to the Core. Apply adds the
+// credentials to req; Refresh is called when the Core answers 401 and, when
+// it succeeds, the request is sent again once.
+type AuthProvider interface {
+	Apply(req *http.Request) error
+	Refresh(ctx context.Context) error
+}
+
+// ErrRefreshUnsupported is returned by providers with static credentials.
+var ErrRefreshUnsupported = errors.New("credentials can't be refreshed")
+
+// AuthMethod selects the AuthProvider built from a CoreConfig.
+type AuthMethod string
+
+const (
+	// AuthAuto keeps the legacy selection: basic auth if BasicAuthUsername
+	// is set, then TokenSource, then AccessToken.
+	AuthAuto AuthMethod = ""
+	// AuthBearer sends AccessToken as a bearer token.
+	AuthBearer AuthMethod = "bearer"
+	// AuthBasic sends BasicAuthUsername and BasicAuthPassword.
+	AuthBasic AuthMethod = "basic"
+	// AuthRefresh uses TokenSource or, if nil, the OAuth2 refresh grant with
+	// OAuth2.RefreshToken.
+	AuthRefresh AuthMethod = "oauth2_refresh"
+	// AuthClientCredentials uses the OAuth2 client credentials grant.
+	AuthClientCredentials AuthMethod = "client_credentials"
+	// AuthTokenExchange uses the OAuth2 token exchange grant (RFC 8693).
+	AuthTokenExchange AuthMethod = "token_exchange"
+)
+
+// TokenExchangeGrant is the grant type of RFC 8693.
+const TokenExchangeGrant = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// OAuth2Config holds the settings of the OAuth2 auth methods.
+type OAuth2Config struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// RefreshToken is used by AuthRefresh.
+	RefreshToken string
+	// SubjectToken, SubjectTokenType (default access_token) and Audience
+	// are used by AuthTokenExchange.
+	SubjectToken     string
+	SubjectTokenType string
+	Audience         string
+	// HTTPClient calls the token endpoint (default http.DefaultClient).
+	HTTPClient *http.Client
+}
+
+// BearerAuth sends a static bearer token.
+type BearerAuth struct {
+	Token string
+}
+
+func (a BearerAuth) Apply(req *http.Request) error {
+	if a.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.Token)
+	}
+	return nil
+}
+
+func (a BearerAuth) Refresh(context.Context) error { return ErrRefreshUnsupported }
+
+// BasicAuth sends a username and password.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+func (a BasicAuth) Apply(req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+func (a BasicAuth) Refresh(context.Context) error { return ErrRefreshUnsupported }
+
+// TokenSourceAuth sends the bearer tokens of a TokenSource and refreshes
+// them on 401.
+type TokenSourceAuth struct {
+	Source TokenSource
+}
+
+// NewTokenSourceAuth returns the AuthProvider of ts.
+func NewTokenSourceAuth(ts TokenSource) *TokenSourceAuth {
+	return &TokenSourceAuth{Source: ts}
+}
+
+func (a *TokenSourceAuth) Apply(req *http.Request) error {
+	tok, err := a.Source.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	if tok != "" {
+		req.Header.Set("Authorization", "Bearer "+tok)
+	}
+	return nil
+}
+
+// Refresh replaces the token the Core rejected, read from the request that
+// got the 401; outside a retry the current token is replaced.
+func (a *TokenSourceAuth) Refresh(ctx context.Context) error {
+	var stale string
+	if req := rejectedRequest(ctx); req != nil {
+		stale = strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	} else {
+		tok, err := a.Source.Token(ctx)
+		if err != nil {
+			return err
+		}
+		stale = tok
+	}
+	fresh, err := a.Source.Refresh(ctx, stale)
+	if err != nil {
+		return err
+	}
+	if fresh == stale {
+		return errors.New("token source returned the rejected token")
+	}
+	return nil
+}
+
+type rejectedRequestCtxKey struct{}
+
+// contextWithRejectedRequest passes to AuthProvider.Refresh the request
+// answered with 401, whose credentials are the ones to replace: with
+// concurrent calls they may differ from those last applied.
+func contextWithRejectedRequest(ctx context.Context, req *http.Request) context.Context {
+	return context.WithValue(ctx, rejectedRequestCtxKey{}, req)
+}
+
+func rejectedRequest(ctx context.Context) *http.Request {
+	req, _ := ctx.Value(rejectedRequestCtxKey{}).(*http.Request)
+	return req
+}
+
+// grantTokenSource caches the tokens of an OAuth2 grant that needs no user
+// interaction, repeating the grant when they expire.
+type grantTokenSource struct {
+	conf  OAuth2Config
+	form  url.Values
+	mu    sync.Mutex
+	token Token
+}
+
+// NewClientCredentialsSource returns a TokenSource using the OAuth2 client
+// credentials grant.
+func NewClientCredentialsSource(c OAuth2Config) TokenSource {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	return &grantTokenSource{conf: c, form: form}
+}
+
+// NewTokenExchangeSource returns a TokenSource exchanging c.SubjectToken
+// for a Core token (RFC 8693).
+func NewTokenExchangeSource(c OAuth2Config) TokenSource {
+	form := url.Values{}
+	form.Set("grant_type", TokenExchangeGrant)
+	form.Set("subject_token", c.SubjectToken)
+	typ := c.SubjectTokenType
+	if typ == "" {
+		typ = "urn:ietf:params:oauth:token-type:access_token"
+	}
+	form.Set("subject_token_type", typ)
+	if c.Audience != "" {
+		form.Set("audience", c.Audience)
+	}
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	return &grantTokenSource{conf: c, form: form}
+}
+
+func (s *grantTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.AccessToken != "" && (s.token.Expiry.IsZero() || time.Until(s.token.Expiry) > expiryDelta) {
+		return s.token.AccessToken, nil
+	}
+	if err := s.grant(ctx); err != nil {
+		return "", err
+	}
+	return s.token.AccessToken, nil
+}
+
+func (s *grantTokenSource) Refresh(ctx context.Context, stale string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.AccessToken != "" && s.token.AccessToken != stale {
+		return s.token.AccessToken, nil
+	}
+	if err := s.grant(ctx); err != nil {
+		return "", err
+	}
+	return s.token.AccessToken, nil
+}
+
+// grant requests a new token; s.mu must be held.
+func (s *grantTokenSource) grant(ctx context.Context) error {
+	if s.conf.TokenURL == "" {
+		return errors.New("token request failed: no token endpoint")
+	}
+	form := url.Values{}
+	for k, v := range s.form {
+		form[k] = v
+	}
+	if s.conf.ClientID != "" {
+		form.Set("client_id", s.conf.ClientID)
+	}
+	if s.conf.ClientSecret != "" {
+		form.Set("client_secret", s.conf.ClientSecret)
+	}
+	t, err := RequestToken(ctx, s.conf.HTTPClient, s.conf.TokenURL, form)
+	if err != nil {
+		return fmt.Errorf("token request failed: %w", err)
+	}
+	s.token = t
+	return nil
+}
+
+// AuthProvider returns the AuthProvider selected by the config (see
+// AuthMethod); nil when no credentials are configured.
+func (c CoreConfig) AuthProvider() (AuthProvider, error) {
+	if c.Auth != nil {
+		return c.Auth, nil
+	}
+	switch c.AuthMethod {
+	case AuthAuto:
+		switch {
+		case c.BasicAuthUsername != "":
+			return BasicAuth{Username: c.BasicAuthUsername, Password: c.BasicAuthPassword}, nil
+		case c.TokenSource != nil:
+			return NewTokenSourceAuth(c.TokenSource), nil
+		case c.AccessToken != "":
+			return BearerAuth{Token: c.AccessToken}, nil
+		}
+		return nil, nil
+	case AuthBearer:
+		if c.AccessToken == "" {
+			return nil, errors.New("bearer auth requires an access token")
+		}
+		return BearerAuth{Token: c.AccessToken}, nil
+	case AuthBasic:
+		if c.BasicAuthUsername == "" {
+			return nil, errors.New("basic auth requires a username")
+		}
+		return BasicAuth{Username: c.BasicAuthUsername, Password: c.BasicAuthPassword}, nil
+	case AuthRefresh:
+		if c.TokenSource != nil {
+			return NewTokenSourceAuth(c.TokenSource), nil
+		}
+		if c.OAuth2.RefreshToken == "" {
+			return nil, ErrNoRefreshToken
+		}
+		ts := NewRefreshTokenSource(c.OAuth2.TokenURL, c.OAuth2.ClientID,
+			Token{AccessToken: c.AccessToken, RefreshToken: c.OAuth2.RefreshToken})
+		ts.HTTPClient = c.OAuth2.HTTPClient
+		return NewTokenSourceAuth(ts), nil
+	case AuthClientCredentials:
+		if c.OAuth2.TokenURL == "" || c.OAuth2.ClientID == "" {
+			return nil, errors.New("client credentials auth requires a token endpoint and a client id")
+		}
+		return NewTokenSourceAuth(NewClientCredentialsSource(c.OAuth2)), nil
+	case AuthTokenExchange:
+		if c.OAuth2.TokenURL == "" || c.OAuth2.SubjectToken == "" {
+			return nil, errors.New("token exchange auth requires a token endpoint and a subject token")
+		}
+		return NewTokenSourceAuth(NewTokenExchangeSource(c.OAuth2)), nil
+	}
+	return nil, fmt.Errorf("unknown auth method %q", c.AuthMethod)
+}
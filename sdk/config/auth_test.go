@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestAuthMethods(t *testing.T) {
+	var grants atomic.Int32
+	issuer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var tok string
+		switch r.FormValue("grant_type") {
+		case "client_credentials":
+			if r.FormValue("client_id") != "cli" || r.FormValue("client_secret") != "s3cr3t" || r.FormValue("scope") != "a b" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			tok = "cc"
+		case config.TokenExchangeGrant:
+			if r.FormValue("subject_token") != "ext" || r.FormValue("audience") != "dhcore" ||
+				!strings.HasSuffix(r.FormValue("subject_token_type"), ":access_token") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			tok = "xc"
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		grants.Add(1)
+		fmt.Fprintf(w, `{"access_token":%q,"expires_in":3600}`, tok)
+	}))
+	defer issuer.Close()
+
+	var got atomic.Value
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.Store(r.Header.Get("Authorization"))
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	oauth := config.OAuth2Config{TokenURL: issuer.URL, ClientID: "cli", ClientSecret: "s3cr3t", Scopes: []string{"a", "b"},
+		SubjectToken: "ext", Audience: "dhcore"}
+	cases := []struct {
+		name string
+		conf config.CoreConfig
+		want string
+	}{
+		{"auto bearer", config.CoreConfig{AccessToken: "tok"}, "Bearer tok"},
+		{"auto basic wins", config.CoreConfig{AccessToken: "tok", BasicAuthUsername: "u", BasicAuthPassword: "p"}, "Basic dTpw"},
+		{"bearer", config.CoreConfig{AuthMethod: config.AuthBearer, AccessToken: "tok", BasicAuthUsername: "u"}, "Bearer tok"},
+		{"basic", config.CoreConfig{AuthMethod: config.AuthBasic, BasicAuthUsername: "u", BasicAuthPassword: "p"}, "Basic dTpw"},
+		{"client credentials", config.CoreConfig{AuthMethod: config.AuthClientCredentials, OAuth2: oauth}, "Bearer cc"},
+		{"token exchange", config.CoreConfig{AuthMethod: config.AuthTokenExchange, OAuth2: oauth}, "Bearer xc"},
+		{"custom", config.CoreConfig{AccessToken: "tok", Auth: config.BearerAuth{Token: "mine"}}, "Bearer mine"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.conf.BaseURL = api.URL
+			core := config.NewHTTPCore(nil, tc.conf)
+			for range 2 {
+				if _, _, err := core.Do(context.Background(), "GET", api.URL, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if h := got.Load(); h != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, h)
+			}
+		})
+	}
+	// tokens are cached until they expire
+	if grants.Load() != 2 {
+		t.Fatalf("expected 2 grants, got %d", grants.Load())
+	}
+}
+
+func TestAuthMethodInvalid(t *testing.T) {
+	for _, conf := range []config.CoreConfig{
+		{AuthMethod: "kerberos"},
+		{AuthMethod: config.AuthBearer},
+		{AuthMethod: config.AuthClientCredentials},
+	} {
+		core := config.NewHTTPCore(nil, conf)
+		if _, _, err := core.Do(context.Background(), "GET", "http://127.0.0.1:1", nil); err == nil ||
+			!strings.Contains(err.Error(), "invalid core auth configuration") {
+			t.Fatalf("%q: expected configuration error, got %v", conf.AuthMethod, err)
+		}
+	}
+}
+
+func TestClientCredentialsRefreshOn401(t *testing.T) {
+	var grants atomic.Int32
+	issuer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"access_token":"t%d","expires_in":3600}`, grants.Add(1))
+	}))
+	defer issuer.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first token is revoked
+		if r.Header.Get("Authorization") != "Bearer t2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	core := config.NewHTTPCore(nil, config.CoreConfig{BaseURL: api.URL, AuthMethod: config.AuthClientCredentials,
+		OAuth2: config.OAuth2Config{TokenURL: issuer.URL, ClientID: "cli"}})
+	if _, _, err := core.Do(context.Background(), "GET", api.URL, nil); err != nil {
+		t.Fatalf("expected retry with a new token: %v", err)
+	}
+	if grants.Load() != 2 {
+		t.Fatalf("expected 2 grants, got %d", grants.Load())
+	}
+}
+
+// rotatingSource issues "t<n>", counting the refreshes that replace the
+// current token.
+type rotatingSource struct {
+	mu     sync.Mutex
+	n      int
+	grants int
+}
+
+func (s *rotatingSource) Token(context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fmt.Sprintf("t%d", s.n), nil
+}
+
+func (s *rotatingSource) Refresh(_ context.Context, stale string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cur := fmt.Sprintf("t%d", s.n); cur != stale {
+		return cur, nil
+	}
+	s.n++
+	s.grants++
+	return fmt.Sprintf("t%d", s.n), nil
+}
+
+func TestConcurrentRefreshOn401(t *testing.T) {
+	const calls = 8
+	var arrived atomic.Int32
+	all := make(chan struct{})
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t1" {
+			// every call is rejected before any of them retries
+			switch n := arrived.Add(1); {
+			case n == calls:
+				close(all)
+			case n < calls:
+				<-all
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	src := &rotatingSource{}
+	core := config.NewHTTPCore(nil, config.CoreConfig{BaseURL: api.URL, Auth: config.NewTokenSourceAuth(src)})
+	var wg sync.WaitGroup
+	errs := make(chan error, calls)
+	for range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := core.Do(context.Background(), "GET", api.URL, nil)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("expected retry with the refreshed token: %v", err)
+		}
+	}
+	if src.grants != 1 {
+		t.Fatalf("expected 1 refresh, got %d", src.grants)
+	}
+}
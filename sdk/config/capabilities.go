@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Feature names a Core functionality gated by API level.
+type Feature string
+
+const (
+	FeatureCreate  Feature = "create"
+	FeatureList    Feature = "list"
+	FeatureGet     Feature = "get"
+	FeatureUpdate  Feature = "update"
+	FeatureDelete  Feature = "delete"
+	FeatureStop    Feature = "stop"
+	FeatureResume  Feature = "resume"
+	FeatureLogs    Feature = "logs"
+	FeatureMetrics Feature = "metrics"
+)
+
+// LevelRange is an inclusive API level interval; 0 means no bound.
+type LevelRange struct {
+	Min int
+	Max int
+}
+
+// Contains reports whether level is within the range.
+func (r LevelRange) Contains(level int) bool {
+	return (r.Min == 0 || level >= r.Min) && (r.Max == 0 || level <= r.Max)
+}
+
+func (r LevelRange) String() string {
+	interval := "level"
+	if r.Min != 0 {
+		interval = fmt.Sprintf("%v <= %s", r.Min, interval)
+	}
+	if r.Max != 0 {
+		interval = fmt.Sprintf("%s <= %v", interval, r.Max)
+	}
+	return interval
+}
+
+var (
+	featureMu sync.RWMutex
+	// featureLevels maps each feature to the API levels supporting it.
+	featureLevels = map[Feature]LevelRange{
+		FeatureCreate:  {Min: 10},
+		FeatureList:    {Min: 10},
+		FeatureGet:     {Min: 10},
+		FeatureUpdate:  {Min: 10},
+		FeatureDelete:  {Min: 10},
+		FeatureStop:    {Min: 10},
+		FeatureResume:  {Min: 10},
+		FeatureLogs:    {Min: 10},
+		FeatureMetrics: {Min: 10},
+	}
+)
+
+// FeatureLevel returns the API levels supporting feature, if known.
+func FeatureLevel(feature Feature) (LevelRange, bool) {
+	featureMu.RLock()
+	defer featureMu.RUnlock()
+	r, ok := featureLevels[feature]
+	return r, ok
+}
+
+// SetFeatureLevel adds or adjusts the API levels supporting feature, e.g.
+// for features of newer Core releases. It is safe for concurrent use with
+// Supports.
+func SetFeatureLevel(feature Feature, r LevelRange) {
+	featureMu.Lock()
+	defer featureMu.Unlock()
+	featureLevels[feature] = r
+}
+
+// ErrAPILevelUnknown is returned when Core does not advertise its API level.
+var ErrAPILevelUnknown = errors.New("environment does not specify API level")
+
+// Capabilities describes what the connected Core supports, as advertised by
+// /.well-known/configuration.
+type Capabilities struct {
+	// APILevel is 0 when Core does not advertise it.
+	APILevel    int
+	APIVersion  string
+	CoreVersion string
+	// Config is the full well-known configuration.
+	Config map[string]interface{}
+}
+
+// Supports reports whether the Core API level is within the range of feature.
+// Unknown features and unknown API levels are reported as unsupported.
+func (c *Capabilities) Supports(feature Feature) bool {
+	r, ok := FeatureLevel(feature)
+	if !ok || c == nil || c.APILevel == 0 {
+		return false
+	}
+	return r.Contains(c.APILevel)
+}
+
+// CheckLevel returns an error when the API level is unknown or outside r.
+func (c *Capabilities) CheckLevel(r LevelRange) error {
+	if c == nil || c.APILevel == 0 {
+		return ErrAPILevelUnknown
+	}
+	if !r.Contains(c.APILevel) {
+		return fmt.Errorf("API level %v is not within the supported interval: %v", c.APILevel, r)
+	}
+	return nil
+}
+
+// ParseAPILevel parses an API level as found in the well-known configuration
+// (number or numeric string). An empty value returns ErrAPILevelUnknown.
+func ParseAPILevel(v interface{}) (int, error) {
+	switch t := v.(type) {
+	case nil:
+		return 0, ErrAPILevelUnknown
+	case float64:
+		return int(t), nil
+	case int:
+		return t, nil
+	case string:
+		s := strings.TrimSpace(t)
+		if s == "" {
+			return 0, ErrAPILevelUnknown
+		}
+		level, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("API level %v is not an integer", t)
+		}
+		return level, nil
+	default:
+		return 0, fmt.Errorf("API level %v is not an integer", t)
+	}
+}
+
+// FetchCapabilities reads the well-known configuration of Core.
+func FetchCapabilities(ctx context.Context, core CoreHTTP, baseURL string) (*Capabilities, error) {
+	body, _, err := core.Do(ctx, "GET", strings.TrimRight(baseURL, "/")+"/.well-known/configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read core configuration: %w", err)
+	}
+	var wk map[string]interface{}
+	if err := json.Unmarshal(body, &wk); err != nil {
+		return nil, fmt.Errorf("invalid core configuration: %w", err)
+	}
+
+	caps := &Capabilities{Config: wk}
+	caps.APIVersion, _ = wk["dhcore_api_version"].(string)
+	caps.CoreVersion, _ = wk["dhcore_version"].(string)
+	if level, err := ParseAPILevel(wk["dhcore_api_level"]); err == nil {
+		caps.APILevel = level
+	} else if !errors.Is(err, ErrAPILevelUnknown) {
+		return nil, err
+	}
+	return caps, nil
+}
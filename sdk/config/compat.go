@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/i18n"
+)
+
+// Version is a semantic version as advertised in dhcore_version.
+type Version struct {
+	Major, Minor, Patch int
+	// Pre is the pre-release tag without the dash (e.g. "SNAPSHOT", "rc.1").
+	Pre string
+}
+
+// ParseVersion parses "1.2.3", "v1.2", "0.11.0-SNAPSHOT" and the like;
+// build metadata ("+...") is ignored and missing components are zero.
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	str := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	str, _, _ = strings.Cut(str, "+")
+	str, v.Pre, _ = strings.Cut(str, "-")
+	parts := strings.Split(str, ".")
+	if str == "" || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// MustParseVersion is ParseVersion panicking on error, for constants.
+func MustParseVersion(s string) Version {
+	v, err := ParseVersion(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Compare returns -1, 0 or +1. Releases sort after their pre-releases;
+// pre-release tags are compared as plain strings.
+func (v Version) Compare(o Version) int {
+	if c := v.compareRelease(o); c != 0 {
+		return c
+	}
+	switch {
+	case v.Pre == o.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case o.Pre == "":
+		return -1
+	}
+	return strings.Compare(v.Pre, o.Pre)
+}
+
+func (v Version) compareRelease(o Version) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// VersionRange is the interval [Min, Max) of Core releases; a zero Max means
+// no upper bound. Pre-release tags are ignored, so 0.11.0-SNAPSHOT is within
+// a range starting at 0.11.0.
+type VersionRange struct {
+	Min Version
+	Max Version
+}
+
+// Contains reports whether v is within the range.
+func (r VersionRange) Contains(v Version) bool {
+	if v.compareRelease(r.Min) < 0 {
+		return false
+	}
+	return r.Max == (Version{}) || v.compareRelease(r.Max) < 0
+}
+
+func (r VersionRange) String() string {
+	if r.Max == (Version{}) {
+		return ">= " + r.Min.String()
+	}
+	return fmt.Sprintf(">= %s, < %s", r.Min, r.Max)
+}
+
+// SupportedCoreVersions is the range of dhcore_version this SDK release is
+// tested against.
+var SupportedCoreVersions = VersionRange{
+	Min: MustParseVersion("0.10.0"),
+	Max: MustParseVersion("1.0.0"),
+}
+
+// CompatPolicy tells CompatibilityCheck how to report a mismatch.
+type CompatPolicy int
+
+const (
+	// CompatWarn logs a warning (see DefaultLogger) and returns nil.
+	CompatWarn CompatPolicy = iota
+	// CompatError returns the mismatch as error.
+	CompatError
+	// CompatIgnore skips the check.
+	CompatIgnore
+)
+
+// ErrCoreVersionUnknown is returned when Core does not advertise its version.
+var ErrCoreVersionUnknown = errors.New("environment does not specify core version")
+
+// VersionMismatchError reports a Core release outside SupportedCoreVersions.
+type VersionMismatchError struct {
+	CoreVersion Version
+	Supported   VersionRange
+}
+
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("core version %s is not supported by this SDK (supported: %s)", e.CoreVersion, e.Supported)
+}
+
+// CompatibilityCheck compares the advertised dhcore_version with
+// SupportedCoreVersions. Under CompatError an unknown, invalid or unsupported
+// version is returned as error (a *VersionMismatchError for the latter);
+// under CompatWarn it is logged with DefaultLogger instead.
+func (c *Capabilities) CompatibilityCheck(policy CompatPolicy) error {
+	if policy == CompatIgnore {
+		return nil
+	}
+	err := c.checkVersion()
+	if err != nil && policy == CompatWarn {
+		DefaultLogger().Warn(i18n.Messagef(i18n.MsgCoreVersionWarning, err))
+		return nil
+	}
+	return err
+}
+
+func (c *Capabilities) checkVersion() error {
+	if c == nil || c.CoreVersion == "" {
+		return ErrCoreVersionUnknown
+	}
+	v, err := ParseVersion(c.CoreVersion)
+	if err != nil {
+		return fmt.Errorf("invalid core version: %w", err)
+	}
+	if !SupportedCoreVersions.Contains(v) {
+		return &VersionMismatchError{CoreVersion: v, Supported: SupportedCoreVersions}
+	}
+	return nil
+}
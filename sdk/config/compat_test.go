@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestVersionCompare(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"0.11.0", "0.11.0", 0},
+		{"v0.11", "0.11.0", 0},
+		{"0.11.0-SNAPSHOT", "0.11.0", -1},
+		{"0.11.1", "0.11.0+build.5", 1},
+		{"0.9.9", "0.10.0", -1},
+		{"1.0.0-rc.2", "1.0.0-rc.1", 1},
+	} {
+		a, err := config.ParseVersion(tc.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := a.Compare(config.MustParseVersion(tc.b)); got != tc.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+	for _, bad := range []string{"", "x.1", "1.2.3.4", "1.-2"} {
+		if _, err := config.ParseVersion(bad); err == nil {
+			t.Errorf("ParseVersion(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCompatibilityCheck(t *testing.T) {
+	ok := &config.Capabilities{CoreVersion: "0.11.0-SNAPSHOT"}
+	if err := ok.CompatibilityCheck(config.CompatError); err != nil {
+		t.Fatalf("expected compatible, got %v", err)
+	}
+
+	old := &config.Capabilities{CoreVersion: "0.9.2"}
+	var mismatch *config.VersionMismatchError
+	if err := old.CompatibilityCheck(config.CompatError); !errors.As(err, &mismatch) || mismatch.CoreVersion.Minor != 9 {
+		t.Fatalf("expected a VersionMismatchError, got %v", err)
+	}
+	if err := old.CompatibilityCheck(config.CompatWarn); err != nil {
+		t.Fatalf("warn policy should not fail: %v", err)
+	}
+
+	unknown := &config.Capabilities{}
+	if err := unknown.CompatibilityCheck(config.CompatError); !errors.Is(err, config.ErrCoreVersionUnknown) {
+		t.Fatalf("expected ErrCoreVersionUnknown, got %v", err)
+	}
+	if err := unknown.CompatibilityCheck(config.CompatIgnore); err != nil {
+		t.Fatal(err)
+	}
+}
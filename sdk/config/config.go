@@ -4,10 +4,30 @@
 
 package config
 
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"go.opentelemetry.io/otel/trace"
+)
+
 // Config complessiva passata all’SDK (niente viper/INI qui)
 type Config struct {
 	Core CoreConfig
 	S3   S3Config
+	// Transfer tunes uploads and downloads of the transfer service
+	Transfer TransferConfig
+	// TracerProvider is optional; when set, services, Core calls and S3
+	// transfers are traced with OpenTelemetry spans
+	TracerProvider trace.TracerProvider
+	// Logger receives the info and warning lines of the services (e.g.
+	// transfer banners); nil uses DefaultLogger
+	Logger *slog.Logger
+	// Project is the default project (dhcore_project), e.g. pinned by a
+	// project overlay; the services don't apply it, callers do
+	Project string
 }
 
 type CoreConfig struct {
@@ -16,6 +36,51 @@ type CoreConfig struct {
 	AccessToken       string
 	BasicAuthUsername string
 	BasicAuthPassword string
+	// TokenSource is optional and takes precedence over AccessToken; it
+	// refreshes the token when it expires or Core answers 401
+	TokenSource TokenSource
+	// AuthMethod selects how requests are authenticated (default AuthAuto);
+	// OAuth2 holds the settings of the OAuth2 methods
+	AuthMethod AuthMethod
+	OAuth2     OAuth2Config
+	// Auth is optional and overrides AuthMethod
+	Auth AuthProvider
+	// Offline makes every call fail at once with an *OfflineError, without
+	// network access (token refreshes and S3 credentials included)
+	Offline bool
+
+	// HTTPClient is optional; nil uses http.DefaultClient
+	HTTPClient *http.Client
+	// Retry is optional; nil disables retries of Core calls
+	Retry *RetryPolicy
+	// Timeouts is optional; zero fields mean no limit
+	Timeouts Timeouts
+	// Pool tunes connection reuse; zero fields keep the Go defaults
+	Pool ConnPool
+	// Middleware wraps the transport of every Core call (see Middleware)
+	Middleware []Middleware
+	// Debug logs method, URL, status, latency and headers of every Core
+	// call, with secrets redacted (see RegisterSecretKeys); DebugBodies adds
+	// request and response bodies. DebugLogger defaults to stderr.
+	Debug       bool
+	DebugBodies bool
+	DebugLogger *slog.Logger
+	// MaxRequestsPerSecond limits Core calls on the client side; 0 means no
+	// limit. Clients with the same BaseURL and rate share one limiter.
+	MaxRequestsPerSecond float64
+	// RateLimiter is optional and takes precedence over MaxRequestsPerSecond
+	RateLimiter *RateLimiter
+
+	// ProxyURL overrides the HTTP(S)_PROXY environment for Core calls
+	ProxyURL string
+	// CACertFile / CACertPEM add a PEM CA bundle to the system roots
+	CACertFile string
+	CACertPEM  []byte
+	// ClientCertFile / ClientKeyFile enable TLS client authentication
+	ClientCertFile string
+	ClientKeyFile  string
+	// InsecureSkipVerify disables server certificate checks (testing only)
+	InsecureSkipVerify bool
 }
 
 type S3Config struct {
@@ -24,4 +89,26 @@ type S3Config struct {
 	AccessToken string
 	Region      string
 	EndpointURL string
+	// Bucket is the default bucket (s3_bucket)
+	Bucket string
+
+	// Expiration of the keys above (aws_credentials_expiration); zero means
+	// they don't expire
+	Expiration time.Time
+	// CoreCredentials makes TransferService fetch temporary credentials from
+	// Core when the keys are missing or expire (see FetchS3Credentials);
+	// OnCredentialsRefresh receives them, e.g. to persist them
+	CoreCredentials      bool
+	OnCredentialsRefresh func(S3Credentials)
+	// Credentials is optional and overrides the static keys
+	Credentials aws.CredentialsProvider
+
+	// HTTPClient is optional; nil uses the AWS SDK default client
+	HTTPClient *http.Client
+	// Transfer tunes buffers and multipart uploads of the client
+	Transfer TransferConfig
+	// Objects are the encryption, ACL, storage class and tags of the objects
+	// stored by the client (s3_sse, s3_sse_kms_key_id, s3_storage_class);
+	// see ContextWithObjectOptions to override them per call
+	Objects ObjectOptions
 }
@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// builtinContentTypes covers data/model formats commonly stored on the platform
+// that are usually missing from the system mime tables.
+var builtinContentTypes = map[string]string{
+	".parquet": "application/vnd.apache.parquet",
+	".onnx":    "application/onnx",
+	".yaml":    "application/yaml",
+	".yml":     "application/yaml",
+	".csv":     "text/csv",
+	".jsonl":   "application/jsonl",
+	".ndjson":  "application/x-ndjson",
+	".md":      "text/markdown",
+	".pkl":     "application/octet-stream",
+	".pt":      "application/octet-stream",
+	".h5":      "application/x-hdf5",
+	".gz":      "application/gzip",
+	".tgz":     "application/gzip",
+}
+
+// NormalizeContentTypes returns the overrides (extension -> content type,
+// with or without the leading dot) keyed by lowercase extensions with the
+// dot, as ContentTypeByName looks them up; empty types are dropped. When
+// several keys name the same extension the first in sorted order wins, so
+// ".csv" beats "CSV" beats "csv" whatever the map order. Overrides that
+// are already normalized are returned as they are, so normalizing once
+// before many lookups costs a single pass.
+func NormalizeContentTypes(overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return nil
+	}
+	if normalizedContentTypes(overrides) {
+		return overrides
+	}
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make(map[string]string, len(keys))
+	for _, k := range keys {
+		ext := normalizeExt(k)
+		if _, ok := out[ext]; ok || ext == "" || overrides[k] == "" {
+			continue
+		}
+		out[ext] = overrides[k]
+	}
+	return out
+}
+
+func normalizedContentTypes(overrides map[string]string) bool {
+	for k, v := range overrides {
+		if v == "" || k == "" || normalizeExt(k) != k {
+			return false
+		}
+	}
+	return true
+}
+
+// ContentTypeByName resolves the content type from the file extension only.
+// Overrides (extension -> content type, with or without the leading dot,
+// see NormalizeContentTypes) win over the builtin table, which wins over
+// mime.TypeByExtension. Returns "" if unknown.
+func ContentTypeByName(name string, overrides map[string]string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == "" {
+		return ""
+	}
+	if ct := NormalizeContentTypes(overrides)[ext]; ct != "" {
+		return ct
+	}
+	if ct, ok := builtinContentTypes[ext]; ok {
+		return ct
+	}
+	return mime.TypeByExtension(ext)
+}
+
+// DetectContentType resolves the content type of an open file, overrides as
+// in ContentTypeByName. The extension is tried first; only when it is
+// unknown the first 512 bytes are sniffed and the file is rewound, so each
+// file is read (and seeked) at most once.
+func DetectContentType(file *os.File, overrides map[string]string) (string, error) {
+	if ct := ContentTypeByName(file.Name(), overrides); ct != "" {
+		return ct, nil
+	}
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("read error: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("rewind error: %w", err)
+	}
+	return http.DetectContentType(header[:n]), nil
+}
+
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
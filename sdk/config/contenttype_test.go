@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestContentType(t *testing.T) {
+	overrides := config.NormalizeContentTypes(map[string]string{
+		".csv":    "text/x-first",
+		"CSV":     "text/x-second",
+		"csv":     "text/x-third",
+		"parquet": "application/x-parquet",
+		"JSON":    "",
+	})
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"data.csv", "", "text/x-first"},                                    // colliding overrides
+		{"DATA.Parquet", "", "application/x-parquet"},                       // override without the dot
+		{"model.onnx", "", "application/onnx"},                              // builtin table
+		{"doc.json", "", "application/json"},                                // mime, empty override dropped
+		{"page", "<html><body>x</body></html>", "text/html; charset=utf-8"}, // sniffed
+		{"blob.unknownext", "%PDF-1.7\n", "application/pdf"},                // sniffed
+	}
+	dir := t.TempDir()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := filepath.Join(dir, c.name)
+			if err := os.WriteFile(p, []byte(c.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			f, err := os.Open(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			got, err := config.DetectContentType(f, overrides)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Fatalf("got %q, want %q", got, c.want)
+			}
+			// the file is read from the start after detection
+			rest, err := io.ReadAll(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(rest) != c.content {
+				t.Fatalf("file not rewound: read %q", rest)
+			}
+		})
+	}
+	// raw overrides are normalized by the lookups themselves
+	raw := map[string]string{"CSV": "text/x-raw", "tsv": "text/tab-separated-values"}
+	if got := config.ContentTypeByName("a.csv", raw); got != "text/x-raw" {
+		t.Fatalf("raw override ignored: got %q", got)
+	}
+	if got := config.ContentTypeByName("b.TSV", raw); got != "text/tab-separated-values" {
+		t.Fatalf("raw override ignored: got %q", got)
+	}
+	for i := 0; i < 20; i++ {
+		if got := config.NormalizeContentTypes(map[string]string{"csv": "b", ".CSV": "a", "Csv": "c"})[".csv"]; got != "a" {
+			t.Fatalf("non-deterministic overrides: got %q", got)
+		}
+	}
+}
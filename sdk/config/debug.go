@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Redacted replaces secret values in debug logs.
+const Redacted = "[REDACTED]"
+
+// debugBodyLimit bounds the bytes of a body written to debug logs.
+const debugBodyLimit = 64 << 10
+
+var (
+	debugSecretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Amz-Security-Token"}
+
+	secretKeysMu sync.RWMutex
+	secretKeys   = map[string]bool{
+		"access_token": true, "refresh_token": true, "id_token": true, "client_secret": true,
+		"password": true, "secret_key": true, "session_token": true,
+	}
+)
+
+// RegisterSecretKeys adds JSON fields and query params whose values are
+// redacted in debug logs (case-insensitive).
+func RegisterSecretKeys(keys ...string) {
+	secretKeysMu.Lock()
+	defer secretKeysMu.Unlock()
+	for _, k := range keys {
+		secretKeys[strings.ToLower(k)] = true
+	}
+}
+
+func isSecretKey(k string) bool {
+	secretKeysMu.RLock()
+	defer secretKeysMu.RUnlock()
+	return secretKeys[strings.ToLower(k)]
+}
+
+type sensitiveBodiesCtxKey struct{}
+
+// ContextWithSensitiveBodies keeps the bodies of the calls made with the
+// returned context out of debug logs, e.g. for secret values whose keys
+// aren't known in advance.
+func ContextWithSensitiveBodies(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sensitiveBodiesCtxKey{}, true)
+}
+
+func sensitiveBodies(ctx context.Context) bool {
+	v, _ := ctx.Value(sensitiveBodiesCtxKey{}).(bool)
+	return v
+}
+
+// debugLogger returns the logger of Debug mode: DebugLogger, or debug level
+// text records on stderr.
+func (c CoreConfig) debugLogger() *slog.Logger {
+	if c.DebugLogger != nil {
+		return c.DebugLogger
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+// debugMiddleware logs each Core call with secrets redacted; bodies are
+// logged when withBodies is set (the response body once it is closed, so
+// streams are not held back).
+func debugMiddleware(logger *slog.Logger, withBodies bool) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			ctx := r.Context()
+			attrs := []any{"method", r.Method, "url", redactURL(r.URL), "headers", redactHeaders(r.Header)}
+			withBodies := withBodies
+			if withBodies && sensitiveBodies(ctx) {
+				withBodies = false
+				attrs = append(attrs, "body", Redacted)
+			}
+			if withBodies && r.Body != nil && r.GetBody != nil {
+				if rc, err := r.GetBody(); err == nil {
+					b, _ := io.ReadAll(io.LimitReader(rc, debugBodyLimit))
+					_ = rc.Close()
+					attrs = append(attrs, "body", redactBody(b))
+				}
+			}
+			logger.DebugContext(ctx, "core request", attrs...)
+
+			start := time.Now()
+			resp, err := next.RoundTrip(r)
+			if err != nil {
+				logger.DebugContext(ctx, "core response", "method", r.Method, "url", redactURL(r.URL),
+					"latency", time.Since(start), "error", err)
+				return resp, err
+			}
+			logger.DebugContext(ctx, "core response", "method", r.Method, "url", redactURL(r.URL),
+				"status", resp.StatusCode, "latency", time.Since(start), "headers", redactHeaders(resp.Header))
+			if withBodies {
+				resp.Body = &debugBody{ReadCloser: resp.Body, log: func(b []byte) {
+					logger.DebugContext(ctx, "core response body", "method", r.Method, "url", redactURL(r.URL),
+						"body", redactBody(b))
+				}}
+			}
+			return resp, nil
+		})
+	}
+}
+
+// debugBody keeps the first debugBodyLimit bytes read and logs them on Close.
+type debugBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	log  func([]byte)
+	once sync.Once
+}
+
+func (d *debugBody) Read(p []byte) (int, error) {
+	n, err := d.ReadCloser.Read(p)
+	if room := debugBodyLimit - d.buf.Len(); room > 0 {
+		d.buf.Write(p[:min(n, room)])
+	}
+	return n, err
+}
+
+func (d *debugBody) Close() error {
+	d.once.Do(func() { d.log(d.buf.Bytes()) })
+	return d.ReadCloser.Close()
+}
+
+func redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for _, s := range debugSecretHeaders {
+		if _, ok := out[http.CanonicalHeaderKey(s)]; ok {
+			out[http.CanonicalHeaderKey(s)] = []string{Redacted}
+		}
+	}
+	return out
+}
+
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	changed := false
+	for k := range q {
+		if isSecretKey(k) || strings.EqualFold(k, "X-Amz-Signature") || strings.EqualFold(k, "X-Amz-Credential") {
+			q[k] = []string{Redacted}
+			changed = true
+		}
+	}
+	if !changed {
+		return u.String()
+	}
+	c := *u
+	c.RawQuery = q.Encode()
+	return c.String()
+}
+
+// redactBody redacts secret fields of JSON bodies; other bodies are logged
+// as they are.
+func redactBody(b []byte) string {
+	var v interface{}
+	if len(b) == 0 || json.Unmarshal(b, &v) != nil {
+		return string(b)
+	}
+	if !redactValue(v) {
+		return string(b)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return string(b)
+	}
+	return string(out)
+}
+
+func redactValue(v interface{}) bool {
+	changed := false
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if _, ok := val.(string); ok && isSecretKey(k) {
+				t[k] = Redacted
+				changed = true
+				continue
+			}
+			if redactValue(val) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, val := range t {
+			if redactValue(val) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
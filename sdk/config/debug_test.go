@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestDebugLoggingRedactsSecrets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"name":"p1","spec":{"access_token":"leaked-response"}}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	config.RegisterSecretKeys("db_password")
+	core := config.NewHTTPCore(nil, config.CoreConfig{
+		BaseURL: srv.URL, APIVersion: "v1", AccessToken: "leaked-token",
+		Debug: true, DebugBodies: true, DebugLogger: logger,
+	})
+
+	b, status, err := core.Do(context.Background(), "POST", srv.URL+"?access_token=leaked-query",
+		[]byte(`{"name":"p1","db_password":"leaked-body"}`))
+	if err != nil || status != 200 {
+		t.Fatalf("unexpected %d %v", status, err)
+	}
+	if !strings.Contains(string(b), "leaked-response") {
+		t.Fatal("the caller must get the response unchanged")
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "leaked") {
+		t.Fatalf("secret in debug log:\n%s", out)
+	}
+	for _, want := range []string{"core request", "core response", "status=200", "latency=", `p1`, config.Redacted} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in debug log:\n%s", want, out)
+		}
+	}
+
+	// streamed bodies are logged once closed
+	buf.Reset()
+	rc, _, err := core.DoStream(context.Background(), "GET", srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.Copy(io.Discard, rc)
+	_ = rc.Close()
+	if out := buf.String(); !strings.Contains(out, "core response body") || strings.Contains(out, "leaked") {
+		t.Fatalf("unexpected stream log:\n%s", out)
+	}
+}
+
+func TestDebugLoggingSensitiveBodies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"db":"leaked-response"}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	core := config.NewHTTPCore(nil, config.CoreConfig{
+		BaseURL: srv.URL, APIVersion: "v1",
+		Debug: true, DebugBodies: true, DebugLogger: logger,
+	})
+	ctx := config.ContextWithSensitiveBodies(context.Background())
+	if _, _, err := core.Do(ctx, "PUT", srv.URL, []byte(`{"db":"leaked-body"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); strings.Contains(out, "leaked") || !strings.Contains(out, "core request") {
+		t.Fatalf("unexpected debug log:\n%s", out)
+	}
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// CoreError is the error returned by CoreHTTP.Do for non-200 responses.
+// Services wrap it with %w, so callers can match it anywhere with errors.As
+// or the IsNotFound / IsConflict / IsUnauthorized helpers.
+type CoreError struct {
+	StatusCode int
+	// Status is the HTTP status line, e.g. "404 Not Found".
+	Status string
+	// Code is the Core error code ("code", or "error" when missing).
+	Code string
+	// Message is the Core error message, if any.
+	Message string
+	// RequestID comes from the X-Request-Id (or X-Correlation-Id) header.
+	RequestID string
+	// Body is the raw response body.
+	Body []byte
+}
+
+func (e *CoreError) Error() string {
+	msg := "core responded with: " + e.Status
+	if e.Message != "" {
+		msg += " - " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += " (request id " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// newCoreError builds a CoreError from a failed response.
+func newCoreError(resp *http.Response, body []byte) *CoreError {
+	e := &CoreError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       body,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+	if e.Status == "" {
+		e.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if e.RequestID == "" {
+		e.RequestID = resp.Header.Get("X-Correlation-Id")
+	}
+	var m map[string]any
+	if json.Unmarshal(body, &m) == nil {
+		e.Message, _ = m["message"].(string)
+		switch code := m["code"].(type) {
+		case string:
+			e.Code = code
+		case float64:
+			e.Code = fmt.Sprint(code)
+		}
+		if e.Code == "" {
+			e.Code, _ = m["error"].(string)
+		}
+	}
+	return e
+}
+
+// AsCoreError returns the CoreError wrapped in err, if any.
+func AsCoreError(err error) (*CoreError, bool) {
+	var ce *CoreError
+	ok := errors.As(err, &ce)
+	return ce, ok
+}
+
+// HasStatus reports whether err wraps a CoreError with the given status.
+func HasStatus(err error, status int) bool {
+	ce, ok := AsCoreError(err)
+	return ok && ce.StatusCode == status
+}
+
+// IsNotFound reports a 404 from Core.
+func IsNotFound(err error) bool { return HasStatus(err, http.StatusNotFound) }
+
+// IsConflict reports a 409 from Core (e.g. an entity that already exists).
+func IsConflict(err error) bool { return HasStatus(err, http.StatusConflict) }
+
+// IsUnauthorized reports a 401 from Core (missing or expired credentials).
+func IsUnauthorized(err error) bool { return HasStatus(err, http.StatusUnauthorized) }
+
+// IsForbidden reports a 403 from Core.
+func IsForbidden(err error) bool { return HasStatus(err, http.StatusForbidden) }
+
+// ErrOffline is matched (errors.Is) by the errors of calls refused in
+// offline mode.
+var ErrOffline = errors.New("offline mode")
+
+// OfflineError is returned, without any network access, by the calls that
+// need the network when CoreConfig.Offline is set.
+type OfflineError struct {
+	// Op is the refused call, e.g. "GET https://core/api/v1/projects"
+	Op string
+}
+
+func (e *OfflineError) Error() string {
+	return "offline mode: " + e.Op + " needs network access"
+}
+
+func (e *OfflineError) Is(target error) bool { return target == ErrOffline }
+
+// IsOffline reports an error caused by offline mode.
+func IsOffline(err error) bool { return errors.Is(err, ErrOffline) }
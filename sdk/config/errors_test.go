@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestCoreError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-42")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status":404,"error":"Not Found","code":"NoSuchEntity","message":"artifact missing"}`))
+	}))
+	defer srv.Close()
+
+	core := config.NewHTTPCore(nil, config.CoreConfig{BaseURL: srv.URL, APIVersion: "v1"})
+	_, status, err := core.Do(context.Background(), "GET", srv.URL, nil)
+	wrapped := fmt.Errorf("get failed (status %d): %w", status, err)
+
+	ce, ok := config.AsCoreError(wrapped)
+	if !ok {
+		t.Fatalf("expected a CoreError, got %T", err)
+	}
+	if ce.StatusCode != 404 || ce.Code != "NoSuchEntity" || ce.Message != "artifact missing" || ce.RequestID != "req-42" {
+		t.Fatalf("unexpected error %+v", ce)
+	}
+	if !config.IsNotFound(wrapped) || config.IsConflict(wrapped) || config.IsUnauthorized(wrapped) {
+		t.Fatal("status helpers mismatch")
+	}
+	if got := err.Error(); got != "core responded with: 404 Not Found - artifact missing (request id req-42)" {
+		t.Fatalf("unexpected message %q", got)
+	}
+}
+
+func TestOfflineCore(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer srv.Close()
+
+	core := config.NewHTTPCore(nil, config.CoreConfig{BaseURL: srv.URL, APIVersion: "v1", Offline: true})
+	_, _, err := core.Do(context.Background(), "GET", srv.URL, nil)
+	var oe *config.OfflineError
+	if !errors.As(err, &oe) || !config.IsOffline(fmt.Errorf("wrapped: %w", err)) {
+		t.Fatalf("expected an offline error, got %v", err)
+	}
+	if _, _, err := core.DoStream(context.Background(), "GET", srv.URL, nil); !config.IsOffline(err) {
+		t.Fatalf("expected an offline error from DoStream, got %v", err)
+	}
+	if hits != 0 {
+		t.Fatalf("offline core reached the network %d times", hits)
+	}
+}
@@ -7,92 +7,306 @@ package config
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// CoreHTTP performs Core API calls. The implementation returned by
+// NewHTTPCore is safe for concurrent use and can be shared by services (see
+// WithCoreHTTP).
 type CoreHTTP interface {
 	BuildURL(project, resource, id string, params map[string]string) string
+	BuildURLValues(project, resource, id string, query url.Values) string
 	Do(ctx context.Context, method, url string, data []byte) ([]byte, int, error)
+	// DoStream is Do without buffering: on 200 the caller reads and closes
+	// the returned body; other statuses are returned as *CoreError.
+	DoStream(ctx context.Context, method, url string, body io.Reader) (io.ReadCloser, int, error)
 }
 
 type httpCore struct {
 	httpClient *http.Client
 	coreConfig CoreConfig
+	logger     *slog.Logger
+	retry      *RetryPolicy
+	limiter    *RateLimiter
+	tracer     trace.Tracer
+	auth       AuthProvider
+	// initErr is a proxy/TLS configuration error, returned by every call
+	initErr error
 }
 
-func NewHTTPCore(httpClient *http.Client, coreConfig CoreConfig) CoreHTTP {
+// NewHTTPCore builds the Core client. A nil httpClient is built from the
+// proxy/TLS settings and HTTPClient of coreConfig (see NewCoreHTTPClient); an
+// invalid configuration is reported by every call. Middlewares from
+// CoreConfig.Middleware and mw wrap the transport, in this order, followed by
+// the debug logger when CoreConfig.Debug is set.
+func NewHTTPCore(httpClient *http.Client, coreConfig CoreConfig, mw ...Middleware) CoreHTTP {
+	var initErr error
 	if httpClient == nil {
-		httpClient = http.DefaultClient
+		if httpClient, initErr = NewCoreHTTPClient(coreConfig); initErr != nil {
+			initErr = fmt.Errorf("invalid core http configuration: %w", initErr)
+			httpClient = http.DefaultClient
+		}
+	}
+	mw = append(slices.Clone(coreConfig.Middleware), mw...)
+	if coreConfig.Debug {
+		// innermost, to log the request as sent
+		mw = append(mw, debugMiddleware(coreConfig.debugLogger(), coreConfig.DebugBodies))
 	}
-	return &httpCore{httpClient: httpClient, coreConfig: coreConfig}
+	httpClient = withMiddleware(httpClient, mw)
+	auth, err := coreConfig.AuthProvider()
+	if err != nil && initErr == nil {
+		initErr = fmt.Errorf("invalid core auth configuration: %w", err)
+	}
+	return &httpCore{httpClient: httpClient, coreConfig: coreConfig, retry: coreConfig.Retry, limiter: limiterFor(coreConfig), tracer: Tracer(nil), auth: auth, initErr: initErr}
 }
 
+// BuildURL builds a Core API URL; path segments and params are escaped and
+// empty params are skipped.
 func (httpCore *httpCore) BuildURL(project, resource, id string, params map[string]string) string {
-	base := fmt.Sprintf("%s/api/%s", httpCore.coreConfig.BaseURL, httpCore.coreConfig.APIVersion)
-	if resource != "projects" && project != "" {
-		base += "/-/" + project
+	q := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	return httpCore.BuildURLValues(project, resource, id, q)
+}
+
+// BuildURLValues is BuildURL with multi-valued params (e.g. several filters).
+func (httpCore *httpCore) BuildURLValues(project, resource, id string, query url.Values) string {
+	base := fmt.Sprintf("%s/api/%s", strings.TrimRight(httpCore.coreConfig.BaseURL, "/"), httpCore.coreConfig.APIVersion)
+	if !IsGlobalResource(resource) && project != "" {
+		base += "/-/" + url.PathEscape(project)
 	}
-	base += "/" + resource
+	base += "/" + url.PathEscape(resource)
 	if id != "" {
-		base += "/" + id
+		base += "/" + url.PathEscape(id)
 	}
-	first := true
-	for k, v := range params {
-		if v == "" {
-			continue
+	if enc := query.Encode(); enc != "" {
+		base += "?" + enc
+	}
+	return base
+}
+
+func (httpCore *httpCore) Do(ctx context.Context, method, url string, data []byte) (_ []byte, status int, err error) {
+	if httpCore.coreConfig.Offline {
+		return nil, 0, &OfflineError{Op: method + " " + url}
+	}
+	if httpCore.initErr != nil {
+		return nil, 0, httpCore.initErr
+	}
+	ctx, span := httpCore.startSpan(ctx, method, url)
+	defer func() { httpCore.endSpan(span, status, err) }()
+
+	timeouts := timeoutsFor(ctx, httpCore.coreConfig.Timeouts)
+	keyed := idempotencyKeyFromContext(ctx) != ""
+	ctx, cancel := withTimeout(ctx, timeouts.Total)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		actx, acancel := withTimeout(ctx, timeouts.Request)
+		b, status, header, err := httpCore.do(actx, method, url, data)
+		if err != nil && actx.Err() != nil && ctx.Err() == nil {
+			err = fmt.Errorf("%w after %v: %w", errAttemptTimeout, timeouts.Request, err)
 		}
-		if first {
-			base += "?"
-			first = false
-		} else {
-			base += "&"
+		acancel()
+		if httpCore.logger != nil {
+			httpCore.logger.DebugContext(ctx, "core request", "method", method, "url", url,
+				"status", status, "attempt", attempt, "duration", time.Since(start), "error", err)
+		}
+		p := httpCore.retry
+		if cp, ok := retryFromContext(ctx); ok {
+			p = cp
+		}
+		if err == nil || p == nil || attempt >= p.MaxAttempts || !p.retryable(method, keyed, status, err) {
+			return b, status, err
+		}
+		wait := p.wait(attempt, header)
+		if dl, ok := ctx.Deadline(); ok && time.Until(dl) <= wait {
+			// the retry would outlive the call
+			return b, status, err
+		}
+		if serr := sleepContext(ctx, wait); serr != nil {
+			return b, status, err
+		}
+	}
+}
+
+// DoStream performs the call without reading the response. Retries apply only
+// when body is nil, since a consumed reader can't be sent again.
+func (httpCore *httpCore) DoStream(ctx context.Context, method, url string, body io.Reader) (_ io.ReadCloser, status int, err error) {
+	if httpCore.coreConfig.Offline {
+		return nil, 0, &OfflineError{Op: method + " " + url}
+	}
+	if httpCore.initErr != nil {
+		return nil, 0, httpCore.initErr
+	}
+	// the span covers the call until the response headers
+	ctx, span := httpCore.startSpan(ctx, method, url)
+	defer func() { httpCore.endSpan(span, status, err) }()
+
+	timeouts := timeoutsFor(ctx, httpCore.coreConfig.Timeouts)
+	keyed := idempotencyKeyFromContext(ctx) != ""
+	var deadline time.Time
+	if timeouts.Total > 0 {
+		deadline = time.Now().Add(timeouts.Total)
+	}
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		// timeouts apply until the response headers: the stream itself is
+		// bounded only by ctx
+		limit := timeouts.Request
+		if !deadline.IsZero() && (limit <= 0 || time.Until(deadline) < limit) {
+			limit = max(time.Until(deadline), time.Nanosecond)
+		}
+		actx, acancel := context.WithCancel(ctx)
+		var timer *time.Timer
+		if limit > 0 {
+			timer = time.AfterFunc(limit, acancel)
+		}
+		resp, err := httpCore.send(actx, method, url, body)
+		timedOut := timer != nil && !timer.Stop()
+		status := 0
+		var header http.Header
+		if err == nil {
+			status, header = resp.StatusCode, resp.Header
+			if status == 200 {
+				return &cancelOnClose{ReadCloser: resp.Body, cancel: acancel}, status, nil
+			}
+			// error bodies are small: keep them for CoreError
+			b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+			_ = resp.Body.Close()
+			err = newCoreError(resp, b)
+		} else if timedOut && ctx.Err() == nil {
+			err = fmt.Errorf("%w after %v: %w", errAttemptTimeout, limit, err)
+		}
+		acancel()
+		if httpCore.logger != nil {
+			httpCore.logger.DebugContext(ctx, "core request", "method", method, "url", url,
+				"status", status, "attempt", attempt, "duration", time.Since(start), "error", err)
+		}
+		p := httpCore.retry
+		if cp, ok := retryFromContext(ctx); ok {
+			p = cp
+		}
+		if body != nil || p == nil || attempt >= p.MaxAttempts || !p.retryable(method, keyed, status, err) {
+			return nil, status, err
+		}
+		wait := p.wait(attempt, header)
+		if !deadline.IsZero() && time.Until(deadline) <= wait {
+			return nil, status, err
+		}
+		if serr := sleepContext(ctx, wait); serr != nil {
+			return nil, status, err
 		}
-		base += fmt.Sprintf("%s=%s", k, v)
 	}
-	return base
 }
 
-func (httpCore *httpCore) Do(ctx context.Context, method, url string, data []byte) ([]byte, int, error) {
+// do performs a single attempt.
+func (httpCore *httpCore) do(ctx context.Context, method, url string, data []byte) ([]byte, int, http.Header, error) {
 	var body io.Reader
 	if data != nil {
 		body = bytes.NewReader(data)
 	}
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	resp, err := httpCore.send(ctx, method, url, body)
 	if err != nil {
-		return nil, 0, err
-	}
-	if data != nil {
-		req.Header.Set("Content-Type", "application/json")
+		return nil, 0, nil, err
 	}
+	defer resp.Body.Close()
 
-	// If access token is set, add Authorization header
-	if tok := httpCore.coreConfig.AccessToken; tok != "" {
-		req.Header.Set("Authorization", "Bearer "+tok)
+	b, rerr := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return b, resp.StatusCode, resp.Header, newCoreError(resp, b)
 	}
+	return b, resp.StatusCode, resp.Header, rerr
+}
 
-	// If basic auth is set, add Basic Auth header
-	if user := httpCore.coreConfig.BasicAuthUsername; user != "" {
-		req.SetBasicAuth(user, httpCore.coreConfig.BasicAuthPassword)
+// send builds the authenticated request and sends it, once the rate limiter
+// allows it. A 401 refreshes the credentials of the AuthProvider and sends
+// the request again when body can be rewound.
+func (httpCore *httpCore) send(ctx context.Context, method, url string, body io.Reader) (*http.Response, error) {
+	resp, err := httpCore.sendOnce(ctx, method, url, body)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || httpCore.auth == nil {
+		return resp, err
+	}
+	seeker, ok := body.(io.Seeker)
+	if body != nil && !ok {
+		return resp, nil
+	}
+	if rerr := httpCore.auth.Refresh(contextWithRejectedRequest(ctx, resp.Request)); rerr != nil {
+		return resp, nil
 	}
+	if seeker != nil {
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return resp, nil
+		}
+	}
+	_ = resp.Body.Close()
+	return httpCore.sendOnce(ctx, method, url, body)
+}
 
-	resp, err := httpCore.httpClient.Do(req)
+// sendOnce performs one authenticated request.
+func (httpCore *httpCore) sendOnce(ctx context.Context, method, url string, body io.Reader) (*http.Response, error) {
+	if err := httpCore.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-	defer resp.Body.Close()
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key := idempotencyKeyFromContext(ctx); key != "" && method == http.MethodPost {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	applyRequestOptions(ctx, req)
 
-	b, rerr := io.ReadAll(resp.Body)
-	if resp.StatusCode != 200 {
-		var m map[string]any
-		if json.Unmarshal(b, &m) == nil {
-			if msg, ok := m["message"].(string); ok && msg != "" {
-				return b, resp.StatusCode, fmt.Errorf("core responded with: %s - %s", resp.Status, msg)
-			}
+	if httpCore.auth != nil {
+		if err := httpCore.auth.Apply(req); err != nil {
+			return nil, err
 		}
-		return b, resp.StatusCode, fmt.Errorf("core responded with: %s", resp.Status)
 	}
-	return b, resp.StatusCode, rerr
+	resp, err := httpCore.httpClient.Do(req)
+	if err == nil {
+		storeResponseHeader(ctx, resp)
+	}
+	return resp, err
+}
+
+// startSpan starts the client span of a Core call.
+func (httpCore *httpCore) startSpan(ctx context.Context, method, url string) (context.Context, trace.Span) {
+	return httpCore.tracer.Start(ctx, "core "+method, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("url.full", url),
+		))
+}
+
+// endSpan records the outcome of a Core call.
+func (httpCore *httpCore) endSpan(span trace.Span, status int, err error) {
+	if status != 0 {
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
 }
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKeyHeader carries the idempotency key of a creation call: Core
+// answers a repeated key with the entity created the first time.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyCtxKey struct{}
+
+// NewIdempotencyKey returns a random key for a single logical operation.
+func NewIdempotencyKey() string {
+	return uuid.NewString()
+}
+
+// ContextWithIdempotencyKey attaches key to the calls made with the returned
+// context. Since Core deduplicates them, such POSTs are retried like PUTs.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, idempotencyCtxKey{}, key)
+}
+
+// idempotencyKeyFromContext returns the key set by ContextWithIdempotencyKey.
+func idempotencyKeyFromContext(ctx context.Context) string {
+	k, _ := ctx.Value(idempotencyCtxKey{}).(string)
+	return k
+}
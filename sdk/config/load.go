@@ -0,0 +1,358 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// LoadOptions tunes Load. The zero value loads the current environment of
+// the CLI INI file, with environment variables on top.
+type LoadOptions struct {
+	// IniPath is the INI file; "" uses DefaultIniPath. A missing file is
+	// not an error.
+	IniPath string
+	// NoIni skips the INI file.
+	NoIni bool
+	// Environment is the INI section to load; "" means current_environment
+	// of [DEFAULT]. Keys of [DEFAULT] apply to every section.
+	Environment string
+	// LookupEnv reads environment variables; nil means os.LookupEnv. Use
+	// NoEnv to ignore them.
+	LookupEnv func(key string) (string, bool)
+	NoEnv     bool
+	// Overrides win over the INI and the environment; keys are those of the
+	// INI, e.g. "dhcore_endpoint".
+	Overrides map[string]string
+	// ProjectDir is where the lookup of the project overlay (see
+	// ProjectFileName) starts; "" means the working directory. NoProject
+	// skips the overlay.
+	ProjectDir string
+	NoProject  bool
+	// ResolveSecrets fills in the secret keys kept out of the INI (keyring,
+	// encrypted values) for the loaded section; utils.ResolveSecrets does it
+	// like the CLI. Without it encrypted values fail to load.
+	ResolveSecrets func(env string, values map[string]string) error
+}
+
+// ProjectFileName is the project overlay: the first one found walking up
+// from LoadOptions.ProjectDir overlays the INI file. Its [DEFAULT] and the
+// section of the environment take precedence over the INI file, but not over
+// environment variables and overrides; its current_environment selects the
+// environment when LoadOptions.Environment is empty.
+const ProjectFileName = ".dhcore.project.ini"
+
+// encryptedValuePrefix marks the values encrypted by the utils package.
+const encryptedValuePrefix = "enc:v1:"
+
+// loadKeys are the settings read by Load, with their defaults.
+var loadKeys = []struct {
+	key, def string
+	secret   bool
+}{
+	{key: "dhcore_endpoint"},
+	{key: "dhcore_api_version", def: "v1"},
+	{key: "dhcore_access_token", secret: true},
+	{key: "dhcore_refresh_token", secret: true},
+	{key: "dhcore_client_id"},
+	{key: "dhcore_user"},
+	{key: "dhcore_password", secret: true},
+	{key: "dhcore_offline"},
+	{key: "oauth2_token_endpoint"},
+	{key: "token_endpoint"},
+	{key: "aws_access_key_id", secret: true},
+	{key: "aws_secret_access_key", secret: true},
+	{key: "aws_session_token", secret: true},
+	{key: "aws_credentials_expiration"},
+	{key: "aws_region"},
+	{key: "aws_endpoint_url"},
+	{key: "s3_bucket"},
+	{key: "s3_sse"},
+	{key: "s3_sse_kms_key_id"},
+	{key: "s3_storage_class"},
+	{key: "dhcore_project"},
+}
+
+// Load builds a Config from the INI file, the project overlay, environment
+// variables and opts.Overrides, in increasing order of precedence, without touching
+// package state: several configurations, e.g. for different Cores, can be
+// loaded in one process. With a refresh token the OAuth2 refresh method is
+// selected; refreshed tokens are not written back to the INI.
+func Load(ctx context.Context, opts LoadOptions) (Config, error) {
+	if err := ctx.Err(); err != nil {
+		return Config{}, err
+	}
+	settings, err := loadSettings(opts)
+	if err != nil {
+		return Config{}, err
+	}
+	values := make(map[string]string, len(settings))
+	for k, s := range settings {
+		values[k] = s.Value
+	}
+	return configFromValues(values)
+}
+
+// loadSettings merges the layers of opts, recording where each value comes
+// from.
+func loadSettings(opts LoadOptions) (map[string]Setting, error) {
+	settings := map[string]Setting{}
+	set := func(key, value string, src Source, origin string) {
+		settings[key] = Setting{Key: key, Value: value, Source: src, Origin: origin}
+	}
+	for _, k := range loadKeys {
+		if k.def != "" {
+			set(k.key, k.def, SourceDefault, "")
+		}
+	}
+
+	env := opts.Environment
+	var project *ini.File
+	var projectPath string
+	if !opts.NoProject {
+		dir := opts.ProjectDir
+		if dir == "" {
+			wd, err := os.Getwd()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get working directory: %w", err)
+			}
+			dir = wd
+		}
+		if projectPath = FindProjectFile(dir); projectPath != "" {
+			var err error
+			if project, err = ini.Load(projectPath); err != nil {
+				return nil, fmt.Errorf("failed to read project file: %w", err)
+			}
+			if env == "" {
+				env = project.Section(ini.DefaultSection).Key("current_environment").String()
+			}
+		}
+	}
+	if !opts.NoIni {
+		path := opts.IniPath
+		if path == "" {
+			path = DefaultIniPath()
+		}
+		var err error
+		if env, err = loadIniSettings(path, env, set); err != nil {
+			return nil, err
+		}
+	}
+	if opts.ResolveSecrets != nil {
+		values := map[string]string{}
+		for k, s := range settings {
+			if s.Source == SourceIni || s.Source == SourceIniDefault {
+				values[k] = s.Value
+			}
+		}
+		if err := opts.ResolveSecrets(env, values); err != nil {
+			return nil, err
+		}
+		for k, v := range values {
+			switch s, ok := settings[k]; {
+			case ok && (s.Source == SourceIni || s.Source == SourceIniDefault):
+				s.Value = v
+				settings[k] = s
+			case v != "":
+				set(k, v, SourceSecretStore, env)
+			}
+		}
+	}
+	if project != nil {
+		// a section missing from the overlay is not an error
+		origin := func(section string) string { return projectPath + " [" + section + "]" }
+		setSection(project.Section(ini.DefaultSection), SourceProject, origin(ini.DefaultSection), set)
+		if env != "" && env != ini.DefaultSection && project.HasSection(env) {
+			setSection(project.Section(env), SourceProject, origin(env), set)
+		}
+	}
+	for k, s := range settings {
+		if strings.HasPrefix(s.Value, encryptedValuePrefix) {
+			return nil, fmt.Errorf("%s is encrypted: set LoadOptions.ResolveSecrets", k)
+		}
+	}
+
+	if !opts.NoEnv {
+		lookup := opts.LookupEnv
+		if lookup == nil {
+			lookup = os.LookupEnv
+		}
+		for _, k := range loadKeys {
+			name := strings.ToUpper(k.key)
+			if v, ok := lookup(name); ok && v != "" {
+				set(k.key, v, SourceEnv, name)
+			}
+		}
+	}
+	for k, v := range opts.Overrides {
+		set(k, v, SourceOverride, "")
+	}
+	return settings, nil
+}
+
+// loadIniSettings reads [DEFAULT] and then section env (or the current one)
+// of the INI file at path; it returns the loaded section.
+func loadIniSettings(path, env string, set func(key, value string, src Source, origin string)) (string, error) {
+	cfg, err := ini.Load(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return env, nil
+		}
+		return "", fmt.Errorf("failed to read ini: %w", err)
+	}
+	def := cfg.Section(ini.DefaultSection)
+	if env == "" {
+		env = def.Key("current_environment").String()
+	}
+	setSection(def, SourceIniDefault, path+" ["+ini.DefaultSection+"]", set)
+	if env != "" && env != ini.DefaultSection {
+		if !cfg.HasSection(env) {
+			return "", fmt.Errorf("environment %q not found in %s", env, path)
+		}
+		setSection(cfg.Section(env), SourceIni, path+" ["+env+"]", set)
+	}
+	return env, nil
+}
+
+// setSection sets the keys of sec; empty keys are unset.
+func setSection(sec *ini.Section, src Source, origin string, set func(key, value string, src Source, origin string)) {
+	for _, k := range sec.Keys() {
+		if k.Value() != "" {
+			set(k.Name(), k.Value(), src, origin)
+		}
+	}
+}
+
+// FindProjectFile returns the project overlay (ProjectFileName) in dir or
+// its nearest parent, or "" when there is none.
+func FindProjectFile(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Locations of the CLI INI file.
+const (
+	// IniPathEnv overrides the location of the INI file.
+	IniPathEnv = "DHCORE_CONFIG"
+	// IniDirName and IniFileName locate the INI file under the XDG config
+	// home; LegacyIniName is the former file in the home directory.
+	IniDirName    = "dhcore"
+	IniFileName   = "config.ini"
+	LegacyIniName = ".dhcore.ini"
+)
+
+// DefaultIniPath returns the INI file of the CLI: $DHCORE_CONFIG, else
+// XDGIniPath, else LegacyIniPath when only that one exists. It never moves
+// or creates files.
+func DefaultIniPath() string {
+	if p := os.Getenv(IniPathEnv); p != "" {
+		return p
+	}
+	path := XDGIniPath()
+	if !fileExists(path) {
+		if legacy := LegacyIniPath(); fileExists(legacy) {
+			return legacy
+		}
+	}
+	return path
+}
+
+// XDGIniPath returns $XDG_CONFIG_HOME/dhcore/config.ini
+// (~/.config/dhcore/config.ini when XDG_CONFIG_HOME is unset).
+func XDGIniPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" || !filepath.IsAbs(dir) {
+		dir = filepath.Join(userHome(), ".config")
+	}
+	return filepath.Join(dir, IniDirName, IniFileName)
+}
+
+// LegacyIniPath returns ~/.dhcore.ini.
+func LegacyIniPath() string {
+	return filepath.Join(userHome(), LegacyIniName)
+}
+
+func userHome() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	return home
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func configFromValues(v map[string]string) (Config, error) {
+	var c Config
+	c.Core.BaseURL = strings.TrimRight(v["dhcore_endpoint"], "/")
+	c.Core.APIVersion = v["dhcore_api_version"]
+	c.Project = v["dhcore_project"]
+	c.Core.AccessToken = v["dhcore_access_token"]
+	c.Core.BasicAuthUsername = v["dhcore_user"]
+	c.Core.BasicAuthPassword = v["dhcore_password"]
+	if s := v["dhcore_offline"]; s != "" {
+		offline, err := strconv.ParseBool(s)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid dhcore_offline %q", s)
+		}
+		c.Core.Offline = offline
+	}
+	if rt := v["dhcore_refresh_token"]; rt != "" && c.Core.BasicAuthUsername == "" {
+		c.Core.AuthMethod = AuthRefresh
+		c.Core.OAuth2 = OAuth2Config{
+			TokenURL:     v["oauth2_token_endpoint"],
+			ClientID:     v["dhcore_client_id"],
+			RefreshToken: rt,
+		}
+		if c.Core.OAuth2.TokenURL == "" {
+			c.Core.OAuth2.TokenURL = v["token_endpoint"]
+		}
+	}
+
+	exp, err := ParseCredentialsExpiration(v["aws_credentials_expiration"])
+	if err != nil {
+		return Config{}, err
+	}
+	c.S3 = S3Config{
+		AccessKey:       v["aws_access_key_id"],
+		SecretKey:       v["aws_secret_access_key"],
+		AccessToken:     v["aws_session_token"],
+		Region:          v["aws_region"],
+		EndpointURL:     v["aws_endpoint_url"],
+		Bucket:          v["s3_bucket"],
+		Expiration:      exp,
+		CoreCredentials: true,
+		Objects: ObjectOptions{
+			SSE:          v["s3_sse"],
+			KMSKeyID:     v["s3_sse_kms_key_id"],
+			StorageClass: v["s3_storage_class"],
+		},
+	}
+	return c, nil
+}
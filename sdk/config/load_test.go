@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+const loadIni = `[DEFAULT]
+current_environment = dev
+aws_region = eu-west-1
+
+[dev]
+dhcore_endpoint = https://dev.example/
+dhcore_access_token = dev-token
+
+[prod]
+dhcore_endpoint = https://prod.example
+dhcore_refresh_token = rt
+oauth2_token_endpoint = https://issuer.example/token
+dhcore_client_id = cli
+aws_secret_access_key = enc:v1:abc
+s3_sse = aws:kms
+s3_sse_kms_key_id = alias/dh
+`
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.ini")
+	if err := os.WriteFile(path, []byte(loadIni), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"DHCORE_API_VERSION": "v2"}
+	lookup := func(k string) (string, bool) { v, ok := env[k]; return v, ok }
+	ctx := context.Background()
+
+	dev, err := config.Load(ctx, config.LoadOptions{IniPath: path, LookupEnv: lookup})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev.Core.BaseURL != "https://dev.example" || dev.Core.AccessToken != "dev-token" ||
+		dev.Core.APIVersion != "v2" || dev.S3.Region != "eu-west-1" {
+		t.Fatalf("unexpected dev config %+v", dev.Core)
+	}
+
+	// prod has an encrypted secret: it needs a resolver
+	if _, err := config.Load(ctx, config.LoadOptions{IniPath: path, Environment: "prod", NoEnv: true}); err == nil {
+		t.Fatal("expected an error for the encrypted value")
+	}
+	prod, err := config.Load(ctx, config.LoadOptions{
+		IniPath: path, Environment: "prod", NoEnv: true,
+		Overrides: map[string]string{"dhcore_api_version": "v3"},
+		ResolveSecrets: func(env string, values map[string]string) error {
+			values["aws_secret_access_key"] = "secret-of-" + env
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prod.Core.BaseURL != "https://prod.example" || prod.Core.APIVersion != "v3" ||
+		prod.Core.AuthMethod != config.AuthRefresh || prod.Core.OAuth2.RefreshToken != "rt" ||
+		prod.Core.OAuth2.TokenURL != "https://issuer.example/token" || prod.S3.SecretKey != "secret-of-prod" ||
+		prod.S3.Objects.SSE != config.SSEKMS || prod.S3.Objects.KMSKeyID != "alias/dh" {
+		t.Fatalf("unexpected prod config %+v", prod.Core)
+	}
+
+	if _, err := config.Load(ctx, config.LoadOptions{IniPath: path, Environment: "missing"}); err == nil {
+		t.Fatal("expected an error for a missing environment")
+	}
+}
+
+func TestLoadProjectOverlay(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.ini")
+	if err := os.WriteFile(path, []byte(loadIni), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	repo := filepath.Join(dir, "repo")
+	sub := filepath.Join(repo, "src", "pkg")
+	if err := os.MkdirAll(sub, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	overlay := "[DEFAULT]\ncurrent_environment = prod\ndhcore_project = demo\n\n[prod]\ns3_bucket = demo-data\ndhcore_endpoint = https://pinned.example\n"
+	if err := os.WriteFile(filepath.Join(repo, config.ProjectFileName), []byte(overlay), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := config.FindProjectFile(sub); got != filepath.Join(repo, config.ProjectFileName) {
+		t.Fatalf("FindProjectFile = %q", got)
+	}
+
+	env := map[string]string{"DHCORE_ENDPOINT": "https://env.example"}
+	opts := config.LoadOptions{
+		IniPath: path, ProjectDir: sub,
+		LookupEnv: func(k string) (string, bool) { v, ok := env[k]; return v, ok },
+		ResolveSecrets: func(_ string, values map[string]string) error {
+			values["aws_secret_access_key"] = "secret"
+			return nil
+		},
+	}
+	ctx := context.Background()
+	// the overlay selects prod and wins over the INI file
+	cfg, err := config.Load(ctx, config.LoadOptions{IniPath: path, ProjectDir: sub, NoEnv: true, ResolveSecrets: opts.ResolveSecrets})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Core.BaseURL != "https://pinned.example" || cfg.Project != "demo" || cfg.S3.Bucket != "demo-data" ||
+		cfg.Core.OAuth2.RefreshToken != "rt" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+
+	opts.Environment = "dev"
+	if cfg, err = config.Load(ctx, opts); err != nil {
+		t.Fatal(err)
+	}
+	// [prod] of the overlay doesn't apply to dev; env vars win over it
+	if cfg.Project != "demo" || cfg.S3.Bucket != "" || cfg.Core.BaseURL != "https://env.example" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+
+	settings, err := config.Explain(ctx, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := settings.Get("dhcore_project"); s.Source != config.SourceProject {
+		t.Errorf("dhcore_project: %+v", s)
+	}
+
+	opts.NoProject = true
+	if cfg, err = config.Load(ctx, opts); err != nil || cfg.Project != "" {
+		t.Fatalf("overlay not skipped: %+v, %v", cfg, err)
+	}
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var defaultLogger atomic.Pointer[slog.Logger]
+
+// SetDefaultLogger sets the logger of the informational and warning lines
+// printed by the SDK (CLI environment bootstrap and update, transfers,
+// compatibility warnings) when Config.Logger isn't set; nil restores the
+// default, "[INFO] ..." and "[WARN] ..." lines on stderr. Use
+// slog.New(slog.DiscardHandler) to silence them.
+func SetDefaultLogger(l *slog.Logger) {
+	defaultLogger.Store(l)
+}
+
+// DefaultLogger returns the logger set with SetDefaultLogger, or the
+// default one.
+func DefaultLogger() *slog.Logger {
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+	return consoleLogger
+}
+
+// LoggerOr returns l, or DefaultLogger when l is nil.
+func LoggerOr(l *slog.Logger) *slog.Logger {
+	if l != nil {
+		return l
+	}
+	return DefaultLogger()
+}
+
+var consoleLogger = slog.New(&consoleHandler{w: os.Stderr, mu: &sync.Mutex{}})
+
+// consoleHandler writes records of level info and above as
+// "[LEVEL] message key=value ...", the format the SDK always printed.
+type consoleHandler struct {
+	w     io.Writer
+	mu    *sync.Mutex
+	attrs []slog.Attr
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", r.Level, r.Message)
+	write := func(a slog.Attr) bool {
+		if !a.Equal(slog.Attr{}) {
+			fmt.Fprintf(&b, " %s=%v", a.Key, a.Value)
+		}
+		return true
+	}
+	for _, a := range h.attrs {
+		write(a)
+	}
+	r.Attrs(write)
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &c
+}
+
+// WithGroup is a no-op: groups are flattened.
+func (h *consoleHandler) WithGroup(string) slog.Handler {
+	return h
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestDefaultLogger(t *testing.T) {
+	t.Cleanup(func() { config.SetDefaultLogger(nil) })
+
+	var buf bytes.Buffer
+	custom := slog.New(slog.NewTextHandler(&buf, nil))
+	config.SetDefaultLogger(custom)
+	if config.DefaultLogger() != custom || config.LoggerOr(nil) != custom {
+		t.Fatal("default logger not replaced")
+	}
+	config.LoggerOr(nil).Info("hello", "env", "prod")
+	if !bytes.Contains(buf.Bytes(), []byte("msg=hello env=prod")) {
+		t.Fatalf("unexpected record %q", buf.String())
+	}
+
+	own := slog.New(slog.DiscardHandler)
+	if config.LoggerOr(own) != own {
+		t.Fatal("explicit logger not used")
+	}
+	config.SetDefaultLogger(nil)
+	if config.DefaultLogger() == custom {
+		t.Fatal("default logger not restored")
+	}
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import "net/http"
+
+// Middleware wraps the transport of Core calls, e.g. to add headers, log or
+// collect metrics. Middlewares must not modify the incoming request: clone it
+// first (see HeaderMiddleware).
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// HeaderMiddleware sets a header on every Core call (e.g. "X-Org").
+func HeaderMiddleware(key, value string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r = r.Clone(r.Context())
+			r.Header.Set(key, value)
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+// HooksMiddleware calls before ahead of each request and after with its
+// outcome; either may be nil. before may return an error to abort the call.
+func HooksMiddleware(before func(*http.Request) error, after func(*http.Request, *http.Response, error)) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if before != nil {
+				r = r.Clone(r.Context())
+				if err := before(r); err != nil {
+					return nil, err
+				}
+			}
+			resp, err := next.RoundTrip(r)
+			if after != nil {
+				after(r, resp, err)
+			}
+			return resp, err
+		})
+	}
+}
+
+// withMiddleware returns a copy of client whose transport is wrapped by mw;
+// the first middleware is the outermost.
+func withMiddleware(client *http.Client, mw []Middleware) *http.Client {
+	if len(mw) == 0 {
+		return client
+	}
+	rt := client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		if mw[i] != nil {
+			rt = mw[i](rt)
+		}
+	}
+	c := *client
+	c.Transport = rt
+	return &c
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestMiddlewareChain(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var order []string
+	trace := func(name string) config.Middleware {
+		return config.HooksMiddleware(func(r *http.Request) error {
+			order = append(order, name)
+			return nil
+		}, nil)
+	}
+	var status int
+	cfg := config.CoreConfig{BaseURL: srv.URL, APIVersion: "v1", Middleware: []config.Middleware{trace("config")}}
+	core := config.NewHTTPCoreWithOptions(cfg, config.NewServiceOptions(config.WithMiddleware(
+		trace("option"),
+		config.HeaderMiddleware("X-Org", "fbk"),
+		config.HooksMiddleware(nil, func(r *http.Request, resp *http.Response, err error) { status = resp.StatusCode }),
+	)))
+
+	if _, _, err := core.Do(context.Background(), "GET", srv.URL, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got.Get("X-Org") != "fbk" || status != 200 {
+		t.Fatalf("header %q, status %d", got.Get("X-Org"), status)
+	}
+	if len(order) != 2 || order[0] != "config" || order[1] != "option" {
+		t.Fatalf("unexpected order %v", order)
+	}
+
+	deny := errors.New("denied by policy")
+	blocked := config.NewHTTPCore(nil, cfg, config.HooksMiddleware(func(*http.Request) error { return deny }, nil))
+	if _, _, err := blocked.Do(context.Background(), "GET", srv.URL, nil); !errors.Is(err, deny) {
+		t.Fatalf("expected the hook error, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Server-side encryption modes of ObjectOptions.SSE.
+const (
+	SSES3  = "AES256"
+	SSEKMS = "aws:kms"
+)
+
+// SSECustomer is the ObjectInfo.SSE of objects encrypted with a key given
+// by the client (SSE-C); it is not an ObjectOptions value.
+const SSECustomer = "SSE-C"
+
+// ObjectOptions are the attributes given to the objects stored by uploads
+// and copies; zero fields are left to the defaults of the bucket.
+type ObjectOptions struct {
+	// SSE is the server-side encryption, SSES3 or SSEKMS
+	SSE string
+	// KMSKeyID is the KMS key (id, ARN or alias) of SSEKMS; empty uses the
+	// AWS managed key
+	KMSKeyID string
+	// ACL is a canned ACL, e.g. "private" or "bucket-owner-full-control"
+	ACL string
+	// StorageClass is e.g. "STANDARD_IA" or "GLACIER_IR"
+	StorageClass string
+	// Tags are the object tags
+	Tags map[string]string
+}
+
+// Validate checks the encryption settings.
+func (o ObjectOptions) Validate() error {
+	switch o.SSE {
+	case "", SSES3, SSEKMS:
+	default:
+		return fmt.Errorf("invalid server-side encryption %q (expected %s or %s)", o.SSE, SSES3, SSEKMS)
+	}
+	if o.KMSKeyID != "" && o.SSE != SSEKMS {
+		return fmt.Errorf("a KMS key id requires %s encryption", SSEKMS)
+	}
+	return nil
+}
+
+// merge returns o with the non-zero fields of over; tags are merged, those
+// of over winning. A KMS key of o is dropped when over changes the
+// encryption.
+func (o ObjectOptions) merge(over ObjectOptions) ObjectOptions {
+	if over.SSE != "" && over.SSE != o.SSE {
+		o.SSE, o.KMSKeyID = over.SSE, ""
+	}
+	if over.KMSKeyID != "" {
+		o.KMSKeyID = over.KMSKeyID
+	}
+	if over.ACL != "" {
+		o.ACL = over.ACL
+	}
+	if over.StorageClass != "" {
+		o.StorageClass = over.StorageClass
+	}
+	if len(over.Tags) > 0 {
+		tags := make(map[string]string, len(o.Tags)+len(over.Tags))
+		for k, v := range o.Tags {
+			tags[k] = v
+		}
+		for k, v := range over.Tags {
+			tags[k] = v
+		}
+		o.Tags = tags
+	}
+	return o
+}
+
+func (o ObjectOptions) tagging() *string {
+	if len(o.Tags) == 0 {
+		return nil
+	}
+	v := url.Values{}
+	for k, t := range o.Tags {
+		v.Set(k, t)
+	}
+	return aws.String(v.Encode())
+}
+
+func (o ObjectOptions) applyPut(in *s3.PutObjectInput) {
+	in.ServerSideEncryption = s3types.ServerSideEncryption(o.SSE)
+	in.SSEKMSKeyId = optString(o.KMSKeyID)
+	in.ACL = s3types.ObjectCannedACL(o.ACL)
+	in.StorageClass = s3types.StorageClass(o.StorageClass)
+	in.Tagging = o.tagging()
+}
+
+func (o ObjectOptions) applyMultipart(in *s3.CreateMultipartUploadInput) {
+	in.ServerSideEncryption = s3types.ServerSideEncryption(o.SSE)
+	in.SSEKMSKeyId = optString(o.KMSKeyID)
+	in.ACL = s3types.ObjectCannedACL(o.ACL)
+	in.StorageClass = s3types.StorageClass(o.StorageClass)
+	in.Tagging = o.tagging()
+}
+
+// applyCopy sets the attributes of a copy; the tags of the source are kept
+// unless o has some.
+func (o ObjectOptions) applyCopy(in *s3.CopyObjectInput) {
+	in.ServerSideEncryption = s3types.ServerSideEncryption(o.SSE)
+	in.SSEKMSKeyId = optString(o.KMSKeyID)
+	in.ACL = s3types.ObjectCannedACL(o.ACL)
+	in.StorageClass = s3types.StorageClass(o.StorageClass)
+	if t := o.tagging(); t != nil {
+		in.Tagging = t
+		in.TaggingDirective = s3types.TaggingDirectiveReplace
+	}
+}
+
+func optString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
+
+type objectOptionsCtxKey struct{}
+
+// ContextWithObjectOptions applies o, over the S3Config.Objects of the
+// client, to the uploads and copies made with the returned context.
+func ContextWithObjectOptions(ctx context.Context, o ObjectOptions) context.Context {
+	if prev, ok := ctx.Value(objectOptionsCtxKey{}).(ObjectOptions); ok {
+		o = prev.merge(o)
+	}
+	return context.WithValue(ctx, objectOptionsCtxKey{}, o)
+}
+
+// objectOptions returns the attributes of the objects stored with ctx.
+func (c *S3Client) objectOptions(ctx context.Context) (ObjectOptions, error) {
+	o := c.objects
+	if over, ok := ctx.Value(objectOptionsCtxKey{}).(ObjectOptions); ok {
+		o = o.merge(over)
+	}
+	return o, o.Validate()
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceOptions collects the customizations accepted by the service
+// constructors (crud.NewCrudService, run.NewRunService,
+// transfer.NewTransferService). Build it with ServiceOption values.
+type ServiceOptions struct {
+	// HTTPClient is used for Core calls; it overrides CoreConfig.HTTPClient.
+	HTTPClient *http.Client
+	// Logger receives debug records for every Core call; nil disables them.
+	Logger *slog.Logger
+	// RetryPolicy retries failed Core calls; it overrides CoreConfig.Retry.
+	RetryPolicy *RetryPolicy
+	// S3Client replaces the client built from S3Config (transfer only).
+	S3Client *S3Client
+	// Middleware is appended to CoreConfig.Middleware.
+	Middleware []Middleware
+	// TracerProvider traces services and Core calls; it overrides
+	// Config.TracerProvider.
+	TracerProvider trace.TracerProvider
+	// CoreHTTP is an existing Core client shared with other services; when
+	// set, HTTPClient, Logger, RetryPolicy and Middleware are ignored.
+	CoreHTTP CoreHTTP
+}
+
+// ServiceOption customizes a service constructor.
+type ServiceOption func(*ServiceOptions)
+
+// WithHTTPClient sets the HTTP client used for Core calls.
+func WithHTTPClient(c *http.Client) ServiceOption {
+	return func(o *ServiceOptions) { o.HTTPClient = c }
+}
+
+// WithLogger sets the logger of Core calls.
+func WithLogger(l *slog.Logger) ServiceOption {
+	return func(o *ServiceOptions) { o.Logger = l }
+}
+
+// WithRetryPolicy enables retries of failed Core calls.
+func WithRetryPolicy(p RetryPolicy) ServiceOption {
+	return func(o *ServiceOptions) { o.RetryPolicy = &p }
+}
+
+// WithS3Client makes the transfer service use an existing S3 client instead
+// of building one from S3Config; other services ignore it.
+func WithS3Client(c *S3Client) ServiceOption {
+	return func(o *ServiceOptions) { o.S3Client = c }
+}
+
+// WithMiddleware adds middlewares to the Core client.
+func WithMiddleware(mw ...Middleware) ServiceOption {
+	return func(o *ServiceOptions) { o.Middleware = append(o.Middleware, mw...) }
+}
+
+// WithTracerProvider enables OpenTelemetry spans.
+func WithTracerProvider(tp trace.TracerProvider) ServiceOption {
+	return func(o *ServiceOptions) { o.TracerProvider = tp }
+}
+
+// WithCoreHTTP makes the service use an existing Core client, so that
+// services share auth (e.g. token refresh), transport and rate limiter.
+func WithCoreHTTP(c CoreHTTP) ServiceOption {
+	return func(o *ServiceOptions) { o.CoreHTTP = c }
+}
+
+// NewServiceOptions applies opts in order.
+func NewServiceOptions(opts ...ServiceOption) ServiceOptions {
+	var o ServiceOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// ForConfig returns o with the settings of conf that o doesn't override
+// (currently the TracerProvider).
+func (o ServiceOptions) ForConfig(conf Config) ServiceOptions {
+	if o.TracerProvider == nil {
+		o.TracerProvider = conf.TracerProvider
+	}
+	return o
+}
+
+// NewHTTPCoreWithOptions is NewHTTPCore honoring the HTTP client, logger,
+// retry policy, middlewares and tracer provider of opts; it returns
+// opts.CoreHTTP when set.
+func NewHTTPCoreWithOptions(coreConfig CoreConfig, opts ServiceOptions) CoreHTTP {
+	if opts.CoreHTTP != nil {
+		return opts.CoreHTTP
+	}
+	c := NewHTTPCore(opts.HTTPClient, coreConfig, opts.Middleware...).(*httpCore)
+	c.logger = opts.Logger
+	c.tracer = Tracer(opts.TracerProvider)
+	if opts.RetryPolicy != nil {
+		c.retry = opts.RetryPolicy
+	}
+	return c
+}
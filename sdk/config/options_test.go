@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestServiceOptionsRetryAndLogger(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	opts := config.NewServiceOptions(
+		config.WithRetryPolicy(config.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+		config.WithLogger(logger),
+	)
+	core := config.NewHTTPCoreWithOptions(config.CoreConfig{BaseURL: srv.URL, APIVersion: "v1"}, opts)
+
+	if _, status, err := core.Do(context.Background(), "GET", srv.URL, nil); err != nil || status != 200 {
+		t.Fatalf("expected success after retries, got %d %v", status, err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("expected 3 attempts, got %d", n)
+	}
+	if n := strings.Count(logs.String(), "core request"); n != 3 {
+		t.Fatalf("expected 3 log records, got %d:\n%s", n, logs.String())
+	}
+
+	// POST is not idempotent: a 503 is returned as is
+	calls.Store(0)
+	if _, status, err := core.Do(context.Background(), "POST", srv.URL, []byte(`{}`)); err == nil || status != 503 {
+		t.Fatalf("expected 503 without retries, got %d %v", status, err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("POST retried %d times", n)
+	}
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// PartSuffix is appended to the path of a file being downloaded; the
+// partial file is renamed once complete, and a download finding one
+// continues from its size.
+const PartSuffix = ".part"
+
+// PartETagSuffix is appended to the path of a partial file for the file
+// keeping the validator of the content it was downloaded from (the ETag of
+// an S3 object, the ETag or Last-Modified of an HTTP resource): the
+// download continues only while the content still has it.
+const PartETagSuffix = ".etag"
+
+// ETagIsMD5 tells whether etag is the MD5 of the content of an object
+// encrypted with sse (ObjectInfo.SSE, "" when unknown or unencrypted): not
+// for multipart uploads, nor for SSE-KMS and SSE-C objects, whose ETags are
+// opaque.
+func ETagIsMD5(etag, sse string) bool {
+	etag = strings.Trim(etag, `"`)
+	if len(etag) != 2*md5.Size || strings.HasPrefix(sse, SSEKMS) || sse == SSECustomer {
+		return false
+	}
+	_, err := hex.DecodeString(etag)
+	return err == nil
+}
+
+// objectSSE returns the encryption of an object as ObjectInfo.SSE does.
+func objectSSE(sse s3types.ServerSideEncryption, customerAlgorithm *string) string {
+	if customerAlgorithm != nil {
+		return SSECustomer
+	}
+	return string(sse)
+}
+
+// verifyETag checks the file at path against etag when it is the MD5 of the
+// object, encrypted with sse.
+func verifyETag(path, etag, sse string) error {
+	if !ETagIsMD5(etag, sse) {
+		return nil
+	}
+	etag = strings.Trim(etag, `"`)
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("checksum of %s failed: %w", path, err)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, etag) {
+		return fmt.Errorf("%s: md5 mismatch (expected %s, got %s)", path, etag, sum)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// DefaultPresignTTL is the validity of presigned URLs when ttl is 0.
+const DefaultPresignTTL = 15 * time.Minute
+
+// MaxPresignTTL is the longest validity S3 accepts for a presigned URL.
+const MaxPresignTTL = 7 * 24 * time.Hour
+
+// PresignGet returns a URL downloading the object with a plain GET, valid for
+// ttl, so it can be handed to a browser or another system without sharing
+// credentials. URLs signed with temporary credentials expire with them.
+func (c *S3Client) PresignGet(ctx context.Context, bucket, key string, ttl time.Duration) (string, error) {
+	ttl, err := presignTTL(ttl)
+	if err != nil {
+		return "", err
+	}
+	req, err := s3.NewPresignClient(c.s3).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign s3://%s/%s: %w", bucket, key, err)
+	}
+	return req.URL, nil
+}
+
+// PresignPut returns a URL storing the body of a plain PUT as the object,
+// valid for ttl.
+func (c *S3Client) PresignPut(ctx context.Context, bucket, key string, ttl time.Duration) (string, error) {
+	ttl, err := presignTTL(ttl)
+	if err != nil {
+		return "", err
+	}
+	req, err := s3.NewPresignClient(c.s3).PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign s3://%s/%s: %w", bucket, key, err)
+	}
+	return req.URL, nil
+}
+
+func presignTTL(ttl time.Duration) (time.Duration, error) {
+	switch {
+	case ttl == 0:
+		return DefaultPresignTTL, nil
+	case ttl < 0 || ttl > MaxPresignTTL:
+		return 0, fmt.Errorf("invalid presign ttl %s (max %s)", ttl, MaxPresignTTL)
+	}
+	return ttl, nil
+}
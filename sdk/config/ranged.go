@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// RangeOptions tunes DownloadFileRanged; zero fields use the PartSize and
+// Concurrency of the client TransferConfig.
+type RangeOptions struct {
+	// PartSize is the size of each byte range; objects not larger than one
+	// part are downloaded in a single stream
+	PartSize int64
+	// Concurrency is the number of ranges fetched in parallel; 1 downloads
+	// in a single stream
+	Concurrency int
+}
+
+// DownloadFileRanged downloads an object as byte ranges fetched in parallel
+// and written in place into localPath. Ranges are requested with the ETag
+// of the object, so a change during the download fails it instead of
+// mixing versions. On failure the partial file is removed. hook may be nil.
+func (c *S3Client) DownloadFileRanged(
+	ctx context.Context,
+	bucket, key, localPath string,
+	hook *ProgressHook,
+	opts RangeOptions,
+) error {
+	partSize := opts.PartSize
+	if partSize <= 0 {
+		partSize = c.transfer.PartSize
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = c.transfer.Concurrency
+	}
+
+	head, err := c.s3.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &bucket, Key: &key})
+	if err != nil {
+		return fmt.Errorf("failed to head object from S3: %w", err)
+	}
+	total := aws.ToInt64(head.ContentLength)
+	if concurrency <= 1 || total <= partSize {
+		return c.DownloadFileWithProgress(ctx, bucket, key, localPath, hook)
+	}
+
+	if hook != nil && hook.OnStart != nil {
+		hook.OnStart(key, total)
+	}
+	f, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create local file: %w", err)
+	}
+	if err := f.Truncate(total); err != nil {
+		f.Close()
+		os.Remove(localPath)
+		return fmt.Errorf("failed to write to local file: %w", err)
+	}
+
+	pw := &progressWriter{key: key, total: total, interval: 250 * time.Millisecond}
+	if hook != nil {
+		pw.onProgress = hook.OnProgress
+	}
+	var mu sync.Mutex
+	progress := func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return pw.Write(p)
+	}
+
+	start := time.Now()
+	err = c.fetchRanges(ctx, bucket, key, head.ETag, f, total, partSize, concurrency, progress)
+	if cerr := f.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("failed to write to local file: %w", cerr)
+	}
+	if err != nil {
+		os.Remove(localPath)
+		return err
+	}
+	if hook != nil && hook.OnDone != nil {
+		hook.OnDone(key, total, time.Since(start))
+	}
+	return nil
+}
+
+// fetchRanges downloads [0, total) in ranges of partSize with concurrency
+// workers; the first error cancels the others.
+func (c *S3Client) fetchRanges(
+	ctx context.Context,
+	bucket, key string,
+	etag *string,
+	f *os.File,
+	total, partSize int64,
+	concurrency int,
+	progress func([]byte) (int, error),
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	offsets := make(chan int64)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for off := range offsets {
+				end := min(off+partSize, total) - 1
+				out, err := c.s3.GetObject(ctx, &s3.GetObjectInput{
+					Bucket:  &bucket,
+					Key:     &key,
+					Range:   aws.String(fmt.Sprintf("bytes=%d-%d", off, end)),
+					IfMatch: etag,
+				})
+				if err != nil {
+					fail(fmt.Errorf("failed to get range %d-%d from S3: %w", off, end, err))
+					continue
+				}
+				dst := io.NewOffsetWriter(f, off)
+				n, err := c.copy(dst, io.TeeReader(out.Body, writerFunc(progress)))
+				out.Body.Close()
+				if err == nil && n != end-off+1 {
+					err = io.ErrUnexpectedEOF
+				}
+				if err != nil {
+					fail(fmt.Errorf("failed to write to local file: %w", err))
+				}
+			}
+		}()
+	}
+feed:
+	for off := int64(0); off < total; off += partSize {
+		select {
+		case offsets <- off:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(offsets)
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
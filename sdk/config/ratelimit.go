@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting Core calls (in the style of
+// golang.org/x/time/rate). It is safe for concurrent use and meant to be
+// shared by every client talking to the same Core.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter allows perSecond calls per second with bursts of up to
+// burst calls; burst <= 0 means ceil(perSecond).
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if burst <= 0 {
+		burst = max(1, int(math.Ceil(perSecond)))
+	}
+	return &RateLimiter{rate: perSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Wait blocks until a call is allowed or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens-- // reserve, possibly going in debt
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, wait); err != nil {
+		// give the reservation back
+		l.mu.Lock()
+		l.tokens = min(l.burst, l.tokens+1)
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+var (
+	sharedLimitersMu sync.Mutex
+	sharedLimiters   = map[string]*RateLimiter{}
+)
+
+// limiterFor returns the limiter of a CoreConfig: the explicit RateLimiter,
+// or one shared by all clients with the same BaseURL and rate.
+func limiterFor(c CoreConfig) *RateLimiter {
+	if c.RateLimiter != nil {
+		return c.RateLimiter
+	}
+	if c.MaxRequestsPerSecond <= 0 {
+		return nil
+	}
+	key := fmt.Sprintf("%s|%g", c.BaseURL, c.MaxRequestsPerSecond)
+	sharedLimitersMu.Lock()
+	defer sharedLimitersMu.Unlock()
+	l, ok := sharedLimiters[key]
+	if !ok {
+		l = NewRateLimiter(c.MaxRequestsPerSecond, 0)
+		sharedLimiters[key] = l
+	}
+	return l
+}
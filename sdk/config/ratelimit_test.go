@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := config.NewRateLimiter(50, 1)
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the first call is free, the other 5 wait 20ms each
+	if took := time.Since(start); took < 90*time.Millisecond {
+		t.Fatalf("6 calls at 50/s took only %v", took)
+	}
+
+	slow := config.NewRateLimiter(0.1, 1)
+	_ = slow.Wait(ctx)
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := slow.Wait(cctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline, got %v", err)
+	}
+}
+
+func TestRateLimiterSharedAcrossClients(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	cfg := config.CoreConfig{BaseURL: srv.URL, APIVersion: "v1", MaxRequestsPerSecond: 20}
+	a := config.NewHTTPCore(nil, cfg)
+	b := config.NewHTTPCore(nil, cfg)
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		for _, c := range []config.CoreHTTP{a, b} {
+			if _, _, err := c.Do(ctx, "GET", srv.URL, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// burst 20 absorbs these 6 calls; a second round must be throttled as
+	// both clients draw from the same bucket
+	for i := 0; i < 20; i++ {
+		if _, _, err := a.Do(ctx, "GET", srv.URL, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if took := time.Since(start); took < 250*time.Millisecond {
+		t.Fatalf("26 calls at 20/s (burst 20) took only %v", took)
+	}
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// Source tells where the effective value of a setting comes from.
+type Source string
+
+const (
+	// SourceOverride is LoadOptions.Overrides, e.g. command line flags.
+	SourceOverride Source = "flag"
+	// SourceEnv is an environment variable.
+	SourceEnv Source = "env"
+	// SourceIni is the section of the environment in the INI file.
+	SourceIni Source = "ini"
+	// SourceIniDefault is the [DEFAULT] section of the INI file.
+	SourceIniDefault Source = "ini:DEFAULT"
+	// SourceProject is the project overlay (see ProjectFileName).
+	SourceProject Source = "project"
+	// SourceSecretStore is the credential store (e.g. the OS keyring).
+	SourceSecretStore Source = "secret-store"
+	// SourceDefault is the built-in default.
+	SourceDefault Source = "default"
+)
+
+// Setting is the effective value of a configuration key.
+type Setting struct {
+	Key   string
+	Value string
+	// Source and Origin tell where Value comes from; Origin is the variable
+	// name for SourceEnv, "path [section]" for the INI and project sources and the
+	// environment for SourceSecretStore
+	Source Source
+	Origin string
+	// Secret values are masked by Explain
+	Secret bool
+}
+
+// Settings is the report of Explain, sorted by key.
+type Settings []Setting
+
+// Map returns key -> value, e.g. for printing as YAML or JSON.
+func (s Settings) Map() map[string]string {
+	m := make(map[string]string, len(s))
+	for _, st := range s {
+		m[st.Key] = st.Value
+	}
+	return m
+}
+
+// Get returns the setting of key.
+func (s Settings) Get(key string) (Setting, bool) {
+	for _, st := range s {
+		if st.Key == key {
+			return st, true
+		}
+	}
+	return Setting{}, false
+}
+
+// Explain reports the effective configuration Load would build with opts:
+// every key set by any layer, with the source of its value. Secret values
+// (tokens, passwords, keys; see RegisterSecretKeys) are replaced by
+// Redacted.
+func Explain(ctx context.Context, opts LoadOptions) (Settings, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	settings, err := loadSettings(opts)
+	if err != nil {
+		return nil, err
+	}
+	out := make(Settings, 0, len(settings))
+	for _, s := range settings {
+		if s.Secret = isSecretSetting(s.Key); s.Secret && s.Value != "" {
+			s.Value = Redacted
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+// isSecretSetting matches the secret keys of Load, the keys registered with
+// RegisterSecretKeys and the keys ending with one of them (e.g.
+// dhcore_access_token).
+func isSecretSetting(key string) bool {
+	for _, k := range loadKeys {
+		if k.key == key {
+			if k.secret {
+				return true
+			}
+			break
+		}
+	}
+	if isSecretKey(key) {
+		return true
+	}
+	secretKeysMu.RLock()
+	defer secretKeysMu.RUnlock()
+	for k := range secretKeys {
+		if strings.HasSuffix(strings.ToLower(key), "_"+k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestExplain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.ini")
+	ini := "[DEFAULT]\ncurrent_environment = dev\naws_region = eu-west-1\n\n[dev]\ndhcore_endpoint = https://ini.example\ndhcore_access_token = secret-token\n"
+	if err := os.WriteFile(path, []byte(ini), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"DHCORE_ENDPOINT": "https://env.example"}
+	settings, err := config.Explain(context.Background(), config.LoadOptions{
+		IniPath:   path,
+		LookupEnv: func(k string) (string, bool) { v, ok := env[k]; return v, ok },
+		Overrides: map[string]string{"aws_endpoint_url": "https://minio.example"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]config.Source{
+		"dhcore_endpoint":     config.SourceEnv,
+		"dhcore_access_token": config.SourceIni,
+		"aws_region":          config.SourceIniDefault,
+		"aws_endpoint_url":    config.SourceOverride,
+		"dhcore_api_version":  config.SourceDefault,
+	}
+	for key, src := range want {
+		s, ok := settings.Get(key)
+		if !ok || s.Source != src {
+			t.Errorf("%s: got %+v, want source %s", key, s, src)
+		}
+	}
+	if s, _ := settings.Get("dhcore_endpoint"); s.Origin != "DHCORE_ENDPOINT" || s.Value != "https://env.example" {
+		t.Errorf("unexpected endpoint setting %+v", s)
+	}
+	if s, _ := settings.Get("dhcore_access_token"); !s.Secret || s.Value != config.Redacted || s.Origin != path+" [dev]" {
+		t.Errorf("token not masked: %+v", s)
+	}
+	if m := settings.Map(); m["aws_region"] != "eu-west-1" || m["dhcore_access_token"] != config.Redacted {
+		t.Errorf("unexpected dump %v", m)
+	}
+}
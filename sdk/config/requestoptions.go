@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// RequestOptions are extra headers and query parameters added to the Core
+// calls of a single operation, e.g. audit headers or experimental flags.
+type RequestOptions struct {
+	Header http.Header
+	Query  url.Values
+}
+
+// RequestOption customizes the Core calls of an operation; services accept
+// them in the Options field of their request structs.
+type RequestOption func(*RequestOptions)
+
+// WithHeader sets a header on the calls of the operation.
+func WithHeader(key, value string) RequestOption {
+	return func(o *RequestOptions) {
+		if o.Header == nil {
+			o.Header = http.Header{}
+		}
+		o.Header.Set(key, value)
+	}
+}
+
+// WithQueryParam adds a query parameter to the calls of the operation.
+func WithQueryParam(key, value string) RequestOption {
+	return func(o *RequestOptions) {
+		if o.Query == nil {
+			o.Query = url.Values{}
+		}
+		o.Query.Add(key, value)
+	}
+}
+
+type requestOptionsCtxKey struct{}
+
+// ContextWithRequestOptions applies opts to the calls made with the returned
+// context, on top of the options already in ctx.
+func ContextWithRequestOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	if len(opts) == 0 {
+		return ctx
+	}
+	var o RequestOptions
+	if prev, ok := ctx.Value(requestOptionsCtxKey{}).(RequestOptions); ok {
+		o.Header = prev.Header.Clone()
+		if prev.Query != nil {
+			o.Query = url.Values{}
+			for k, v := range prev.Query {
+				o.Query[k] = append([]string(nil), v...)
+			}
+		}
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return context.WithValue(ctx, requestOptionsCtxKey{}, o)
+}
+
+// applyRequestOptions adds the options of ctx to req.
+func applyRequestOptions(ctx context.Context, req *http.Request) {
+	o, ok := ctx.Value(requestOptionsCtxKey{}).(RequestOptions)
+	if !ok {
+		return
+	}
+	for k, v := range o.Header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	if len(o.Query) > 0 {
+		q := req.URL.Query()
+		for k, v := range o.Query {
+			for _, s := range v {
+				q.Add(k, s)
+			}
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+type responseHeaderCtxKey struct{}
+
+// ContextWithResponseHeader stores in *h the header of the responses to the
+// calls made with the returned context (of the last one, when there are
+// several), e.g. to read the ETag of an entity.
+func ContextWithResponseHeader(ctx context.Context, h *http.Header) context.Context {
+	return context.WithValue(ctx, responseHeaderCtxKey{}, h)
+}
+
+// storeResponseHeader copies the header of resp where ctx asks for it.
+func storeResponseHeader(ctx context.Context, resp *http.Response) {
+	if h, ok := ctx.Value(responseHeaderCtxKey{}).(*http.Header); ok && h != nil {
+		*h = resp.Header.Clone()
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ResourceOptions describes how a resource kind is addressed on Core.
+type ResourceOptions struct {
+	// Global resources live at /api/{version}/{plural} instead of
+	// /api/{version}/-/{project}/{plural} (e.g. projects).
+	Global bool
+}
+
+// Resource is a registered resource kind: the plural used in Core URLs and
+// the aliases accepted from users.
+type Resource struct {
+	Plural  string
+	Aliases []string
+	Options ResourceOptions
+}
+
+var (
+	resourcesMu sync.RWMutex
+	resources   = map[string]Resource{}
+)
+
+func init() {
+	for _, r := range []Resource{
+		{Plural: "artifacts", Aliases: []string{"artifact"}},
+		{Plural: "dataitems", Aliases: []string{"dataitem"}},
+		{Plural: "functions", Aliases: []string{"function", "fn"}},
+		{Plural: "models", Aliases: []string{"model"}},
+		{Plural: "projects", Aliases: []string{"project"}, Options: ResourceOptions{Global: true}},
+		{Plural: "runs", Aliases: []string{"run"}},
+		{Plural: "secrets", Aliases: []string{"secret"}},
+		{Plural: "tasks", Aliases: []string{"task"}},
+		{Plural: "workflows", Aliases: []string{"workflow"}},
+		{Plural: "logs", Aliases: []string{"log"}},
+	} {
+		if err := RegisterResource(r.Plural, r.Aliases, r.Options); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterResource makes a resource kind addressable by its plural and
+// aliases, e.g. RegisterResource("triggers", []string{"trigger"}, ResourceOptions{}).
+// Registering an existing plural again adds the aliases and replaces the
+// options. A name already used by another resource is an error.
+func RegisterResource(plural string, aliases []string, opts ResourceOptions) error {
+	plural = strings.TrimSpace(plural)
+	if plural == "" {
+		return errors.New("resource plural is required")
+	}
+
+	resourcesMu.Lock()
+	defer resourcesMu.Unlock()
+
+	for _, name := range append([]string{plural}, aliases...) {
+		if owner, ok := lookupLocked(name); ok && owner.Plural != plural {
+			return fmt.Errorf("resource name %q is already used by %q", name, owner.Plural)
+		}
+	}
+
+	r := resources[plural]
+	r.Plural = plural
+	r.Options = opts
+	for _, a := range aliases {
+		if a = strings.TrimSpace(a); a != "" && a != plural && !slices.Contains(r.Aliases, a) {
+			r.Aliases = append(r.Aliases, a)
+		}
+	}
+	resources[plural] = r
+	return nil
+}
+
+func lookupLocked(name string) (Resource, bool) {
+	if r, ok := resources[name]; ok {
+		return r, true
+	}
+	for _, r := range resources {
+		if slices.Contains(r.Aliases, name) {
+			return r, true
+		}
+	}
+	return Resource{}, false
+}
+
+// LookupResource finds a resource by plural or alias.
+func LookupResource(name string) (Resource, bool) {
+	resourcesMu.RLock()
+	defer resourcesMu.RUnlock()
+	r, ok := lookupLocked(name)
+	r.Aliases = slices.Clone(r.Aliases)
+	return r, ok
+}
+
+// ResolveResource returns the plural (the URL segment) for a plural or alias.
+func ResolveResource(name string) (string, error) {
+	r, ok := LookupResource(name)
+	if !ok {
+		return "", fmt.Errorf("resource '%v' is not supported", name)
+	}
+	return r.Plural, nil
+}
+
+// IsGlobalResource reports whether name is a registered resource that is not
+// scoped by project.
+func IsGlobalResource(name string) bool {
+	r, ok := LookupResource(name)
+	return ok && r.Options.Global
+}
+
+// RegisteredResources returns all resources sorted by plural.
+func RegisteredResources() []Resource {
+	resourcesMu.RLock()
+	out := make([]Resource, 0, len(resources))
+	for _, r := range resources {
+		r.Aliases = slices.Clone(r.Aliases)
+		out = append(out, r)
+	}
+	resourcesMu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Plural < out[j].Plural })
+	return out
+}
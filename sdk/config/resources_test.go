@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestRegisterResource(t *testing.T) {
+	if err := config.RegisterResource("triggers", []string{"trigger", "trg"}, config.ResourceOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := config.ResolveResource("trg"); err != nil || got != "triggers" {
+		t.Fatalf("ResolveResource(trg) = %q, %v", got, err)
+	}
+	if got, _ := config.ResolveResource("fn"); got != "functions" {
+		t.Fatalf("builtin alias lost: %q", got)
+	}
+	if _, err := config.ResolveResource("unknown"); err == nil {
+		t.Fatal("expected error for unknown resource")
+	}
+
+	// aliases can't be stolen from another kind
+	if err := config.RegisterResource("tasks", []string{"run"}, config.ResourceOptions{}); err == nil {
+		t.Fatal("expected alias conflict")
+	}
+
+	if err := config.RegisterResource("templates", []string{"template"}, config.ResourceOptions{Global: true}); err != nil {
+		t.Fatal(err)
+	}
+	core := config.NewHTTPCore(nil, config.CoreConfig{BaseURL: "http://core", APIVersion: "v1"})
+	if got := core.BuildURL("demo", "templates", "", nil); got != "http://core/api/v1/templates" {
+		t.Fatalf("global resource url = %s", got)
+	}
+	if got := core.BuildURL("demo", "triggers", "t1", nil); got != "http://core/api/v1/-/demo/triggers/t1" {
+		t.Fatalf("project resource url = %s", got)
+	}
+}
+
+func TestBuildURLEscaping(t *testing.T) {
+	core := config.NewHTTPCore(nil, config.CoreConfig{BaseURL: "http://core/", APIVersion: "v1"})
+
+	got := core.BuildURL("my proj", "artifacts", "a/b", map[string]string{"name": "a b&c+d", "empty": ""})
+	if want := "http://core/api/v1/-/my%20proj/artifacts/a%2Fb?name=a+b%26c%2Bd"; got != want {
+		t.Fatalf("BuildURL = %s, want %s", got, want)
+	}
+
+	got = core.BuildURLValues("demo", "artifacts", "", url.Values{"label": {"città", "x=y"}, "size": {"10"}})
+	if want := "http://core/api/v1/-/demo/artifacts?label=citt%C3%A0&label=x%3Dy&size=10"; got != want {
+		t.Fatalf("BuildURLValues = %s, want %s", got, want)
+	}
+}
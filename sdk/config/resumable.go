@@ -0,0 +1,298 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// UploadStateSuffix is appended to the path of a file to name the sidecar
+// holding the state of its resumable upload.
+const UploadStateSuffix = ".dhupload"
+
+// UploadState is the progress of a resumable multipart upload, saved next to
+// the file after each part.
+type UploadState struct {
+	Bucket   string `json:"bucket"`
+	Key      string `json:"key"`
+	UploadID string `json:"upload_id"`
+	// Size, ModTime and PartSize identify the file the parts were cut from:
+	// a state that doesn't match is discarded
+	Size     int64        `json:"size"`
+	ModTime  time.Time    `json:"mod_time"`
+	PartSize int64        `json:"part_size"`
+	Parts    []UploadPart `json:"parts"`
+}
+
+// UploadPart is a part already stored by S3.
+type UploadPart struct {
+	Number int32  `json:"number"`
+	ETag   string `json:"etag"`
+	// SHA256 of the part, hex encoded; a part whose content changed since is
+	// uploaded again
+	SHA256 string `json:"sha256"`
+}
+
+// LoadUploadState reads the state of the upload of the file at path, nil
+// when there is none.
+func LoadUploadState(path string) (*UploadState, error) {
+	b, err := os.ReadFile(path + UploadStateSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var st UploadState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	return &st, nil
+}
+
+func (st *UploadState) save(path string) error {
+	b, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %w", err)
+	}
+	tmp := path + UploadStateSuffix + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path+UploadStateSuffix)
+}
+
+func (st *UploadState) matches(bucket, key string, info os.FileInfo, partSize int64) bool {
+	return st != nil && st.UploadID != "" && st.Bucket == bucket && st.Key == key &&
+		st.Size == info.Size() && st.ModTime.Equal(info.ModTime()) && st.PartSize == partSize
+}
+
+// resumablePartSize is PartSize, raised for files that would otherwise need
+// more parts than S3 accepts.
+func (c *S3Client) resumablePartSize(size int64) int64 {
+	const maxParts = int64(manager.MaxUploadParts)
+	return max(c.transfer.PartSize, (size+maxParts-1)/maxParts)
+}
+
+// uploadResumable uploads file in parts of resumablePartSize, recording each
+// stored part in the sidecar state; a later call for the same file, bucket
+// and key skips the parts already stored. The sidecar is removed once the upload
+// completes. If it can't be written, the upload goes on without resume.
+func (c *S3Client) uploadResumable(
+	ctx context.Context,
+	bucket, key string,
+	file *os.File,
+	info os.FileInfo,
+	contentType string,
+	objOpts ObjectOptions,
+	pw *progressWriter,
+) (*manager.UploadOutput, error) {
+	path := file.Name()
+	partSize := c.resumablePartSize(info.Size())
+
+	st, err := LoadUploadState(path)
+	if err != nil || !st.matches(bucket, key, info, partSize) {
+		if st != nil && st.UploadID != "" && st.Bucket != "" {
+			// best effort: the parts of a stale upload are billed until aborted
+			_, _ = c.s3.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(st.Bucket),
+				Key:      aws.String(st.Key),
+				UploadId: aws.String(st.UploadID),
+			})
+		}
+		st = nil
+	}
+
+	out, err := c.uploadParts(ctx, file, info, partSize, contentType, objOpts, pw, st, bucket, key)
+	if st != nil && hasErrorCode(err, "NoSuchUpload") {
+		// the upload expired or was aborted on the server: start over
+		pw.written = 0
+		out, err = c.uploadParts(ctx, file, info, partSize, contentType, objOpts, pw, nil, bucket, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = os.Remove(path + UploadStateSuffix)
+	return out, nil
+}
+
+func (c *S3Client) uploadParts(
+	ctx context.Context,
+	file *os.File,
+	info os.FileInfo,
+	partSize int64,
+	contentType string,
+	objOpts ObjectOptions,
+	pw *progressWriter,
+	st *UploadState,
+	bucket, key string,
+) (*manager.UploadOutput, error) {
+	path := file.Name()
+	size := info.Size()
+
+	if st == nil {
+		in := &s3.CreateMultipartUploadInput{
+			Bucket:      aws.String(bucket),
+			Key:         aws.String(key),
+			ContentType: aws.String(contentType),
+		}
+		objOpts.applyMultipart(in)
+		created, err := c.s3.CreateMultipartUpload(ctx, in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create multipart upload: %w", err)
+		}
+		st = &UploadState{
+			Bucket:   bucket,
+			Key:      key,
+			UploadID: aws.ToString(created.UploadId),
+			Size:     size,
+			ModTime:  info.ModTime(),
+			PartSize: partSize,
+		}
+		_ = st.save(path)
+	}
+
+	done := map[int32]UploadPart{}
+	for _, p := range st.Parts {
+		done[p.Number] = p
+	}
+	count := int32((size + partSize - 1) / partSize)
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	progress := func(chunk []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = pw.Write(chunk)
+	}
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	parts := make(chan int32)
+	for w := 0; w < c.transfer.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, partSize)
+			for n := range parts {
+				off := int64(n-1) * partSize
+				chunk := buf[:min(partSize, size-off)]
+				if _, err := file.ReadAt(chunk, off); err != nil && !errors.Is(err, io.EOF) {
+					fail(fmt.Errorf("read error: %w", err))
+					continue
+				}
+				sum := sha256.Sum256(chunk)
+				hash := hex.EncodeToString(sum[:])
+				if p, ok := done[n]; ok && p.SHA256 == hash {
+					progress(chunk)
+					continue
+				}
+				out, err := c.s3.UploadPart(ctx, &s3.UploadPartInput{
+					Bucket:        aws.String(bucket),
+					Key:           aws.String(key),
+					UploadId:      aws.String(st.UploadID),
+					PartNumber:    aws.Int32(n),
+					Body:          bytes.NewReader(chunk),
+					ContentLength: aws.Int64(int64(len(chunk))),
+				})
+				if err != nil {
+					fail(fmt.Errorf("failed to upload part %d: %w", n, err))
+					continue
+				}
+				mu.Lock()
+				st.Parts = appendPart(st.Parts, UploadPart{Number: n, ETag: aws.ToString(out.ETag), SHA256: hash})
+				_ = st.save(path)
+				mu.Unlock()
+				progress(chunk)
+			}
+		}()
+	}
+feed:
+	for n := int32(1); n <= count; n++ {
+		select {
+		case parts <- n:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(parts)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	completed := make([]s3types.CompletedPart, len(st.Parts))
+	for i, p := range st.Parts {
+		completed[i] = s3types.CompletedPart{PartNumber: aws.Int32(p.Number), ETag: aws.String(p.ETag)}
+	}
+	out, err := c.s3.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(st.UploadID),
+		MultipartUpload: &s3types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return &manager.UploadOutput{
+		Location:       aws.ToString(out.Location),
+		VersionID:      out.VersionId,
+		UploadID:       st.UploadID,
+		ETag:           out.ETag,
+		CompletedParts: completed,
+		Key:            out.Key,
+	}, nil
+}
+
+// hasErrorCode tells whether err is the S3 error code, which most
+// operations report as a generic API error rather than a typed one.
+func hasErrorCode(err error, code string) bool {
+	var apiErr interface{ ErrorCode() string }
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
+
+// appendPart adds p to parts, replacing a part with the same number, sorted
+// by number as CompleteMultipartUpload requires.
+func appendPart(parts []UploadPart, p UploadPart) []UploadPart {
+	for i := range parts {
+		if parts[i].Number == p.Number {
+			parts[i] = p
+			return parts
+		}
+	}
+	parts = append(parts, p)
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number })
+	return parts
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how Core calls are retried. Idempotent requests
+// (GET, HEAD, PUT, DELETE, and POST with an idempotency key, see
+// ContextWithIdempotencyKey) are retried on network errors and 5xx responses;
+// every request, POST included, is retried on 429 Too Many Requests and when
+// the connection could not be established (nothing reached the server).
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts; values <= 1 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled at each
+	// attempt (default 200ms).
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts (default 5s).
+	MaxBackoff time.Duration
+	// Jitter randomizes each wait by up to this fraction (0..1), so
+	// concurrent clients do not retry in lockstep.
+	Jitter float64
+}
+
+// DefaultRetryPolicy is a reasonable policy for interactive use.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, InitialBackoff: 200 * time.Millisecond, MaxBackoff: 5 * time.Second, Jitter: 0.2}
+
+// NoRetry disables retries, e.g. as per-call override with ContextWithRetry.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+type retryCtxKey struct{}
+
+// ContextWithRetry overrides the retry policy of the client for the calls
+// made with the returned context.
+func ContextWithRetry(ctx context.Context, p RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryCtxKey{}, &p)
+}
+
+// retryFromContext returns the per-call policy, if any.
+func retryFromContext(ctx context.Context) (*RetryPolicy, bool) {
+	p, ok := ctx.Value(retryCtxKey{}).(*RetryPolicy)
+	return p, ok
+}
+
+// backoff returns the wait before retry number attempt (1-based).
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	if d <= 0 {
+		d = 200 * time.Millisecond
+	}
+	maxd := p.maxBackoff()
+	for i := 1; i < attempt && d < maxd; i++ {
+		d *= 2
+	}
+	d = min(d, maxd)
+	if j := min(p.Jitter, 1); j > 0 {
+		// d ± j*d
+		d += time.Duration((rand.Float64()*2 - 1) * j * float64(d))
+	}
+	return d
+}
+
+// wait returns the wait before retry number attempt: the Retry-After of
+// the response when present, else the backoff; both are capped at
+// MaxBackoff.
+func (p *RetryPolicy) wait(attempt int, h http.Header) time.Duration {
+	if ra := retryAfter(h, time.Now()); ra > 0 {
+		return min(ra, p.maxBackoff())
+	}
+	return p.backoff(attempt)
+}
+
+func (p *RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff <= 0 {
+		return 5 * time.Second
+	}
+	return p.MaxBackoff
+}
+
+// retryable reports whether a call ended with status/err may be repeated.
+func (p *RetryPolicy) retryable(method string, keyed bool, status int, err error) bool {
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	if err != nil && status == 0 && isDialError(err) {
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	default:
+		// a POST with an idempotency key can't create duplicates
+		if !keyed {
+			return false
+		}
+	}
+	if errors.Is(err, errAttemptTimeout) {
+		return true
+	}
+	if err != nil && status == 0 {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return status >= 500
+}
+
+// isDialError reports whether err happened while connecting, before any byte
+// of the request was sent.
+func isDialError(err error) bool {
+	var op *net.OpError
+	return errors.As(err, &op) && op.Op == "dial"
+}
+
+// retryAfter parses a Retry-After header, expressed in seconds or as an
+// HTTP date relative to now.
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		return time.Duration(max(s, 0)) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
+
+// sleepContext waits d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
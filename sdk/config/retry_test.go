@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestCoreConfigRetry(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	policy := config.RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond, Jitter: 0.5}
+	core := config.NewHTTPCore(nil, config.CoreConfig{BaseURL: srv.URL, APIVersion: "v1", Retry: &policy})
+	ctx := context.Background()
+
+	if _, status, err := core.Do(ctx, "GET", srv.URL, nil); err == nil || status != 502 {
+		t.Fatalf("expected 502, got %d %v", status, err)
+	}
+	if n := calls.Swap(0); n != 4 {
+		t.Fatalf("expected 4 attempts, got %d", n)
+	}
+
+	// per-call override
+	if _, _, err := core.Do(config.ContextWithRetry(ctx, config.NoRetry), "GET", srv.URL, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected a single attempt with NoRetry, got %d", n)
+	}
+}
+
+func TestRetryConnectionRefused(t *testing.T) {
+	// a closed listener: the connection is refused, so even POST is retried
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := "http://" + l.Addr().String()
+	_ = l.Close()
+
+	var attempts atomic.Int32
+	rt := config.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		attempts.Add(1)
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	core := config.NewHTTPCoreWithOptions(config.CoreConfig{BaseURL: addr, APIVersion: "v1"}, config.NewServiceOptions(
+		config.WithHTTPClient(&http.Client{Transport: rt}),
+		config.WithRetryPolicy(config.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+	))
+	if _, _, err := core.Do(context.Background(), "POST", addr, []byte(`{}`)); err == nil {
+		t.Fatal("expected connection error")
+	}
+	if n := attempts.Load(); n != 3 {
+		t.Fatalf("expected 3 attempts, got %d", n)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	var retryAfter atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1)%2 == 1 {
+			w.Header().Set("Retry-After", retryAfter.Load().(string))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	policy := config.RetryPolicy{MaxAttempts: 2, MaxBackoff: 20 * time.Millisecond}
+	core := config.NewHTTPCore(nil, config.CoreConfig{BaseURL: srv.URL, APIVersion: "v1", Retry: &policy})
+	// both forms are capped at MaxBackoff
+	for _, v := range []string{"3600", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)} {
+		retryAfter.Store(v)
+		start := time.Now()
+		if _, status, err := core.Do(context.Background(), "GET", srv.URL, nil); err != nil || status != 200 {
+			t.Fatalf("Retry-After %s: got %d %v", v, status, err)
+		}
+		if d := time.Since(start); d > 2*time.Second {
+			t.Fatalf("Retry-After %s: waited %v", v, d)
+		}
+	}
+
+	// a wait beyond the total timeout gives up at once
+	calls.Store(0)
+	retryAfter.Store("3600")
+	policy.MaxBackoff = time.Hour
+	core = config.NewHTTPCore(nil, config.CoreConfig{BaseURL: srv.URL, APIVersion: "v1", Retry: &policy,
+		Timeouts: config.Timeouts{Total: 10 * time.Second}})
+	start := time.Now()
+	if _, status, err := core.Do(context.Background(), "GET", srv.URL, nil); err == nil || status != 429 {
+		t.Fatalf("expected 429, got %d %v", status, err)
+	}
+	if d := time.Since(start); d > 2*time.Second || calls.Load() != 1 {
+		t.Fatalf("expected a single attempt without waiting, got %d in %v", calls.Load(), d)
+	}
+}
@@ -5,10 +5,12 @@
 package config
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -23,15 +25,26 @@ import (
 )
 
 type S3Client struct {
-	s3 *s3.Client
+	s3       *s3.Client
+	transfer TransferConfig
+	objects  ObjectOptions
 }
 
 func NewS3Client(ctx context.Context, cfgCreds S3Config) (*S3Client, error) {
-	creds := aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(
+	if err := cfgCreds.Objects.Validate(); err != nil {
+		return nil, err
+	}
+	var provider aws.CredentialsProvider = credentials.NewStaticCredentialsProvider(
 		cfgCreds.AccessKey,
 		cfgCreds.SecretKey,
 		cfgCreds.AccessToken,
-	))
+	)
+	if cfgCreds.Credentials != nil {
+		provider = cfgCreds.Credentials
+	}
+	creds := aws.NewCredentialsCache(provider, func(o *aws.CredentialsCacheOptions) {
+		o.ExpiryWindow = credentialsRefreshWindow
+	})
 
 	cfg, err := config.LoadDefaultConfig(ctx,
 		config.WithCredentialsProvider(creds),
@@ -46,10 +59,15 @@ func NewS3Client(ctx context.Context, cfgCreds S3Config) (*S3Client, error) {
 			o.BaseEndpoint = aws.String(cfgCreds.EndpointURL)
 			o.UsePathStyle = true // necessario per molti S3-compat
 		}
+		if cfgCreds.HTTPClient != nil {
+			o.HTTPClient = cfgCreds.HTTPClient
+		}
 	}
 
 	return &S3Client{
-		s3: s3.NewFromConfig(cfg, s3Options),
+		s3:       s3.NewFromConfig(cfg, s3Options),
+		transfer: cfgCreds.Transfer.WithDefaults(),
+		objects:  cfgCreds.Objects,
 	}, nil
 }
 
@@ -58,6 +76,8 @@ type S3File struct {
 	Name         string
 	Size         int64
 	LastModified string
+	// ETag as listed, quotes included
+	ETag string
 }
 
 /* -------------------- LIST (paginata) -------------------- */
@@ -92,6 +112,7 @@ func (c *S3Client) ListFilesPaged(
 			Name:         name,
 			Size:         aws.ToInt64(obj.Size),
 			LastModified: obj.LastModified.Format("2006-01-02T15:04:05Z07:00"),
+			ETag:         aws.ToString(obj.ETag),
 		})
 	}
 
@@ -194,49 +215,77 @@ func (pw *progressWriter) Write(p []byte) (int, error) {
 
 /* -------------------- DOWNLOAD -------------------- */
 
-func (c *S3Client) DownloadFile(ctx context.Context, bucket, key, localPath string) error {
-	out, err := c.s3.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: &bucket,
-		Key:    &key,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to get object from S3: %w", err)
+// copy copies src to dst with the configured buffer size; dst is wrapped so
+// *os.File.ReadFrom does not replace the buffer with its own.
+func (c *S3Client) copy(dst io.Writer, src io.Reader) (int64, error) {
+	size := c.transfer.BufferSize
+	if size <= 0 {
+		size = DefaultBufferSize
 	}
-	defer out.Body.Close()
-
-	f, err := os.Create(localPath)
-	if err != nil {
-		return fmt.Errorf("failed to create local file: %w", err)
-	}
-	defer f.Close()
+	return io.CopyBuffer(struct{ io.Writer }{dst}, src, make([]byte, size))
+}
 
-	if _, err := io.Copy(f, out.Body); err != nil {
-		return fmt.Errorf("failed to write to local file: %w", err)
-	}
-	return nil
+func (c *S3Client) DownloadFile(ctx context.Context, bucket, key, localPath string) error {
+	return c.DownloadFileWithProgress(ctx, bucket, key, localPath, nil)
 }
 
+// DownloadFileWithProgress downloads an object to localPath through
+// localPath+PartSuffix, renamed once complete. The ETag of the object is
+// kept next to the partial file: when a download is interrupted, the next
+// one continues from the size of the partial file with a range GET made
+// only if the object still has that ETag (else it starts over), and the
+// resumed file is checked against the ETag when it is the MD5 of the
+// object. hook may be nil.
 func (c *S3Client) DownloadFileWithProgress(
 	ctx context.Context,
 	bucket, key, localPath string,
 	hook *ProgressHook,
 ) error {
-	out, err := c.s3.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: &bucket,
-		Key:    &key,
-	})
+	part := localPath + PartSuffix
+	var offset int64
+	var etag string
+	if st, err := os.Stat(part); err == nil && st.Mode().IsRegular() && st.Size() > 0 {
+		// without the ETag of the first GET a change of the object goes unnoticed
+		if b, err := os.ReadFile(part + PartETagSuffix); err == nil && len(b) > 0 {
+			offset, etag = st.Size(), string(b)
+		}
+	}
+
+	in := &s3.GetObjectInput{Bucket: &bucket, Key: &key}
+	if offset > 0 {
+		in.Range = aws.String(fmt.Sprintf("bytes=%d-", offset))
+		in.IfMatch = aws.String(etag)
+	}
+	out, err := c.s3.GetObject(ctx, in)
+	if offset > 0 && (hasErrorCode(err, "InvalidRange") || hasErrorCode(err, "PreconditionFailed")) {
+		// partial file complete or stale: start over
+		offset = 0
+		out, err = c.s3.GetObject(ctx, &s3.GetObjectInput{Bucket: &bucket, Key: &key})
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get object from S3: %w", err)
 	}
 	defer out.Body.Close()
 
-	total := aws.ToInt64(out.ContentLength)
+	total := offset + aws.ToInt64(out.ContentLength)
 
 	if hook != nil && hook.OnStart != nil {
 		hook.OnStart(key, total)
 	}
 
-	f, err := os.Create(localPath)
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if offset > 0 {
+		flags = os.O_WRONLY | os.O_APPEND
+	} else {
+		// a stale ETag must not outlive the partial file it belonged to
+		os.Remove(part + PartETagSuffix)
+		if etag := aws.ToString(out.ETag); etag != "" {
+			if err := os.WriteFile(part+PartETagSuffix, []byte(etag), 0o644); err != nil {
+				return fmt.Errorf("failed to create local file: %w", err)
+			}
+		}
+	}
+	f, err := os.OpenFile(part, flags, 0o644)
 	if err != nil {
 		return fmt.Errorf("failed to create local file: %w", err)
 	}
@@ -245,6 +294,7 @@ func (c *S3Client) DownloadFileWithProgress(
 	pw := &progressWriter{
 		key:        key,
 		total:      total,
+		written:    offset,
 		interval:   250 * time.Millisecond,
 		onProgress: nil,
 	}
@@ -255,9 +305,23 @@ func (c *S3Client) DownloadFileWithProgress(
 	start := time.Now()
 	tee := io.TeeReader(out.Body, pw)
 
-	if _, err := io.Copy(f, tee); err != nil {
+	if _, err := c.copy(f, tee); err != nil {
+		return fmt.Errorf("failed to write to local file: %w", err)
+	}
+	if err := f.Close(); err != nil {
 		return fmt.Errorf("failed to write to local file: %w", err)
 	}
+	if offset > 0 {
+		if err := verifyETag(part, aws.ToString(out.ETag), objectSSE(out.ServerSideEncryption, out.SSECustomerAlgorithm)); err != nil {
+			os.Remove(part)
+			os.Remove(part + PartETagSuffix)
+			return err
+		}
+	}
+	if err := os.Rename(part, localPath); err != nil {
+		return fmt.Errorf("failed to write to local file: %w", err)
+	}
+	os.Remove(part + PartETagSuffix)
 
 	if hook != nil && hook.OnDone != nil {
 		hook.OnDone(key, total, time.Since(start))
@@ -265,45 +329,26 @@ func (c *S3Client) DownloadFileWithProgress(
 	return nil
 }
 
-/* -------------------- UPLOAD -------------------- */
-
-// Compat: upload senza progress (non tocco il tuo codice esistente)
-func (c *S3Client) UploadFile(ctx context.Context, bucket, key string, file *os.File) (interface{}, error) {
-	const threshold = 100 * 1024 * 1024
-
-	info, err := file.Stat()
+// GetObjectStream opens an object for reading, without touching the disk;
+// it returns the body, to be closed by the caller, and its size (-1 when
+// unknown).
+func (c *S3Client) GetObjectStream(ctx context.Context, bucket, key string) (io.ReadCloser, int64, error) {
+	out, err := c.s3.GetObject(ctx, &s3.GetObjectInput{Bucket: &bucket, Key: &key})
 	if err != nil {
-		return nil, fmt.Errorf("stat error: %w", err)
-	}
-	size := info.Size()
-	if _, err := file.Seek(0, io.SeekStart); err != nil {
-		return nil, fmt.Errorf("seek error: %w", err)
+		return nil, 0, fmt.Errorf("failed to get object from S3: %w", err)
 	}
-
-	// Detect MIME TYPE
-	buf := make([]byte, 512)
-	n, _ := file.Read(buf)
-	mime := http.DetectContentType(buf[:n])
-	if _, err := file.Seek(0, io.SeekStart); err != nil {
-		return nil, fmt.Errorf("rewind error: %w", err)
+	size := int64(-1)
+	if out.ContentLength != nil {
+		size = *out.ContentLength
 	}
+	return out.Body, size, nil
+}
 
-	if size > threshold {
-		return manager.NewUploader(c.s3).Upload(ctx, &s3.PutObjectInput{
-			Bucket:      aws.String(bucket),
-			Key:         aws.String(key),
-			Body:        file,
-			ContentType: aws.String(mime),
-		})
-	}
+/* -------------------- UPLOAD -------------------- */
 
-	return c.s3.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:        aws.String(bucket),
-		Key:           aws.String(key),
-		Body:          file,
-		ContentLength: aws.Int64(size),
-		ContentType:   aws.String(mime),
-	})
+// Compat: upload senza progress (non tocco il tuo codice esistente)
+func (c *S3Client) UploadFile(ctx context.Context, bucket, key string, file *os.File) (interface{}, error) {
+	return c.UploadFileWithContentType(ctx, bucket, key, file, "", nil)
 }
 
 // Nuovo: upload con progress (usa lo stesso threshold/strategy)
@@ -313,8 +358,19 @@ func (c *S3Client) UploadFileWithProgress(
 	file *os.File,
 	hook *ProgressHook,
 ) (interface{}, error) {
-	const threshold = 100 * 1024 * 1024
+	return c.UploadFileWithContentType(ctx, bucket, key, file, "", hook)
+}
 
+// UploadFileWithContentType uploads with an already resolved content type, so
+// callers that detected it once don't pay for a second read; an empty
+// contentType falls back to DetectContentType. hook may be nil.
+func (c *S3Client) UploadFileWithContentType(
+	ctx context.Context,
+	bucket, key string,
+	file *os.File,
+	contentType string,
+	hook *ProgressHook,
+) (interface{}, error) {
 	info, err := file.Stat()
 	if err != nil {
 		return nil, fmt.Errorf("stat error: %w", err)
@@ -324,12 +380,14 @@ func (c *S3Client) UploadFileWithProgress(
 		return nil, fmt.Errorf("seek error: %w", err)
 	}
 
-	// MIME
-	header := make([]byte, 512)
-	n, _ := file.Read(header)
-	mime := http.DetectContentType(header[:n])
-	if _, err := file.Seek(0, io.SeekStart); err != nil {
-		return nil, fmt.Errorf("rewind error: %w", err)
+	if contentType == "" {
+		if contentType, err = DetectContentType(file, nil); err != nil {
+			return nil, err
+		}
+	}
+	objOpts, err := c.objectOptions(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	if hook != nil && hook.OnStart != nil {
@@ -347,30 +405,266 @@ func (c *S3Client) UploadFileWithProgress(
 	}
 
 	start := time.Now()
-	reader := io.TeeReader(file, pw)
+	var reader io.Reader = file
+	if pw.onProgress != nil {
+		reader = io.TeeReader(file, pw)
+	}
 
-	if size > threshold {
-		out, err := manager.NewUploader(c.s3).Upload(ctx, &s3.PutObjectInput{
+	if size > c.transfer.MultipartThreshold && c.transfer.ResumableUploads {
+		out, err := c.uploadResumable(ctx, bucket, key, file, info, contentType, objOpts, pw)
+		if err != nil {
+			return nil, err
+		}
+		if hook != nil && hook.OnDone != nil {
+			hook.OnDone(key, size, time.Since(start))
+		}
+		return out, nil
+	}
+	if size > c.transfer.MultipartThreshold {
+		uploader := manager.NewUploader(c.s3, func(u *manager.Uploader) {
+			u.PartSize = c.transfer.PartSize
+			u.Concurrency = c.transfer.Concurrency
+		})
+		in := &s3.PutObjectInput{
 			Bucket:      aws.String(bucket),
 			Key:         aws.String(key),
 			Body:        reader,
-			ContentType: aws.String(mime),
-		})
-		if hook != nil && hook.OnDone != nil {
+			ContentType: aws.String(contentType),
+		}
+		objOpts.applyPut(in)
+		out, err := uploader.Upload(ctx, in)
+		if err == nil && hook != nil && hook.OnDone != nil {
 			hook.OnDone(key, size, time.Since(start))
 		}
 		return out, err
 	}
 
-	out, err := c.s3.PutObject(ctx, &s3.PutObjectInput{
+	in := &s3.PutObjectInput{
 		Bucket:        aws.String(bucket),
 		Key:           aws.String(key),
 		Body:          reader,
 		ContentLength: aws.Int64(size),
-		ContentType:   aws.String(mime),
-	})
-	if hook != nil && hook.OnDone != nil {
+		ContentType:   aws.String(contentType),
+	}
+	objOpts.applyPut(in)
+	out, err := c.s3.PutObject(ctx, in)
+	if err == nil && hook != nil && hook.OnDone != nil {
 		hook.OnDone(key, size, time.Since(start))
 	}
 	return out, err
 }
+
+// UploadReader uploads the content of r, for data that isn't in a local file
+// (generated, piped from stdin, ...). size is the length of r, 0 or negative
+// when unknown: the content is then sent in parts of PartSize, buffered in
+// memory. An empty contentType is stored as application/octet-stream. hook
+// may be nil; its totals are 0 when size is unknown.
+func (c *S3Client) UploadReader(
+	ctx context.Context,
+	bucket, key string,
+	r io.Reader,
+	size int64,
+	contentType string,
+	hook *ProgressHook,
+) (interface{}, error) {
+	if size < 0 {
+		size = 0
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	objOpts, err := c.objectOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if hook != nil && hook.OnStart != nil {
+		hook.OnStart(key, size)
+	}
+	pw := &progressWriter{key: key, total: size, interval: 250 * time.Millisecond}
+	if hook != nil {
+		pw.onProgress = hook.OnProgress
+	}
+	start := time.Now()
+	reader := io.TeeReader(r, pw)
+	done := func() {
+		if hook != nil && hook.OnDone != nil {
+			hook.OnDone(key, pw.written, time.Since(start))
+		}
+	}
+
+	if size > 0 && size <= c.transfer.MultipartThreshold {
+		// PutObject needs a seekable body to sign the payload
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, fmt.Errorf("read error: %w", err)
+		}
+		in := &s3.PutObjectInput{
+			Bucket:        aws.String(bucket),
+			Key:           aws.String(key),
+			Body:          bytes.NewReader(buf),
+			ContentLength: aws.Int64(size),
+			ContentType:   aws.String(contentType),
+		}
+		objOpts.applyPut(in)
+		out, err := c.s3.PutObject(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		done()
+		return out, nil
+	}
+
+	uploader := manager.NewUploader(c.s3, func(u *manager.Uploader) {
+		u.PartSize = c.transfer.PartSize
+		u.Concurrency = c.transfer.Concurrency
+	})
+	in := &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        reader,
+		ContentType: aws.String(contentType),
+	}
+	objOpts.applyPut(in)
+	out, err := uploader.Upload(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	done()
+	return out, nil
+}
+
+/* -------------------- COPY (server-side) -------------------- */
+
+// CopyObject copies an object within S3 without downloading it; content
+// type and user metadata are kept, and the ObjectOptions applied. S3 limits
+// single copies to 5 GB.
+func (c *S3Client) CopyObject(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string) error {
+	objOpts, err := c.objectOptions(ctx)
+	if err != nil {
+		return err
+	}
+	in := &s3.CopyObjectInput{
+		Bucket:     aws.String(dstBucket),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(url.PathEscape(srcBucket) + "/" + escapeKey(srcKey)),
+	}
+	objOpts.applyCopy(in)
+	_, err = c.s3.CopyObject(ctx, in)
+	if err != nil {
+		return fmt.Errorf("failed to copy s3://%s/%s: %w", srcBucket, srcKey, err)
+	}
+	return nil
+}
+
+/* -------------------- HEAD -------------------- */
+
+// ErrObjectNotFound is returned (wrapped) by HeadObject for a missing key.
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectInfo describes an object without its content.
+type ObjectInfo struct {
+	Bucket      string
+	Key         string
+	Size        int64
+	ContentType string
+	// ETag quotes included
+	ETag         string
+	LastModified time.Time
+	// Metadata is the user metadata (x-amz-meta-*)
+	Metadata map[string]string
+	// SSE is the server-side encryption: SSES3, SSEKMS, SSECustomer or ""
+	SSE string
+}
+
+// HeadObject returns the size, type and metadata of an object; a missing key
+// is an error matching ErrObjectNotFound.
+func (c *S3Client) HeadObject(ctx context.Context, bucket, key string) (*ObjectInfo, error) {
+	out, err := c.s3.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if hasErrorCode(err, "NotFound") || hasErrorCode(err, "NoSuchKey") {
+		return nil, fmt.Errorf("s3://%s/%s: %w", bucket, key, ErrObjectNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to head s3://%s/%s: %w", bucket, key, err)
+	}
+	return &ObjectInfo{
+		Bucket:       bucket,
+		Key:          key,
+		Size:         aws.ToInt64(out.ContentLength),
+		ContentType:  aws.ToString(out.ContentType),
+		ETag:         aws.ToString(out.ETag),
+		LastModified: aws.ToTime(out.LastModified),
+		Metadata:     out.Metadata,
+		SSE:          objectSSE(out.ServerSideEncryption, out.SSECustomerAlgorithm),
+	}, nil
+}
+
+// Exists tells whether the object exists.
+func (c *S3Client) Exists(ctx context.Context, bucket, key string) (bool, error) {
+	_, err := c.HeadObject(ctx, bucket, key)
+	if errors.Is(err, ErrObjectNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+/* -------------------- DELETE -------------------- */
+
+// DeleteObject removes an object; a missing key is not an error.
+func (c *S3Client) DeleteObject(ctx context.Context, bucket, key string) error {
+	_, err := c.s3.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		return fmt.Errorf("failed to delete s3://%s/%s: %w", bucket, key, err)
+	}
+	return nil
+}
+
+// DeletePrefix removes every object under prefix, folder placeholders
+// included, a page of up to 1000 at a time, and returns how many were
+// deleted. An empty prefix is refused, so that a whole bucket is never
+// emptied by mistake.
+func (c *S3Client) DeletePrefix(ctx context.Context, bucket, prefix string) (int, error) {
+	if prefix == "" {
+		return 0, errors.New("refusing to delete with an empty prefix")
+	}
+	deleted := 0
+	max := int32(1000)
+	var token *string
+	for {
+		files, next, err := c.ListFilesPaged(ctx, bucket, prefix, &max, token)
+		if err != nil {
+			return deleted, err
+		}
+		if len(files) > 0 {
+			ids := make([]s3types.ObjectIdentifier, len(files))
+			for i, f := range files {
+				ids[i] = s3types.ObjectIdentifier{Key: aws.String(f.Path)}
+			}
+			out, err := c.s3.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+				Bucket: aws.String(bucket),
+				Delete: &s3types.Delete{Objects: ids, Quiet: aws.Bool(true)},
+			})
+			if err != nil {
+				return deleted, fmt.Errorf("failed to delete under s3://%s/%s: %w", bucket, prefix, err)
+			}
+			deleted += len(ids) - len(out.Errors)
+			if len(out.Errors) > 0 {
+				e := out.Errors[0]
+				return deleted, fmt.Errorf("failed to delete s3://%s/%s: %s (%d failed)",
+					bucket, aws.ToString(e.Key), aws.ToString(e.Message), len(out.Errors))
+			}
+		}
+		if next == nil || *next == "" {
+			return deleted, nil
+		}
+		token = next
+	}
+}
+
+// escapeKey URL-encodes each segment of an object key.
+func escapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
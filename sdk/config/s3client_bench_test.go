@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/s3test"
+)
+
+// Throughput against the in-memory S3 server; run against a real endpoint
+// (by adapting newBenchS3) to tune TransferConfig for a given network:
+//
+//	go test ./sdk/config -run ^$ -bench S3 -benchmem
+
+func newBenchS3(b *testing.B, tune config.TransferConfig) (*config.S3Client, *s3test.Server) {
+	b.Helper()
+	srv := s3test.NewServer()
+	b.Cleanup(srv.Close)
+	cfg := srv.Config()
+	cfg.Transfer = tune
+	client, err := config.NewS3Client(context.Background(), cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return client, srv
+}
+
+func BenchmarkS3DownloadBufferSize(b *testing.B) {
+	const size = 32 << 20
+	for _, buf := range []int{32 << 10, 128 << 10, 1 << 20} {
+		b.Run(fmt.Sprintf("buffer=%dKB", buf>>10), func(b *testing.B) {
+			client, srv := newBenchS3(b, config.TransferConfig{BufferSize: buf})
+			srv.PutObject("datalake", "bench/blob", make([]byte, size))
+			target := filepath.Join(b.TempDir(), "blob")
+			b.SetBytes(size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := client.DownloadFile(context.Background(), "datalake", "bench/blob", target); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkS3UploadPartSize(b *testing.B) {
+	const size = 32 << 20
+	input := filepath.Join(b.TempDir(), "blob")
+	if err := os.WriteFile(input, make([]byte, size), 0o644); err != nil {
+		b.Fatal(err)
+	}
+	for _, tc := range []struct{ part, concurrency int }{
+		{5 << 20, 1}, {5 << 20, 5}, {16 << 20, 2},
+	} {
+		b.Run(fmt.Sprintf("part=%dMB/concurrency=%d", tc.part>>20, tc.concurrency), func(b *testing.B) {
+			client, _ := newBenchS3(b, config.TransferConfig{
+				MultipartThreshold: 1 << 20,
+				PartSize:           int64(tc.part),
+				Concurrency:        tc.concurrency,
+			})
+			b.SetBytes(size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				f, err := os.Open(input)
+				if err != nil {
+					b.Fatal(err)
+				}
+				_, err = client.UploadFileWithContentType(context.Background(), "datalake", "bench/blob", f, "application/octet-stream", nil)
+				_ = f.Close()
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,535 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/s3test"
+)
+
+func newTestS3(t *testing.T) (*config.S3Client, *s3test.Server) {
+	t.Helper()
+	srv := s3test.NewServer()
+	t.Cleanup(srv.Close)
+	client, err := config.NewS3Client(context.Background(), srv.Config())
+	if err != nil {
+		t.Fatalf("failed to init s3 client: %v", err)
+	}
+	return client, srv
+}
+
+func TestListFilesPagination(t *testing.T) {
+	client, srv := newTestS3(t)
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		srv.PutObject("datalake", fmt.Sprintf("demo/dir/f%d.txt", i), []byte("data"))
+	}
+	srv.PutObject("datalake", "demo/dir/", nil) // folder placeholder
+	srv.PutObject("datalake", "other/f.txt", []byte("x"))
+
+	pageSize := int32(2)
+	files, token, err := client.ListFilesPaged(ctx, "datalake", "demo/dir/", &pageSize, nil)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(files) != 2 || token == nil {
+		t.Fatalf("expected a truncated first page, got %d files, token %v", len(files), token)
+	}
+
+	all, err := client.ListFilesAll(ctx, "datalake", "demo/dir/")
+	if err != nil {
+		t.Fatalf("list all failed: %v", err)
+	}
+	if len(all) != 6 {
+		t.Fatalf("expected 6 objects (placeholder included), got %d", len(all))
+	}
+
+	var walked []string
+	err = client.WalkPrefix(ctx, "datalake", "demo/dir/", 2, func(obj s3types.Object) error {
+		walked = append(walked, *obj.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk failed: %v", err)
+	}
+	if len(walked) != 5 {
+		t.Fatalf("walk should skip the folder placeholder, got %v", walked)
+	}
+}
+
+func TestUploadAndDownloadWithProgress(t *testing.T) {
+	client, srv := newTestS3(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	local := filepath.Join(dir, "model.onnx")
+	payload := make([]byte, 300*1024)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	if err := os.WriteFile(local, payload, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var uploaded int64
+	hook := &config.ProgressHook{
+		OnProgress: func(_ string, written, _ int64) { uploaded = written },
+	}
+	if _, err := client.UploadFileWithProgress(ctx, "datalake", "demo/model.onnx", f, hook); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	obj, ok := srv.GetObject("datalake", "demo/model.onnx")
+	if !ok || len(obj.Data) != len(payload) {
+		t.Fatalf("object not stored correctly")
+	}
+	if obj.ContentType != "application/onnx" {
+		t.Fatalf("unexpected content type %q", obj.ContentType)
+	}
+	if uploaded != int64(len(payload)) {
+		t.Fatalf("progress reported %d bytes, want %d", uploaded, len(payload))
+	}
+
+	target := filepath.Join(dir, "copy.onnx")
+	var done bool
+	err = client.DownloadFileWithProgress(ctx, "datalake", "demo/model.onnx", target, &config.ProgressHook{
+		OnDone: func(string, int64, time.Duration) { done = true },
+	})
+	if err != nil || !done {
+		t.Fatalf("download failed: %v", err)
+	}
+	got, err := os.ReadFile(target)
+	if err != nil || len(got) != len(payload) {
+		t.Fatalf("downloaded file mismatch (%d bytes, %v)", len(got), err)
+	}
+}
+
+// cancelOnPart cancels the upload when part n is sent, as a network drop
+// would interrupt it.
+type cancelOnPart struct {
+	next   http.RoundTripper
+	part   string
+	cancel context.CancelFunc
+}
+
+func (c cancelOnPart) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.URL.Query().Get("partNumber") == c.part {
+		c.cancel()
+		return nil, context.Canceled
+	}
+	return c.next.RoundTrip(r)
+}
+
+func TestResumableUpload(t *testing.T) {
+	srv := s3test.NewServer()
+	t.Cleanup(srv.Close)
+	dir := t.TempDir()
+
+	local := filepath.Join(dir, "dataset.bin")
+	payload := make([]byte, 12*1024*1024) // parts of 5, 5 and 2 MB
+	for i := range payload {
+		payload[i] = byte(i % 251)
+	}
+	if err := os.WriteFile(local, payload, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	transfer := config.TransferConfig{MultipartThreshold: 1, Concurrency: 1, ResumableUploads: true}
+
+	upload := func(ctx context.Context, cfg config.S3Config) error {
+		cfg.Transfer = transfer
+		client, err := config.NewS3Client(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("failed to init s3 client: %v", err)
+		}
+		f, err := os.Open(local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		_, err = client.UploadFileWithContentType(ctx, "datalake", "demo/dataset.bin", f, "application/octet-stream", nil)
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := srv.Config()
+	cfg.HTTPClient = &http.Client{Transport: cancelOnPart{next: srv.Client().Transport, part: "3", cancel: cancel}}
+	if err := upload(ctx, cfg); err == nil {
+		t.Fatal("expected the interrupted upload to fail")
+	}
+	st, err := config.LoadUploadState(local)
+	if err != nil || st == nil || len(st.Parts) != 2 {
+		t.Fatalf("expected a state with 2 parts, got %+v (%v)", st, err)
+	}
+
+	before := len(srv.Requests())
+	if err := upload(context.Background(), srv.Config()); err != nil {
+		t.Fatalf("resumed upload failed: %v", err)
+	}
+	for _, r := range srv.Requests()[before:] {
+		if strings.Contains(r, "partNumber=1") || strings.Contains(r, "partNumber=2") || strings.Contains(r, "?uploads") {
+			t.Fatalf("resumed upload should only send the missing part, got %s", r)
+		}
+	}
+	obj, ok := srv.GetObject("datalake", "demo/dataset.bin")
+	if !ok || !bytes.Equal(obj.Data, payload) {
+		t.Fatal("object not stored correctly")
+	}
+	if _, err := os.Stat(local + config.UploadStateSuffix); !os.IsNotExist(err) {
+		t.Fatalf("state file should be removed after the upload, got %v", err)
+	}
+}
+
+func TestResumableUploadPartSizeOfLargeFiles(t *testing.T) {
+	srv := s3test.NewServer()
+	t.Cleanup(srv.Close)
+
+	// a sparse file needing more than MaxUploadParts parts of 5 MB; only
+	// the first part is read before the upload is interrupted
+	local := filepath.Join(t.TempDir(), "huge.bin")
+	f, err := os.Create(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	const size = 60 << 30
+	if err := f.Truncate(size); err != nil {
+		t.Skipf("sparse files not supported: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := srv.Config()
+	cfg.Transfer = config.TransferConfig{MultipartThreshold: 1, Concurrency: 1, ResumableUploads: true}
+	cfg.HTTPClient = &http.Client{Transport: cancelOnPart{next: srv.Client().Transport, part: "2", cancel: cancel}}
+	client, err := config.NewS3Client(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to init s3 client: %v", err)
+	}
+	if _, err := client.UploadFileWithContentType(ctx, "datalake", "demo/huge.bin", f, "application/octet-stream", nil); err == nil {
+		t.Fatal("expected the interrupted upload to fail")
+	}
+	st, err := config.LoadUploadState(local)
+	if err != nil || st == nil || len(st.Parts) != 1 {
+		t.Fatalf("expected a state with 1 part, got %+v (%v)", st, err)
+	}
+	if parts := (size + st.PartSize - 1) / st.PartSize; parts > 10000 {
+		t.Fatalf("part size %d needs %d parts", st.PartSize, parts)
+	}
+	if st.PartSize <= config.DefaultPartSize {
+		t.Fatalf("part size not raised: %d", st.PartSize)
+	}
+}
+
+func TestDownloadFileRanged(t *testing.T) {
+	client, srv := newTestS3(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	payload := make([]byte, 1024*1024+17)
+	for i := range payload {
+		payload[i] = byte(i % 253)
+	}
+	srv.PutObject("datalake", "demo/big.bin", payload)
+
+	var written, total int64
+	target := filepath.Join(dir, "big.bin")
+	err := client.DownloadFileRanged(ctx, "datalake", "demo/big.bin", target, &config.ProgressHook{
+		OnProgress: func(_ string, w, t int64) { written, total = w, t },
+	}, config.RangeOptions{PartSize: 100 * 1024, Concurrency: 4})
+	if err != nil {
+		t.Fatalf("ranged download failed: %v", err)
+	}
+	got, err := os.ReadFile(target)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Fatalf("downloaded file mismatch (%d bytes, %v)", len(got), err)
+	}
+	if written != int64(len(payload)) || total != written {
+		t.Fatalf("progress reported %d/%d bytes, want %d", written, total, len(payload))
+	}
+
+	// objects not larger than a part take a single stream
+	srv.PutObject("datalake", "demo/small.txt", []byte("small"))
+	small := filepath.Join(dir, "small.txt")
+	if err := client.DownloadFileRanged(ctx, "datalake", "demo/small.txt", small, nil, config.RangeOptions{Concurrency: 4}); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if got, _ := os.ReadFile(small); string(got) != "small" {
+		t.Fatalf("unexpected content %q", got)
+	}
+
+	if err := client.DownloadFileRanged(ctx, "datalake", "demo/missing", filepath.Join(dir, "missing"), nil, config.RangeOptions{}); err == nil {
+		t.Fatal("expected an error for a missing object")
+	}
+}
+
+func TestDownloadResumesPartialFile(t *testing.T) {
+	client, srv := newTestS3(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	payload := make([]byte, 300*1024)
+	for i := range payload {
+		payload[i] = byte(i % 241)
+	}
+	srv.PutObject("datalake", "demo/big.bin", payload)
+	target := filepath.Join(dir, "big.bin")
+
+	obj, _ := srv.GetObject("datalake", "demo/big.bin")
+	partial := func(data []byte, etag string) {
+		t.Helper()
+		if err := os.WriteFile(target+config.PartSuffix, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target+config.PartSuffix+".etag", []byte(etag), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a partial file continues from its size
+	partial(payload[:100*1024], obj.ETag)
+	var first int64 = -1
+	err := client.DownloadFileWithProgress(ctx, "datalake", "demo/big.bin", target, &config.ProgressHook{
+		OnProgress: func(_ string, written, _ int64) {
+			if first < 0 {
+				first = written
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("resumed download failed: %v", err)
+	}
+	if got, _ := os.ReadFile(target); !bytes.Equal(got, payload) {
+		t.Fatal("resumed file mismatch")
+	}
+	if first <= 100*1024 {
+		t.Fatalf("progress should start after the partial file, got %d", first)
+	}
+	for _, p := range []string{target + config.PartSuffix, target + config.PartSuffix + ".etag"} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Fatalf("%s should be gone, got %v", p, err)
+		}
+	}
+
+	// a corrupted partial file fails the MD5 check and is dropped
+	partial(make([]byte, 100*1024), obj.ETag)
+	if err := client.DownloadFile(ctx, "datalake", "demo/big.bin", target); err == nil {
+		t.Fatal("expected an md5 mismatch")
+	}
+	if _, err := os.Stat(target + config.PartSuffix); !os.IsNotExist(err) {
+		t.Fatalf("corrupted partial file should be removed, got %v", err)
+	}
+
+	// a partial file of another version of the object, or of unknown
+	// version, starts over
+	for _, etag := range []string{`"0123456789abcdef0123456789abcdef"`, ""} {
+		partial(make([]byte, 100*1024), etag)
+		if err := client.DownloadFile(ctx, "datalake", "demo/big.bin", target); err != nil {
+			t.Fatalf("download failed: %v", err)
+		}
+		if got, _ := os.ReadFile(target); !bytes.Equal(got, payload) {
+			t.Fatal("restarted file mismatch")
+		}
+	}
+
+	// the ETag of an SSE-KMS object is not its MD5
+	kms := config.ContextWithObjectOptions(ctx, config.ObjectOptions{SSE: config.SSEKMS})
+	if _, err := client.UploadReader(kms, "datalake", "demo/big.bin", bytes.NewReader(payload), int64(len(payload)), "", nil); err != nil {
+		t.Fatal(err)
+	}
+	obj, _ = srv.GetObject("datalake", "demo/big.bin")
+	partial(payload[:100*1024], obj.ETag)
+	first = -1
+	err = client.DownloadFileWithProgress(ctx, "datalake", "demo/big.bin", target, &config.ProgressHook{
+		OnProgress: func(_ string, written, _ int64) {
+			if first < 0 {
+				first = written
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("resumed download of an SSE-KMS object failed: %v", err)
+	}
+	if got, _ := os.ReadFile(target); !bytes.Equal(got, payload) || first <= 100*1024 {
+		t.Fatalf("SSE-KMS object not resumed (first progress at %d)", first)
+	}
+}
+
+func TestUploadReader(t *testing.T) {
+	client, srv := newTestS3(t)
+	ctx := context.Background()
+
+	// a pipe is not seekable and has no known length
+	payload := bytes.Repeat([]byte("0123456789abcdef"), 768*1024) // 12 MB
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := pw.Write(payload)
+		pw.CloseWithError(err)
+	}()
+	var done int64
+	hook := &config.ProgressHook{OnDone: func(_ string, total int64, _ time.Duration) { done = total }}
+	if _, err := client.UploadReader(ctx, "datalake", "demo/stream.bin", pr, -1, "", hook); err != nil {
+		t.Fatalf("upload of unknown size failed: %v", err)
+	}
+	obj, ok := srv.GetObject("datalake", "demo/stream.bin")
+	if !ok || !bytes.Equal(obj.Data, payload) || obj.ContentType != "application/octet-stream" {
+		t.Fatal("object of unknown size not stored correctly")
+	}
+	if done != int64(len(payload)) {
+		t.Fatalf("expected OnDone with %d bytes, got %d", len(payload), done)
+	}
+
+	small := "a,b\n1,2\n"
+	r := io.MultiReader(strings.NewReader(small))
+	if _, err := client.UploadReader(ctx, "datalake", "demo/small.csv", r, int64(len(small)), "text/csv", nil); err != nil {
+		t.Fatalf("upload of known size failed: %v", err)
+	}
+	obj, ok = srv.GetObject("datalake", "demo/small.csv")
+	if !ok || string(obj.Data) != small || obj.ContentType != "text/csv" {
+		t.Fatalf("unexpected object %+v", obj)
+	}
+}
+
+func TestPresignedURLs(t *testing.T) {
+	client, srv := newTestS3(t)
+	srv.RequireAccessKey = "test"
+	ctx := context.Background()
+
+	putURL, err := client.PresignPut(ctx, "datalake", "demo/shared.txt", time.Hour)
+	if err != nil {
+		t.Fatalf("presign put failed: %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodPut, putURL, strings.NewReader("shared"))
+	resp, err := srv.Client().Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("presigned put failed: %v %v", resp, err)
+	}
+	resp.Body.Close()
+
+	getURL, err := client.PresignGet(ctx, "datalake", "demo/shared.txt", 0)
+	if err != nil {
+		t.Fatalf("presign get failed: %v", err)
+	}
+	if !strings.Contains(getURL, "X-Amz-Expires=900") {
+		t.Fatalf("expected the default ttl in %s", getURL)
+	}
+	resp, err = srv.Client().Get(getURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "shared" {
+		t.Fatalf("presigned get returned %d %q", resp.StatusCode, body)
+	}
+
+	if _, err := client.PresignGet(ctx, "datalake", "demo/shared.txt", 8*24*time.Hour); err == nil {
+		t.Fatal("expected an error for a ttl over 7 days")
+	}
+}
+
+func TestHeadExistsAndDeletePrefix(t *testing.T) {
+	client, srv := newTestS3(t)
+	ctx := context.Background()
+	srv.PutObject("datalake", "run/1/a.csv", []byte("a,b"))
+	srv.PutObject("datalake", "run/1/sub/b.csv", []byte("b"))
+	srv.PutObject("datalake", "run/10/c.csv", []byte("c"))
+
+	info, err := client.HeadObject(ctx, "datalake", "run/1/a.csv")
+	if err != nil || info.Size != 3 || info.ETag == "" {
+		t.Fatalf("unexpected head %+v (%v)", info, err)
+	}
+	if _, err := client.HeadObject(ctx, "datalake", "missing"); !errors.Is(err, config.ErrObjectNotFound) {
+		t.Fatalf("expected ErrObjectNotFound, got %v", err)
+	}
+	if ok, err := client.Exists(ctx, "datalake", "missing"); ok || err != nil {
+		t.Fatalf("expected a missing object, got %v (%v)", ok, err)
+	}
+
+	n, err := client.DeletePrefix(ctx, "datalake", "run/1/")
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 deleted, got %d (%v)", n, err)
+	}
+	if keys := srv.Keys("datalake"); len(keys) != 1 || keys[0] != "run/10/c.csv" {
+		t.Fatalf("unexpected keys left %v", keys)
+	}
+	if _, err := client.DeletePrefix(ctx, "datalake", ""); err == nil {
+		t.Fatal("expected an error for an empty prefix")
+	}
+}
+
+func TestObjectOptions(t *testing.T) {
+	srv := s3test.NewServer()
+	t.Cleanup(srv.Close)
+	cfg := srv.Config()
+	cfg.Objects = config.ObjectOptions{SSE: config.SSEKMS, KMSKeyID: "alias/dh", Tags: map[string]string{"team": "ml"}}
+	cfg.Transfer = config.TransferConfig{MultipartThreshold: 1}
+	client, err := config.NewS3Client(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// above the threshold, with the defaults of the client
+	local := filepath.Join(t.TempDir(), "model.bin")
+	if err := os.WriteFile(local, []byte("weights"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := client.UploadFileWithContentType(context.Background(), "datalake", "m/model.bin", f, "", nil); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	obj, _ := srv.GetObject("datalake", "m/model.bin")
+	if obj.SSE != config.SSEKMS || obj.KMSKeyID != "alias/dh" || obj.Tags["team"] != "ml" {
+		t.Fatalf("unexpected attributes %+v", obj)
+	}
+
+	// per call: SSE-S3 drops the KMS key, tags are merged
+	ctx := config.ContextWithObjectOptions(context.Background(), config.ObjectOptions{
+		SSE: config.SSES3, StorageClass: "STANDARD_IA", ACL: "private", Tags: map[string]string{"stage": "prod"},
+	})
+	if _, err := client.UploadReader(ctx, "datalake", "m/small.txt", strings.NewReader("x"), 1, "", nil); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	obj, _ = srv.GetObject("datalake", "m/small.txt")
+	if obj.SSE != config.SSES3 || obj.KMSKeyID != "" || obj.StorageClass != "STANDARD_IA" || obj.ACL != "private" ||
+		obj.Tags["team"] != "ml" || obj.Tags["stage"] != "prod" {
+		t.Fatalf("unexpected attributes %+v", obj)
+	}
+
+	if err := client.CopyObject(ctx, "datalake", "m/model.bin", "release", "model.bin"); err != nil {
+		t.Fatalf("copy failed: %v", err)
+	}
+	obj, _ = srv.GetObject("release", "model.bin")
+	if obj.SSE != config.SSES3 || obj.Tags["stage"] != "prod" {
+		t.Fatalf("unexpected attributes of the copy %+v", obj)
+	}
+
+	cfg.Objects = config.ObjectOptions{SSE: config.SSES3, KMSKeyID: "alias/dh"}
+	if _, err := config.NewS3Client(context.Background(), cfg); err == nil {
+		t.Fatal("expected an error for a KMS key without SSE-KMS")
+	}
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// S3Credentials are temporary S3 credentials issued by Core for the current
+// user.
+type S3Credentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	// Expiration is zero when the credentials don't expire
+	Expiration time.Time
+	// EndpointURL and Region, when returned by Core
+	EndpointURL string
+	Region      string
+}
+
+// credentialsRefreshWindow is how long before their expiration credentials
+// are renewed, so transfers in progress don't fail.
+const credentialsRefreshWindow = 2 * time.Minute
+
+// FetchS3Credentials asks Core for temporary S3 credentials of the current
+// user (GET /api/{version}/credentials).
+func FetchS3Credentials(ctx context.Context, core CoreHTTP, conf CoreConfig) (*S3Credentials, error) {
+	url := strings.TrimRight(conf.BaseURL, "/") + "/api/" + conf.APIVersion + "/credentials"
+	body, _, err := core.Do(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch S3 credentials: %w", err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("invalid credentials response: %w", err)
+	}
+	str := func(k string) string { v, _ := raw[k].(string); return v }
+	c := &S3Credentials{
+		AccessKey:    str("aws_access_key_id"),
+		SecretKey:    str("aws_secret_access_key"),
+		SessionToken: str("aws_session_token"),
+		EndpointURL:  str("aws_endpoint_url"),
+		Region:       str("aws_region"),
+	}
+	if c.AccessKey == "" || c.SecretKey == "" {
+		return nil, errors.New("invalid credentials response: no S3 credentials")
+	}
+	switch v := raw["aws_credentials_expiration"].(type) {
+	case string:
+		if c.Expiration, err = ParseCredentialsExpiration(v); err != nil {
+			return nil, fmt.Errorf("invalid credentials response: %w", err)
+		}
+	case float64:
+		c.Expiration = time.Unix(int64(v), 0)
+	}
+	return c, nil
+}
+
+// ParseCredentialsExpiration parses aws_credentials_expiration, either an
+// RFC 3339 time or Unix seconds; "" is the zero time.
+func ParseCredentialsExpiration(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(n, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid credentials expiration %q", s)
+}
+
+// CoreCredentialsProvider is an aws.CredentialsProvider returning the
+// credentials of Core: the initial ones until they expire, then those of
+// FetchS3Credentials. Wrapped in an aws.CredentialsCache (as NewS3Client
+// does), they are renewed shortly before they expire.
+type CoreCredentialsProvider struct {
+	core CoreHTTP
+	conf CoreConfig
+	// OnRefresh is called with the credentials fetched from Core
+	OnRefresh func(S3Credentials)
+
+	mu      sync.Mutex
+	initial *S3Credentials
+}
+
+// NewCoreCredentialsProvider returns the provider of the Core reached by
+// core; initial (optional) are the credentials already known, e.g. from
+// the CLI environment.
+func NewCoreCredentialsProvider(core CoreHTTP, conf CoreConfig, initial *S3Credentials) *CoreCredentialsProvider {
+	return &CoreCredentialsProvider{core: core, conf: conf, initial: initial}
+}
+
+func (p *CoreCredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	p.mu.Lock()
+	initial := p.initial
+	p.initial = nil
+	p.mu.Unlock()
+
+	c := initial
+	if c == nil || c.AccessKey == "" || (!c.Expiration.IsZero() && time.Until(c.Expiration) < credentialsRefreshWindow) {
+		var err error
+		if c, err = FetchS3Credentials(ctx, p.core, p.conf); err != nil {
+			return aws.Credentials{}, err
+		}
+		if p.OnRefresh != nil {
+			p.OnRefresh(*c)
+		}
+	}
+	return aws.Credentials{
+		AccessKeyID:     c.AccessKey,
+		SecretAccessKey: c.SecretKey,
+		SessionToken:    c.SessionToken,
+		Source:          "dhcore",
+		CanExpire:       !c.Expiration.IsZero(),
+		Expires:         c.Expiration,
+	}, nil
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// Timeouts bound Core calls; zero fields mean no limit.
+type Timeouts struct {
+	// Connect bounds establishing the connection (TCP and TLS handshake).
+	// It is a transport setting and can't be overridden per call.
+	Connect time.Duration
+	// Request bounds a single attempt, response body included (for DoStream,
+	// until the response headers arrive).
+	Request time.Duration
+	// Total bounds a whole call, retries and backoff included (for DoStream,
+	// until the response headers arrive).
+	Total time.Duration
+}
+
+type timeoutsCtxKey struct{}
+
+// ContextWithTimeouts overrides the Request and Total timeouts of the client
+// for the calls made with the returned context; zero fields keep the client
+// settings. E.g. a long ListAllPages can get a larger Total.
+func ContextWithTimeouts(ctx context.Context, t Timeouts) context.Context {
+	return context.WithValue(ctx, timeoutsCtxKey{}, t)
+}
+
+// timeoutsFor merges the per-call override of ctx on base.
+func timeoutsFor(ctx context.Context, base Timeouts) Timeouts {
+	if t, ok := ctx.Value(timeoutsCtxKey{}).(Timeouts); ok {
+		if t.Request != 0 {
+			base.Request = t.Request
+		}
+		if t.Total != 0 {
+			base.Total = t.Total
+		}
+	}
+	return base
+}
+
+// errAttemptTimeout marks an attempt cut by Timeouts.Request while the call
+// itself may still be retried.
+var errAttemptTimeout = errors.New("core request timed out")
+
+// withTimeout is context.WithTimeout with d <= 0 meaning no timeout.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// cancelOnClose releases the context of a streamed response with its body.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
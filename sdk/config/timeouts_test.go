@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestCoreTimeouts(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first attempt hangs, the next ones answer at once
+		if calls.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	cfg := config.CoreConfig{
+		BaseURL:  srv.URL,
+		Timeouts: config.Timeouts{Request: 50 * time.Millisecond},
+		Retry:    &config.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+	}
+	core := config.NewHTTPCore(nil, cfg)
+	if _, _, err := core.Do(ctx, "GET", srv.URL, nil); err != nil {
+		t.Fatalf("expected the retry to succeed after the attempt timeout: %v", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("expected 2 attempts, got %d", n)
+	}
+
+	// per-call override: a total budget shorter than the hanging attempt
+	calls.Store(0)
+	cfg.Timeouts, cfg.Retry = config.Timeouts{}, nil
+	core = config.NewHTTPCore(nil, cfg)
+	start := time.Now()
+	_, _, err := core.Do(config.ContextWithTimeouts(ctx, config.Timeouts{Total: 50 * time.Millisecond}), "GET", srv.URL, nil)
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > time.Second {
+		t.Fatalf("expected the total timeout, got %v after %v", err, time.Since(start))
+	}
+
+	// streams are bounded only until the headers
+	calls.Store(1)
+	cfg.Timeouts = config.Timeouts{Request: 50 * time.Millisecond}
+	body, _, err := config.NewHTTPCore(nil, cfg).DoStream(ctx, "GET", srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	b, err := io.ReadAll(body)
+	_ = body.Close()
+	if err != nil || string(b) != "{}" {
+		t.Fatalf("stream cut by the request timeout: %q %v", b, err)
+	}
+}
@@ -0,0 +1,229 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource provides the bearer token of Core calls. The client asks for a
+// token before each request and, when Core answers 401, calls Refresh once
+// and retries with the new token.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+	// Refresh returns a new token after stale was rejected; if another call
+	// already replaced stale, the current token is returned.
+	Refresh(ctx context.Context, stale string) (string, error)
+}
+
+// Token is an OAuth2 token pair.
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	// Expiry is zero when unknown
+	Expiry time.Time
+}
+
+// ErrNoRefreshToken is returned when a refresh is needed but no refresh token
+// is available.
+var ErrNoRefreshToken = errors.New("no refresh token available")
+
+// expiryDelta refreshes tokens a bit before they expire, so they don't die
+// in flight.
+const expiryDelta = 30 * time.Second
+
+// RefreshTokenSource performs the OAuth2 refresh_token grant against TokenURL
+// when the access token expires or is rejected. It is safe for concurrent
+// use: concurrent callers share a single refresh.
+type RefreshTokenSource struct {
+	TokenURL string
+	ClientID string
+	// HTTPClient is optional; nil uses http.DefaultClient
+	HTTPClient *http.Client
+	// OnRefresh, when set, is called with each new token, e.g. to persist it
+	OnRefresh func(Token)
+
+	mu    sync.Mutex
+	token Token
+}
+
+// NewRefreshTokenSource starts from t; a zero Expiry is read from the "exp"
+// claim when the access token is a JWT.
+func NewRefreshTokenSource(tokenURL, clientID string, t Token) *RefreshTokenSource {
+	if t.Expiry.IsZero() {
+		t.Expiry = jwtExpiry(t.AccessToken)
+	}
+	return &RefreshTokenSource{TokenURL: tokenURL, ClientID: clientID, token: t}
+}
+
+// Token returns the access token, refreshed first if expired or close to.
+func (s *RefreshTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.token
+	if t.AccessToken != "" && (t.Expiry.IsZero() || time.Until(t.Expiry) > expiryDelta) {
+		return t.AccessToken, nil
+	}
+	if t.RefreshToken == "" {
+		// let Core judge a possibly expired token
+		return t.AccessToken, nil
+	}
+	if err := s.refresh(ctx); err != nil {
+		return "", err
+	}
+	return s.token.AccessToken, nil
+}
+
+// Refresh implements TokenSource.
+func (s *RefreshTokenSource) Refresh(ctx context.Context, stale string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.AccessToken != stale {
+		return s.token.AccessToken, nil
+	}
+	if err := s.refresh(ctx); err != nil {
+		return "", err
+	}
+	return s.token.AccessToken, nil
+}
+
+// Current returns the token pair held by the source.
+func (s *RefreshTokenSource) Current() Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// refresh performs the grant; s.mu must be held.
+func (s *RefreshTokenSource) refresh(ctx context.Context) error {
+	if s.token.RefreshToken == "" {
+		return ErrNoRefreshToken
+	}
+	if s.TokenURL == "" {
+		return errors.New("token refresh failed: no token endpoint")
+	}
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", s.token.RefreshToken)
+	if s.ClientID != "" {
+		form.Set("client_id", s.ClientID)
+	}
+	t, err := RequestToken(ctx, s.HTTPClient, s.TokenURL, form)
+	if err != nil {
+		return fmt.Errorf("token refresh failed: %w", err)
+	}
+	if t.RefreshToken == "" {
+		// the server may keep the refresh token unchanged
+		t.RefreshToken = s.token.RefreshToken
+	}
+	s.token = t
+	if s.OnRefresh != nil {
+		s.OnRefresh(t)
+	}
+	return nil
+}
+
+// OAuth2Error is an error response of an OAuth2 endpoint (RFC 6749 5.2).
+type OAuth2Error struct {
+	StatusCode  int
+	Code        string
+	Description string
+}
+
+func (e *OAuth2Error) Error() string {
+	msg := fmt.Sprintf("token endpoint responded with: %d", e.StatusCode)
+	if e.Code != "" {
+		msg += " " + e.Code
+	}
+	if e.Description != "" {
+		msg += ": " + e.Description
+	}
+	return msg
+}
+
+// RequestToken performs a grant against an OAuth2 token endpoint; error
+// responses are returned as *OAuth2Error. A nil client uses
+// http.DefaultClient.
+func RequestToken(ctx context.Context, client *http.Client, tokenURL string, form url.Values) (Token, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Token{}, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Token{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		oe := &OAuth2Error{StatusCode: resp.StatusCode}
+		var body struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		if json.Unmarshal(b, &body) == nil {
+			oe.Code, oe.Description = body.Error, body.Description
+		}
+		return Token{}, oe
+	}
+
+	var body struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return Token{}, fmt.Errorf("invalid response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return Token{}, errors.New("no access_token in response")
+	}
+	t := Token{AccessToken: body.AccessToken, RefreshToken: body.RefreshToken}
+	if body.ExpiresIn > 0 {
+		t.Expiry = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	} else {
+		t.Expiry = jwtExpiry(t.AccessToken)
+	}
+	return t, nil
+}
+
+// jwtExpiry reads the "exp" claim of a JWT without verifying it; zero if tok
+// is not a JWT.
+func jwtExpiry(tok string) time.Time {
+	parts := strings.Split(tok, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
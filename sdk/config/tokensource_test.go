@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestTokenSourceRefreshOn401(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("old")
+	var grants atomic.Int32
+	issuer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != "refresh_token" || r.FormValue("refresh_token") != "r1" || r.FormValue("client_id") != "cli" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		n := grants.Add(1)
+		tok := fmt.Sprintf("new%d", n)
+		valid.Store(tok)
+		fmt.Fprintf(w, `{"access_token":%q,"refresh_token":"r2","expires_in":3600}`, tok)
+	}))
+	defer issuer.Close()
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+valid.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == "POST" {
+			if b, _ := io.ReadAll(r.Body); string(b) != `{"a":1}` {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	var persisted config.Token
+	ts := config.NewRefreshTokenSource(issuer.URL, "cli", config.Token{AccessToken: "old", RefreshToken: "r1"})
+	ts.OnRefresh = func(tok config.Token) { persisted = tok }
+	core := config.NewHTTPCore(nil, config.CoreConfig{BaseURL: api.URL, APIVersion: "v1", TokenSource: ts})
+	ctx := context.Background()
+
+	if _, _, err := core.Do(ctx, "GET", api.URL, nil); err != nil {
+		t.Fatalf("valid token: %v", err)
+	}
+
+	// the token is revoked server side: the 401 triggers a refresh and the
+	// body is sent again
+	valid.Store("revoked")
+	if _, _, err := core.Do(ctx, "POST", api.URL, []byte(`{"a":1}`)); err != nil {
+		t.Fatalf("expected retry with the refreshed token: %v", err)
+	}
+	if grants.Load() != 1 {
+		t.Fatalf("expected 1 grant, got %d", grants.Load())
+	}
+	if persisted.AccessToken != "new1" || persisted.RefreshToken != "r2" {
+		t.Fatalf("unexpected persisted token %+v", persisted)
+	}
+}
+
+func TestTokenSourceRefreshesExpired(t *testing.T) {
+	var grants atomic.Int32
+	issuer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		grants.Add(1)
+		w.Write([]byte(`{"access_token":"fresh","expires_in":3600}`))
+	}))
+	defer issuer.Close()
+
+	var seen atomic.Value
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen.Store(r.Header.Get("Authorization"))
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	ts := config.NewRefreshTokenSource(issuer.URL, "", config.Token{
+		AccessToken: "stale", RefreshToken: "r1", Expiry: time.Now().Add(-time.Minute),
+	})
+	core := config.NewHTTPCore(nil, config.CoreConfig{BaseURL: api.URL, APIVersion: "v1", TokenSource: ts})
+	for i := 0; i < 3; i++ {
+		if _, _, err := core.Do(context.Background(), "GET", api.URL, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := seen.Load(); got != "Bearer fresh" {
+		t.Fatalf("expected refreshed token, got %v", got)
+	}
+	if grants.Load() != 1 {
+		t.Fatalf("expected 1 grant, got %d", grants.Load())
+	}
+	if cur := ts.Current(); cur.RefreshToken != "r1" {
+		t.Fatalf("refresh token should be kept, got %q", cur.RefreshToken)
+	}
+}
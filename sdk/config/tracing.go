@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Span attributes set by the SDK, besides the OpenTelemetry HTTP ones.
+const (
+	AttrOperation = attribute.Key("dhcore.operation")
+	AttrProject   = attribute.Key("dhcore.project")
+	AttrResource  = attribute.Key("dhcore.resource")
+)
+
+// Tracer returns the SDK tracer of tp; a nil tp disables tracing.
+func Tracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		return noop.NewTracerProvider().Tracer(ModulePath)
+	}
+	return tp.Tracer(ModulePath, trace.WithInstrumentationVersion(SDKVersion()))
+}
+
+// StartSpan starts the span of an SDK operation (e.g. "crud.create") on a
+// project resource; empty project and resource are omitted.
+func StartSpan(ctx context.Context, tracer trace.Tracer, operation, project, resource string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	kv := append([]attribute.KeyValue{AttrOperation.String(operation)}, attrs...)
+	if project != "" {
+		kv = append(kv, AttrProject.String(project))
+	}
+	if resource != "" {
+		kv = append(kv, AttrResource.String(resource))
+	}
+	return tracer.Start(ctx, operation, trace.WithAttributes(kv...))
+}
+
+// EndSpan records the outcome of an operation, with the Core status of a
+// *CoreError, and ends span.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		var ce *CoreError
+		if errors.As(err, &ce) {
+			span.SetAttributes(attribute.Int("http.response.status_code", ce.StatusCode))
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
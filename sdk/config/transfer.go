@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import "github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+
+// Transfer tuning defaults.
+const (
+	DefaultBufferSize         = 128 * 1024
+	DefaultMultipartThreshold = 100 * 1024 * 1024
+	DefaultPartSize           = manager.DefaultUploadPartSize
+	DefaultConcurrency        = manager.DefaultUploadConcurrency
+)
+
+// TransferConfig tunes HTTP and S3 transfers; zero fields use the defaults.
+// See the benchmarks in s3client_bench_test.go to pick values for a network.
+type TransferConfig struct {
+	// BufferSize is the copy buffer of downloads (default 128 KB).
+	BufferSize int
+	// MultipartThreshold is the file size above which S3 uploads are split
+	// in parts (default 100 MB).
+	MultipartThreshold int64
+	// PartSize is the size of each part of a multipart upload (default and
+	// minimum 5 MB).
+	PartSize int64
+	// Concurrency is the number of parts uploaded in parallel (default 5).
+	Concurrency int
+	// ResumableUploads saves the progress of multipart uploads in a sidecar
+	// file next to the uploaded one (<file>.dhupload), so that uploading the
+	// same file to the same key again after a failure skips the parts
+	// already stored.
+	ResumableUploads bool
+}
+
+// WithDefaults returns t with zero or invalid fields replaced by defaults.
+func (t TransferConfig) WithDefaults() TransferConfig {
+	if t.BufferSize <= 0 {
+		t.BufferSize = DefaultBufferSize
+	}
+	if t.MultipartThreshold <= 0 {
+		t.MultipartThreshold = DefaultMultipartThreshold
+	}
+	if t.PartSize < manager.MinUploadPartSize {
+		t.PartSize = DefaultPartSize
+	}
+	if t.Concurrency <= 0 {
+		t.Concurrency = DefaultConcurrency
+	}
+	return t
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+)
+
+// ConnPool tunes the connection pool of the Core transport, e.g. to bound
+// the sockets opened by bulk parallel calls.
+type ConnPool struct {
+	// MaxIdleConns bounds idle connections across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost bounds idle connections kept per host (Go default
+	// 2: parallel calls beyond it open and drop a connection each time)
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost bounds all connections per host, queuing further calls
+	MaxConnsPerHost int
+	// IdleConnTimeout closes connections idle for longer
+	IdleConnTimeout time.Duration
+	// KeepAlive is the TCP keep-alive period; negative disables it
+	KeepAlive time.Duration
+	// DisableKeepAlives uses a new connection for every request
+	DisableKeepAlives bool
+	// DisableHTTP2 sticks to HTTP/1.1
+	DisableHTTP2 bool
+}
+
+// hasTransportSettings reports whether c customizes proxy, TLS, dialing or
+// pooling.
+func (c CoreConfig) hasTransportSettings() bool {
+	return c.ProxyURL != "" || c.CACertFile != "" || len(c.CACertPEM) > 0 ||
+		c.ClientCertFile != "" || c.ClientKeyFile != "" || c.InsecureSkipVerify ||
+		c.Timeouts.Connect > 0 || c.Pool != (ConnPool{})
+}
+
+// NewCoreHTTPClient builds the HTTP client for Core calls from the proxy, TLS,
+// connect timeout and pool settings of c. Without such settings it returns
+// c.HTTPClient, or http.DefaultClient.
+func NewCoreHTTPClient(c CoreConfig) (*http.Client, error) {
+	if !c.hasTransportSettings() {
+		if c.HTTPClient != nil {
+			return c.HTTPClient, nil
+		}
+		return http.DefaultClient, nil
+	}
+
+	var base *http.Transport
+	if c.HTTPClient != nil {
+		t, ok := c.HTTPClient.Transport.(*http.Transport)
+		if c.HTTPClient.Transport != nil && !ok {
+			return nil, errors.New("proxy and TLS settings need an *http.Transport in CoreConfig.HTTPClient")
+		}
+		base = t
+	}
+	if base == nil {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	tr := base.Clone()
+
+	if c.ProxyURL != "" {
+		proxy, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		tr.Proxy = http.ProxyURL(proxy)
+	}
+
+	if d, ka := c.Timeouts.Connect, c.Pool.KeepAlive; d > 0 || ka != 0 {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		if d > 0 {
+			dialer.Timeout = d
+			tr.TLSHandshakeTimeout = d
+		}
+		if ka != 0 {
+			dialer.KeepAlive = ka
+		}
+		tr.DialContext = dialer.DialContext
+	}
+
+	p := c.Pool
+	if p.MaxIdleConns > 0 {
+		tr.MaxIdleConns = p.MaxIdleConns
+	}
+	if p.MaxIdleConnsPerHost > 0 {
+		tr.MaxIdleConnsPerHost = p.MaxIdleConnsPerHost
+	}
+	if p.MaxConnsPerHost > 0 {
+		tr.MaxConnsPerHost = p.MaxConnsPerHost
+	}
+	if p.IdleConnTimeout > 0 {
+		tr.IdleConnTimeout = p.IdleConnTimeout
+	}
+	tr.DisableKeepAlives = tr.DisableKeepAlives || p.DisableKeepAlives
+	if p.DisableHTTP2 {
+		tr.ForceAttemptHTTP2 = false
+		// a non-nil empty map turns HTTP/2 off
+		tr.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	tlsConf := tr.TLSClientConfig
+	if tlsConf == nil {
+		tlsConf = &tls.Config{}
+	}
+	tlsConf.InsecureSkipVerify = c.InsecureSkipVerify
+	if p.DisableHTTP2 {
+		// a cloned transport may already advertise h2
+		tlsConf.NextProtos = slices.DeleteFunc(slices.Clone(tlsConf.NextProtos), func(s string) bool { return s == "h2" })
+	}
+
+	if c.CACertFile != "" || len(c.CACertPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pem := c.CACertPEM
+		if c.CACertFile != "" {
+			if pem, err = os.ReadFile(c.CACertFile); err != nil {
+				return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+			}
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates found in CA bundle")
+		}
+		tlsConf.RootCAs = pool
+	}
+
+	if c.ClientCertFile != "" || c.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+	tr.TLSClientConfig = tlsConf
+
+	client := &http.Client{}
+	if c.HTTPClient != nil {
+		*client = *c.HTTPClient
+	}
+	client.Transport = tr
+	return client, nil
+}
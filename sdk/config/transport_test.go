@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestCoreTLSSettings(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	// self-signed core: rejected by default
+	plain := config.NewHTTPCore(nil, config.CoreConfig{BaseURL: srv.URL, APIVersion: "v1"})
+	if _, _, err := plain.Do(ctx, "GET", srv.URL, nil); err == nil {
+		t.Fatal("expected a certificate error")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for name, cfg := range map[string]config.CoreConfig{
+		"ca file":  {CACertFile: caFile},
+		"ca pem":   {CACertPEM: caPEM},
+		"insecure": {InsecureSkipVerify: true},
+	} {
+		cfg.BaseURL, cfg.APIVersion = srv.URL, "v1"
+		if _, _, err := config.NewHTTPCore(nil, cfg).Do(ctx, "GET", srv.URL, nil); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	bad := config.NewHTTPCore(nil, config.CoreConfig{BaseURL: srv.URL, CACertFile: filepath.Join(t.TempDir(), "missing.pem")})
+	if _, _, err := bad.Do(ctx, "GET", srv.URL, nil); err == nil {
+		t.Fatal("expected a configuration error")
+	}
+}
+
+func TestCoreProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String() // absolute URI when acting as proxy
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer proxy.Close()
+
+	core := config.NewHTTPCore(nil, config.CoreConfig{BaseURL: "http://core.invalid", APIVersion: "v1", ProxyURL: proxy.URL})
+	url := core.BuildURL("demo", "artifacts", "", nil)
+	if _, _, err := core.Do(context.Background(), "GET", url, nil); err != nil {
+		t.Fatal(err)
+	}
+	if proxied != url {
+		t.Fatalf("proxy saw %q, want %q", proxied, url)
+	}
+}
+
+func TestCorePoolSettings(t *testing.T) {
+	base := config.CoreConfig{BaseURL: "http://core", APIVersion: "v1"}
+	if c, err := config.NewCoreHTTPClient(base); err != nil || c != http.DefaultClient {
+		t.Fatalf("expected the default client, got %v %v", c, err)
+	}
+
+	base.Pool = config.ConnPool{MaxIdleConnsPerHost: 32, MaxConnsPerHost: 64, IdleConnTimeout: time.Minute, DisableHTTP2: true}
+	c, err := config.NewCoreHTTPClient(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := c.Transport.(*http.Transport)
+	if tr.MaxIdleConnsPerHost != 32 || tr.MaxConnsPerHost != 64 || tr.IdleConnTimeout != time.Minute {
+		t.Fatalf("pool settings not applied: %+v", tr)
+	}
+	if tr.ForceAttemptHTTP2 || tr.TLSNextProto == nil {
+		t.Fatal("expected HTTP/2 to be disabled")
+	}
+	if http.DefaultTransport.(*http.Transport).MaxIdleConnsPerHost == 32 {
+		t.Fatal("the default transport must not be modified")
+	}
+}
+
+func TestCoreHTTP2Negotiated(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"proto":"` + r.Proto + `"}`))
+	}))
+	srv.EnableHTTP2 = true
+	srv.TLS = &tls.Config{NextProtos: []string{"h2", "http/1.1"}}
+	srv.StartTLS()
+	defer srv.Close()
+
+	for _, disable := range []bool{false, true} {
+		cfg := config.CoreConfig{BaseURL: srv.URL, APIVersion: "v1", InsecureSkipVerify: true,
+			Pool: config.ConnPool{DisableHTTP2: disable}}
+		b, _, err := config.NewHTTPCore(nil, cfg).Do(context.Background(), "GET", srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"proto":"HTTP/2.0"}`
+		if disable {
+			want = `{"proto":"HTTP/1.1"}`
+		}
+		if string(b) != want {
+			t.Fatalf("disable=%v: got %s", disable, b)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Severity ranks the problems reported by Validate.
+type Severity string
+
+const (
+	// SeverityError makes calls fail.
+	SeverityError Severity = "error"
+	// SeverityWarning may make some calls fail or behave unexpectedly.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo is a remark that needs no action.
+	SeverityInfo Severity = "info"
+)
+
+// Problem is a finding of Validate.
+type Problem struct {
+	Severity Severity
+	// Field is the setting at fault, e.g. "Core.BaseURL"
+	Field   string
+	Message string
+	// Hint suggests how to fix it; may be empty
+	Hint string
+}
+
+func (p Problem) String() string {
+	s := fmt.Sprintf("%s: %s: %s", p.Severity, p.Field, p.Message)
+	if p.Hint != "" {
+		s += " (" + p.Hint + ")"
+	}
+	return s
+}
+
+// HasErrors reports whether problems include a SeverityError.
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTimeout bounds the well-known calls of Validate.
+const validateTimeout = 10 * time.Second
+
+// tokenExpiryWarning is how close to its expiration an access token is
+// reported.
+const tokenExpiryWarning = 5 * time.Minute
+
+var apiVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// Validate checks cfg and returns the problems found, nil when there are
+// none: endpoint and API version format, auth settings and token expiry,
+// S3 credentials, then reachability of Core and consistency of its
+// well-known configuration with cfg.
+func Validate(cfg Config) []Problem {
+	return ValidateContext(context.Background(), cfg)
+}
+
+// ValidateContext is Validate with the well-known calls bound to ctx.
+func ValidateContext(ctx context.Context, cfg Config) []Problem {
+	problems, endpointOK := validateCore(cfg.Core)
+	problems = append(problems, validateS3(cfg.S3)...)
+	if endpointOK {
+		problems = append(problems, validateWellKnown(ctx, cfg.Core)...)
+	}
+	return problems
+}
+
+func validateCore(c CoreConfig) ([]Problem, bool) {
+	var ps []Problem
+	add := func(sev Severity, field, msg, hint string) {
+		ps = append(ps, Problem{Severity: sev, Field: field, Message: msg, Hint: hint})
+	}
+
+	endpointOK := false
+	if c.BaseURL == "" {
+		add(SeverityError, "Core.BaseURL", "endpoint is empty", "set dhcore_endpoint or DHCORE_ENDPOINT")
+	} else if u, err := url.Parse(c.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		add(SeverityError, "Core.BaseURL", fmt.Sprintf("invalid endpoint %q", c.BaseURL), "use an absolute http(s) URL, e.g. https://core.example.com")
+	} else {
+		endpointOK = true
+		if u.Scheme == "http" && u.Hostname() != "localhost" && u.Hostname() != "127.0.0.1" {
+			add(SeverityWarning, "Core.BaseURL", "endpoint uses plain http", "tokens are sent unencrypted; use https")
+		}
+	}
+
+	switch {
+	case c.APIVersion == "":
+		add(SeverityError, "Core.APIVersion", "API version is empty", "set dhcore_api_version, e.g. v1")
+	case !apiVersionPattern.MatchString(c.APIVersion):
+		add(SeverityWarning, "Core.APIVersion", fmt.Sprintf("unexpected API version %q", c.APIVersion), "API versions look like v1")
+	}
+
+	auth, err := c.AuthProvider()
+	switch {
+	case err != nil:
+		add(SeverityError, "Core.AuthMethod", err.Error(), "complete the settings of the auth method")
+	case auth == nil:
+		add(SeverityWarning, "Core.AccessToken", "no credentials configured", "log in, or set an access token or basic auth")
+	}
+
+	// a static bearer token can't be renewed
+	if c.Auth == nil && c.TokenSource == nil && c.AccessToken != "" &&
+		(c.AuthMethod == AuthAuto || c.AuthMethod == AuthBearer) && c.BasicAuthUsername == "" {
+		if exp := jwtExpiry(c.AccessToken); !exp.IsZero() {
+			left := time.Until(exp)
+			switch {
+			case left <= 0:
+				add(SeverityError, "Core.AccessToken", fmt.Sprintf("access token expired at %s", exp.Format(time.RFC3339)), "log in again, or use a refresh token (AuthMethod oauth2_refresh)")
+			case left < tokenExpiryWarning:
+				add(SeverityWarning, "Core.AccessToken", fmt.Sprintf("access token expires in %s", left.Round(time.Second)), "log in again, or use a refresh token (AuthMethod oauth2_refresh)")
+			}
+		}
+	}
+	return ps, endpointOK
+}
+
+func validateS3(s S3Config) []Problem {
+	var ps []Problem
+	add := func(sev Severity, field, msg, hint string) {
+		ps = append(ps, Problem{Severity: sev, Field: field, Message: msg, Hint: hint})
+	}
+	if s.Credentials != nil {
+		return nil
+	}
+
+	switch {
+	case s.AccessKey == "" && s.SecretKey == "":
+		if !s.CoreCredentials {
+			add(SeverityInfo, "S3.AccessKey", "no S3 credentials, transfers are unavailable", "set aws_access_key_id and aws_secret_access_key, or S3Config.CoreCredentials")
+		}
+	case s.AccessKey == "":
+		add(SeverityError, "S3.AccessKey", "secret key without access key", "set aws_access_key_id")
+	case s.SecretKey == "":
+		add(SeverityError, "S3.SecretKey", "access key without secret key", "set aws_secret_access_key")
+	}
+	if !s.Expiration.IsZero() && time.Until(s.Expiration) <= 0 {
+		sev, hint := SeverityError, "refresh the credentials, or enable S3Config.CoreCredentials"
+		if s.CoreCredentials {
+			sev, hint = SeverityInfo, "new credentials will be fetched from Core"
+		}
+		add(sev, "S3.Expiration", fmt.Sprintf("S3 credentials expired at %s", s.Expiration.Format(time.RFC3339)), hint)
+	}
+	if s.EndpointURL != "" {
+		if u, err := url.Parse(s.EndpointURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add(SeverityError, "S3.EndpointURL", fmt.Sprintf("invalid endpoint %q", s.EndpointURL), "use an absolute http(s) URL")
+		}
+	}
+	if (s.AccessKey != "" || s.CoreCredentials) && s.Region == "" && s.EndpointURL == "" {
+		add(SeverityWarning, "S3.Region", "neither region nor endpoint set", "set aws_region or aws_endpoint_url")
+	}
+	return ps
+}
+
+// validateWellKnown checks that Core answers and that its well-known
+// configuration matches c.
+func validateWellKnown(ctx context.Context, c CoreConfig) []Problem {
+	var ps []Problem
+	add := func(sev Severity, field, msg, hint string) {
+		ps = append(ps, Problem{Severity: sev, Field: field, Message: msg, Hint: hint})
+	}
+	client, err := NewCoreHTTPClient(c)
+	if err != nil {
+		add(SeverityError, "Core", fmt.Sprintf("invalid transport settings: %v", err), "check the proxy and TLS settings")
+		return ps
+	}
+	ctx, cancel := context.WithTimeout(ctx, validateTimeout)
+	defer cancel()
+
+	base := strings.TrimRight(c.BaseURL, "/")
+	wk, err := fetchWellKnown(ctx, client, base+"/.well-known/configuration")
+	if err != nil {
+		add(SeverityError, "Core.BaseURL", fmt.Sprintf("core is not reachable: %v", err), "check the endpoint, the network and the proxy settings")
+		return ps
+	}
+
+	str := func(m map[string]interface{}, k string) string { v, _ := m[k].(string); return v }
+	if v := str(wk, "dhcore_api_version"); v != "" && c.APIVersion != "" && v != c.APIVersion {
+		add(SeverityWarning, "Core.APIVersion", fmt.Sprintf("core advertises API version %s, configured %s", v, c.APIVersion), "refresh the environment")
+	}
+	if v := str(wk, "dhcore_endpoint"); v != "" && strings.TrimRight(v, "/") != base {
+		add(SeverityWarning, "Core.BaseURL", fmt.Sprintf("core advertises endpoint %s", v), "the configured endpoint may be a proxy or an outdated address")
+	}
+	if v := str(wk, "dhcore_version"); v != "" {
+		if ver, err := ParseVersion(v); err == nil && !SupportedCoreVersions.Contains(ver) {
+			add(SeverityWarning, "Core", fmt.Sprintf("core version %s is not supported by this SDK (supported: %s)", v, SupportedCoreVersions), "some calls may fail")
+		}
+	}
+
+	if c.OAuth2.TokenURL != "" {
+		oidc, err := fetchWellKnown(ctx, client, base+"/.well-known/openid-configuration")
+		if err != nil {
+			add(SeverityWarning, "Core.OAuth2.TokenURL", fmt.Sprintf("cannot read the OpenID configuration: %v", err), "")
+		} else if v := str(oidc, "token_endpoint"); v != "" && v != c.OAuth2.TokenURL {
+			add(SeverityWarning, "Core.OAuth2.TokenURL", fmt.Sprintf("issuer advertises token endpoint %s", v), "refresh the environment")
+		}
+	}
+	return ps
+}
+
+func fetchWellKnown(ctx context.Context, client *http.Client, u string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s answered %d", u, resp.StatusCode)
+	}
+	var m map[string]interface{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid response of %s: %w", u, err)
+	}
+	return m, nil
+}
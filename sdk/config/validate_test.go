@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestValidate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/configuration" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"dhcore_api_version":"v2","dhcore_version":"0.11.0"}`)
+	}))
+	defer srv.Close()
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, time.Now().Add(-time.Hour).Unix())))
+	problems := config.Validate(config.Config{
+		Core: config.CoreConfig{BaseURL: srv.URL, APIVersion: "v1", AccessToken: "h." + payload + ".s"},
+		S3:   config.S3Config{AccessKey: "ak", Region: "us-east-1"},
+	})
+	found := map[string]config.Severity{}
+	for _, p := range problems {
+		found[p.Field] = p.Severity
+	}
+	want := map[string]config.Severity{
+		"Core.AccessToken": config.SeverityError,   // expired
+		"S3.SecretKey":     config.SeverityError,   // incomplete
+		"Core.APIVersion":  config.SeverityWarning, // v2 advertised
+	}
+	for field, sev := range want {
+		if found[field] != sev {
+			t.Errorf("%s: got %q, want %q (problems: %v)", field, found[field], sev, problems)
+		}
+	}
+	if !config.HasErrors(problems) {
+		t.Fatal("expected errors")
+	}
+
+	srv.Close()
+	var errs []config.Problem
+	for _, p := range config.Validate(config.Config{Core: config.CoreConfig{BaseURL: srv.URL, APIVersion: "v1", AccessToken: "tok"}}) {
+		if p.Severity == config.SeverityError {
+			errs = append(errs, p)
+		}
+	}
+	if len(errs) != 1 || errs[0].Field != "Core.BaseURL" {
+		t.Fatalf("expected unreachable endpoint, got %v", errs)
+	}
+}
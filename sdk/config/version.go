@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// ModulePath is the Go module path of the SDK.
+const ModulePath = "github.com/scc-digitalhub/digitalhub-cli-sdk"
+
+var sdkVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if info.Main.Path == ModulePath && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == ModulePath {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "(devel)"
+})
+
+// SDKVersion returns the SDK module version from the build info of the
+// running binary, or "(devel)" when built from a local checkout.
+func SDKVersion() string {
+	return sdkVersion()
+}
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dhcoretest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Fixture is the content of a fixture file (YAML or JSON):
+//
+//	wellKnown: {dhcore_version: "0.12.0"}
+//	entities:
+//	  artifacts:
+//	    - {project: demo, name: dataset, kind: artifact, spec: {path: "s3://datalake/demo/dataset.csv"}}
+//	logs:
+//	  <run id>: [{status: {container: c-python-<run id>, metrics: [...]}}]
+//
+// Entities are keyed by resource ("projects", "artifacts", "runs", ...) and take
+// their project from the "project" field.
+type Fixture struct {
+	WellKnown map[string]interface{}              `json:"wellKnown,omitempty"`
+	OpenID    map[string]interface{}              `json:"openid,omitempty"`
+	Entities  map[string][]map[string]interface{} `json:"entities,omitempty"`
+	Logs      map[string][]interface{}            `json:"logs,omitempty"`
+}
+
+// Load adds the fixture content to the server.
+func (s *Server) Load(f Fixture) {
+	if f.WellKnown != nil {
+		s.SetWellKnown(f.WellKnown)
+	}
+	if f.OpenID != nil {
+		s.SetOpenID(f.OpenID)
+	}
+	for resource, list := range f.Entities {
+		for _, e := range list {
+			project, _ := e["project"].(string)
+			s.Add(project, resource, e)
+		}
+	}
+	for id, entries := range f.Logs {
+		s.SetLogs(id, entries)
+	}
+}
+
+// LoadFixture parses YAML (or JSON) fixture data and loads it.
+func (s *Server) LoadFixture(data []byte) error {
+	jsonBytes, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return fmt.Errorf("yaml to json failed: %w", err)
+	}
+	var f Fixture
+	if err := json.Unmarshal(jsonBytes, &f); err != nil {
+		return fmt.Errorf("invalid fixture: %w", err)
+	}
+	s.Load(f)
+	return nil
+}
+
+// LoadFixtureFile loads a fixture from a local file.
+func (s *Server) LoadFixtureFile(filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture: %w", err)
+	}
+	if err := s.LoadFixture(data); err != nil {
+		return fmt.Errorf("%s: %w", filePath, err)
+	}
+	return nil
+}
+
+// LoadFixtureFS loads every fixture matching pattern (e.g. "testdata/*.yaml")
+// from fsys, which is typically an embed.FS or os.DirFS.
+func (s *Server) LoadFixtureFS(fsys fs.FS, pattern string) error {
+	matches, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no fixtures match %s", pattern)
+	}
+	for _, m := range matches {
+		data, err := fs.ReadFile(fsys, m)
+		if err != nil {
+			return fmt.Errorf("failed to read fixture: %w", err)
+		}
+		if err := s.LoadFixture(data); err != nil {
+			return fmt.Errorf("%s: %w", path.Clean(m), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,954 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package dhcoretest provides an in-memory fake of the DigitalHub Core API,
+// served by net/http/httptest, for offline tests of the SDK services.
+//
+// It implements the endpoints the SDK relies on: generic CRUD with paging,
+// name/versions filters and sorting, run stop/resume/logs, project sharing,
+// secret values, spec schemas and the .well-known documents.
+package dhcoretest
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+const (
+	// APIVersion served by the fake core
+	APIVersion = "v1"
+	// DefaultPageSize used when the request does not specify "size"
+	DefaultPageSize = 10
+)
+
+// Request is a recorded call received by the fake core.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Server is a fake Core. The zero value is not usable, use NewServer.
+type Server struct {
+	*httptest.Server
+
+	// Token, when set, is required as Bearer token on every API call.
+	Token string
+	// PageSize overrides DefaultPageSize.
+	PageSize int
+	// StopState is the state runs enter on stop, STOPPED when empty (e.g.
+	// STOPPING for a run that doesn't stop).
+	StopState string
+
+	mu        sync.Mutex
+	entities  map[string][]map[string]interface{} // "<project>/<resource>" -> entities (insertion order)
+	logs      map[string][]interface{}            // run id -> log entries
+	shares    map[string][]map[string]interface{} // project id -> shares
+	secrets   map[string]map[string]string        // project -> secret name -> value
+	wellKnown map[string]interface{}
+	openID    map[string]interface{}
+	s3creds   map[string]interface{}
+	schemas   map[string][]map[string]interface{} // ENTITY -> schemas
+	requests  []Request
+	failures  map[string]int // "METHOD path" -> forced status code
+	// "<project>/<resource>|<Idempotency-Key>" -> id of the created entity
+	idempotent map[string]string
+}
+
+// NewServer starts a fake core. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		entities: map[string][]map[string]interface{}{},
+		logs:     map[string][]interface{}{},
+		shares:   map[string][]map[string]interface{}{},
+		secrets:  map[string]map[string]string{},
+		schemas:  map[string][]map[string]interface{}{},
+		failures: map[string]int{},
+
+		idempotent: map[string]string{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.wellKnown = map[string]interface{}{
+		"dhcore_name":        "dhcoretest",
+		"dhcore_version":     "0.0.0-test",
+		"dhcore_api_version": APIVersion,
+		"dhcore_api_level":   "10",
+		"dhcore_endpoint":    s.URL,
+	}
+	s.openID = map[string]interface{}{
+		"issuer":                 s.URL,
+		"token_endpoint":         s.URL + "/auth/token",
+		"authorization_endpoint": s.URL + "/auth/authorize",
+		"userinfo_endpoint":      s.URL + "/userinfo",
+		"jwks_uri":               s.URL + "/.well-known/jwks.json",
+	}
+	return s
+}
+
+// Config returns an SDK configuration pointing at the fake core.
+func (s *Server) Config() config.Config {
+	return config.Config{
+		Core: config.CoreConfig{
+			BaseURL:     s.URL,
+			APIVersion:  APIVersion,
+			AccessToken: s.Token,
+		},
+	}
+}
+
+// Add stores an entity under project/resource and returns its id. Missing id,
+// key, metadata and status.state are filled in like the core does on create.
+func (s *Server) Add(project, resource string, entity map[string]interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.add(project, resource, entity)
+}
+
+// Get returns a copy of a stored entity.
+func (s *Server) Get(project, resource, id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, e := s.find(project, resource, id)
+	if e == nil {
+		return nil, false
+	}
+	return clone(e), true
+}
+
+// ETag returns the ETag sent with a stored entity, unquoted; empty when it
+// doesn't exist.
+func (s *Server) ETag(project, resource, id string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, e := s.find(project, resource, id)
+	if e == nil {
+		return ""
+	}
+	return version(e)
+}
+
+// Patch merges patch into a stored entity as a JSON merge patch does, e.g.
+// to move a run to another state; false when the entity doesn't exist.
+func (s *Server) Patch(project, resource, id string, patch map[string]interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, e := s.find(project, resource, id)
+	if e == nil {
+		return false
+	}
+	mergePatch(e, clone(patch))
+	return true
+}
+
+// List returns copies of all entities stored under project/resource.
+func (s *Server) List(project, resource string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []map[string]interface{}
+	for _, e := range s.entities[bucketKey(project, resource)] {
+		out = append(out, clone(e))
+	}
+	return out
+}
+
+// SetLogs sets the entries returned by GET .../runs/{id}/logs.
+func (s *Server) SetLogs(runID string, entries []interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs[runID] = entries
+}
+
+// SetWellKnown merges values into /.well-known/configuration.
+func (s *Server) SetWellKnown(values map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range values {
+		s.wellKnown[k] = v
+	}
+}
+
+// SetOpenID merges values into /.well-known/openid-configuration.
+func (s *Server) SetOpenID(values map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range values {
+		s.openID[k] = v
+	}
+}
+
+// SetS3Credentials sets the response of GET /api/{version}/credentials
+// (aws_access_key_id, aws_secret_access_key, ...); nil answers 404.
+func (s *Server) SetS3Credentials(values map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.s3creds = values
+}
+
+// SetSchema publishes schema as the JSON schema of the spec of kind of
+// entity (e.g. "FUNCTION", "python"), served at
+// /api/{version}/schemas/{entity}[/{kind}].
+func (s *Server) SetSchema(entity, kind string, schema map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entity = strings.ToUpper(entity)
+	sc := map[string]interface{}{"id": entity + ":" + kind, "entity": entity, "kind": kind, "schema": schema}
+	list := s.schemas[entity]
+	for i, old := range list {
+		if old["kind"] == kind {
+			list[i] = sc
+			return
+		}
+	}
+	s.schemas[entity] = append(list, sc)
+}
+
+// Fail forces the next calls to method+path (e.g. "GET", "/api/v1/-/p/artifacts")
+// to answer with status until cleared with status 0.
+func (s *Server) Fail(method, path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if status == 0 {
+		delete(s.failures, method+" "+path)
+		return
+	}
+	s.failures[method+" "+path] = status
+}
+
+// Requests returns the calls received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+/* -------------------- routing -------------------- */
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+
+	if code, ok := s.failures[r.Method+" "+r.URL.Path]; ok {
+		writeError(w, code, "forced failure")
+		return
+	}
+
+	switch r.URL.Path {
+	case "/.well-known/configuration":
+		writeJSON(w, http.StatusOK, s.wellKnown)
+		return
+	case "/.well-known/openid-configuration":
+		writeJSON(w, http.StatusOK, s.openID)
+		return
+	}
+
+	prefix := "/api/" + APIVersion + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if s.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, "invalid token")
+		return
+	}
+
+	if r.URL.Path == prefix+"credentials" && r.Method == http.MethodGet {
+		if s.s3creds == nil {
+			writeError(w, http.StatusNotFound, "no credentials")
+			return
+		}
+		writeJSON(w, http.StatusOK, s.s3creds)
+		return
+	}
+
+	if rest, ok := strings.CutPrefix(r.URL.Path, prefix+"schemas/"); ok && r.Method == http.MethodGet {
+		s.handleSchemas(w, rest)
+		return
+	}
+
+	// projects[/{id}] or -/{project}/{resource}[/{id}[/{action}]]
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/"), "/")
+	var project, resource, id, action string
+	if parts[0] == "-" {
+		if len(parts) < 3 {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		project, resource = parts[1], parts[2]
+		parts = parts[3:]
+	} else {
+		resource = parts[0]
+		parts = parts[1:]
+	}
+	if len(parts) > 0 {
+		id = parts[0]
+	}
+	if len(parts) > 1 {
+		action = parts[1]
+	}
+	if len(parts) > 2 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	// If-Match is checked against the ETag of the entity
+	if m := r.Header.Get("If-Match"); m != "" && id != "" && action == "" {
+		if _, e := s.find(project, resource, id); e != nil && strings.Trim(m, `"`) != version(e) {
+			writeError(w, http.StatusPreconditionFailed, fmt.Sprintf("%s %s has changed", resource, id))
+			return
+		}
+	}
+
+	switch {
+	case resource == "secrets" && id == "data" && action == "":
+		s.handleSecretData(w, r, body, project)
+	case action != "":
+		s.handleAction(w, r, project, resource, id, action)
+	case id == "" && r.Method == http.MethodGet:
+		s.handleList(w, r, project, resource)
+	case id == "" && r.Method == http.MethodPost:
+		s.handleCreate(w, r, body, project, resource)
+	case id == "" && r.Method == http.MethodDelete:
+		s.handleDeleteByName(w, r, project, resource)
+	case r.Method == http.MethodGet:
+		if _, e := s.find(project, resource, id); e != nil {
+			if v := version(e); v != "" {
+				w.Header().Set("ETag", strconv.Quote(v))
+			}
+			writeJSON(w, http.StatusOK, e)
+			return
+		}
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", resource, id))
+	case r.Method == http.MethodPut:
+		s.handleUpdate(w, body, project, resource, id)
+	case r.Method == http.MethodPatch:
+		s.handlePatch(w, r, body, project, resource, id)
+	case r.Method == http.MethodDelete:
+		idx, e := s.find(project, resource, id)
+		if e == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", resource, id))
+			return
+		}
+		k := bucketKey(project, resource)
+		s.entities[k] = append(s.entities[k][:idx], s.entities[k][idx+1:]...)
+		if resource == "secrets" {
+			delete(s.secrets[project], fmt.Sprint(e["name"]))
+		}
+		writeJSON(w, http.StatusOK, e)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request, project, resource string) {
+	q := r.URL.Query()
+	items := s.filter(project, resource, q)
+
+	size := s.PageSize
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	if v, err := strconv.Atoi(q.Get("size")); err == nil && v > 0 {
+		size = v
+	}
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 0 {
+		page = 0
+	}
+	totalPages := (len(items) + size - 1) / size
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	content := []interface{}{}
+	for i := page * size; i < len(items) && i < (page+1)*size; i++ {
+		content = append(content, items[i])
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"content": content,
+		"pageable": map[string]interface{}{
+			"pageNumber": page,
+			"pageSize":   size,
+		},
+		"totalPages":       totalPages,
+		"totalElements":    len(items),
+		"numberOfElements": len(content),
+	})
+}
+
+// handleSchemas serves {entity} (a page of all its schemas) and
+// {entity}/{kind}.
+func (s *Server) handleSchemas(w http.ResponseWriter, path string) {
+	entity, kind, _ := strings.Cut(path, "/")
+	list := s.schemas[strings.ToUpper(entity)]
+	if kind == "" {
+		content := []interface{}{}
+		for _, sc := range list {
+			content = append(content, sc)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"content":          content,
+			"pageable":         map[string]interface{}{"pageNumber": 0, "pageSize": len(content)},
+			"totalPages":       1,
+			"totalElements":    len(content),
+			"numberOfElements": len(content),
+		})
+		return
+	}
+	for _, sc := range list {
+		if sc["kind"] == kind {
+			writeJSON(w, http.StatusOK, sc)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("schema %s %s not found", entity, kind))
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request, body []byte, project, resource string) {
+	var entity map[string]interface{}
+	if err := json.Unmarshal(body, &entity); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json: "+err.Error())
+		return
+	}
+	// a repeated Idempotency-Key returns the entity created the first time
+	idemKey := ""
+	if k := r.Header.Get(config.IdempotencyKeyHeader); k != "" {
+		idemKey = bucketKey(project, resource) + "|" + k
+		if _, e := s.find(project, resource, s.idempotent[idemKey]); e != nil {
+			writeJSON(w, http.StatusOK, e)
+			return
+		}
+	}
+	if id, _ := entity["id"].(string); id != "" {
+		if _, e := s.find(project, resource, id); e != nil {
+			writeError(w, http.StatusConflict, fmt.Sprintf("%s %s already exists", resource, id))
+			return
+		}
+	}
+	id := s.add(project, resource, entity)
+	if idemKey != "" {
+		s.idempotent[idemKey] = id
+	}
+	_, e := s.find(project, resource, id)
+	writeJSON(w, http.StatusOK, e)
+}
+
+func (s *Server) handleUpdate(w http.ResponseWriter, body []byte, project, resource, id string) {
+	idx, old := s.find(project, resource, id)
+	if old == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", resource, id))
+		return
+	}
+	var entity map[string]interface{}
+	if err := json.Unmarshal(body, &entity); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json: "+err.Error())
+		return
+	}
+	entity["id"] = id
+	meta, _ := entity["metadata"].(map[string]interface{})
+	if meta == nil {
+		meta = map[string]interface{}{}
+		entity["metadata"] = meta
+	}
+	if oldMeta, ok := old["metadata"].(map[string]interface{}); ok {
+		if c, ok := oldMeta["created"]; ok {
+			meta["created"] = c
+		}
+	}
+	meta["updated"] = time.Now().UTC().Format(time.RFC3339Nano)
+	s.entities[bucketKey(project, resource)][idx] = entity
+	writeJSON(w, http.StatusOK, entity)
+}
+
+// handlePatch applies a JSON Merge Patch or a JSON Patch (add, replace and
+// remove on object members) to a stored entity.
+func (s *Server) handlePatch(w http.ResponseWriter, r *http.Request, body []byte, project, resource, id string) {
+	idx, old := s.find(project, resource, id)
+	if old == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", resource, id))
+		return
+	}
+	entity := clone(old)
+	switch ct := r.Header.Get("Content-Type"); ct {
+	case "application/merge-patch+json":
+		var patch map[string]interface{}
+		if err := json.Unmarshal(body, &patch); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid merge patch: "+err.Error())
+			return
+		}
+		mergePatch(entity, patch)
+	case "application/json-patch+json":
+		var ops []struct {
+			Op    string      `json:"op"`
+			Path  string      `json:"path"`
+			Value interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(body, &ops); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid json patch: "+err.Error())
+			return
+		}
+		for _, op := range ops {
+			if err := applyPatchOp(entity, op.Op, op.Path, op.Value); err != nil {
+				writeError(w, http.StatusUnprocessableEntity, err.Error())
+				return
+			}
+		}
+	default:
+		writeError(w, http.StatusUnsupportedMediaType, "unsupported patch type "+ct)
+		return
+	}
+	entity["id"] = id
+	if meta, ok := entity["metadata"].(map[string]interface{}); ok {
+		meta["updated"] = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	s.entities[bucketKey(project, resource)][idx] = entity
+	writeJSON(w, http.StatusOK, entity)
+}
+
+// mergePatch applies an RFC 7386 merge patch to target.
+func mergePatch(target, patch map[string]interface{}) {
+	for k, v := range patch {
+		if v == nil {
+			delete(target, k)
+			continue
+		}
+		if pm, ok := v.(map[string]interface{}); ok {
+			tm, ok := target[k].(map[string]interface{})
+			if !ok {
+				tm = map[string]interface{}{}
+				target[k] = tm
+			}
+			mergePatch(tm, pm)
+			continue
+		}
+		target[k] = v
+	}
+}
+
+// applyPatchOp applies an RFC 6902 add, replace or remove on an object member.
+func applyPatchOp(doc map[string]interface{}, op, path string, value interface{}) error {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, p := range parts {
+		parts[i] = strings.ReplaceAll(strings.ReplaceAll(p, "~1", "/"), "~0", "~")
+	}
+	parent := doc
+	for _, p := range parts[:len(parts)-1] {
+		next, ok := parent[p].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("path %s not found", path)
+		}
+		parent = next
+	}
+	last := parts[len(parts)-1]
+	_, exists := parent[last]
+	switch op {
+	case "add":
+		parent[last] = value
+	case "replace":
+		if !exists {
+			return fmt.Errorf("path %s not found", path)
+		}
+		parent[last] = value
+	case "remove":
+		if !exists {
+			return fmt.Errorf("path %s not found", path)
+		}
+		delete(parent, last)
+	default:
+		return fmt.Errorf("unsupported patch op %q", op)
+	}
+	return nil
+}
+
+func (s *Server) handleDeleteByName(w http.ResponseWriter, r *http.Request, project, resource string) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "missing name")
+		return
+	}
+	k := bucketKey(project, resource)
+	kept := s.entities[k][:0]
+	removed := 0
+	for _, e := range s.entities[k] {
+		if n, _ := e["name"].(string); n == name {
+			removed++
+			continue
+		}
+		kept = append(kept, e)
+	}
+	s.entities[k] = kept
+	if removed == 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", resource, name))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleAction(w http.ResponseWriter, r *http.Request, project, resource, id, action string) {
+	_, e := s.find(project, resource, id)
+	if e == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", resource, id))
+		return
+	}
+	switch {
+	case action == "logs" && r.Method == http.MethodGet:
+		entries := s.logs[id]
+		if entries == nil {
+			entries = []interface{}{}
+		}
+		writeJSON(w, http.StatusOK, entries)
+	case action == "stop" && r.Method == http.MethodPost:
+		state := s.StopState
+		if state == "" {
+			state = "STOPPED"
+		}
+		setState(e, state)
+		writeJSON(w, http.StatusOK, e)
+	case action == "resume" && r.Method == http.MethodPost:
+		setState(e, "RUNNING")
+		writeJSON(w, http.StatusOK, e)
+	case action == "share" && resource == "projects":
+		s.handleShare(w, r, id)
+	default:
+		writeError(w, http.StatusNotFound, "unsupported action "+action)
+	}
+}
+
+// handleShare lists (GET), adds (POST ?user=&role=) and removes (DELETE ?id=)
+// the shares of a project.
+func (s *Server) handleShare(w http.ResponseWriter, r *http.Request, project string) {
+	q := r.URL.Query()
+	switch r.Method {
+	case http.MethodGet:
+		out := []map[string]interface{}{}
+		out = append(out, s.shares[project]...)
+		writeJSON(w, http.StatusOK, out)
+	case http.MethodPost:
+		if q.Get("user") == "" {
+			writeError(w, http.StatusBadRequest, "user is required")
+			return
+		}
+		share := map[string]interface{}{"id": newID(), "project": project, "user": q.Get("user")}
+		if role := q.Get("role"); role != "" {
+			share["role"] = role
+		}
+		s.shares[project] = append(s.shares[project], share)
+		writeJSON(w, http.StatusOK, share)
+	case http.MethodDelete:
+		for i, sh := range s.shares[project] {
+			if sh["id"] == q.Get("id") {
+				s.shares[project] = append(s.shares[project][:i], s.shares[project][i+1:]...)
+				writeJSON(w, http.StatusOK, sh)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "share not found")
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleSecretData reads (GET ?keys=a,b) and writes (PUT {"name": "value"})
+// secret values; writing a new name creates its secret entity.
+func (s *Server) handleSecretData(w http.ResponseWriter, r *http.Request, body []byte, project string) {
+	switch r.Method {
+	case http.MethodGet:
+		out := map[string]string{}
+		for _, k := range strings.Split(r.URL.Query().Get("keys"), ",") {
+			if v, ok := s.secrets[project][k]; ok {
+				out[k] = v
+			}
+		}
+		writeJSON(w, http.StatusOK, out)
+	case http.MethodPut:
+		var values map[string]string
+		if err := json.Unmarshal(body, &values); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid json: "+err.Error())
+			return
+		}
+		if s.secrets[project] == nil {
+			s.secrets[project] = map[string]string{}
+		}
+		for name, v := range values {
+			if _, ok := s.secrets[project][name]; !ok {
+				s.add(project, "secrets", map[string]interface{}{"name": name, "kind": "secret"})
+			}
+			s.secrets[project][name] = v
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+/* -------------------- storage helpers (lock held) -------------------- */
+
+func (s *Server) add(project, resource string, entity map[string]interface{}) string {
+	entity = clone(entity)
+	if resource == "projects" {
+		if id, _ := entity["id"].(string); id == "" {
+			entity["id"] = entity["name"]
+		}
+	} else if project != "" {
+		entity["project"] = project
+	}
+	id, _ := entity["id"].(string)
+	if id == "" {
+		id = newID()
+		entity["id"] = id
+	}
+	if _, ok := entity["kind"]; !ok {
+		entity["kind"] = strings.TrimSuffix(resource, "s")
+	}
+	if _, ok := entity["key"]; !ok {
+		entity["key"] = entityKey(entity, project, resource)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	meta, _ := entity["metadata"].(map[string]interface{})
+	if meta == nil {
+		meta = map[string]interface{}{}
+		entity["metadata"] = meta
+	}
+	if _, ok := meta["created"]; !ok {
+		meta["created"] = now
+	}
+	meta["updated"] = now
+
+	status, _ := entity["status"].(map[string]interface{})
+	if status == nil {
+		status = map[string]interface{}{}
+		entity["status"] = status
+	}
+	if _, ok := status["state"]; !ok {
+		status["state"] = "CREATED"
+	}
+
+	k := bucketKey(project, resource)
+	s.entities[k] = append(s.entities[k], entity)
+	return id
+}
+
+func (s *Server) find(project, resource, id string) (int, map[string]interface{}) {
+	for i, e := range s.entities[bucketKey(project, resource)] {
+		if fmt.Sprint(e["id"]) == id {
+			return i, e
+		}
+	}
+	return -1, nil
+}
+
+// filter applies the query parameters the SDK sends: name, versions, kind, state,
+// (for runs) task and (for tasks) function. Other parameters are ignored.
+func (s *Server) filter(project, resource string, q url.Values) []map[string]interface{} {
+	var out []map[string]interface{}
+	for _, e := range s.entities[bucketKey(project, resource)] {
+		if v := q.Get("name"); v != "" && fmt.Sprint(e["name"]) != v {
+			continue
+		}
+		if v := q.Get("kind"); v != "" && fmt.Sprint(e["kind"]) != v {
+			continue
+		}
+		if v := q.Get("state"); v != "" {
+			st, _ := e["status"].(map[string]interface{})
+			if st == nil || fmt.Sprint(st["state"]) != v {
+				continue
+			}
+		}
+		if v := q.Get("function"); v != "" {
+			sp, _ := e["spec"].(map[string]interface{})
+			if sp == nil || fmt.Sprint(sp["function"]) != v {
+				continue
+			}
+		}
+		if v := q.Get("task"); v != "" {
+			sp, _ := e["spec"].(map[string]interface{})
+			if sp == nil || fmt.Sprint(sp["task"]) != v {
+				continue
+			}
+		}
+		if v := q.Get("q"); v != "" && !strings.Contains(fmt.Sprint(e["name"]), v) {
+			continue
+		}
+		if v := q.Get("user"); v != "" && fmt.Sprint(e["user"]) != v {
+			continue
+		}
+		if !matchMetadata(e, q) {
+			continue
+		}
+		out = append(out, e)
+	}
+
+	if q.Get("versions") == "latest" {
+		// keep only the most recently created entity per name
+		latest := map[string]int{}
+		for i, e := range out {
+			latest[fmt.Sprint(e["name"])] = i
+		}
+		var filtered []map[string]interface{}
+		for i, e := range out {
+			if latest[fmt.Sprint(e["name"])] == i {
+				filtered = append(filtered, e)
+			}
+		}
+		out = filtered
+	}
+	sortEntities(out, q["sort"])
+	return out
+}
+
+// sortEntities orders entities by the "field,asc|desc" keys of the sort
+// params: name, or created and updated of the metadata.
+func sortEntities(entities []map[string]interface{}, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	value := func(e map[string]interface{}, field string) string {
+		if field == "name" {
+			return fmt.Sprint(e["name"])
+		}
+		meta, _ := e["metadata"].(map[string]interface{})
+		v, _ := meta[field].(string)
+		return v
+	}
+	sort.SliceStable(entities, func(i, j int) bool {
+		for _, k := range keys {
+			field, dir, _ := strings.Cut(k, ",")
+			a, b := value(entities[i], field), value(entities[j], field)
+			if a == b {
+				continue
+			}
+			if strings.EqualFold(dir, "desc") {
+				return a > b
+			}
+			return a < b
+		}
+		return false
+	})
+}
+
+// matchMetadata applies the labels and created/updated range filters;
+// timestamps compare as RFC 3339 strings.
+func matchMetadata(e map[string]interface{}, q url.Values) bool {
+	meta, _ := e["metadata"].(map[string]interface{})
+	labels := map[string]bool{}
+	if ls, ok := meta["labels"].([]interface{}); ok {
+		for _, l := range ls {
+			labels[fmt.Sprint(l)] = true
+		}
+	}
+	for _, l := range q["labels"] {
+		if !labels[l] {
+			return false
+		}
+	}
+	for _, f := range []string{"created", "updated"} {
+		ts, _ := meta[f].(string)
+		if v := q.Get(f + "_after"); v != "" && ts < v {
+			return false
+		}
+		if v := q.Get(f + "_before"); v != "" && ts >= v {
+			return false
+		}
+	}
+	return true
+}
+
+/* -------------------- misc -------------------- */
+
+func bucketKey(project, resource string) string {
+	if resource == "projects" {
+		project = ""
+	}
+	return project + "/" + resource
+}
+
+func entityKey(entity map[string]interface{}, project, resource string) string {
+	kind := fmt.Sprint(entity["kind"])
+	id := fmt.Sprint(entity["id"])
+	if resource == "projects" {
+		return fmt.Sprintf("store://%s", id)
+	}
+	if name, _ := entity["name"].(string); name != "" {
+		return fmt.Sprintf("%s://%s/%s:%s", kind, project, name, id)
+	}
+	return fmt.Sprintf("%s://%s/%s", kind, project, id)
+}
+
+func setState(e map[string]interface{}, state string) {
+	st, _ := e["status"].(map[string]interface{})
+	if st == nil {
+		st = map[string]interface{}{}
+		e["status"] = st
+	}
+	st["state"] = state
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// version is the ETag of an entity: derived from metadata.updated but, as
+// with Core, not equal to it.
+func version(e map[string]interface{}) string {
+	meta, _ := e["metadata"].(map[string]interface{})
+	v, _ := meta["updated"].(string)
+	if v == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(v))
+	return hex.EncodeToString(sum[:8])
+}
+
+// clone deep-copies an entity through JSON so callers can't alias server state.
+func clone(m map[string]interface{}) map[string]interface{} {
+	b, _ := json.Marshal(m)
+	var out map[string]interface{}
+	_ = json.Unmarshal(b, &out)
+	if out == nil {
+		out = map[string]interface{}{}
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]interface{}{
+		"status":  status,
+		"error":   http.StatusText(status),
+		"message": msg,
+	})
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// FromMap converts a generic entity, e.g. an element of
+// CrudService.ListAllPages, to T.
+func FromMap[T any](m map[string]interface{}) (T, error) {
+	var v T
+	b, err := json.Marshal(m)
+	if err != nil {
+		return v, fmt.Errorf("failed to marshal: %w", err)
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return v, fmt.Errorf("failed to decode entity: %w", err)
+	}
+	return v, nil
+}
+
+// FromList converts the result of CrudService.ListAllPages to []T.
+func FromList[T any](items []interface{}) ([]T, error) {
+	out := make([]T, 0, len(items))
+	for i, it := range items {
+		m, ok := it.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("item %d is not an object", i)
+		}
+		v, err := FromMap[T](m)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// ToMap converts v, e.g. an Artifact, to a generic map.
+func ToMap(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("%T is not an object: %w", v, err)
+	}
+	return m, nil
+}
+
+// unmarshalExtra decodes b into the struct pointed by v and returns the
+// fields of b that v doesn't declare.
+func unmarshalExtra(b []byte, v interface{}) (map[string]interface{}, error) {
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, err
+	}
+	for k := range jsonFields(reflect.TypeOf(v).Elem()) {
+		delete(all, k)
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+	return all, nil
+}
+
+// marshalExtra encodes the struct v with the fields of extra it doesn't
+// declare.
+func marshalExtra(v interface{}, extra map[string]interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	known := jsonFields(reflect.TypeOf(v))
+	for k, x := range extra {
+		if known[k] {
+			continue
+		}
+		raw, err := json.Marshal(x)
+		if err != nil {
+			return nil, err
+		}
+		m[k] = raw
+	}
+	return json.Marshal(m)
+}
+
+var fieldsCache sync.Map // reflect.Type -> map[string]bool
+
+// jsonFields returns the JSON names of the fields of struct type t.
+func jsonFields(t reflect.Type) map[string]bool {
+	if f, ok := fieldsCache.Load(t); ok {
+		return f.(map[string]bool)
+	}
+	fields := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = true
+	}
+	fieldsCache.Store(t, fields)
+	return fields
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package entities provides typed models of the Core entities. Stable fields
+// are typed; the others are kept in the Extra map of the enclosing struct,
+// so an entity read from Core and written back loses nothing. FromMap,
+// FromList and ToMap convert from and to the generic maps of the crud
+// service.
+package entities
+
+// Entity is the envelope shared by all entities; S is the type of the spec.
+type Entity[S any] struct {
+	ID       string   `json:"id,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Kind     string   `json:"kind,omitempty"`
+	Key      string   `json:"key,omitempty"`
+	Project  string   `json:"project,omitempty"`
+	User     string   `json:"user,omitempty"`
+	Metadata Metadata `json:"metadata"`
+	Spec     S        `json:"spec,omitempty"`
+	Status   Status   `json:"status"`
+	// Extra holds the top-level fields not listed above
+	Extra map[string]interface{} `json:"-"`
+}
+
+type plainEntity[S any] Entity[S]
+
+func (e *Entity[S]) UnmarshalJSON(b []byte) error {
+	extra, err := unmarshalExtra(b, (*plainEntity[S])(e))
+	e.Extra = extra
+	return err
+}
+
+func (e Entity[S]) MarshalJSON() ([]byte, error) {
+	return marshalExtra(plainEntity[S](e), e.Extra)
+}
+
+// Metadata are the descriptive fields of an entity; Created, Updated and the
+// authors are set by Core (timestamps are ISO 8601 strings).
+type Metadata struct {
+	Project     string                 `json:"project,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Version     string                 `json:"version,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Labels      []string               `json:"labels,omitempty"`
+	Embedded    bool                   `json:"embedded,omitempty"`
+	Created     string                 `json:"created,omitempty"`
+	Updated     string                 `json:"updated,omitempty"`
+	CreatedBy   string                 `json:"created_by,omitempty"`
+	UpdatedBy   string                 `json:"updated_by,omitempty"`
+	Extra       map[string]interface{} `json:"-"`
+}
+
+type plainMetadata Metadata
+
+func (m *Metadata) UnmarshalJSON(b []byte) error {
+	extra, err := unmarshalExtra(b, (*plainMetadata)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	return marshalExtra(plainMetadata(m), m.Extra)
+}
+
+// Status is the state of an entity; runs keep results, metrics and the
+// like in Extra.
+type Status struct {
+	State   string                 `json:"state,omitempty"`
+	Message string                 `json:"message,omitempty"`
+	Extra   map[string]interface{} `json:"-"`
+}
+
+type plainStatus Status
+
+func (s *Status) UnmarshalJSON(b []byte) error {
+	extra, err := unmarshalExtra(b, (*plainStatus)(s))
+	s.Extra = extra
+	return err
+}
+
+func (s Status) MarshalJSON() ([]byte, error) {
+	return marshalExtra(plainStatus(s), s.Extra)
+}
+
+type (
+	Project  = Entity[ProjectSpec]
+	Artifact = Entity[ArtifactSpec]
+	DataItem = Entity[DataItemSpec]
+	Model    = Entity[ModelSpec]
+	Run      = Entity[RunSpec]
+	Task     = Entity[TaskSpec]
+	// Function and Workflow specs depend on the runtime (the kind) and
+	// are kept as maps
+	Function = Entity[map[string]interface{}]
+	Workflow = Entity[map[string]interface{}]
+)
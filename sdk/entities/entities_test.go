@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package entities_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/entities"
+)
+
+const artifactJSON = `{
+	"id": "a1", "name": "dataset", "kind": "artifact", "project": "demo",
+	"key": "store://demo/artifact/artifact/dataset:a1", "custom": {"x": 1},
+	"metadata": {"name": "dataset", "labels": ["raw"], "created": "2025-01-01T00:00:00Z", "openmetadata": {"id": "om"}},
+	"spec": {"path": "s3://datalake/demo/dataset.csv", "src_path": "./dataset.csv"},
+	"status": {"state": "CREATED", "files": [{"path": "dataset.csv"}]}
+}`
+
+func TestRoundTripKeepsUnknownFields(t *testing.T) {
+	var a entities.Artifact
+	if err := json.Unmarshal([]byte(artifactJSON), &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Spec.Path != "s3://datalake/demo/dataset.csv" || a.Status.State != "CREATED" ||
+		a.Metadata.Labels[0] != "raw" || a.Spec.Extra["src_path"] != "./dataset.csv" {
+		t.Fatalf("unexpected artifact %+v", a)
+	}
+	if _, ok := a.Extra["custom"]; !ok || a.Extra["id"] != nil {
+		t.Fatalf("unexpected extra %v", a.Extra)
+	}
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want map[string]interface{}
+	_ = json.Unmarshal(b, &got)
+	_ = json.Unmarshal([]byte(artifactJSON), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("round trip changed the entity:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestConverters(t *testing.T) {
+	var m map[string]interface{}
+	_ = json.Unmarshal([]byte(artifactJSON), &m)
+
+	list, err := entities.FromList[entities.Artifact]([]interface{}{m, m})
+	if err != nil || len(list) != 2 || list[1].ID != "a1" {
+		t.Fatalf("FromList: %+v, %v", list, err)
+	}
+	if _, err := entities.FromList[entities.Artifact]([]interface{}{"x"}); err == nil {
+		t.Fatal("expected an error for a non-object item")
+	}
+
+	run, err := entities.FromMap[entities.Run](map[string]interface{}{
+		"id": "r1", "kind": "python+run",
+		"spec": map[string]interface{}{"task": "python+job://demo/f:1", "local_execution": false},
+	})
+	if err != nil || run.Spec.Task != "python+job://demo/f:1" || run.Spec.Extra["local_execution"] != false {
+		t.Fatalf("FromMap: %+v, %v", run, err)
+	}
+
+	back, err := entities.ToMap(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, _ := back["spec"].(map[string]interface{})
+	if back["id"] != "r1" || spec["local_execution"] != false {
+		t.Fatalf("ToMap: %v", back)
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package entities
+
+type ProjectSpec struct {
+	// Context is the working directory of the project
+	Context string                 `json:"context,omitempty"`
+	Extra   map[string]interface{} `json:"-"`
+}
+
+type ArtifactSpec struct {
+	Path  string                 `json:"path,omitempty"`
+	Extra map[string]interface{} `json:"-"`
+}
+
+type DataItemSpec struct {
+	Path  string                 `json:"path,omitempty"`
+	Extra map[string]interface{} `json:"-"`
+}
+
+type ModelSpec struct {
+	Path      string                 `json:"path,omitempty"`
+	Framework string                 `json:"framework,omitempty"`
+	Algorithm string                 `json:"algorithm,omitempty"`
+	Extra     map[string]interface{} `json:"-"`
+}
+
+type TaskSpec struct {
+	// Function is the key of the function the task runs
+	Function string                 `json:"function,omitempty"`
+	Extra    map[string]interface{} `json:"-"`
+}
+
+type RunSpec struct {
+	// Task and Function are keys, e.g. "python+job://project/func:id"
+	Task       string                 `json:"task,omitempty"`
+	Function   string                 `json:"function,omitempty"`
+	Inputs     map[string]interface{} `json:"inputs,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Extra      map[string]interface{} `json:"-"`
+}
+
+type (
+	plainProjectSpec  ProjectSpec
+	plainArtifactSpec ArtifactSpec
+	plainDataItemSpec DataItemSpec
+	plainModelSpec    ModelSpec
+	plainTaskSpec     TaskSpec
+	plainRunSpec      RunSpec
+)
+
+func (s *ProjectSpec) UnmarshalJSON(b []byte) (err error) {
+	s.Extra, err = unmarshalExtra(b, (*plainProjectSpec)(s))
+	return err
+}
+
+func (s ProjectSpec) MarshalJSON() ([]byte, error) {
+	return marshalExtra(plainProjectSpec(s), s.Extra)
+}
+
+func (s *ArtifactSpec) UnmarshalJSON(b []byte) (err error) {
+	s.Extra, err = unmarshalExtra(b, (*plainArtifactSpec)(s))
+	return err
+}
+
+func (s ArtifactSpec) MarshalJSON() ([]byte, error) {
+	return marshalExtra(plainArtifactSpec(s), s.Extra)
+}
+
+func (s *DataItemSpec) UnmarshalJSON(b []byte) (err error) {
+	s.Extra, err = unmarshalExtra(b, (*plainDataItemSpec)(s))
+	return err
+}
+
+func (s DataItemSpec) MarshalJSON() ([]byte, error) {
+	return marshalExtra(plainDataItemSpec(s), s.Extra)
+}
+
+func (s *ModelSpec) UnmarshalJSON(b []byte) (err error) {
+	s.Extra, err = unmarshalExtra(b, (*plainModelSpec)(s))
+	return err
+}
+
+func (s ModelSpec) MarshalJSON() ([]byte, error) {
+	return marshalExtra(plainModelSpec(s), s.Extra)
+}
+
+func (s *TaskSpec) UnmarshalJSON(b []byte) (err error) {
+	s.Extra, err = unmarshalExtra(b, (*plainTaskSpec)(s))
+	return err
+}
+
+func (s TaskSpec) MarshalJSON() ([]byte, error) {
+	return marshalExtra(plainTaskSpec(s), s.Extra)
+}
+
+func (s *RunSpec) UnmarshalJSON(b []byte) (err error) {
+	s.Extra, err = unmarshalExtra(b, (*plainRunSpec)(s))
+	return err
+}
+
+func (s RunSpec) MarshalJSON() ([]byte, error) {
+	return marshalExtra(plainRunSpec(s), s.Extra)
+}
@@ -0,0 +1,375 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package httpreplay provides a VCR-style http.RoundTripper that records Core
+// and S3 interactions to sanitized YAML fixture files and replays them in tests.
+//
+// Record once against a real environment (or to reproduce a user report):
+//
+//	rec, _ := httpreplay.New("testdata/issue-42.yaml", httpreplay.ModeRecord)
+//	cfg.Core.HTTPClient = rec.Client()
+//	cfg.S3.HTTPClient = rec.Client()
+//	... run the scenario ...
+//	_ = rec.Save()
+//
+// and replay it offline with ModeReplay. Secrets (Authorization headers, AWS
+// signatures/tokens, token fields in JSON bodies) are redacted before saving.
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Mode selects whether the recorder talks to the network.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the fixture file only.
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests to the real transport and records them.
+	ModeRecord
+	// ModeAuto replays when the fixture file exists, records otherwise.
+	ModeAuto
+)
+
+// Redacted replaces secret values in fixtures.
+const Redacted = "REDACTED"
+
+// ErrNoInteraction is returned in replay mode when no recorded interaction matches.
+var ErrNoInteraction = errors.New("httpreplay: no recorded interaction matches request")
+
+var (
+	secretHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Amz-Security-Token", "Proxy-Authorization"}
+	secretParams  = []string{"X-Amz-Signature", "X-Amz-Credential", "X-Amz-Security-Token", "access_token", "refresh_token", "client_secret", "password"}
+	secretFields  = []string{"access_token", "refresh_token", "id_token", "client_secret", "password",
+		"aws_secret_access_key", "aws_session_token", "secret_key", "session_token", "dhcore_access_token", "dhcore_refresh_token"}
+)
+
+// Interaction is a single recorded request/response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the sanitized request side of an interaction.
+type RecordedRequest struct {
+	Method       string              `json:"method"`
+	Path         string              `json:"path"`
+	Query        string              `json:"query,omitempty"`
+	Headers      map[string][]string `json:"headers,omitempty"`
+	Body         string              `json:"body,omitempty"`
+	BodyEncoding string              `json:"bodyEncoding,omitempty"`
+}
+
+// RecordedResponse is the sanitized response side of an interaction.
+type RecordedResponse struct {
+	Status       int                 `json:"status"`
+	Headers      map[string][]string `json:"headers,omitempty"`
+	Body         string              `json:"body,omitempty"`
+	BodyEncoding string              `json:"bodyEncoding,omitempty"`
+}
+
+type cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper recording or replaying interactions.
+type Recorder struct {
+	// Transport is used in record mode; nil means http.DefaultTransport.
+	Transport http.RoundTripper
+	// MatchBody also compares request bodies when replaying.
+	MatchBody bool
+
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New creates a recorder bound to a fixture file.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode}
+	if mode == ModeAuto {
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		} else {
+			r.mode = ModeRecord
+		}
+	}
+	if r.mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+		var c cassette
+		if err := yaml.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+		}
+		r.interactions = c.Interactions
+		r.used = make([]bool, len(c.Interactions))
+	}
+	return r, nil
+}
+
+// Mode returns the effective mode (ModeAuto is resolved at construction).
+func (r *Recorder) Mode() Mode { return r.mode }
+
+// Client returns an http.Client using the recorder as transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the recorded (or loaded) interactions.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the fixture file. No-op in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := yaml.Marshal(cassette{Interactions: r.interactions})
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = b
+	}
+	recReq := recordRequest(req, reqBody)
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recReq)
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	out := req.Clone(req.Context())
+	if reqBody != nil {
+		out.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	resp, err := transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	body, enc := encodeBody(sanitizeBody(respBody))
+	r.mu.Lock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: recReq,
+		Response: RecordedResponse{
+			Status:       resp.StatusCode,
+			Headers:      sanitizeHeaders(resp.Header),
+			Body:         body,
+			BodyEncoding: enc,
+		},
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recReq RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, it := range r.interactions {
+		if r.used[i] || !r.matches(it.Request, recReq) {
+			continue
+		}
+		r.used[i] = true
+		body, err := decodeBody(it.Response.Body, it.Response.BodyEncoding)
+		if err != nil {
+			return nil, err
+		}
+		header := http.Header{}
+		for k, v := range it.Response.Headers {
+			header[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+		}
+		header.Set("Content-Length", fmt.Sprint(len(body)))
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", it.Response.Status, http.StatusText(it.Response.Status)),
+			StatusCode:    it.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s?%s", ErrNoInteraction, recReq.Method, recReq.Path, recReq.Query)
+}
+
+func (r *Recorder) matches(a, b RecordedRequest) bool {
+	if a.Method != b.Method || a.Path != b.Path || a.Query != b.Query {
+		return false
+	}
+	return !r.MatchBody || a.Body == b.Body
+}
+
+/* -------------------- sanitizing -------------------- */
+
+func recordRequest(req *http.Request, body []byte) RecordedRequest {
+	b, enc := encodeBody(sanitizeBody(body))
+	return RecordedRequest{
+		Method:       req.Method,
+		Path:         req.URL.Path,
+		Query:        sanitizeQuery(req.URL.Query()),
+		Headers:      sanitizeHeaders(req.Header),
+		Body:         b,
+		BodyEncoding: enc,
+	}
+}
+
+// sanitizeQuery redacts secret params and encodes the rest in a stable order,
+// so that replay matching does not depend on map iteration.
+func sanitizeQuery(q url.Values) string {
+	for _, p := range secretParams {
+		for k := range q {
+			if strings.EqualFold(k, p) {
+				q[k] = []string{Redacted}
+			}
+		}
+	}
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range q[k] {
+			parts = append(parts, url.QueryEscape(k)+"="+url.QueryEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func sanitizeHeaders(h http.Header) map[string][]string {
+	if len(h) == 0 {
+		return nil
+	}
+	out := map[string][]string{}
+	for k, v := range h {
+		out[k] = append([]string(nil), v...)
+	}
+	for _, s := range secretHeaders {
+		if _, ok := out[http.CanonicalHeaderKey(s)]; ok {
+			out[http.CanonicalHeaderKey(s)] = []string{Redacted}
+		}
+	}
+	return out
+}
+
+// sanitizeBody redacts well-known secret fields of JSON bodies; other bodies
+// are returned unchanged.
+func sanitizeBody(body []byte) []byte {
+	var v interface{}
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return body
+	}
+	if !redact(v) {
+		return body
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+func redact(v interface{}) bool {
+	changed := false
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if isSecretField(k) {
+				if _, ok := val.(string); ok {
+					t[k] = Redacted
+					changed = true
+					continue
+				}
+			}
+			if redact(val) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, val := range t {
+			if redact(val) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func isSecretField(k string) bool {
+	for _, s := range secretFields {
+		if strings.EqualFold(k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeBody(b []byte) (string, string) {
+	if len(b) == 0 {
+		return "", ""
+	}
+	if utf8.Valid(b) {
+		return string(b), ""
+	}
+	return base64.StdEncoding.EncodeToString(b), "base64"
+}
+
+func decodeBody(s, enc string) ([]byte, error) {
+	if enc == "base64" {
+		return base64.StdEncoding.DecodeString(s)
+	}
+	return []byte(s), nil
+}
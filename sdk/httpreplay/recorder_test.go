@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httpreplay_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/dhcoretest"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/httpreplay"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/crud"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "testdata", "list.yaml")
+	ctx := context.Background()
+	listReq := crud.ListRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"},
+		Params:          map[string]string{"name": "dataset"},
+	}
+
+	// 1) record against the fake core
+	srv := dhcoretest.NewServer()
+	srv.Token = "super-secret-token"
+	srv.Add("demo", "artifacts", map[string]interface{}{"name": "dataset", "spec": map[string]interface{}{"path": "s3://b/k"}})
+
+	rec, err := httpreplay.New(fixture, httpreplay.ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != httpreplay.ModeRecord {
+		t.Fatalf("expected record mode for a missing fixture")
+	}
+	cfg := srv.Config()
+	cfg.Core.HTTPClient = rec.Client()
+	svc, err := crud.NewCrudService(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded, _, err := svc.ListAllPages(ctx, listReq)
+	if err != nil {
+		t.Fatalf("list failed while recording: %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	data, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "super-secret-token") {
+		t.Fatalf("token leaked into fixture:\n%s", data)
+	}
+
+	// 2) replay with the server gone
+	rep, err := httpreplay.New(fixture, httpreplay.ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Mode() != httpreplay.ModeReplay {
+		t.Fatalf("expected replay mode for an existing fixture")
+	}
+	cfg.Core.HTTPClient = rep.Client()
+	svc, err = crud.NewCrudService(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, _, err := svc.ListAllPages(ctx, listReq)
+	if err != nil {
+		t.Fatalf("list failed while replaying: %v", err)
+	}
+	if len(replayed) != len(recorded) || len(replayed) != 1 {
+		t.Fatalf("replayed %d elements, recorded %d", len(replayed), len(recorded))
+	}
+
+	// every interaction is served once
+	if _, _, err := svc.ListAllPages(ctx, listReq); !errors.Is(err, httpreplay.ErrNoInteraction) {
+		t.Fatalf("expected ErrNoInteraction, got %v", err)
+	}
+}
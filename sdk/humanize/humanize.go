@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package humanize formats sizes, rates, percentages and durations the same
+// way the SDK prints them, so CLI layers and consumers stay consistent.
+package humanize
+
+import (
+	"fmt"
+	"time"
+)
+
+// Binary size units (1 KB = 1024 B), as used by the SDK progress output.
+const (
+	KB = 1024
+	MB = 1024 * KB
+	GB = 1024 * MB
+	TB = 1024 * GB
+)
+
+// Bytes formats a size, e.g. 1536 -> "1.50 KB".
+func Bytes(n int64) string {
+	switch {
+	case n >= TB:
+		return fmt.Sprintf("%.2f TB", float64(n)/float64(TB))
+	case n >= GB:
+		return fmt.Sprintf("%.2f GB", float64(n)/float64(GB))
+	case n >= MB:
+		return fmt.Sprintf("%.2f MB", float64(n)/float64(MB))
+	case n >= KB:
+		return fmt.Sprintf("%.2f KB", float64(n)/float64(KB))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+// Rate formats a throughput, e.g. "12.00 MB/s". Zero elapsed gives "-".
+func Rate(n int64, elapsed time.Duration) string {
+	if elapsed <= 0 {
+		return "-"
+	}
+	return Bytes(int64(float64(n)/elapsed.Seconds())) + "/s"
+}
+
+// Ratio returns done/total as a percentage in [0, 100]; 0 when total is unknown.
+func Ratio(done, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	if done >= total {
+		return 100
+	}
+	if done < 0 {
+		return 0
+	}
+	return float64(done) / float64(total) * 100
+}
+
+// Percent formats done/total, e.g. "42.00%".
+func Percent(done, total int64) string {
+	return fmt.Sprintf("%.2f%%", Ratio(done, total))
+}
+
+// Duration formats an elapsed time with 100ms precision, e.g. "1m2.3s".
+func Duration(d time.Duration) string {
+	return d.Truncate(100 * time.Millisecond).String()
+}
+
+// ETA estimates the remaining time from the progress so far. It returns 0 when
+// it cannot be estimated (nothing transferred yet or unknown total).
+func ETA(done, total int64, elapsed time.Duration) time.Duration {
+	if done <= 0 || total <= 0 || done >= total || elapsed <= 0 {
+		return 0
+	}
+	remaining := float64(total-done) * float64(elapsed) / float64(done)
+	return time.Duration(remaining).Round(time.Second)
+}
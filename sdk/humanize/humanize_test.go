@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package humanize
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBytes(t *testing.T) {
+	cases := map[int64]string{
+		0:           "0 B",
+		1023:        "1023 B",
+		1536:        "1.50 KB",
+		5 * MB:      "5.00 MB",
+		3 * GB / 2:  "1.50 GB",
+		2 * TB:      "2.00 TB",
+		10*MB + 512: "10.00 MB",
+	}
+	for in, want := range cases {
+		if got := Bytes(in); got != want {
+			t.Errorf("Bytes(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRatePercentETA(t *testing.T) {
+	if got := Rate(10*MB, 2*time.Second); got != "5.00 MB/s" {
+		t.Errorf("Rate = %q", got)
+	}
+	if got := Rate(10, 0); got != "-" {
+		t.Errorf("Rate with zero elapsed = %q", got)
+	}
+	if got := Percent(1, 3); got != "33.33%" {
+		t.Errorf("Percent = %q", got)
+	}
+	if got := Percent(5, 0); got != "0.00%" {
+		t.Errorf("Percent unknown total = %q", got)
+	}
+	if got := Ratio(20, 10); got != 100 {
+		t.Errorf("Ratio overflow = %v", got)
+	}
+	if got := ETA(25, 100, 10*time.Second); got != 30*time.Second {
+		t.Errorf("ETA = %v", got)
+	}
+	if got := ETA(0, 100, time.Second); got != 0 {
+		t.Errorf("ETA without progress = %v", got)
+	}
+	if got := Duration(1234567 * time.Microsecond); got != "1.2s" {
+		t.Errorf("Duration = %q", got)
+	}
+}
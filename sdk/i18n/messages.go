@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package i18n holds the catalog of user-facing messages printed by the SDK,
+// with English defaults and a hook to plug translations in.
+package i18n
+
+import (
+	"fmt"
+	"sync"
+)
+
+// MessageID identifies a user-facing message of the catalog. IDs are stable and
+// can be used as keys of translation files.
+type MessageID string
+
+const (
+	MsgUploadPreparing           MessageID = "upload.preparing"
+	MsgUploadDirPreparing        MessageID = "upload.dir.preparing"
+	MsgUploadDirPreparingTotals  MessageID = "upload.dir.preparing.totals"
+	MsgDownloadPreparing         MessageID = "download.preparing"
+	MsgDownloadPreparingTotals   MessageID = "download.preparing.totals"
+	MsgDownloadListingFailed     MessageID = "download.listing.failed"
+	MsgTransferSize              MessageID = "transfer.size"
+	MsgUploading                 MessageID = "transfer.uploading"
+	MsgDownloading               MessageID = "transfer.downloading"
+	MsgUploadDone                MessageID = "transfer.upload.done"
+	MsgDownloadDone              MessageID = "transfer.download.done"
+	MsgTransferDoneIn            MessageID = "transfer.done.in"
+	MsgProgress                  MessageID = "progress"
+	MsgProgressUnknownTotal      MessageID = "progress.unknown.total"
+	MsgProgressTransferred       MessageID = "progress.transferred"
+	MsgEnvFromVariables          MessageID = "env.from.variables"
+	MsgEnvFreshness              MessageID = "env.freshness"
+	MsgEnvNoTimestamp            MessageID = "env.no.timestamp"
+	MsgEnvInvalidTimestamp       MessageID = "env.invalid.timestamp"
+	MsgEnvOutdated               MessageID = "env.outdated"
+	MsgEnvFresh                  MessageID = "env.fresh"
+	MsgEnvUpdating               MessageID = "env.updating"
+	MsgEnvNoEndpoint             MessageID = "env.no.endpoint"
+	MsgEnvConfigFetchFailed      MessageID = "env.config.fetch.failed"
+	MsgEnvOpenIDFetchFailed      MessageID = "env.openid.fetch.failed"
+	MsgEnvTimestampSet           MessageID = "env.timestamp.set"
+	MsgEnvPersistFailed          MessageID = "env.persist.failed"
+	MsgEnvPersisted              MessageID = "env.persisted"
+	MsgEnvUsing                  MessageID = "env.using"
+	MsgEnvUsingDefault           MessageID = "env.using.default"
+	MsgEnvFallbackDefault        MessageID = "env.fallback.default"
+	MsgIniNotFound               MessageID = "ini.not.found"
+	MsgIniBootstrapFailed        MessageID = "ini.bootstrap.failed"
+	MsgIniReloadFailed           MessageID = "ini.reload.failed"
+	MsgIniReadFailed             MessageID = "ini.read.failed"
+	MsgIniUpdateFailed           MessageID = "ini.update.failed"
+	MsgIniSectionUpdated         MessageID = "ini.section.updated"
+	MsgIniMigrated               MessageID = "ini.migrated"
+	MsgTokenPersistFailed        MessageID = "token.persist.failed"
+	MsgResourceNotSupported      MessageID = "resource.not.supported"
+	MsgInputReadFailed           MessageID = "input.read.failed"
+	MsgInputCancelling           MessageID = "input.cancelling"
+	MsgInputInvalidYesNo         MessageID = "input.invalid.yes.no"
+	MsgApiLevelChecking          MessageID = "apilevel.checking"
+	MsgApiLevelMissing           MessageID = "apilevel.missing"
+	MsgApiLevelNotInteger        MessageID = "apilevel.not.integer"
+	MsgApiLevelOutOfRange        MessageID = "apilevel.out.of.range"
+	MsgCoreVersionWarning        MessageID = "coreversion.warning"
+	MsgStateUpdated              MessageID = "state.updated"
+	MsgStateUnconfirmed          MessageID = "state.unconfirmed"
+	MsgRunNoMetrics              MessageID = "run.no.metrics"
+	MsgYamlCommentWithParameters MessageID = "yaml.comment.with.parameters"
+)
+
+// defaultMessages is the English catalog; every MessageID must have an entry.
+var defaultMessages = map[MessageID]string{
+	MsgUploadPreparing:           "Preparing upload %s → s3://%s/%s",
+	MsgUploadDirPreparing:        "Preparing upload directory %s → s3://%s/%s",
+	MsgUploadDirPreparingTotals:  "Preparing upload directory %s → s3://%s/%s (%d files, %s)",
+	MsgDownloadPreparing:         "Preparing download s3://%s/%s → %s",
+	MsgDownloadPreparingTotals:   "Preparing download s3://%s/%s → %s (%d files, %s)",
+	MsgDownloadListingFailed:     "Listing failed, proceeding without totals: %v",
+	MsgTransferSize:              "size: %s",
+	MsgUploading:                 "uploading: %6.2f%%",
+	MsgDownloading:               "downloading: %6.2f%%",
+	MsgUploadDone:                "done:      100.00%% in %s",
+	MsgDownloadDone:              "done:        100.00%% in %s",
+	MsgTransferDoneIn:            "done in %s",
+	MsgProgress:                  "Progress: %6.2f%% (%s / %s)",
+	MsgProgressUnknownTotal:      "Progress: [%c] %s downloaded",
+	MsgProgressTransferred:       "Progress: %s transferred",
+	MsgEnvFromVariables:          "INI file has been created from environment variables...skip update",
+	MsgEnvFreshness:              "Config freshness (%s): isSet=%v value=%q",
+	MsgEnvNoTimestamp:            "Update: no timestamp.",
+	MsgEnvInvalidTimestamp:       "Update: invalid timestamp (%v).",
+	MsgEnvOutdated:               "Update: outdated (age %s ≥ TTL %s).",
+	MsgEnvFresh:                  "Fresh: age %s < TTL %s.",
+	MsgEnvUpdating:               "Updating environment…",
+	MsgEnvNoEndpoint:             "Skip: dhcore_endpoint is empty.",
+	MsgEnvConfigFetchFailed:      "Config fetch failed: %v",
+	MsgEnvOpenIDFetchFailed:      "OpenID fetch failed: %v",
+	MsgEnvTimestampSet:           "Set %s=%s",
+	MsgEnvPersistFailed:          "Persist failed: %v",
+	MsgEnvPersisted:              "Persisted to [%s].",
+	MsgEnvUsing:                  "Using env: [%s]",
+	MsgEnvUsingDefault:           "Using env: [DEFAULT]",
+	MsgEnvFallbackDefault:        "Env not found, falling back to [DEFAULT]",
+	MsgIniNotFound:               "INI not found; Get information from Env variables",
+	MsgIniBootstrapFailed:        "Bootstrap failed: %v",
+	MsgIniReloadFailed:           "INI written but cannot reload: %v (ENV-only mode)",
+	MsgIniReadFailed:             "Failed to read ini file: %v",
+	MsgIniUpdateFailed:           "Failed to update ini file: %v",
+	MsgIniSectionUpdated:         "Updated section [%s] in %s",
+	MsgIniMigrated:               "Copied %s to %s",
+	MsgTokenPersistFailed:        "Token refreshed but not persisted: %v",
+	MsgResourceNotSupported:      "Resource '%v' is not supported.",
+	MsgInputReadFailed:           "Error in reading user input: %v",
+	MsgInputCancelling:           "Cancelling.",
+	MsgInputInvalidYesNo:         "Invalid input, must be y or n",
+	MsgApiLevelChecking:          "Checking API level for %v command...",
+	MsgApiLevelMissing:           "ERROR: Unable to check compatibility, environment does not specify API level.",
+	MsgApiLevelNotInteger:        "ERROR: API level %v is not an integer.",
+	MsgApiLevelOutOfRange:        "ERROR: API level %v is not within the supported interval: %v",
+	MsgCoreVersionWarning:        "Core compatibility: %v; some calls may fail.",
+	MsgStateUpdated:              "Core response successful, new state: %v",
+	MsgStateUnconfirmed:          "WARNING: core response successful, but unable to confirm new state.",
+	MsgRunNoMetrics:              "No metrics for this run.",
+	MsgYamlCommentWithParameters: "#   with parameters: %v",
+}
+
+// Translator returns the localized format string for a message, or "" to fall
+// back to the English default. Formats must keep the verbs of the default.
+type Translator func(id MessageID) string
+
+var (
+	translatorMu sync.RWMutex
+	translator   Translator
+)
+
+// SetTranslator installs the translation hook; nil restores English.
+func SetTranslator(t Translator) {
+	translatorMu.Lock()
+	defer translatorMu.Unlock()
+	translator = t
+}
+
+// SetTranslations installs a static translation table (e.g. loaded from a
+// YAML/JSON file). Missing entries fall back to English.
+func SetTranslations(messages map[MessageID]string) {
+	if messages == nil {
+		SetTranslator(nil)
+		return
+	}
+	table := make(map[MessageID]string, len(messages))
+	for k, v := range messages {
+		table[k] = v
+	}
+	SetTranslator(func(id MessageID) string { return table[id] })
+}
+
+// DefaultMessages returns a copy of the English catalog, handy as a template
+// for translation files.
+func DefaultMessages() map[MessageID]string {
+	out := make(map[MessageID]string, len(defaultMessages))
+	for k, v := range defaultMessages {
+		out[k] = v
+	}
+	return out
+}
+
+// Message returns the (possibly translated) format string for id.
+func Message(id MessageID) string {
+	translatorMu.RLock()
+	t := translator
+	translatorMu.RUnlock()
+	if t != nil {
+		if s := t(id); s != "" {
+			return s
+		}
+	}
+	if s, ok := defaultMessages[id]; ok {
+		return s
+	}
+	return string(id)
+}
+
+// Messagef formats the (possibly translated) message id with args.
+func Messagef(id MessageID, a ...any) string {
+	return fmt.Sprintf(Message(id), a...)
+}
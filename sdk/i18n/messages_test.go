@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package i18n
+
+import "testing"
+
+func TestMessagefTranslations(t *testing.T) {
+	t.Cleanup(func() { SetTranslator(nil) })
+
+	if got := Messagef(MsgEnvPersisted, "prod"); got != "Persisted to [prod]." {
+		t.Fatalf("default: got %q", got)
+	}
+
+	SetTranslations(map[MessageID]string{MsgEnvPersisted: "Salvato in [%s]."})
+	if got := Messagef(MsgEnvPersisted, "prod"); got != "Salvato in [prod]." {
+		t.Fatalf("translated: got %q", got)
+	}
+	// missing translations fall back to English
+	if got := Message(MsgEnvUpdating); got != "Updating environment…" {
+		t.Fatalf("fallback: got %q", got)
+	}
+
+	SetTranslator(nil)
+	if got := Message(MsgEnvPersisted); got != "Persisted to [%s]." {
+		t.Fatalf("reset: got %q", got)
+	}
+}
+
+func TestDefaultMessagesComplete(t *testing.T) {
+	for id, msg := range DefaultMessages() {
+		if msg == "" {
+			t.Errorf("empty default for %s", id)
+		}
+	}
+	if got := Message("unknown.id"); got != "unknown.id" {
+		t.Fatalf("unknown id: got %q", got)
+	}
+}
@@ -0,0 +1,310 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package prometheus exposes run metrics and transfer throughput in the
+// Prometheus text format, to be scraped (Exporter is an http.Handler) or
+// pushed to a Pushgateway.
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/run"
+)
+
+// DefaultNamespace prefixes the metric names when NewExporter gets none.
+const DefaultNamespace = "dhcore"
+
+// ContentType is the media type of the text format written by Exporter.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Exporter collects the metrics to expose. It is safe for concurrent use.
+type Exporter struct {
+	namespace string
+
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+type family struct {
+	help    string
+	typ     string
+	samples map[string]float64 // by rendered labels
+}
+
+// RunMetrics are the metrics of a run to export.
+type RunMetrics struct {
+	Project string
+	RunID   string
+	// Series are the metrics of the run, see run.RunService.GetMetrics;
+	// the last value of each is exported as a gauge named
+	// <namespace>_run_<name>
+	Series []run.MetricSeries
+	// Include, when set, exports only the named series
+	Include []string
+	// Labels are added to every sample of the run, e.g. the experiment
+	Labels map[string]string
+}
+
+// Transfer is the outcome of an upload or a download.
+type Transfer struct {
+	Project string
+	// Operation is e.g. "upload" or "download"
+	Operation string
+	Bytes     int64
+	Files     int
+	Duration  time.Duration
+	Labels    map[string]string
+}
+
+// NewExporter returns an empty exporter; namespace defaults to
+// DefaultNamespace.
+func NewExporter(namespace string) *Exporter {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	return &Exporter{namespace: sanitizeName(namespace), families: map[string]*family{}}
+}
+
+// AddRun sets the metrics of a run, replacing the values it had.
+func (e *Exporter) AddRun(m RunMetrics) {
+	labels := withLabels(m.Labels, "project", m.Project, "run", m.RunID)
+	include := map[string]bool{}
+	for _, n := range m.Include {
+		include[n] = true
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, s := range m.Series {
+		if len(include) > 0 && !include[s.Name] {
+			continue
+		}
+		p, ok := s.Last()
+		if !ok {
+			continue
+		}
+		name := e.namespace + "_run_" + sanitizeName(s.Name)
+		e.family(name, "gauge", fmt.Sprintf("Last value of the run metric %s.", s.Name)).samples[labels] = p.Value
+	}
+}
+
+// AddTransfer accounts a transfer: bytes, files and seconds are counters
+// summed over the transfers with the same labels, the throughput is a
+// gauge holding the rate of the last one.
+func (e *Exporter) AddTransfer(t Transfer) {
+	labels := withLabels(t.Labels, "project", t.Project, "operation", t.Operation)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ns := e.namespace
+	e.family(ns+"_transfer_bytes_total", "counter", "Bytes transferred.").samples[labels] += float64(t.Bytes)
+	e.family(ns+"_transfer_files_total", "counter", "Files transferred.").samples[labels] += float64(t.Files)
+	e.family(ns+"_transfer_seconds_total", "counter", "Time spent transferring, in seconds.").samples[labels] += t.Duration.Seconds()
+	if t.Duration > 0 {
+		e.family(ns+"_transfer_throughput_bytes_per_second", "gauge", "Throughput of the last transfer.").samples[labels] = float64(t.Bytes) / t.Duration.Seconds()
+	}
+}
+
+// Reset drops all the collected metrics.
+func (e *Exporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.families = map[string]*family{}
+}
+
+func (e *Exporter) family(name, typ, help string) *family {
+	f := e.families[name]
+	if f == nil {
+		f = &family{help: help, typ: typ, samples: map[string]float64{}}
+		e.families[name] = f
+	}
+	return f
+}
+
+// WriteTo writes the metrics in the Prometheus text format, sorted by name
+// and labels.
+func (e *Exporter) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	e.mu.Lock()
+	names := make([]string, 0, len(e.families))
+	for n := range e.families {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		f := e.families[n]
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s %s\n", n, escapeHelp(f.help), n, f.typ)
+		keys := make([]string, 0, len(f.samples))
+		for k := range f.samples {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&buf, "%s%s %s\n", n, k, formatFloat(f.samples[k]))
+		}
+	}
+	e.mu.Unlock()
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP serves the metrics, for a Prometheus scrape.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = e.WriteTo(w)
+}
+
+// PushRequest addresses a Pushgateway group.
+type PushRequest struct {
+	// URL of the Pushgateway, e.g. http://pushgateway:9091
+	URL string
+	// Job names the group, "dhcore" when empty
+	Job string
+	// Grouping labels select the group within the job, e.g.
+	// {"project": p, "run": id} for a group per run
+	Grouping map[string]string
+	// Client defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// Push replaces the metrics of the group req with those of the exporter.
+func (e *Exporter) Push(ctx context.Context, req PushRequest) error {
+	if req.URL == "" {
+		return errors.New("pushgateway url not specified")
+	}
+	job := req.Job
+	if job == "" {
+		job = DefaultNamespace
+	}
+	u := strings.TrimRight(req.URL, "/") + "/metrics/job" + pathValue(job)
+	keys := make([]string, 0, len(req.Grouping))
+	for k := range req.Grouping {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		u += "/" + sanitizeName(k) + pathValue(req.Grouping[k])
+	}
+
+	var body bytes.Buffer
+	if _, err := e.WriteTo(&body); err != nil {
+		return err
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPut, u, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	r.Header.Set("Content-Type", ContentType)
+	client := req.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(r)
+	if err != nil {
+		return fmt.Errorf("push failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("push failed (status %d): %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// withLabels renders the labels of a sample, sorted by name: extra and the
+// pairs kv, which take precedence; empty values are omitted.
+func withLabels(extra map[string]string, kv ...string) string {
+	all := map[string]string{}
+	for k, v := range extra {
+		all[sanitizeName(k)] = v
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		all[kv[i]] = kv[i+1]
+	}
+	keys := make([]string, 0, len(all))
+	for k, v := range all {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + `="` + escapeLabel(all[k]) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// sanitizeName maps a name to [a-zA-Z_][a-zA-Z0-9_]*.
+func sanitizeName(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// pathValue encodes a job or grouping label value in a Pushgateway path:
+// empty values and values with a slash are base64 encoded, the others
+// escaped.
+func pathValue(v string) string {
+	if v == "" || strings.Contains(v, "/") {
+		return "@base64/" + base64Value(v)
+	}
+	return "/" + url.PathEscape(v)
+}
+
+func base64Value(v string) string {
+	if v == "" {
+		return "="
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(v))
+}
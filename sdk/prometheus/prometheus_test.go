@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheus_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/prometheus"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/run"
+)
+
+func newExporter() *prometheus.Exporter {
+	e := prometheus.NewExporter("")
+	e.AddRun(prometheus.RunMetrics{
+		Project: "demo",
+		RunID:   "r1",
+		Series: []run.MetricSeries{
+			{Name: "loss", Points: []run.MetricPoint{{Value: 0.9}, {Value: 0.25}}},
+			{Name: "val-accuracy", Points: []run.MetricPoint{{Value: 0.8}}},
+			{Name: "empty"},
+		},
+		Labels: map[string]string{"experiment": `sweep "a"`},
+	})
+	e.AddTransfer(prometheus.Transfer{Project: "demo", Operation: "upload", Bytes: 1000, Files: 2, Duration: time.Second})
+	e.AddTransfer(prometheus.Transfer{Project: "demo", Operation: "upload", Bytes: 3000, Files: 1, Duration: time.Second})
+	return e
+}
+
+func TestWriteTo(t *testing.T) {
+	var b strings.Builder
+	if _, err := newExporter().WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"# TYPE dhcore_run_loss gauge\n",
+		`dhcore_run_loss{experiment="sweep \"a\"",project="demo",run="r1"} 0.25` + "\n",
+		`dhcore_run_val_accuracy{experiment="sweep \"a\"",project="demo",run="r1"} 0.8` + "\n",
+		"# TYPE dhcore_transfer_bytes_total counter\n",
+		`dhcore_transfer_bytes_total{operation="upload",project="demo"} 4000` + "\n",
+		`dhcore_transfer_files_total{operation="upload",project="demo"} 3` + "\n",
+		`dhcore_transfer_throughput_bytes_per_second{operation="upload",project="demo"} 3000` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "empty") {
+		t.Errorf("series without points exported:\n%s", out)
+	}
+}
+
+func TestPush(t *testing.T) {
+	var path, contentType, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("method = %s", r.Method)
+		}
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	err := newExporter().Push(context.Background(), prometheus.PushRequest{
+		URL:      srv.URL + "/",
+		Job:      "training",
+		Grouping: map[string]string{"run": "r1", "project": "demo", "path": "a/b", "stage": "eval?x=1#2 %"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/metrics/job/training/path@base64/YS9i/project/demo/run/r1/stage/eval?x=1#2 %"; path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+	if contentType != prometheus.ContentType {
+		t.Errorf("content type = %s", contentType)
+	}
+	if !strings.Contains(body, "dhcore_run_loss") {
+		t.Errorf("body = %s", body)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad metrics", http.StatusBadRequest)
+	}))
+	defer failing.Close()
+	err = newExporter().Push(context.Background(), prometheus.PushRequest{URL: failing.URL})
+	if err == nil || !strings.Contains(err.Error(), "status 400") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,568 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package s3test provides an in-memory, S3-compatible server for hermetic tests
+// of config.S3Client and the transfer service.
+//
+// It speaks the path-style REST API used by the SDK (ListObjectsV2 with
+// continuation tokens, Get/Head with ranges, Put, Copy, Delete and multipart
+// uploads) over TLS, since the AWS SDK only streams unseekable bodies with
+// trailing checksums over HTTPS. Buckets are created implicitly.
+package s3test
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// Object is a stored object.
+type Object struct {
+	Key          string
+	Data         []byte
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+	Metadata     map[string]string
+	// attributes requested by the upload (x-amz-server-side-encryption,
+	// x-amz-acl, x-amz-storage-class, x-amz-tagging, ...)
+	SSE          string
+	KMSKeyID     string
+	ACL          string
+	StorageClass string
+	Tags         map[string]string
+}
+
+// Size returns the object size in bytes.
+func (o *Object) Size() int64 { return int64(len(o.Data)) }
+
+// Server is a fake S3 endpoint. Use NewServer.
+type Server struct {
+	*httptest.Server
+
+	// RequireAccessKey, when set, rejects requests signed (or presigned)
+	// with another access key id.
+	RequireAccessKey string
+
+	mu       sync.Mutex
+	buckets  map[string]map[string]*Object
+	uploads  map[string]*multipartUpload
+	requests []string // "METHOD /bucket/key?query"
+}
+
+type multipartUpload struct {
+	bucket, key, contentType string
+	header                   http.Header
+	parts                    map[int][]byte
+}
+
+// NewServer starts a fake S3 server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		buckets: map[string]map[string]*Object{},
+		uploads: map[string]*multipartUpload{},
+	}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Config returns an S3 configuration pointing at the fake server, with an HTTP
+// client that trusts its certificate.
+func (s *Server) Config() config.S3Config {
+	return config.S3Config{
+		AccessKey:   "test",
+		SecretKey:   "test",
+		Region:      "us-east-1",
+		EndpointURL: s.URL,
+		HTTPClient:  s.Client(),
+	}
+}
+
+// PutObject stores an object directly, bypassing the HTTP API.
+func (s *Server) PutObject(bucket, key string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(bucket, key, data, "application/octet-stream")
+}
+
+// GetObject returns a stored object.
+func (s *Server) GetObject(bucket, key string) (*Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.buckets[bucket][key]
+	if !ok {
+		return nil, false
+	}
+	cp := *o
+	cp.Data = append([]byte(nil), o.Data...)
+	return &cp, true
+}
+
+// Keys returns the sorted keys stored in bucket.
+func (s *Server) Keys(bucket string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedKeys(bucket, "")
+}
+
+// Requests returns the calls received so far as "METHOD /bucket/key?query".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+/* -------------------- routing -------------------- */
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+
+	// presigned URLs carry the credential in the query
+	credential := r.Header.Get("Authorization") + " Credential=" + r.URL.Query().Get("X-Amz-Credential")
+	if s.RequireAccessKey != "" && !strings.Contains(credential, "Credential="+s.RequireAccessKey+"/") {
+		writeError(w, http.StatusForbidden, "InvalidAccessKeyId", "unknown access key")
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	q := r.URL.Query()
+	if bucket == "" {
+		writeError(w, http.StatusBadRequest, "InvalidBucketName", "missing bucket")
+		return
+	}
+
+	switch {
+	case key == "" && r.Method == http.MethodGet:
+		s.listObjectsV2(w, bucket, q)
+	case key == "" && r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case key == "" && r.Method == http.MethodPut:
+		s.bucket(bucket)
+		w.WriteHeader(http.StatusOK)
+
+	case key == "" && r.Method == http.MethodPost && q.Has("delete"):
+		s.deleteObjects(w, bucket, body)
+
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		s.createMultipart(w, bucket, key, r)
+	case r.Method == http.MethodPut && q.Get("uploadId") != "":
+		s.uploadPart(w, q, body)
+	case r.Method == http.MethodPost && q.Get("uploadId") != "":
+		s.completeMultipart(w, bucket, key, q.Get("uploadId"))
+	case r.Method == http.MethodDelete && q.Get("uploadId") != "":
+		delete(s.uploads, q.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		s.copyObject(w, r, bucket, key, r.Header.Get("X-Amz-Copy-Source"))
+	case r.Method == http.MethodPut:
+		ct := r.Header.Get("Content-Type")
+		o := s.put(bucket, key, body, ct)
+		o.Metadata = userMetadata(r.Header)
+		setAttributes(o, r.Header)
+		w.Header().Set("ETag", o.ETag)
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		s.getObject(w, r, bucket, key)
+	case r.Method == http.MethodDelete:
+		delete(s.bucket(bucket), key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method)
+	}
+}
+
+func (s *Server) listObjectsV2(w http.ResponseWriter, bucket string, q url.Values) {
+	prefix := q.Get("prefix")
+	maxKeys := 1000
+	if v, err := strconv.Atoi(q.Get("max-keys")); err == nil && v > 0 {
+		maxKeys = v
+	}
+	// the continuation token is the last key returned by the previous page
+	after := q.Get("continuation-token")
+	if after == "" {
+		after = q.Get("start-after")
+	}
+
+	type content struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         int64
+		StorageClass string
+	}
+	type result struct {
+		XMLName               xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+		Name                  string
+		Prefix                string
+		KeyCount              int
+		MaxKeys               int
+		IsTruncated           bool
+		ContinuationToken     string `xml:",omitempty"`
+		NextContinuationToken string `xml:",omitempty"`
+		Contents              []content
+	}
+
+	res := result{Name: bucket, Prefix: prefix, MaxKeys: maxKeys, ContinuationToken: q.Get("continuation-token")}
+	for _, k := range s.sortedKeys(bucket, prefix) {
+		if after != "" && k <= after {
+			continue
+		}
+		if len(res.Contents) == maxKeys {
+			res.IsTruncated = true
+			res.NextContinuationToken = res.Contents[len(res.Contents)-1].Key
+			break
+		}
+		o := s.buckets[bucket][k]
+		res.Contents = append(res.Contents, content{
+			Key:          k,
+			LastModified: o.LastModified.Format("2006-01-02T15:04:05.000Z"),
+			ETag:         o.ETag,
+			Size:         int64(len(o.Data)),
+			StorageClass: "STANDARD",
+		})
+	}
+	res.KeyCount = len(res.Contents)
+	writeXML(w, http.StatusOK, res)
+}
+
+func (s *Server) getObject(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	o, ok := s.buckets[bucket][key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+		return
+	}
+
+	if v := r.Header.Get("If-Match"); v != "" && v != o.ETag {
+		writeError(w, http.StatusPreconditionFailed, "PreconditionFailed", "If-Match")
+		return
+	}
+	if v := r.Header.Get("If-Unmodified-Since"); v != "" {
+		if t, err := http.ParseTime(v); err == nil && o.LastModified.Truncate(time.Second).After(t) {
+			writeError(w, http.StatusPreconditionFailed, "PreconditionFailed", "If-Unmodified-Since")
+			return
+		}
+	}
+
+	data := o.Data
+	status := http.StatusOK
+	if rng := r.Header.Get("Range"); rng != "" {
+		start, end, ok := parseRange(rng, int64(len(data)))
+		if !ok {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(data)))
+			writeError(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange", rng)
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+		data = data[start : end+1]
+		status = http.StatusPartialContent
+	}
+
+	h := w.Header()
+	h.Set("Content-Length", strconv.Itoa(len(data)))
+	h.Set("Content-Type", o.ContentType)
+	h.Set("ETag", o.ETag)
+	h.Set("Last-Modified", o.LastModified.Format(http.TimeFormat))
+	h.Set("Accept-Ranges", "bytes")
+	if o.SSE != "" {
+		h.Set("X-Amz-Server-Side-Encryption", o.SSE)
+	}
+	for k, v := range o.Metadata {
+		h.Set("X-Amz-Meta-"+k, v)
+	}
+	w.WriteHeader(status)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(data)
+	}
+}
+
+func (s *Server) createMultipart(w http.ResponseWriter, bucket, key string, r *http.Request) {
+	id := fmt.Sprintf("upload-%d", len(s.requests))
+	s.uploads[id] = &multipartUpload{
+		bucket:      bucket,
+		key:         key,
+		contentType: r.Header.Get("Content-Type"),
+		header:      r.Header.Clone(),
+		parts:       map[int][]byte{},
+	}
+	writeXML(w, http.StatusOK, struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		Bucket   string
+		Key      string
+		UploadId string
+	}{Bucket: bucket, Key: key, UploadId: id})
+}
+
+func (s *Server) uploadPart(w http.ResponseWriter, q url.Values, body []byte) {
+	up, ok := s.uploads[q.Get("uploadId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", q.Get("uploadId"))
+		return
+	}
+	n, err := strconv.Atoi(q.Get("partNumber"))
+	if err != nil || n < 1 {
+		writeError(w, http.StatusBadRequest, "InvalidArgument", "partNumber")
+		return
+	}
+	up.parts[n] = body
+	w.Header().Set("ETag", etag(body))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) completeMultipart(w http.ResponseWriter, bucket, key, id string) {
+	up, ok := s.uploads[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", id)
+		return
+	}
+	nums := make([]int, 0, len(up.parts))
+	for n := range up.parts {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+	var data []byte
+	for _, n := range nums {
+		data = append(data, up.parts[n]...)
+	}
+	delete(s.uploads, id)
+	o := s.put(bucket, key, data, up.contentType)
+	setAttributes(o, up.header)
+	writeXML(w, http.StatusOK, struct {
+		XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+		Location string
+		Bucket   string
+		Key      string
+		ETag     string
+	}{Location: s.URL + "/" + bucket + "/" + key, Bucket: bucket, Key: key, ETag: o.ETag})
+}
+
+func (s *Server) copyObject(w http.ResponseWriter, r *http.Request, bucket, key, source string) {
+	source, err := url.PathUnescape(strings.TrimPrefix(source, "/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidArgument", "x-amz-copy-source")
+		return
+	}
+	srcBucket, srcKey, _ := strings.Cut(source, "/")
+	src, ok := s.buckets[srcBucket][srcKey]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+		return
+	}
+	o := s.put(bucket, key, src.Data, src.ContentType)
+	o.Metadata = src.Metadata
+	o.Tags = src.Tags
+	setAttributes(o, r.Header)
+	writeXML(w, http.StatusOK, struct {
+		XMLName      xml.Name `xml:"CopyObjectResult"`
+		ETag         string
+		LastModified string
+	}{ETag: o.ETag, LastModified: o.LastModified.Format(time.RFC3339)})
+}
+
+func (s *Server) deleteObjects(w http.ResponseWriter, bucket string, body []byte) {
+	var req struct {
+		Objects []struct {
+			Key string
+		} `xml:"Object"`
+	}
+	if err := xml.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "MalformedXML", err.Error())
+		return
+	}
+	type deleted struct {
+		Key string
+	}
+	res := struct {
+		XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ DeleteResult"`
+		Deleted []deleted
+	}{}
+	for _, o := range req.Objects {
+		delete(s.bucket(bucket), o.Key)
+		res.Deleted = append(res.Deleted, deleted{Key: o.Key})
+	}
+	writeXML(w, http.StatusOK, res)
+}
+
+/* -------------------- helpers (lock held) -------------------- */
+
+func (s *Server) bucket(name string) map[string]*Object {
+	b, ok := s.buckets[name]
+	if !ok {
+		b = map[string]*Object{}
+		s.buckets[name] = b
+	}
+	return b
+}
+
+func (s *Server) put(bucket, key string, data []byte, contentType string) *Object {
+	if contentType == "" {
+		contentType = "binary/octet-stream"
+	}
+	o := &Object{
+		Key:          key,
+		Data:         append([]byte(nil), data...),
+		ContentType:  contentType,
+		ETag:         etag(data),
+		LastModified: time.Now().UTC().Truncate(time.Second),
+	}
+	s.bucket(bucket)[key] = o
+	return o
+}
+
+func (s *Server) sortedKeys(bucket, prefix string) []string {
+	var keys []string
+	for k := range s.buckets[bucket] {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+/* -------------------- wire format -------------------- */
+
+// readBody returns the request payload, decoding aws-chunked framing
+// (used by the SDK for streaming uploads with trailing checksums).
+func readBody(r *http.Request) ([]byte, error) {
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") &&
+		!strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return raw, nil
+	}
+
+	var out bytes.Buffer
+	br := bufio.NewReader(bytes.NewReader(raw))
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("invalid aws-chunked body: %w", err)
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid aws-chunked size %q", sizeHex)
+		}
+		if size == 0 {
+			// trailers follow; they are not needed
+			return out.Bytes(), nil
+		}
+		if _, err := io.CopyN(&out, br, size); err != nil {
+			return nil, fmt.Errorf("invalid aws-chunked body: %w", err)
+		}
+		if _, err := br.Discard(2); err != nil { // CRLF
+			return nil, fmt.Errorf("invalid aws-chunked body: %w", err)
+		}
+	}
+}
+
+func parseRange(h string, size int64) (int64, int64, bool) {
+	spec, ok := strings.CutPrefix(h, "bytes=")
+	if !ok || size == 0 {
+		return 0, 0, false
+	}
+	a, b, _ := strings.Cut(spec, "-")
+	var start, end int64
+	var err error
+	switch {
+	case a == "": // suffix: last N bytes
+		n, err := strconv.ParseInt(b, 10, 64)
+		if err != nil || n <= 0 {
+			return 0, 0, false
+		}
+		start, end = max(size-n, 0), size-1
+	default:
+		if start, err = strconv.ParseInt(a, 10, 64); err != nil {
+			return 0, 0, false
+		}
+		end = size - 1
+		if b != "" {
+			if end, err = strconv.ParseInt(b, 10, 64); err != nil {
+				return 0, 0, false
+			}
+		}
+	}
+	if start >= size || start > end {
+		return 0, 0, false
+	}
+	return start, min(end, size-1), true
+}
+
+func userMetadata(h http.Header) map[string]string {
+	m := map[string]string{}
+	for k, v := range h {
+		if name, ok := strings.CutPrefix(strings.ToLower(k), "x-amz-meta-"); ok && len(v) > 0 {
+			m[name] = v[0]
+		}
+	}
+	return m
+}
+
+// setAttributes records the attributes requested in h; tags of a copy are
+// replaced only with the REPLACE directive.
+func setAttributes(o *Object, h http.Header) {
+	o.SSE = h.Get("X-Amz-Server-Side-Encryption")
+	o.KMSKeyID = h.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id")
+	if o.SSE == "aws:kms" {
+		// as on S3, the ETag of an SSE-KMS object is not the MD5 of its content
+		o.ETag = etag(append([]byte("aws:kms:"), o.Data...))
+	}
+	o.ACL = h.Get("X-Amz-Acl")
+	o.StorageClass = h.Get("X-Amz-Storage-Class")
+	if h.Get("X-Amz-Copy-Source") != "" && h.Get("X-Amz-Tagging-Directive") != "REPLACE" {
+		return
+	}
+	if v, err := url.ParseQuery(h.Get("X-Amz-Tagging")); err == nil && len(v) > 0 {
+		o.Tags = map[string]string{}
+		for k := range v {
+			o.Tags[k] = v.Get(k)
+		}
+	}
+}
+
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func writeXML(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	writeXML(w, status, struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+		Message string
+	}{Code: code, Message: msg})
+}
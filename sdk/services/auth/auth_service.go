@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AuthService logs users in against the authorization server of Core, as
+// advertised by its OpenID metadata.
+type AuthService struct {
+	http       config.CoreHTTP
+	httpClient *http.Client
+	baseURL    string
+	clientID   string
+	tracer     trace.Tracer
+
+	// tokens of the session, for Logout
+	mu     sync.Mutex
+	tokens config.Token
+}
+
+// NewAuthService builds the service; opts customize HTTP client, logger and
+// retries (see config.ServiceOption). The client id is conf.Core.OAuth2.ClientID
+// unless set per login.
+func NewAuthService(_ context.Context, conf config.Config, opts ...config.ServiceOption) (*AuthService, error) {
+	if conf.Core.BaseURL == "" {
+		return nil, errors.New("invalid core config")
+	}
+	o := config.NewServiceOptions(opts...).ForConfig(conf)
+	httpClient := conf.Core.OAuth2.HTTPClient
+	if httpClient == nil {
+		httpClient = o.HTTPClient
+	}
+	return &AuthService{
+		http:       config.NewHTTPCoreWithOptions(conf.Core, o),
+		httpClient: httpClient,
+		baseURL:    strings.TrimRight(conf.Core.BaseURL, "/"),
+		clientID:   conf.Core.OAuth2.ClientID,
+		tokens:     config.Token{AccessToken: conf.Core.AccessToken, RefreshToken: conf.Core.OAuth2.RefreshToken},
+		tracer:     config.Tracer(o.TracerProvider),
+	}, nil
+}
+
+// Endpoints reads the endpoints of the authorization server from
+// /.well-known/openid-configuration of Core.
+func (s *AuthService) Endpoints(ctx context.Context) (*Endpoints, error) {
+	body, _, err := s.http.Do(ctx, "GET", s.baseURL+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read openid configuration: %w", err)
+	}
+	var e Endpoints
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, fmt.Errorf("invalid openid configuration: %w", err)
+	}
+	return &e, nil
+}
+
+// persist stores a token obtained by a login with store, or in the CLI
+// environment when store is nil.
+func persist(store func(config.Token) error, t config.Token) error {
+	if store == nil {
+		store = utils.PersistToken
+	}
+	if err := store(t); err != nil {
+		return fmt.Errorf("failed to store token: %w", err)
+	}
+	return nil
+}
+
+func (s *AuthService) currentTokens() config.Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens
+}
+
+func (s *AuthService) setTokens(t config.Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = t
+}
+
+// postForm posts a form to an endpoint of the authorization server.
+func (s *AuthService) postForm(ctx context.Context, endpoint string, form url.Values) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	client := s.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	return b, resp.StatusCode, err
+}
+
+func (s *AuthService) clientIDOr(id string) (string, error) {
+	if id != "" {
+		return id, nil
+	}
+	if s.clientID == "" {
+		return "", errors.New("missing client id")
+	}
+	return s.clientID, nil
+}
@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/dhcoretest"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/auth"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+	"github.com/spf13/viper"
+)
+
+// newIssuer starts an authorization server mux, advertised by a fake core.
+func newIssuer(t *testing.T, mux *http.ServeMux) (*auth.AuthService, *httptest.Server) {
+	t.Helper()
+	issuer := httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	srv := dhcoretest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.SetOpenID(map[string]interface{}{
+		"issuer":                        issuer.URL,
+		"authorization_endpoint":        issuer.URL + "/authorize",
+		"device_authorization_endpoint": issuer.URL + "/device",
+		"token_endpoint":                issuer.URL + "/token",
+		"revocation_endpoint":           issuer.URL + "/revoke",
+	})
+	conf := srv.Config()
+	conf.Core.OAuth2.ClientID = "cli"
+	svc, err := auth.NewAuthService(context.Background(), conf)
+	if err != nil {
+		t.Fatalf("failed to init sdk: %v", err)
+	}
+	return svc, issuer
+}
+
+func TestDeviceLogin(t *testing.T) {
+	var polls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("client_id") != "cli" || r.FormValue("scope") != "openid offline_access" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"device_code":"dev1","user_code":"ABCD-EFGH","verification_uri":"https://issuer/activate","expires_in":60,"interval":1}`))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != auth.DeviceCodeGrant || r.FormValue("device_code") != "dev1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// the user approves after the first poll
+		if polls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"authorization_pending"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"at","refresh_token":"rt","expires_in":3600}`))
+	})
+	svc, _ := newIssuer(t, mux)
+
+	var shown auth.DeviceCode
+	var stored config.Token
+	tok, err := svc.DeviceLogin(context.Background(), auth.DeviceLoginOptions{
+		Scopes: []string{"openid", "offline_access"},
+		OnCode: func(c auth.DeviceCode) error { shown = c; return nil },
+		Store:  func(t config.Token) error { stored = t; return nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shown.UserCode != "ABCD-EFGH" || shown.VerificationURI != "https://issuer/activate" || shown.ExpiresAt.IsZero() {
+		t.Fatalf("unexpected code %+v", shown)
+	}
+	if tok.AccessToken != "at" || stored.RefreshToken != "rt" || polls.Load() != 2 {
+		t.Fatalf("unexpected token %+v (stored %+v) after %d polls", tok, stored, polls.Load())
+	}
+}
+
+func TestDeviceLoginDenied(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"device_code":"dev1","user_code":"X","verification_uri":"https://issuer/activate","interval":1}`))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"access_denied"}`))
+	})
+	svc, _ := newIssuer(t, mux)
+
+	_, err := svc.DeviceLogin(context.Background(), auth.DeviceLoginOptions{
+		OnCode: func(auth.DeviceCode) error { return nil },
+		Store:  func(config.Token) error { t.Fatal("denied login stored"); return nil },
+	})
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Fatalf("expected denied error, got %v", err)
+	}
+}
+
+func TestLoginPKCE(t *testing.T) {
+	var challenge string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("client_id") != "cli" || q.Get("code_challenge_method") != "S256" || q.Get("response_type") != "code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		challenge = q.Get("code_challenge")
+		// the user logs in: back to the loopback listener
+		http.Redirect(w, r, q.Get("redirect_uri")+"?code=c1&state="+q.Get("state"), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		sum := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if r.FormValue("grant_type") != "authorization_code" || r.FormValue("code") != "c1" ||
+			base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"at","refresh_token":"rt","expires_in":3600}`))
+	})
+	svc, _ := newIssuer(t, mux)
+
+	var stored config.Token
+	tok, err := svc.Login(context.Background(), auth.LoginOptions{
+		ListenAddr: "127.0.0.1:0",
+		Scopes:     []string{"openid"},
+		// the "browser" follows the redirect to the callback
+		OpenURL: func(u string) error {
+			go func() {
+				if resp, err := http.Get(u); err == nil {
+					resp.Body.Close()
+				}
+			}()
+			return nil
+		},
+		Store: func(t config.Token) error { stored = t; return nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.AccessToken != "at" || stored.RefreshToken != "rt" {
+		t.Fatalf("unexpected token %+v (stored %+v)", tok, stored)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	iniPath := filepath.Join(t.TempDir(), "config.ini")
+	t.Setenv(utils.ConfigPathEnv, iniPath)
+	ini := "[test]\ndhcore_endpoint = https://core.example\ndhcore_refresh_token = rt-old\n"
+	if err := os.WriteFile(iniPath, []byte(ini), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set(utils.CurrentEnvironment, "test")
+	viper.Set(utils.DhCoreRefreshToken, "rt-old")
+
+	revoked := map[string]string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"access_token":"at","refresh_token":"rt","expires_in":3600}`))
+	})
+	mux.HandleFunc("/revoke", func(w http.ResponseWriter, r *http.Request) {
+		revoked[r.FormValue("token_type_hint")] = r.FormValue("token")
+	})
+	svc, _ := newIssuer(t, mux)
+
+	if err := svc.Logout(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if revoked["refresh_token"] != "rt-old" {
+		t.Fatalf("refresh token not revoked: %v", revoked)
+	}
+	if viper.GetString(utils.DhCoreRefreshToken) != "" {
+		t.Fatal("refresh token still in viper")
+	}
+	raw, err := os.ReadFile(iniPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "rt-old") || !strings.Contains(string(raw), "https://core.example") {
+		t.Fatalf("unexpected ini after logout:\n%s", raw)
+	}
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// DeviceCodeGrant is the grant type of RFC 8628.
+const DeviceCodeGrant = "urn:ietf:params:oauth:grant-type:device_code"
+
+// defaultPollInterval is used when the server doesn't send one.
+const defaultPollInterval = 5 * time.Second
+
+// DeviceLogin runs the device authorization grant, for hosts without a
+// browser: the code is shown with opts.OnCode and the token endpoint is
+// polled until the user approves it on another device. The token is stored
+// (see DeviceLoginOptions.Store) and returned.
+func (s *AuthService) DeviceLogin(ctx context.Context, opts DeviceLoginOptions) (_ *config.Token, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "auth.device_login", "", "")
+	defer func() { config.EndSpan(span, err) }()
+
+	if opts.OnCode == nil {
+		return nil, errors.New("missing OnCode callback")
+	}
+	clientID, err := s.clientIDOr(opts.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	ep, err := s.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ep.DeviceAuthorization == "" || ep.Token == "" {
+		return nil, errors.New("the authorization server doesn't support the device flow")
+	}
+
+	form := url.Values{}
+	form.Set("client_id", clientID)
+	if len(opts.Scopes) > 0 {
+		form.Set("scope", strings.Join(opts.Scopes, " "))
+	}
+	dc, err := s.requestDeviceCode(ctx, ep.DeviceAuthorization, form)
+	if err != nil {
+		return nil, err
+	}
+	code := DeviceCode{
+		UserCode:                dc.UserCode,
+		VerificationURI:         dc.VerificationURI,
+		VerificationURIComplete: dc.VerificationURIComplete,
+	}
+	if dc.ExpiresIn > 0 {
+		code.ExpiresAt = time.Now().Add(time.Duration(dc.ExpiresIn) * time.Second)
+	}
+	if err := opts.OnCode(code); err != nil {
+		return nil, err
+	}
+
+	if !code.ExpiresAt.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, code.ExpiresAt)
+		defer cancel()
+	}
+	interval := defaultPollInterval
+	if dc.Interval > 0 {
+		interval = time.Duration(dc.Interval) * time.Second
+	}
+	poll := url.Values{}
+	poll.Set("grant_type", DeviceCodeGrant)
+	poll.Set("device_code", dc.DeviceCode)
+	poll.Set("client_id", clientID)
+	for {
+		if err := sleep(ctx, interval); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil {
+				return nil, errors.New("device code expired before the login was approved")
+			}
+			return nil, err
+		}
+		t, err := config.RequestToken(ctx, s.httpClient, ep.Token, poll)
+		var oe *config.OAuth2Error
+		switch {
+		case err == nil:
+			s.setTokens(t)
+			if err := persist(opts.Store, t); err != nil {
+				return &t, err
+			}
+			return &t, nil
+		case errors.As(err, &oe) && oe.Code == "authorization_pending":
+		case errors.As(err, &oe) && oe.Code == "slow_down":
+			interval += 5 * time.Second
+		case errors.As(err, &oe) && oe.Code == "access_denied":
+			return nil, errors.New("device login denied by the user")
+		case errors.As(err, &oe) && oe.Code == "expired_token":
+			return nil, errors.New("device code expired before the login was approved")
+		default:
+			return nil, fmt.Errorf("device login failed: %w", err)
+		}
+	}
+}
+
+type deviceCodeResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURL         string `json:"verification_url"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+func (s *AuthService) requestDeviceCode(ctx context.Context, endpoint string, form url.Values) (*deviceCodeResponse, error) {
+	b, status, err := s.postForm(ctx, endpoint, form)
+	if err != nil {
+		return nil, fmt.Errorf("device authorization failed: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("device authorization failed: %d: %s", status, strings.TrimSpace(string(b)))
+	}
+	var dc deviceCodeResponse
+	if err := json.Unmarshal(b, &dc); err != nil {
+		return nil, fmt.Errorf("invalid device authorization response: %w", err)
+	}
+	if dc.VerificationURI == "" {
+		// pre-RFC name used by some servers
+		dc.VerificationURI = dc.VerificationURL
+	}
+	if dc.DeviceCode == "" || dc.UserCode == "" || dc.VerificationURI == "" {
+		return nil, errors.New("invalid device authorization response: missing code or verification uri")
+	}
+	return &dc, nil
+}
+
+// sleep waits d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
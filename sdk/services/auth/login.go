@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// Login runs the authorization code grant with PKCE (RFC 7636): a loopback
+// listener receives the redirect of the authorization server, opened with
+// opts.OpenURL, and the code is exchanged at the token endpoint. The token
+// is stored (see LoginOptions.Store) and returned.
+func (s *AuthService) Login(ctx context.Context, opts LoginOptions) (_ *config.Token, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "auth.login", "", "")
+	defer func() { config.EndSpan(span, err) }()
+
+	if opts.OpenURL == nil {
+		return nil, errors.New("missing OpenURL callback")
+	}
+	clientID, err := s.clientIDOr(opts.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	ep, err := s.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ep.Authorization == "" || ep.Token == "" {
+		return nil, errors.New("the authorization server doesn't advertise authorization and token endpoints")
+	}
+
+	addr := opts.ListenAddr
+	if addr == "" {
+		addr = DefaultListenAddr
+	}
+	path := opts.CallbackPath
+	if path == "" {
+		path = "/callback"
+	}
+	ln, err := (&net.ListenConfig{}).Listen(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start login listener: %w", err)
+	}
+	host, port, _ := net.SplitHostPort(addr)
+	if _, p, err := net.SplitHostPort(ln.Addr().String()); err == nil {
+		port = p
+	}
+	redirectURI := "http://" + net.JoinHostPort(host, port) + path
+
+	verifier := randomString(32)
+	challenge := sha256.Sum256([]byte(verifier))
+	state := randomString(16)
+
+	results := make(chan callbackResult, 1)
+	srv := &http.Server{Handler: callbackHandler(path, state, results)}
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Close()
+
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", clientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("state", state)
+	q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	q.Set("code_challenge_method", "S256")
+	if len(opts.Scopes) > 0 {
+		q.Set("scope", strings.Join(opts.Scopes, " "))
+	}
+	authURL := ep.Authorization
+	if strings.Contains(authURL, "?") {
+		authURL += "&" + q.Encode()
+	} else {
+		authURL += "?" + q.Encode()
+	}
+	if err := opts.OpenURL(authURL); err != nil {
+		return nil, err
+	}
+
+	var res callbackResult
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res = <-results:
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", res.code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("client_id", clientID)
+	form.Set("code_verifier", verifier)
+	t, err := config.RequestToken(ctx, s.httpClient, ep.Token, form)
+	if err != nil {
+		return nil, fmt.Errorf("code exchange failed: %w", err)
+	}
+	s.setTokens(t)
+	if err := persist(opts.Store, t); err != nil {
+		return &t, err
+	}
+	return &t, nil
+}
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// callbackHandler receives the redirect of the authorization server; only
+// the first valid callback is reported.
+func callbackHandler(path, state string, results chan<- callbackResult) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("state") != state {
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		}
+		var res callbackResult
+		switch {
+		case q.Get("error") != "":
+			res.err = fmt.Errorf("login failed: %s %s", q.Get("error"), q.Get("error_description"))
+		case q.Get("code") == "":
+			res.err = errors.New("login failed: no code in callback")
+		default:
+			res.code = q.Get("code")
+		}
+		select {
+		case results <- res:
+		default:
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if res.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintln(w, res.err.Error())
+			return
+		}
+		fmt.Fprintln(w, "Login completed, you can close this window.")
+	})
+}
+
+// randomString returns n random bytes, base64url encoded.
+func randomString(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+	"github.com/spf13/viper"
+)
+
+// Logout revokes the tokens of the session at the authorization server, if
+// it advertises a revocation endpoint (RFC 7009), then removes the secrets
+// of the current environment from Viper, the INI and the credential store.
+// Local secrets are removed even when the revocation fails.
+func (s *AuthService) Logout(ctx context.Context) (err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "auth.logout", "", "")
+	defer func() { config.EndSpan(span, err) }()
+
+	tokens := s.currentTokens()
+	if tokens.AccessToken == "" {
+		tokens.AccessToken = viper.GetString(utils.DhCoreAccessToken)
+	}
+	if tokens.RefreshToken == "" {
+		tokens.RefreshToken = viper.GetString(utils.DhCoreRefreshToken)
+	}
+
+	var errs []error
+	if tokens.AccessToken != "" || tokens.RefreshToken != "" {
+		if rerr := s.revoke(ctx, tokens); rerr != nil {
+			errs = append(errs, rerr)
+		}
+	}
+	s.setTokens(config.Token{})
+	if cerr := utils.ClearSecrets(); cerr != nil {
+		errs = append(errs, fmt.Errorf("failed to remove local credentials: %w", cerr))
+	}
+	return errors.Join(errs...)
+}
+
+// revoke revokes the refresh token, which ends the session, then the access
+// token.
+func (s *AuthService) revoke(ctx context.Context, t config.Token) error {
+	ep, err := s.Endpoints(ctx)
+	if err != nil {
+		return err
+	}
+	if ep.Revocation == "" {
+		return nil
+	}
+	clientID, _ := s.clientIDOr("")
+	for _, tok := range []struct{ value, hint string }{
+		{t.RefreshToken, "refresh_token"},
+		{t.AccessToken, "access_token"},
+	} {
+		if tok.value == "" {
+			continue
+		}
+		form := url.Values{}
+		form.Set("token", tok.value)
+		form.Set("token_type_hint", tok.hint)
+		if clientID != "" {
+			form.Set("client_id", clientID)
+		}
+		_, status, err := s.postForm(ctx, ep.Revocation, form)
+		if err != nil {
+			return fmt.Errorf("token revocation failed: %w", err)
+		}
+		// unknown or already revoked tokens are answered with 200 as well
+		if status != http.StatusOK {
+			return fmt.Errorf("token revocation failed: revocation endpoint responded with %d", status)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// Endpoints of the authorization server (OpenID metadata).
+type Endpoints struct {
+	Issuer              string   `json:"issuer"`
+	Authorization       string   `json:"authorization_endpoint"`
+	DeviceAuthorization string   `json:"device_authorization_endpoint"`
+	Token               string   `json:"token_endpoint"`
+	Revocation          string   `json:"revocation_endpoint"`
+	ScopesSupported     []string `json:"scopes_supported"`
+}
+
+// DeviceCode is the code the user enters at VerificationURI to approve a
+// device login.
+type DeviceCode struct {
+	UserCode        string
+	VerificationURI string
+	// VerificationURIComplete embeds the user code, e.g. for a QR code
+	VerificationURIComplete string
+	ExpiresAt               time.Time
+}
+
+// DeviceLoginOptions configures a device authorization grant (RFC 8628).
+type DeviceLoginOptions struct {
+	// ClientID overrides CoreConfig.OAuth2.ClientID
+	ClientID string
+	Scopes   []string
+	// OnCode presents the code to the user; required
+	OnCode func(DeviceCode) error
+	// Store persists the token; nil stores it in the CLI environment
+	// (utils.PersistToken)
+	Store func(config.Token) error
+}
+
+// DefaultListenAddr is the loopback address of the Login redirect.
+const DefaultListenAddr = "localhost:4000"
+
+// LoginOptions configures an authorization code login with PKCE.
+type LoginOptions struct {
+	// ClientID overrides CoreConfig.OAuth2.ClientID
+	ClientID string
+	Scopes   []string
+	// ListenAddr is the loopback address receiving the redirect (default
+	// DefaultListenAddr); port 0 picks a free one
+	ListenAddr string
+	// CallbackPath is the path of the redirect URI (default "/callback")
+	CallbackPath string
+	// OpenURL shows the authorization URL to the user, e.g. opening a
+	// browser; required
+	OpenURL func(string) error
+	// Store persists the token; nil stores it in the CLI environment
+	// (utils.PersistToken)
+	Store func(config.Token) error
+}
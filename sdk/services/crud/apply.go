@@ -0,0 +1,266 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+type ApplyRequest struct {
+	// Project, when set, overrides the project of the documents; Resource
+	// is used for the documents whose key and kind don't tell it (e.g.
+	// functions, whose kind is the runtime)
+	ResourceRequest
+
+	// FilePath is a YAML file of one or more documents separated by
+	// "---", a directory of such files or a glob pattern; Data is used
+	// when it is empty
+	FilePath string
+	Data     []byte
+	// DryRun validates the documents and reports what would be created or
+	// updated, without changing anything
+	DryRun bool
+}
+
+// ChangeAction is what Apply did with a document.
+type ChangeAction string
+
+const (
+	ChangeCreated   ChangeAction = "created"
+	ChangeUpdated   ChangeAction = "updated"
+	ChangeUnchanged ChangeAction = "unchanged"
+)
+
+// Change is the outcome of a document of Apply or CreateAll.
+type Change struct {
+	// Source is "file#n", the n-th document of the file
+	Source   string
+	Resource string
+	Project  string
+	Name     string
+	ID       string
+	Action   ChangeAction
+	// DryRun marks a change that was not made
+	DryRun bool
+	// Err is the failure of the document, when the request goes on after
+	// errors; Action is then the attempted one
+	Err error
+}
+
+// ChangeSet summarizes Apply and CreateAll.
+type ChangeSet struct {
+	Changes []Change
+}
+
+// Count returns the number of successful changes with action a.
+func (r *ChangeSet) Count(a ChangeAction) int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Action == a && c.Err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Err joins the errors of the failed changes; nil when there are none.
+func (r *ChangeSet) Err() error {
+	var errs []error
+	for _, c := range r.Changes {
+		if c.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Source, c.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Apply creates the entities of the manifest that don't exist and updates
+// the others, in document order. An entity exists when its id, or else its
+// name (latest version), is found; it is unchanged when every field of the
+// document already has that value, and it is then left alone. On failure
+// the result holds the changes made so far. With DryRun the changes are
+// only computed and the documents to create or update are validated.
+func (s *CrudService) Apply(ctx context.Context, req ApplyRequest) (_ *ChangeSet, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.apply", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	docs, err := loadDocuments(req.FilePath, req.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveResources(docs, req.Resource); err != nil {
+		return nil, err
+	}
+
+	res := &ChangeSet{}
+	for _, doc := range docs {
+		c, err := s.applyDocument(ctx, req.Project, doc, req.DryRun)
+		if err != nil {
+			return res, fmt.Errorf("%s: %w", doc.Source, err)
+		}
+		res.Changes = append(res.Changes, c)
+	}
+	return res, nil
+}
+
+func (s *CrudService) applyDocument(ctx context.Context, project string, doc document, dryRun bool) (Change, error) {
+	e := maps.Clone(doc.Entity)
+	delete(e, "user")
+	c := Change{Source: doc.Source, Resource: doc.Resource, DryRun: dryRun}
+	c.Name, _ = e["name"].(string)
+
+	if config.IsGlobalResource(doc.Resource) {
+		project = ""
+	} else {
+		if project == "" {
+			project, _ = e["project"].(string)
+		}
+		if project == "" {
+			project = keyProject(e)
+		}
+		if project == "" {
+			return c, errors.New("project is mandatory for non-project resources")
+		}
+		e["project"] = project
+	}
+	c.Project = project
+
+	existing, err := s.findExisting(ctx, project, doc.Resource, e)
+	if err != nil {
+		return c, err
+	}
+
+	if existing == nil {
+		c.Action = ChangeCreated
+		if dryRun {
+			c.ID, _ = e["id"].(string)
+			return c, s.validate(ctx, project, doc.Resource, e)
+		}
+		body, err := json.Marshal(e)
+		if err != nil {
+			return c, fmt.Errorf("failed to marshal: %w", err)
+		}
+		u := s.http.BuildURL(project, doc.Resource, "", nil)
+		b, _, err := s.http.Do(config.ContextWithIdempotencyKey(ctx, config.NewIdempotencyKey()), "POST", u, body)
+		if err != nil {
+			return c, err
+		}
+		var created struct {
+			ID string `json:"id"`
+		}
+		_ = json.Unmarshal(b, &created)
+		c.ID = created.ID
+		return c, nil
+	}
+
+	c.ID, _ = existing["id"].(string)
+	if isSubset(e, existing) {
+		c.Action = ChangeUnchanged
+		return c, nil
+	}
+	c.Action = ChangeUpdated
+	merged := mergeEntity(existing, e)
+	if dryRun {
+		return c, s.validate(ctx, project, doc.Resource, merged)
+	}
+	body, err := json.Marshal(merged)
+	if err != nil {
+		return c, fmt.Errorf("failed to marshal: %w", err)
+	}
+	if _, status, err := s.http.Do(ctx, "PUT", s.http.BuildURL(project, doc.Resource, c.ID, nil), body); err != nil {
+		return c, fmt.Errorf("update failed (status %d): %w", status, err)
+	}
+	return c, nil
+}
+
+// findExisting returns the entity e refers to by id, or by name (the latest
+// version; projects are addressed by name), or nil.
+func (s *CrudService) findExisting(ctx context.Context, project, resource string, e map[string]interface{}) (map[string]interface{}, error) {
+	id, _ := e["id"].(string)
+	name, _ := e["name"].(string)
+	if id == "" && config.IsGlobalResource(resource) {
+		id = name
+	}
+	if id != "" {
+		b, _, err := s.http.Do(ctx, "GET", s.http.BuildURL(project, resource, id, nil), nil)
+		if config.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, fmt.Errorf("json parsing failed: %w", err)
+		}
+		return m, nil
+	}
+	if name == "" {
+		return nil, errors.New("the document has neither id nor name")
+	}
+	for item, err := range s.ListIter(ctx, ListRequest{
+		ResourceRequest: ResourceRequest{Project: project, Resource: resource},
+		Params:          map[string]string{"name": name, "versions": "latest"},
+	}) {
+		if err != nil {
+			return nil, err
+		}
+		if item["name"] == name {
+			return item, nil
+		}
+	}
+	return nil, nil
+}
+
+// isSubset reports whether every field of want has the same value in got;
+// objects are compared field by field, other values as a whole.
+func isSubset(want, got map[string]interface{}) bool {
+	for k, w := range want {
+		g, ok := got[k]
+		if !ok {
+			return false
+		}
+		wm, wok := w.(map[string]interface{})
+		gm, gok := g.(map[string]interface{})
+		if wok && gok {
+			if !isSubset(wm, gm) {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(w, g) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeEntity returns existing with the fields of e; metadata is merged
+// field by field so that the audit fields set by Core are kept.
+func mergeEntity(existing, e map[string]interface{}) map[string]interface{} {
+	out := maps.Clone(existing)
+	for k, v := range e {
+		if k == "metadata" {
+			em, _ := existing[k].(map[string]interface{})
+			vm, ok := v.(map[string]interface{})
+			if ok && em != nil {
+				m := maps.Clone(em)
+				maps.Copy(m, vm)
+				out[k] = m
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// DefaultBulkConcurrency is the number of parallel deletions of DeleteMany
+// when BulkDeleteRequest.Concurrency is not set.
+const DefaultBulkConcurrency = 4
+
+type BulkDeleteRequest struct {
+	ResourceRequest
+
+	// IDs and Names are deleted, by name with all versions; Filter adds
+	// the entities it matches (see NewFilter), and must set at least one
+	// condition
+	IDs    []string
+	Names  []string
+	Filter *Filter
+
+	Cascade     bool
+	Concurrency int
+	// OnProgress is called after each deletion, from the deleting
+	// goroutines, with the number of deletions done out of total
+	OnProgress func(r DeleteResult, done, total int)
+}
+
+// DeleteResult is the outcome of the deletion of an entity.
+type DeleteResult struct {
+	// ID or Name, as requested (Filter matches are deleted by ID)
+	ID   string
+	Name string
+	Err  error
+}
+
+// DeleteResults are the outcomes of DeleteMany, in request order.
+type DeleteResults []DeleteResult
+
+// Failed returns the results with an error.
+func (r DeleteResults) Failed() DeleteResults {
+	var out DeleteResults
+	for _, x := range r {
+		if x.Err != nil {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+// Err joins the errors of the failed deletions; nil when all succeeded.
+func (r DeleteResults) Err() error {
+	var errs []error
+	for _, x := range r.Failed() {
+		target := x.ID
+		if target == "" {
+			target = x.Name
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", target, x.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// DeleteMany deletes the entities of req concurrently and reports the outcome
+// of each one. The error is about the request itself, e.g. a failing filter
+// list; failed deletions are in the results (see DeleteResults.Err).
+func (s *CrudService) DeleteMany(ctx context.Context, req BulkDeleteRequest) (_ DeleteResults, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.delete_many", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+
+	if req.Resource == "" {
+		return nil, errors.New("endpoint is required")
+	}
+	if !config.IsGlobalResource(req.Resource) && req.Project == "" {
+		return nil, errors.New("project is mandatory for non-project resources")
+	}
+	if req.Filter != nil && len(req.Filter.Values()) == 0 {
+		return nil, errors.New("filter has no conditions: it would delete every entity")
+	}
+
+	results := make(DeleteResults, 0, len(req.IDs)+len(req.Names))
+	seen := map[string]bool{}
+	for _, id := range req.IDs {
+		if !seen[id] {
+			seen[id] = true
+			results = append(results, DeleteResult{ID: id})
+		}
+	}
+	for _, name := range req.Names {
+		results = append(results, DeleteResult{Name: name})
+	}
+	if req.Filter != nil {
+		for item, err := range s.ListIter(ctx, ListRequest{ResourceRequest: req.ResourceRequest, Filter: req.Filter}) {
+			if err != nil {
+				return nil, fmt.Errorf("filter list failed: %w", err)
+			}
+			if id, _ := item["id"].(string); id != "" && !seen[id] {
+				seen[id] = true
+				name, _ := item["name"].(string)
+				results = append(results, DeleteResult{ID: id, Name: name})
+			}
+		}
+	}
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	workers := req.Concurrency
+	if workers <= 0 {
+		workers = DefaultBulkConcurrency
+	}
+	var (
+		mu   sync.Mutex
+		done int
+		wg   sync.WaitGroup
+		next = make(chan int)
+	)
+	for range min(workers, len(results)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				r := &results[i]
+				if r.Err = ctx.Err(); r.Err == nil {
+					dr := DeleteRequest{ResourceRequest: req.ResourceRequest, ID: r.ID, Cascade: req.Cascade}
+					if r.ID == "" {
+						dr.Name = r.Name
+					}
+					r.Err = s.Delete(ctx, dr)
+				}
+				if req.OnProgress != nil {
+					mu.Lock()
+					done++
+					n := done
+					mu.Unlock()
+					req.OnProgress(*r, n, len(results))
+				}
+			}
+		}()
+	}
+	for i := range results {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results, nil
+}
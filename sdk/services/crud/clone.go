@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+)
+
+// Copier copies data between storage paths (e.g. s3://bucket/key);
+// transfer.TransferService implements it with server-side S3 copies.
+type Copier interface {
+	Copy(ctx context.Context, src, dst string) error
+}
+
+type CloneRequest struct {
+	// Project and Resource locate the source entity
+	ResourceRequest
+
+	// ID, or else Name (latest version), is the entity to clone
+	ID   string
+	Name string
+
+	// TargetProject defaults to the source project, TargetName to the
+	// source name (a new version when the project is the same)
+	TargetProject string
+	TargetName    string
+
+	// CopyData, when set, copies the data at spec.path (s3:// paths only)
+	// to s3://<bucket>/<project>/<resource>/<id>/ in the same bucket, as
+	// an upload would, and points the copy to it; otherwise the copy
+	// refers to the data of the source
+	CopyData Copier
+
+	// IdempotencyKey makes a retried clone return the entity created the
+	// first time; empty generates a new key per call
+	IdempotencyKey string
+}
+
+// Clone creates a copy of an entity, e.g. to promote a model from a
+// staging project to production, and returns it as stored by Core. The
+// copy gets a new id; status and labels are kept, the audit fields are
+// set by Core.
+func (s *CrudService) Clone(ctx context.Context, req CloneRequest) (_ []byte, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.clone", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.Resource == "" {
+		return nil, errors.New("endpoint is required")
+	}
+	if config.IsGlobalResource(req.Resource) {
+		return nil, fmt.Errorf("%s can't be cloned", req.Resource)
+	}
+	if req.Project == "" {
+		return nil, errors.New("project is mandatory for non-project resources")
+	}
+	if req.ID == "" && req.Name == "" {
+		return nil, errors.New("you must specify id or name")
+	}
+
+	src, err := s.fetchEntities(ctx, ExportRequest{ResourceRequest: req.ResourceRequest, ID: req.ID, Name: req.Name})
+	if err != nil {
+		return nil, err
+	}
+	status := src[0]["status"]
+	e := cleanEntity(src[0], false)
+	if status != nil {
+		e["status"] = status
+	}
+
+	project, name := req.TargetProject, req.TargetName
+	if project == "" {
+		project = req.Project
+	}
+	if name == "" {
+		name, _ = e["name"].(string)
+	}
+	id := utils.UUIDv4NoDash()
+	e["id"], e["project"], e["name"] = id, project, name
+	if meta, ok := e["metadata"].(map[string]interface{}); ok {
+		meta = maps.Clone(meta)
+		if _, ok := meta["project"]; ok {
+			meta["project"] = project
+		}
+		if _, ok := meta["name"]; ok {
+			meta["name"] = name
+		}
+		e["metadata"] = meta
+	}
+
+	if req.CopyData != nil {
+		if err := cloneData(ctx, req, e, project, id); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+	key := req.IdempotencyKey
+	if key == "" {
+		key = config.NewIdempotencyKey()
+	}
+	b, _, err := s.http.Do(config.ContextWithIdempotencyKey(ctx, key), "POST", s.http.BuildURL(project, req.Resource, "", nil), body)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// cloneData copies the data at spec.path of e next to the copy and updates
+// spec.path.
+func cloneData(ctx context.Context, req CloneRequest, e map[string]interface{}, project, id string) error {
+	spec, _ := e["spec"].(map[string]interface{})
+	src, _ := spec["path"].(string)
+	if src == "" {
+		return errors.New("the entity has no spec.path to copy")
+	}
+	pp, err := utils.ParsePath(src)
+	if err != nil {
+		return err
+	}
+	if pp.Scheme != "s3" {
+		return fmt.Errorf("can't copy %s: only s3:// paths are supported", src)
+	}
+	dst := fmt.Sprintf("s3://%s/%s/%s/%s/", pp.Host, project, strings.TrimSuffix(req.Resource, "s"), id)
+	if !strings.HasSuffix(pp.Path, "/") {
+		dst += path.Base(pp.Path)
+	}
+	if err := req.CopyData.Copy(ctx, src, dst); err != nil {
+		return fmt.Errorf("failed to copy data: %w", err)
+	}
+	spec = maps.Clone(spec)
+	spec["path"] = dst
+	e["spec"] = spec
+	return nil
+}
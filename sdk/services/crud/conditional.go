@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// ErrConflict matches the ConflictError of a conditional Update or Delete.
+var ErrConflict = errors.New("entity modified concurrently")
+
+// ConflictError reports that the entity changed since the version given as
+// IfMatch was read.
+type ConflictError struct {
+	Resource string
+	ID       string
+	Expected string
+	// Current is empty when the mismatch was detected by Core
+	Current string
+	// Err is the underlying *config.CoreError, if any
+	Err error
+}
+
+func (e *ConflictError) Error() string {
+	msg := fmt.Sprintf("%s %s was modified concurrently (expected version %s", e.Resource, e.ID, e.Expected)
+	if e.Current != "" {
+		msg += ", current " + e.Current
+	}
+	return msg + ")"
+}
+
+func (e *ConflictError) Is(target error) bool { return target == ErrConflict }
+
+func (e *ConflictError) Unwrap() error { return e.Err }
+
+// ETag returns the version of an entity as returned by Get, for the IfMatch
+// of a later Update or Delete: its metadata.updated timestamp, as written
+// in the JSON (numbers are kept digit for digit).
+func ETag(entity []byte) string {
+	var m struct {
+		Metadata struct {
+			Updated json.RawMessage `json:"updated"`
+		} `json:"metadata"`
+	}
+	if json.Unmarshal(entity, &m) != nil || len(m.Metadata.Updated) == 0 || string(m.Metadata.Updated) == "null" {
+		return ""
+	}
+	var v string
+	if json.Unmarshal(m.Metadata.Updated, &v) == nil {
+		return v
+	}
+	return string(m.Metadata.Updated)
+}
+
+// checkIfMatch verifies that the entity is still at version ifMatch and
+// returns ctx with the If-Match header carrying the ETag Core sent with the
+// entity, so that Core can enforce it too.
+func (s *CrudService) checkIfMatch(ctx context.Context, rr ResourceRequest, id, ifMatch string) (context.Context, error) {
+	url := s.http.BuildURL(rr.Project, rr.Resource, id, nil)
+	var header http.Header
+	b, _, err := s.http.Do(config.ContextWithResponseHeader(ctx, &header), "GET", url, nil)
+	if err != nil {
+		return ctx, fmt.Errorf("failed to read current version: %w", err)
+	}
+	if cur := ETag(b); cur != ifMatch {
+		return ctx, &ConflictError{Resource: rr.Resource, ID: id, Expected: ifMatch, Current: cur}
+	}
+	etag := header.Get("ETag")
+	if etag == "" {
+		return ctx, nil
+	}
+	return config.ContextWithRequestOptions(ctx, config.WithHeader("If-Match", quoteETag(etag))), nil
+}
+
+// quoteETag returns etag as an entity tag, quoted unless it already is.
+func quoteETag(etag string) string {
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+// asConflict turns a 412 from Core into a ConflictError.
+func asConflict(err error, rr ResourceRequest, id, ifMatch string) error {
+	if ifMatch != "" && config.HasStatus(err, http.StatusPreconditionFailed) {
+		return &ConflictError{Resource: rr.Resource, ID: id, Expected: ifMatch, Err: err}
+	}
+	return err
+}
@@ -9,58 +9,135 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
+	"maps"
 
-	"sigs.k8s.io/yaml"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
 )
 
-func (s *CrudService) Create(ctx context.Context, req CreateRequest) error {
+// Create creates the entities of req.FilePath (see CreateAll), or the entity
+// named req.Name (e.g. a project) when there is no file. With
+// ContinueOnError the failures are joined in the error.
+func (s *CrudService) Create(ctx context.Context, req CreateRequest) (err error) {
+	if req.FilePath != "" || len(req.Data) > 0 {
+		res, err := s.CreateAll(ctx, req)
+		if err != nil {
+			return err
+		}
+		return res.Err()
+	}
+
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.create", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
 	if req.Resource == "" {
 		return errors.New("endpoint is required")
 	}
-	if req.Resource != "projects" && req.Project == "" {
+	if !config.IsGlobalResource(req.Resource) && req.Project == "" {
 		return errors.New("project is mandatory for non-project resources")
 	}
+	if req.DryRun {
+		return s.validate(ctx, req.Project, req.Resource, map[string]interface{}{"name": req.Name})
+	}
 
-	var jsonMap map[string]any
+	// caso project senza file: usa solo name
+	body, err := json.Marshal(map[string]any{"name": req.Name})
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %w", err)
+	}
 
-	if req.FilePath != "" {
-		// leggi YAML e converti in JSON -> map
-		data, err := os.ReadFile(req.FilePath)
-		if err != nil {
-			return fmt.Errorf("failed to read YAML file: %w", err)
-		}
-		jsonBytes, err := yaml.YAMLToJSON(data)
-		if err != nil {
-			return fmt.Errorf("yaml to json failed: %w", err)
+	key := req.IdempotencyKey
+	if key == "" {
+		key = config.NewIdempotencyKey()
+	}
+	url := s.http.BuildURL(req.Project, req.Resource, "", nil)
+	_, _, err = s.http.Do(config.ContextWithIdempotencyKey(ctx, key), "POST", url, body)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateAll creates the entities of the YAML documents of req.FilePath (a
+// file of documents separated by "---", a directory or a glob pattern) or
+// req.Data. Entities are created after those they may refer to: projects,
+// then secrets and artifacts, data items and models, then functions and
+// workflows, then tasks and finally runs. The resource of a document comes
+// from its key or kind, else from req.Resource; req.Project, when set,
+// overrides the project of the documents.
+//
+// It stops at the first failure unless req.ContinueOnError is set; the
+// result lists the documents processed so far either way.
+func (s *CrudService) CreateAll(ctx context.Context, req CreateRequest) (_ *ChangeSet, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.create", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	docs, err := loadDocuments(req.FilePath, req.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveResources(docs, req.Resource); err != nil {
+		return nil, err
+	}
+	sortByDependency(docs)
+
+	res := &ChangeSet{}
+	for _, doc := range docs {
+		c := Change{Source: doc.Source, Resource: doc.Resource, Action: ChangeCreated, DryRun: req.DryRun}
+		c.Name, _ = doc.Entity["name"].(string)
+		key := req.IdempotencyKey
+		if key != "" && len(docs) > 1 {
+			key += "/" + doc.Source
 		}
-		if err := json.Unmarshal(jsonBytes, &jsonMap); err != nil {
-			return fmt.Errorf("failed to parse after JSON conversion: %w", err)
+		c.ID, c.Project, c.Err = s.createDocument(ctx, req, doc, key)
+		res.Changes = append(res.Changes, c)
+		if c.Err != nil && !req.ContinueOnError {
+			return res, fmt.Errorf("%s: %w", doc.Source, c.Err)
 		}
+	}
+	return res, nil
+}
 
-		delete(jsonMap, "user")
-		if req.Resource != "projects" {
-			jsonMap["project"] = req.Project
+// createDocument creates doc and returns its id and project.
+func (s *CrudService) createDocument(ctx context.Context, req CreateRequest, doc document, key string) (id, project string, err error) {
+	e := maps.Clone(doc.Entity)
+	delete(e, "user")
+	if req.ResetID {
+		delete(e, "id")
+	}
+	if !config.IsGlobalResource(doc.Resource) {
+		if project = req.Project; project == "" {
+			project, _ = e["project"].(string)
 		}
-		if req.ResetID {
-			delete(jsonMap, "id")
+		if project == "" {
+			project = keyProject(e)
 		}
-	} else {
-		// caso project senza file: usa solo name
-		jsonMap = map[string]any{
-			"name": req.Name,
+		if project == "" {
+			return "", "", errors.New("project is mandatory for non-project resources")
 		}
+		e["project"] = project
+	}
+	if req.DryRun {
+		id, _ = e["id"].(string)
+		return id, project, s.validate(ctx, project, doc.Resource, e)
 	}
 
-	body, err := json.Marshal(jsonMap)
+	body, err := json.Marshal(e)
 	if err != nil {
-		return fmt.Errorf("failed to marshal: %w", err)
+		return "", project, fmt.Errorf("failed to marshal: %w", err)
 	}
-
-	url := s.http.BuildURL(req.Project, req.Resource, "", nil)
-	_, _, err = s.http.Do(ctx, "POST", url, body)
+	if key == "" {
+		key = config.NewIdempotencyKey()
+	}
+	url := s.http.BuildURL(project, doc.Resource, "", nil)
+	b, _, err := s.http.Do(config.ContextWithIdempotencyKey(ctx, key), "POST", url, body)
 	if err != nil {
-		return err
+		return "", project, err
 	}
-	return nil
+	var created struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(b, &created)
+	return created.ID, project, nil
 }
@@ -7,19 +7,52 @@ package crud
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 
 	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type CrudService struct {
-	http config.CoreHTTP
+	http    config.CoreHTTP
+	baseURL string
+	tracer  trace.Tracer
+
+	// noValidateEndpoint is set once Core turns out not to have the
+	// validation endpoint of dry runs
+	noValidateEndpoint atomic.Bool
+
+	// schemas caches the compiled spec schemas by "<resource>/<kind>"
+	schemas sync.Map
 }
 
-func NewCrudService(_ context.Context, conf config.Config) (*CrudService, error) {
+// NewCrudService builds the service; opts customize HTTP client, logger and
+// retries (see config.ServiceOption).
+func NewCrudService(_ context.Context, conf config.Config, opts ...config.ServiceOption) (*CrudService, error) {
 	if conf.Core.BaseURL == "" || conf.Core.APIVersion == "" {
 		return nil, errors.New("invalid core config")
 	}
+	o := config.NewServiceOptions(opts...).ForConfig(conf)
 	return &CrudService{
-		http: config.NewHTTPCore(nil, conf.Core),
+		http:    config.NewHTTPCoreWithOptions(conf.Core, o),
+		baseURL: conf.Core.BaseURL,
+		tracer:  config.Tracer(o.TracerProvider),
 	}, nil
 }
+
+// Capabilities returns the API level and features of the connected Core, so
+// callers can degrade gracefully instead of failing on older releases.
+func (s *CrudService) Capabilities(ctx context.Context) (*config.Capabilities, error) {
+	return config.FetchCapabilities(ctx, s.http, s.baseURL)
+}
+
+// CompatibilityCheck fetches the Core version and compares it with the range
+// supported by the SDK, warning or failing according to policy.
+func (s *CrudService) CompatibilityCheck(ctx context.Context, policy config.CompatPolicy) error {
+	caps, err := s.Capabilities(ctx)
+	if err != nil {
+		return err
+	}
+	return caps.CompatibilityCheck(policy)
+}
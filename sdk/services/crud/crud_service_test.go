@@ -0,0 +1,1133 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/dhcoretest"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/s3test"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/crud"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/transfer"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newOfflineService(t *testing.T) (*crud.CrudService, *dhcoretest.Server) {
+	t.Helper()
+	srv := dhcoretest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Token = "test-token"
+
+	svc, err := crud.NewCrudService(context.Background(), srv.Config())
+	if err != nil {
+		t.Fatalf("failed to init sdk: %v", err)
+	}
+	return svc, srv
+}
+
+func TestCrudLifecycleOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+
+	manifest := filepath.Join(t.TempDir(), "artifact.yaml")
+	if err := os.WriteFile(manifest, []byte("kind: artifact\nname: dataset\nuser: someone\nspec:\n  path: s3://datalake/demo/dataset.csv\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.Create(ctx, crud.CreateRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"},
+		FilePath:        manifest,
+	}); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	stored := srv.List("demo", "artifacts")
+	if len(stored) != 1 {
+		t.Fatalf("expected 1 artifact, got %d", len(stored))
+	}
+	if _, ok := stored[0]["user"]; ok {
+		t.Fatalf("user field should be stripped on create")
+	}
+	id := stored[0]["id"].(string)
+
+	body, _, err := svc.Get(ctx, crud.GetRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"},
+		Name:            "dataset",
+	})
+	if err != nil {
+		t.Fatalf("get by name failed: %v", err)
+	}
+	var page map[string]interface{}
+	if err := json.Unmarshal(body, &page); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := page["content"].([]interface{}); len(content) != 1 {
+		t.Fatalf("expected latest version only, got %v", page["content"])
+	}
+
+	stored[0]["spec"] = map[string]interface{}{"path": "s3://datalake/demo/other.csv"}
+	update, _ := json.Marshal(stored[0])
+	if err := svc.Update(ctx, crud.UpdateRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"},
+		ID:              id,
+		Body:            update,
+	}); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	got, _ := srv.Get("demo", "artifacts", id)
+	if got["spec"].(map[string]interface{})["path"] != "s3://datalake/demo/other.csv" {
+		t.Fatalf("update not applied: %v", got["spec"])
+	}
+
+	if err := svc.Delete(ctx, crud.DeleteRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"},
+		Name:            "dataset",
+	}); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if left := srv.List("demo", "artifacts"); len(left) != 0 {
+		t.Fatalf("expected no artifacts after delete, got %d", len(left))
+	}
+
+	if _, status, err := svc.Get(ctx, crud.GetRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"},
+		ID:              id,
+	}); err == nil || status != 404 {
+		t.Fatalf("expected 404 after delete, got status %d err %v", status, err)
+	}
+}
+
+func TestListAllPagesOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	srv.PageSize = 3
+	for i := 0; i < 8; i++ {
+		srv.Add("demo", "functions", map[string]interface{}{"name": "fn", "kind": "python"})
+	}
+
+	elements, totalPages, err := svc.ListAllPages(context.Background(), crud.ListRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "functions"},
+	})
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(elements) != 8 || totalPages != 3 {
+		t.Fatalf("got %d elements in %d pages, want 8 in 3", len(elements), totalPages)
+	}
+}
+
+func TestCapabilitiesOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+
+	caps, err := svc.Capabilities(ctx)
+	if err != nil {
+		t.Fatalf("capabilities failed: %v", err)
+	}
+	if caps.APILevel != 10 || caps.CoreVersion != "0.0.0-test" {
+		t.Fatalf("unexpected capabilities %+v", caps)
+	}
+	// a feature of a newer Core release
+	search := config.Feature("test-search")
+	config.SetFeatureLevel(search, config.LevelRange{Min: 11})
+	if !caps.Supports(config.FeatureLogs) || caps.Supports(search) {
+		t.Fatalf("unexpected feature support at level %d", caps.APILevel)
+	}
+	if err := caps.CheckLevel(config.LevelRange{Min: 12}); err == nil {
+		t.Fatal("expected level 10 to be below 12")
+	}
+
+	srv.SetWellKnown(map[string]interface{}{"dhcore_api_level": 12})
+	if caps, err = svc.Capabilities(ctx); err != nil || !caps.Supports(search) {
+		t.Fatalf("expected search at level 12, got %+v (%v)", caps, err)
+	}
+	// the table may be adjusted while in use
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		config.SetFeatureLevel(search, config.LevelRange{Min: 11, Max: 12})
+	}()
+	_ = caps.Supports(search)
+	<-done
+
+	srv.SetWellKnown(map[string]interface{}{"dhcore_api_level": ""})
+	if caps, err = svc.Capabilities(ctx); err != nil || caps.APILevel != 0 || caps.Supports(config.FeatureGet) {
+		t.Fatalf("unknown level must disable features, got %+v (%v)", caps, err)
+	}
+
+	// the test server advertises 0.0.0-test, outside the supported range
+	if err := svc.CompatibilityCheck(ctx, config.CompatError); err == nil {
+		t.Fatal("expected a version mismatch")
+	}
+	srv.SetWellKnown(map[string]interface{}{"dhcore_version": "0.12.0"})
+	if err := svc.CompatibilityCheck(ctx, config.CompatError); err != nil {
+		t.Fatalf("expected 0.12.0 to be supported: %v", err)
+	}
+}
+
+type countingTransport struct{ n int }
+
+func (c *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.n++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestCrudServiceWithHTTPClient(t *testing.T) {
+	srv := dhcoretest.NewServer()
+	defer srv.Close()
+	rt := &countingTransport{}
+
+	svc, err := crud.NewCrudService(context.Background(), srv.Config(), config.WithHTTPClient(&http.Client{Transport: rt}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Capabilities(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if rt.n != 1 {
+		t.Fatalf("expected the custom client to be used, got %d calls", rt.n)
+	}
+}
+
+func TestCoreErrorPropagates(t *testing.T) {
+	svc, _ := newOfflineService(t)
+	_, _, err := svc.Get(context.Background(), crud.GetRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"},
+		ID:              "missing",
+	})
+	if !config.IsNotFound(err) {
+		t.Fatalf("expected a not found CoreError, got %v", err)
+	}
+}
+
+func TestCreateRetryIsIdempotent(t *testing.T) {
+	srv := dhcoretest.NewServer()
+	defer srv.Close()
+
+	// the first create reaches Core but its response is lost
+	lost := false
+	drop := func(next http.RoundTripper) http.RoundTripper {
+		return config.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(r)
+			if err == nil && r.Method == "POST" && !lost {
+				lost = true
+				resp.Body.Close()
+				return &http.Response{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway",
+					Header: http.Header{}, Body: http.NoBody, Request: r}, nil
+			}
+			return resp, err
+		})
+	}
+	svc, err := crud.NewCrudService(context.Background(), srv.Config(),
+		config.WithMiddleware(drop),
+		config.WithRetryPolicy(config.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Create(context.Background(), crud.CreateRequest{
+		ResourceRequest: crud.ResourceRequest{Resource: "projects"},
+		Name:            "demo",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.List("", "projects")); n != 1 {
+		t.Fatalf("expected a single project, got %d", n)
+	}
+	var keys []string
+	for _, r := range srv.Requests() {
+		if r.Method == "POST" {
+			keys = append(keys, r.Header.Get(config.IdempotencyKeyHeader))
+		}
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Fatalf("expected the same key on both attempts, got %q", keys)
+	}
+}
+
+func TestTracingSpans(t *testing.T) {
+	srv := dhcoretest.NewServer()
+	defer srv.Close()
+	rec := tracetest.NewSpanRecorder()
+	conf := srv.Config()
+	conf.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+
+	svc, err := crud.NewCrudService(context.Background(), conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = svc.Get(context.Background(), crud.GetRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"},
+		ID:              "missing",
+	})
+	if err == nil {
+		t.Fatal("expected not found")
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	call, op := spans[0], spans[1]
+	if op.Name() != "crud.get" || call.Name() != "core GET" {
+		t.Fatalf("unexpected spans %q, %q", op.Name(), call.Name())
+	}
+	if call.Parent().SpanID() != op.SpanContext().SpanID() {
+		t.Fatal("core call should be a child of the operation")
+	}
+	attrs := map[string]string{}
+	for _, kv := range op.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["dhcore.project"] != "demo" || attrs["dhcore.resource"] != "artifacts" || attrs["http.response.status_code"] != "404" {
+		t.Fatalf("unexpected attributes %v", attrs)
+	}
+	if op.Status().Code != codes.Error {
+		t.Fatalf("expected error status, got %v", op.Status())
+	}
+}
+
+func TestRequestOptions(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	id := srv.Add("demo", "artifacts", map[string]interface{}{"name": "a", "kind": "artifact"})
+
+	if _, _, err := svc.Get(context.Background(), crud.GetRequest{
+		ResourceRequest: crud.ResourceRequest{
+			Project:  "demo",
+			Resource: "artifacts",
+			Options: []config.RequestOption{
+				config.WithHeader("X-Audit", "job-42"),
+				config.WithQueryParam("experimental", "on"),
+			},
+		},
+		ID: id,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	reqs := srv.Requests()
+	last := reqs[len(reqs)-1]
+	if last.Header.Get("X-Audit") != "job-42" || last.Query.Get("experimental") != "on" {
+		t.Fatalf("options not applied: %v %v", last.Header, last.Query)
+	}
+}
+
+func TestPatchOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	id := srv.Add("demo", "artifacts", map[string]interface{}{
+		"name": "a", "kind": "artifact",
+		"spec": map[string]interface{}{"path": "s3://b/a", "size": 1.0},
+	})
+	rr := crud.ResourceRequest{Project: "demo", Resource: "artifacts"}
+
+	if err := svc.Patch(ctx, crud.PatchRequest{ResourceRequest: rr, ID: id,
+		Patch: []byte(`{"spec":{"size":null,"src":"local"}}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Patch(ctx, crud.PatchRequest{ResourceRequest: rr, ID: id, Type: crud.JSONPatch,
+		Patch: []byte(`[{"op":"replace","path":"/name","value":"b"}]`)}); err != nil {
+		t.Fatal(err)
+	}
+	e, _ := srv.Get("demo", "artifacts", id)
+	spec := e["spec"].(map[string]interface{})
+	if e["name"] != "b" || spec["path"] != "s3://b/a" || spec["src"] != "local" || spec["size"] != nil {
+		t.Fatalf("unexpected entity after patch: %v", e)
+	}
+	reqs := srv.Requests()
+	if ct := reqs[len(reqs)-1].Header.Get("Content-Type"); ct != string(crud.JSONPatch) {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	if err := svc.Patch(ctx, crud.PatchRequest{ResourceRequest: rr, ID: id, Patch: []byte(`[]`)}); err == nil {
+		t.Fatal("expected a merge patch to be rejected when not an object")
+	}
+}
+
+func TestConditionalUpdate(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	rr := crud.ResourceRequest{Project: "demo", Resource: "artifacts"}
+	id := srv.Add("demo", "artifacts", map[string]interface{}{"name": "a", "kind": "artifact"})
+
+	b, _, err := svc.Get(ctx, crud.GetRequest{ResourceRequest: rr, ID: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	etag := crud.ETag(b)
+	if etag == "" {
+		t.Fatal("expected an etag")
+	}
+
+	// another user updates the entity in the meantime
+	time.Sleep(time.Millisecond)
+	if err := svc.Update(ctx, crud.UpdateRequest{ResourceRequest: rr, ID: id,
+		Body: []byte(`{"name":"a","kind":"artifact","spec":{"by":"other"}}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	err = svc.Update(ctx, crud.UpdateRequest{ResourceRequest: rr, ID: id, IfMatch: etag,
+		Body: []byte(`{"name":"a","kind":"artifact","spec":{"by":"me"}}`)})
+	var conflict *crud.ConflictError
+	if !errors.Is(err, crud.ErrConflict) || !errors.As(err, &conflict) || conflict.Expected != etag {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	if err := svc.Delete(ctx, crud.DeleteRequest{ResourceRequest: rr, ID: id, IfMatch: etag}); !errors.Is(err, crud.ErrConflict) {
+		t.Fatalf("expected a conflict on delete, got %v", err)
+	}
+	if e, _ := srv.Get("demo", "artifacts", id); e["spec"].(map[string]interface{})["by"] != "other" {
+		t.Fatalf("the other change must be kept: %v", e)
+	}
+
+	// with the fresh version the update goes through, with If-Match
+	b, _, _ = svc.Get(ctx, crud.GetRequest{ResourceRequest: rr, ID: id})
+	etag = srv.ETag("demo", "artifacts", id)
+	if err := svc.Update(ctx, crud.UpdateRequest{ResourceRequest: rr, ID: id, IfMatch: crud.ETag(b),
+		Body: []byte(`{"name":"a","kind":"artifact","spec":{"by":"me"}}`)}); err != nil {
+		t.Fatal(err)
+	}
+	reqs := srv.Requests()
+	if got := reqs[len(reqs)-1].Header.Get("If-Match"); got != `"`+etag+`"` {
+		t.Fatalf("expected the quoted ETag of Core as If-Match, got %q", got)
+	}
+
+	// numeric timestamps are kept as written
+	if got := crud.ETag([]byte(`{"metadata":{"updated":1729000000123}}`)); got != "1729000000123" {
+		t.Fatalf("unexpected numeric etag %q", got)
+	}
+}
+
+func TestVersionsOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+
+	for i, created := range []string{"2025-01-02T00:00:00Z", "2025-01-03T00:00:00Z", "2025-01-01T00:00:00Z"} {
+		srv.Add("demo", "artifacts", map[string]interface{}{
+			"id": fmt.Sprintf("v%d", i), "name": "dataset",
+			"metadata": map[string]interface{}{"created": created},
+		})
+	}
+	srv.Add("demo", "artifacts", map[string]interface{}{"id": "other", "name": "other"})
+
+	versions, err := svc.ListVersions(ctx, "demo", "artifacts", "dataset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, v := range versions {
+		ids = append(ids, v.ID)
+	}
+	if fmt.Sprint(ids) != "[v1 v0 v2]" || !versions[0].Latest || versions[1].Latest {
+		t.Fatalf("unexpected versions %+v", versions)
+	}
+
+	b, _, err := svc.GetVersion(ctx, "demo", "artifacts", "dataset", "v2")
+	if err != nil || !strings.Contains(string(b), `"v2"`) {
+		t.Fatalf("GetVersion: %s, %v", b, err)
+	}
+	if _, _, err := svc.GetVersion(ctx, "demo", "artifacts", "dataset", "other"); err == nil {
+		t.Fatal("expected an error for a version of another entity")
+	}
+}
+
+func TestListFilterOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+
+	add := func(name, kind, state, created string, labels ...interface{}) {
+		srv.Add("demo", "runs", map[string]interface{}{
+			"name": name, "kind": kind, "user": "alice",
+			"status":   map[string]interface{}{"state": state},
+			"metadata": map[string]interface{}{"created": created, "labels": labels},
+		})
+	}
+	add("train-a", "python+run", "COMPLETED", "2025-01-01T00:00:00Z", "ml")
+	add("train-b", "python+run", "COMPLETED", "2025-02-01T00:00:00Z", "ml", "gpu")
+	add("train-c", "python+run", "ERROR", "2025-02-01T00:00:00Z", "ml", "gpu")
+	add("etl", "container+run", "COMPLETED", "2025-02-01T00:00:00Z", "ml", "gpu")
+
+	filter := crud.NewFilter().NameContains("train").Kind("python+run").State("COMPLETED").
+		User("alice").Labels("ml", "gpu").CreatedAfter(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))
+	items, _, err := svc.ListAllPages(ctx, crud.ListRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "runs"},
+		Params:          map[string]string{"state": "ERROR", "size": "1"},
+		Filter:          filter,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].(map[string]interface{})["name"] != "train-b" {
+		t.Fatalf("unexpected items %v", items)
+	}
+
+	q := srv.Requests()[0].Query
+	if q.Get("created_after") != "2025-01-15T00:00:00Z" || len(q["labels"]) != 2 || q.Get("state") != "COMPLETED" {
+		t.Fatalf("unexpected query %v", q)
+	}
+}
+
+func TestListIterOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	srv.PageSize = 2
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		srv.Add("demo", "artifacts", map[string]interface{}{"name": fmt.Sprintf("a%d", i)})
+	}
+	req := crud.ListRequest{ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"}}
+
+	var names []string
+	for item, err := range svc.ListIter(ctx, req) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, item["name"].(string))
+	}
+	if fmt.Sprint(names) != "[a0 a1 a2 a3 a4]" {
+		t.Fatalf("unexpected items %v", names)
+	}
+
+	// stopping early doesn't fetch the remaining pages
+	before := len(srv.Requests())
+	for range svc.ListIter(ctx, req) {
+		break
+	}
+	if n := len(srv.Requests()) - before; n != 1 {
+		t.Fatalf("expected 1 page fetched, got %d", n)
+	}
+
+	srv.Fail("GET", "/api/v1/-/demo/artifacts", http.StatusInternalServerError)
+	var gotErr error
+	for _, err := range svc.ListIter(ctx, req) {
+		gotErr = err
+	}
+	if !config.HasStatus(gotErr, http.StatusInternalServerError) {
+		t.Fatalf("expected the core error, got %v", gotErr)
+	}
+}
+
+func TestListAllPagesConcurrentOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	srv.PageSize = 3
+	ctx := context.Background()
+	var want []string
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("a%02d", i)
+		want = append(want, name)
+		srv.Add("demo", "artifacts", map[string]interface{}{"name": name})
+	}
+
+	items, total, err := svc.ListAllPages(ctx, crud.ListRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"},
+		Concurrency:     4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, it := range items {
+		got = append(got, it.(map[string]interface{})["name"].(string))
+	}
+	if total != 7 || fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("unexpected list (%d pages): %v", total, got)
+	}
+}
+
+func TestDeleteManyOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	for i := 0; i < 6; i++ {
+		kind := "artifact"
+		if i >= 4 {
+			kind = "dataset"
+		}
+		srv.Add("demo", "artifacts", map[string]interface{}{"id": fmt.Sprintf("a%d", i), "name": fmt.Sprintf("n%d", i), "kind": kind})
+	}
+
+	var progress []int
+	var mu sync.Mutex
+	results, err := svc.DeleteMany(ctx, crud.BulkDeleteRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"},
+		IDs:             []string{"a0", "missing"},
+		Names:           []string{"n1"},
+		Filter:          crud.NewFilter().Kind("dataset"),
+		Concurrency:     3,
+		OnProgress: func(_ crud.DeleteResult, done, total int) {
+			mu.Lock()
+			defer mu.Unlock()
+			progress = append(progress, done)
+			if total != 5 {
+				t.Errorf("total = %d", total)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 5 || len(progress) != 5 {
+		t.Fatalf("unexpected results %+v, progress %v", results, progress)
+	}
+	failed := results.Failed()
+	if len(failed) != 1 || failed[0].ID != "missing" || !config.IsNotFound(failed[0].Err) || results.Err() == nil {
+		t.Fatalf("unexpected failures %+v", failed)
+	}
+	var left []string
+	for _, e := range srv.List("demo", "artifacts") {
+		left = append(left, e["id"].(string))
+	}
+	if fmt.Sprint(left) != "[a2 a3]" {
+		t.Fatalf("unexpected entities left %v", left)
+	}
+
+	// an empty filter matches everything: it is refused
+	if _, err := svc.DeleteMany(ctx, crud.BulkDeleteRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"},
+		Filter:          crud.NewFilter(),
+	}); err == nil {
+		t.Fatal("expected an empty filter to be refused")
+	}
+	if n := len(srv.List("demo", "artifacts")); n != 2 {
+		t.Fatalf("an empty filter deleted entities: %d left", n)
+	}
+}
+
+const applyManifest = `kind: project
+name: demo
+metadata:
+  description: demo project
+---
+kind: artifact
+name: dataset
+project: demo
+spec:
+  path: s3://datalake/demo/dataset.csv
+---
+# a function: its kind doesn't tell the resource
+kind: python
+key: store://demo/function/python/train
+name: train
+spec:
+  python_version: PYTHON3_10
+`
+
+func TestApplyOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	srv.Add("", "projects", map[string]interface{}{"name": "demo", "metadata": map[string]interface{}{"description": "demo project"}})
+	srv.Add("demo", "artifacts", map[string]interface{}{
+		"id": "a1", "name": "dataset", "kind": "artifact",
+		"spec": map[string]interface{}{"path": "s3://old/dataset.csv"},
+	})
+
+	res, err := svc.Apply(ctx, crud.ApplyRequest{Data: []byte(applyManifest)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actions []string
+	for _, c := range res.Changes {
+		actions = append(actions, c.Resource+":"+string(c.Action))
+	}
+	if fmt.Sprint(actions) != "[projects:unchanged artifacts:updated functions:created]" {
+		t.Fatalf("unexpected changes %v", actions)
+	}
+	a, _ := srv.Get("demo", "artifacts", "a1")
+	if a["spec"].(map[string]interface{})["path"] != "s3://datalake/demo/dataset.csv" || a["kind"] != "artifact" {
+		t.Fatalf("artifact not updated: %v", a)
+	}
+	if fns := srv.List("demo", "functions"); len(fns) != 1 || fns[0]["id"] != res.Changes[2].ID {
+		t.Fatalf("function not created: %v", fns)
+	}
+
+	// applying again changes nothing
+	if res, err = svc.Apply(ctx, crud.ApplyRequest{Data: []byte(applyManifest)}); err != nil {
+		t.Fatal(err)
+	}
+	if res.Count(crud.ChangeUnchanged) != 3 {
+		t.Fatalf("unexpected changes %+v", res.Changes)
+	}
+
+	if _, err := svc.Apply(ctx, crud.ApplyRequest{Data: []byte("kind: python\nname: f\nproject: demo\n")}); err == nil {
+		t.Fatal("expected an error for a document of unknown resource")
+	}
+}
+
+func TestCreateAllOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"a-task.yaml":     "kind: python+job\nname: train-job\nspec:\n  function: python://demo/train\n",
+		"b-multi.yml":     "kind: python\nname: train\n---\n# comment only\n---\nkind: artifact\nname: dataset\n",
+		"c-project.yaml":  "kind: project\nname: demo\n",
+		"ignored.txt":     "kind: artifact\nname: nope\n",
+		"sub/model.yaml":  "kind: model\nname: clf\n",
+		"sub/broken.yaml": "kind: artifact\nname: [unterminated\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := crud.CreateRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "functions"},
+		FilePath:        filepath.Join(dir, "*.y*ml"),
+		DryRun:          true,
+	}
+
+	res, err := svc.CreateAll(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	for _, c := range res.Changes {
+		order = append(order, c.Resource+"/"+c.Name)
+	}
+	if fmt.Sprint(order) != "[projects/demo artifacts/dataset functions/train tasks/train-job]" {
+		t.Fatalf("unexpected order %v", order)
+	}
+	for _, r := range srv.Requests() {
+		if !strings.HasSuffix(r.Path, "/validate") {
+			t.Fatalf("dry run called core: %s %s", r.Method, r.Path)
+		}
+	}
+
+	req.DryRun = false
+	if _, err := svc.CreateAll(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.List("", "projects")) != 1 || len(srv.List("demo", "tasks")) != 1 || len(srv.List("demo", "functions")) != 1 {
+		t.Fatal("entities not created")
+	}
+
+	// a directory is read recursively; the broken file fails the load
+	if _, err := svc.CreateAll(ctx, crud.CreateRequest{FilePath: dir}); err == nil || !strings.Contains(err.Error(), "broken.yaml") {
+		t.Fatalf("expected a parse error, got %v", err)
+	}
+
+	// the project exists now: go on after its failure
+	srv.Fail("POST", "/api/v1/projects", http.StatusConflict)
+	req.ContinueOnError = true
+	res, err = svc.CreateAll(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count(crud.ChangeCreated) != 3 || !config.IsConflict(res.Err()) {
+		t.Fatalf("unexpected result %+v", res.Changes)
+	}
+	if err := svc.Create(ctx, req); !config.IsConflict(err) {
+		t.Fatalf("Create should report the failure, got %v", err)
+	}
+}
+
+func TestDryRunOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	srv.Add("", "projects", map[string]interface{}{"name": "demo"})
+	id := srv.Add("demo", "artifacts", map[string]interface{}{
+		"name": "dataset", "kind": "artifact", "spec": map[string]interface{}{"path": "s3://old"},
+	})
+
+	// the fake core has no validation endpoint: the checks are client-side
+	res, err := svc.Apply(ctx, crud.ApplyRequest{Data: []byte(applyManifest), DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count(crud.ChangeUpdated) != 2 || res.Count(crud.ChangeCreated) != 1 || !res.Changes[1].DryRun {
+		t.Fatalf("unexpected changes %+v", res.Changes)
+	}
+	if a, _ := srv.Get("demo", "artifacts", id); a["spec"].(map[string]interface{})["path"] != "s3://old" {
+		t.Fatalf("dry run updated the artifact: %v", a)
+	}
+	if len(srv.List("demo", "functions")) != 0 {
+		t.Fatal("dry run created the function")
+	}
+	validations := 0
+	for _, r := range srv.Requests() {
+		if strings.HasSuffix(r.Path, "/validate") {
+			validations++
+		}
+	}
+	if validations != 1 {
+		t.Fatalf("the missing endpoint should be probed once, got %d calls", validations)
+	}
+
+	bad := "kind: artifact\nname: my dataset\nproject: demo\nspec: s3://x\n"
+	_, err = svc.Apply(ctx, crud.ApplyRequest{Data: []byte(bad), DryRun: true})
+	var verr *crud.ValidationError
+	if !errors.As(err, &verr) || len(verr.Problems) != 2 {
+		t.Fatalf("expected 2 validation problems, got %v", err)
+	}
+
+	up := crud.UpdateRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"},
+		ID:              id, Body: []byte(`{"name":"dataset","kind":"artifact","spec":{"path":"s3://new"}}`), DryRun: true,
+	}
+	if err := svc.Update(ctx, up); err != nil {
+		t.Fatal(err)
+	}
+	if a, _ := srv.Get("demo", "artifacts", id); a["spec"].(map[string]interface{})["path"] != "s3://old" {
+		t.Fatalf("dry run updated the artifact: %v", a)
+	}
+	up.ID = "missing"
+	if err := svc.Update(ctx, up); !config.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	// a core with the endpoint has the last word
+	svc2, srv2 := newOfflineService(t)
+	srv2.Fail("POST", "/api/v1/-/demo/artifacts/validate", http.StatusBadRequest)
+	err = svc2.Create(ctx, crud.CreateRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "artifacts"},
+		Data:            []byte("kind: artifact\nname: dataset\n"), DryRun: true,
+	})
+	if !errors.As(err, &verr) || !config.HasStatus(verr, http.StatusBadRequest) {
+		t.Fatalf("expected a validation error from core, got %v", err)
+	}
+	if len(srv2.List("demo", "artifacts")) != 0 {
+		t.Fatal("dry run created the artifact")
+	}
+}
+
+func TestExportOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	for _, name := range []string{"model-b", "model-a"} {
+		srv.Add("demo", "models", map[string]interface{}{
+			"name": name, "kind": "sklearn", "user": "alice",
+			"metadata": map[string]interface{}{"description": "a model", "created_by": "alice", "labels": []interface{}{"x"}},
+			"spec":     map[string]interface{}{"path": "s3://models/" + name, "framework": "sklearn"},
+			"status":   map[string]interface{}{"state": "READY"},
+		})
+	}
+	req := crud.ExportRequest{ResourceRequest: crud.ResourceRequest{Project: "demo", Resource: "models"}}
+
+	res, err := svc.Export(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Documents) != 2 || res.Documents[0].Name != "model-a" || res.Documents[0].ID == "" {
+		t.Fatalf("unexpected documents %+v", res.Documents)
+	}
+	want := "kind: sklearn\nname: model-a\nproject: demo\nmetadata:\n  description: a model\n  labels:\n  - x\n" +
+		"spec:\n  framework: sklearn\n  path: s3://models/model-a\n"
+	if got := string(res.Documents[0].YAML); got != want {
+		t.Fatalf("unexpected yaml:\n%s", got)
+	}
+	bundle := res.Bundle()
+	if strings.Count(string(bundle), "---\n") != 1 {
+		t.Fatalf("unexpected bundle:\n%s", bundle)
+	}
+
+	// the bundle is re-importable, e.g. in another project
+	if err := svc.Create(ctx, crud.CreateRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "copy", Resource: "models"},
+		Data:            bundle,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if models := srv.List("copy", "models"); len(models) != 2 {
+		t.Fatalf("bundle not imported: %v", models)
+	}
+
+	req.Name, req.KeepIDs = "model-b", true
+	if res, err = svc.Export(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Documents) != 1 || !strings.HasPrefix(string(res.Documents[0].YAML), "id: "+res.Documents[0].ID+"\nkey: ") {
+		t.Fatalf("unexpected export %+v", res.Documents)
+	}
+	req.Name = "missing"
+	if _, err := svc.Export(ctx, req); err == nil {
+		t.Fatal("expected an error for a missing entity")
+	}
+}
+
+func TestCloneOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	store := s3test.NewServer()
+	t.Cleanup(store.Close)
+	cfg := srv.Config()
+	cfg.S3 = store.Config()
+	ts, err := transfer.NewTransferService(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.PutObject("datalake", "staging/model/m1/model.pkl", []byte("weights"))
+	srv.Add("staging", "models", map[string]interface{}{
+		"id": "m1", "name": "clf", "kind": "sklearn",
+		"metadata": map[string]interface{}{"labels": []interface{}{"candidate"}},
+		"spec":     map[string]interface{}{"path": "s3://datalake/staging/model/m1/model.pkl"},
+		"status":   map[string]interface{}{"state": "READY"},
+	})
+	req := crud.CloneRequest{
+		ResourceRequest: crud.ResourceRequest{Project: "staging", Resource: "models"},
+		Name:            "clf",
+		TargetProject:   "prod",
+		CopyData:        ts,
+	}
+
+	b, err := svc.Clone(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	path := m["spec"].(map[string]interface{})["path"]
+	if m["project"] != "prod" || m["name"] != "clf" || m["id"] == "m1" ||
+		path != "s3://datalake/prod/model/"+m["id"].(string)+"/model.pkl" ||
+		m["status"].(map[string]interface{})["state"] != "READY" {
+		t.Fatalf("unexpected clone %v", m)
+	}
+	if obj, ok := store.GetObject("datalake", "prod/model/"+m["id"].(string)+"/model.pkl"); !ok || string(obj.Data) != "weights" {
+		t.Fatal("model data not copied")
+	}
+
+	// without CopyData the clone shares the data of the source
+	req.CopyData, req.TargetName = nil, "clf-shared"
+	if b, err = svc.Clone(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "s3://datalake/staging/model/m1/model.pkl") || len(srv.List("prod", "models")) != 2 {
+		t.Fatalf("unexpected clone %s", b)
+	}
+}
+
+func TestLabelsOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	rr := crud.ResourceRequest{Project: "demo", Resource: "artifacts"}
+	id := srv.Add("demo", "artifacts", map[string]interface{}{
+		"name": "a", "metadata": map[string]interface{}{"labels": []interface{}{"raw"}, "description": "kept"},
+	})
+	srv.Add("demo", "artifacts", map[string]interface{}{"name": "b", "metadata": map[string]interface{}{"labels": []interface{}{"raw", "deprecated"}}})
+	srv.Add("demo", "artifacts", map[string]interface{}{"name": "c"})
+
+	// the patch is conditional on the ETag of Core, not on metadata.updated;
+	// lookups by name read it back by id
+	ifMatch := func(etag string) {
+		t.Helper()
+		reqs := srv.Requests()
+		if got := reqs[len(reqs)-1].Header.Get("If-Match"); got != `"`+etag+`"` {
+			t.Fatalf("expected If-Match %q, got %q", `"`+etag+`"`, got)
+		}
+	}
+	etag := srv.ETag("demo", "artifacts", id)
+	labels, err := svc.Label(ctx, crud.LabelRequest{ResourceRequest: rr, Name: "a", Labels: []string{"team-a", "raw"}})
+	if err != nil || fmt.Sprint(labels) != "[raw team-a]" {
+		t.Fatalf("label: %v, %v", labels, err)
+	}
+	ifMatch(etag)
+	a, _ := srv.Get("demo", "artifacts", id)
+	if a["metadata"].(map[string]interface{})["description"] != "kept" {
+		t.Fatalf("metadata lost: %v", a)
+	}
+	etag = srv.ETag("demo", "artifacts", id)
+	if labels, err = svc.Unlabel(ctx, crud.LabelRequest{ResourceRequest: rr, ID: id, Labels: []string{"raw"}}); err != nil || fmt.Sprint(labels) != "[team-a]" {
+		t.Fatalf("unlabel: %v, %v", labels, err)
+	}
+	ifMatch(etag)
+	if labels, err = svc.Labels(ctx, crud.GetRequest{ResourceRequest: rr, ID: id}); err != nil || fmt.Sprint(labels) != "[team-a]" {
+		t.Fatalf("labels: %v, %v", labels, err)
+	}
+
+	items, _, err := svc.ListAllPages(ctx, crud.ListRequest{ResourceRequest: rr, LabelSelector: "raw,!deprecated"})
+	if err != nil || len(items) != 0 {
+		t.Fatalf("unexpected items %v, %v", items, err)
+	}
+	items, _, err = svc.ListAllPages(ctx, crud.ListRequest{ResourceRequest: rr, LabelSelector: "!deprecated"})
+	if err != nil || len(items) != 2 {
+		t.Fatalf("unexpected items %v, %v", items, err)
+	}
+	if _, err := crud.ParseLabelSelector("a,!"); err == nil {
+		t.Fatal("expected an error for an empty exclusion")
+	}
+
+	srv.Fail("PATCH", "/api/v1/-/demo/artifacts/"+id, http.StatusPreconditionFailed)
+	_, err = svc.Label(ctx, crud.LabelRequest{ResourceRequest: rr, ID: id, Labels: []string{"new"}})
+	if !errors.Is(err, crud.ErrConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+}
+
+func TestFieldsOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	rr := crud.ResourceRequest{Project: "demo", Resource: "functions"}
+	id := srv.Add("demo", "functions", map[string]interface{}{
+		"name": "train", "kind": "python", "spec": map[string]interface{}{"source": strings.Repeat("x", 1024)},
+	})
+	fields := []string{"id", "name", "status.state", "metadata.missing"}
+
+	items, _, err := svc.ListAllPages(ctx, crud.ListRequest{ResourceRequest: rr, Fields: fields})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("[map[id:%s name:train status:map[state:CREATED]]]", id)
+	if fmt.Sprint(items) != want {
+		t.Fatalf("unexpected items %v", items)
+	}
+	reqs := srv.Requests()
+	if got := reqs[len(reqs)-1].Query.Get(crud.FieldsParam); got != "id,name,status.state,metadata.missing" {
+		t.Fatalf("fields not sent: %q", got)
+	}
+
+	b, _, err := svc.Get(ctx, crud.GetRequest{ResourceRequest: rr, ID: id, Fields: []string{"kind"}})
+	if err != nil || string(b) != `{"kind":"python"}` {
+		t.Fatalf("get: %s, %v", b, err)
+	}
+	b, _, err = svc.Get(ctx, crud.GetRequest{ResourceRequest: rr, Name: "train", Fields: []string{"name"}})
+	if err != nil || !strings.Contains(string(b), `"content":[{"name":"train"}]`) {
+		t.Fatalf("get by name: %s, %v", b, err)
+	}
+}
+
+func TestListSortOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	rr := crud.ResourceRequest{Project: "demo", Resource: "artifacts"}
+	for i, name := range []string{"b", "a", "b", "c"} {
+		srv.Add("demo", "artifacts", map[string]interface{}{
+			"id": fmt.Sprint(i), "name": name,
+			"metadata": map[string]interface{}{"created": fmt.Sprintf("2025-01-0%dT00:00:00Z", i+1)},
+		})
+	}
+	srv.PageSize = 2
+
+	items, _, err := svc.ListAllPages(ctx, crud.ListRequest{
+		ResourceRequest: rr,
+		Params:          map[string]string{"sort": "ignored,asc"},
+		Sort:            []crud.Sort{crud.Asc(crud.SortName), crud.Desc(crud.SortCreated)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, it := range items {
+		ids = append(ids, it.(map[string]interface{})["id"].(string))
+	}
+	if fmt.Sprint(ids) != "[1 2 0 3]" {
+		t.Fatalf("unexpected order %v", ids)
+	}
+	reqs := srv.Requests()
+	if got := reqs[len(reqs)-1].Query["sort"]; fmt.Sprint(got) != "[name,asc created,desc]" {
+		t.Fatalf("unexpected sort params %v", got)
+	}
+}
+
+func TestSchemasOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	srv.SetSchema("FUNCTION", "python", map[string]interface{}{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"type":     "object",
+		"required": []interface{}{"source"},
+		"properties": map[string]interface{}{
+			"source": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"code"},
+				"properties": map[string]interface{}{
+					"code": map[string]interface{}{"type": "string"},
+				},
+			},
+			"python_version": map[string]interface{}{"enum": []interface{}{"PYTHON3_9", "PYTHON3_10"}},
+		},
+	})
+	srv.SetSchema("FUNCTION", "container", map[string]interface{}{"type": "object"})
+
+	list, err := svc.Schemas(ctx, "function")
+	if err != nil || len(list) != 2 || list[0].Kind != "container" {
+		t.Fatalf("schemas: %+v, %v", list, err)
+	}
+
+	manifest := `kind: python
+name: ok
+project: demo
+spec:
+  source: {code: "print(1)"}
+---
+kind: python
+name: broken
+project: demo
+spec:
+  source: {lang: python}
+  python_version: PYTHON2
+`
+	err = svc.ValidateManifest(ctx, crud.ValidateManifestRequest{Resource: "functions", Data: []byte(manifest)})
+	var verr *crud.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if verr.Name != "manifest#2 broken" || len(verr.Problems) != 2 ||
+		!strings.HasPrefix(verr.Problems[0], "spec.python_version: ") ||
+		!strings.HasPrefix(verr.Problems[1], "spec.source: ") || !strings.Contains(verr.Problems[1], "code") {
+		t.Fatalf("unexpected problems %q of %s", verr.Problems, verr.Name)
+	}
+
+	// the schemas were cached by Schemas
+	for _, r := range srv.Requests() {
+		if strings.HasSuffix(r.Path, "/schemas/FUNCTION/python") {
+			t.Fatalf("unexpected request %s", r.Path)
+		}
+	}
+
+	err = svc.ValidateSpec(ctx, "functions", map[string]interface{}{"kind": "java", "spec": map[string]interface{}{}})
+	if !config.IsNotFound(err) {
+		t.Fatalf("expected a missing schema, got %v", err)
+	}
+}
+
+func TestRelationshipsOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	rr := crud.ResourceRequest{Project: "demo", Resource: "artifacts"}
+	id := srv.Add("demo", "artifacts", map[string]interface{}{
+		"name": "model",
+		"metadata": map[string]interface{}{"relationships": []interface{}{
+			map[string]interface{}{"type": "produced_by", "dest": "python+job:run://demo/r1"},
+		}},
+	})
+
+	rels, err := svc.Relate(ctx, crud.RelationshipRequest{ResourceRequest: rr, Name: "model", Relationships: []utils.Relationship{
+		{Type: utils.RelationshipDerivedFrom, Dest: "store://demo/artifact/artifact/base:b1"},
+		{Type: utils.RelationshipProducedBy, Dest: "python+job:run://demo/r1"},
+	}})
+	if err != nil || len(rels) != 2 || rels[1].Type != utils.RelationshipDerivedFrom {
+		t.Fatalf("relate: %v, %v", rels, err)
+	}
+	if _, err := svc.Relate(ctx, crud.RelationshipRequest{ResourceRequest: rr, ID: id, Relationships: []utils.Relationship{{Type: "likes", Dest: "store://demo/x/y"}}}); err == nil {
+		t.Fatal("expected an error for an unknown type")
+	}
+	if _, err := svc.Relate(ctx, crud.RelationshipRequest{ResourceRequest: rr, ID: id, Relationships: []utils.Relationship{{Type: "consumes", Dest: "model"}}}); err == nil {
+		t.Fatal("expected an error for a dest that isn't a key")
+	}
+
+	rels, err = svc.Unrelate(ctx, crud.RelationshipRequest{ResourceRequest: rr, ID: id, Relationships: []utils.Relationship{{Type: utils.RelationshipProducedBy}}})
+	if err != nil || len(rels) != 1 {
+		t.Fatalf("unrelate: %v, %v", rels, err)
+	}
+	rels, err = svc.Relationships(ctx, crud.GetRequest{ResourceRequest: rr, ID: id})
+	if err != nil || fmt.Sprint(rels) != "[{derived_from store://demo/artifact/artifact/base:b1}]" {
+		t.Fatalf("relationships: %v, %v", rels, err)
+	}
+}
@@ -8,19 +8,33 @@ import (
 	"context"
 	"errors"
 	"fmt"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
 )
 
-func (s *CrudService) Delete(ctx context.Context, req DeleteRequest) error {
+func (s *CrudService) Delete(ctx context.Context, req DeleteRequest) (err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.delete", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
 	if req.Resource == "" {
 		return errors.New("endpoint is required")
 	}
 
-	if req.Resource != "projects" && req.Project == "" {
+	if !config.IsGlobalResource(req.Resource) && req.Project == "" {
 		return errors.New("project is mandatory for non-project resources")
 	}
 	if req.ID == "" && req.Name == "" {
 		return errors.New("you must specify id or name")
 	}
+	if req.IfMatch != "" {
+		if req.ID == "" {
+			return errors.New("a conditional delete requires the id")
+		}
+		if ctx, err = s.checkIfMatch(ctx, req.ResourceRequest, req.ID, req.IfMatch); err != nil {
+			return err
+		}
+	}
 
 	params := map[string]string{
 		"cascade": "false",
@@ -30,7 +44,7 @@ func (s *CrudService) Delete(ctx context.Context, req DeleteRequest) error {
 	}
 
 	id := req.ID
-	if id == "" && req.Resource != "projects" {
+	if id == "" && !config.IsGlobalResource(req.Resource) {
 		params["name"] = req.Name
 		params["versions"] = "all"
 	}
@@ -39,7 +53,7 @@ func (s *CrudService) Delete(ctx context.Context, req DeleteRequest) error {
 
 	_, status, err := s.http.Do(ctx, "DELETE", url, nil)
 	if err != nil {
-		return fmt.Errorf("delete failed (status %d): %w", status, err)
+		return fmt.Errorf("delete failed (status %d): %w", status, asConflict(err, req.ResourceRequest, req.ID, req.IfMatch))
 	}
 	return nil
 }
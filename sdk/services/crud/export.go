@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+type ExportRequest struct {
+	ResourceRequest
+
+	// ID, or else Name (latest version), exports one entity; when both are
+	// empty every entity matching Params and Filter is exported
+	ID   string
+	Name string
+
+	Params map[string]string
+	Filter *Filter
+
+	// KeepIDs keeps id and key, so that applying the export updates the
+	// same entities instead of creating new ones
+	KeepIDs bool
+}
+
+// ExportedDocument is an entity as clean YAML.
+type ExportedDocument struct {
+	Resource string
+	Project  string
+	Name     string
+	ID       string
+	YAML     []byte
+}
+
+// ExportResult holds the documents of Export, sorted by name and creation.
+type ExportResult struct {
+	Documents []ExportedDocument
+}
+
+// Bundle joins the documents in one multi-document YAML stream, as read by
+// Create and Apply.
+func (r *ExportResult) Bundle() []byte {
+	var buf bytes.Buffer
+	for i, d := range r.Documents {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(d.YAML)
+	}
+	return buf.Bytes()
+}
+
+// Export fetches entities and returns them as YAML that Create and Apply
+// accept again: status, user and the audit fields of metadata (created,
+// updated, created_by, updated_by) are removed, and so are id and key
+// unless req.KeepIDs. Fields are written in a stable order (id and key,
+// kind, name, project, metadata, spec, then the others alphabetically), so
+// that exports of the same entities can be diffed.
+func (s *CrudService) Export(ctx context.Context, req ExportRequest) (_ *ExportResult, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.export", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.Resource == "" {
+		return nil, errors.New("endpoint is required")
+	}
+	if !config.IsGlobalResource(req.Resource) && req.Project == "" {
+		return nil, errors.New("project is mandatory for non-project resources")
+	}
+
+	entities, err := s.fetchEntities(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entities, func(i, j int) bool {
+		ni, _ := entities[i]["name"].(string)
+		nj, _ := entities[j]["name"].(string)
+		if ni != nj {
+			return ni < nj
+		}
+		// ISO 8601 timestamps of Core sort as strings
+		return metadataField(entities[i], "created") < metadataField(entities[j], "created")
+	})
+
+	res := &ExportResult{}
+	for _, e := range entities {
+		d := ExportedDocument{Resource: req.Resource}
+		d.Project, _ = e["project"].(string)
+		d.Name, _ = e["name"].(string)
+		d.ID, _ = e["id"].(string)
+		if d.YAML, err = marshalCanonical(cleanEntity(e, req.KeepIDs)); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", req.Resource, d.ID, err)
+		}
+		res.Documents = append(res.Documents, d)
+	}
+	return res, nil
+}
+
+// fetchEntities returns the entity req.ID, the latest version of req.Name,
+// or else the entities matching req.Params and req.Filter.
+func (s *CrudService) fetchEntities(ctx context.Context, req ExportRequest) ([]map[string]interface{}, error) {
+	if req.ID != "" {
+		b, _, err := s.http.Do(ctx, "GET", s.http.BuildURL(req.Project, req.Resource, req.ID, nil), nil)
+		if err != nil {
+			return nil, err
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, fmt.Errorf("json parsing failed: %w", err)
+		}
+		return []map[string]interface{}{m}, nil
+	}
+
+	lr := ListRequest{ResourceRequest: req.ResourceRequest, Params: req.Params, Filter: req.Filter}
+	if req.Name != "" {
+		lr.Params = map[string]string{"name": req.Name, "versions": "latest"}
+		lr.Filter = nil
+	}
+	var out []map[string]interface{}
+	for item, err := range s.ListIter(ctx, lr) {
+		if err != nil {
+			return nil, err
+		}
+		if req.Name == "" || item["name"] == req.Name {
+			out = append(out, item)
+		}
+	}
+	if req.Name != "" && len(out) == 0 {
+		return nil, fmt.Errorf("%s %s not found", req.Resource, req.Name)
+	}
+	return out, nil
+}
+
+func metadataField(e map[string]interface{}, f string) string {
+	meta, _ := e["metadata"].(map[string]interface{})
+	v, _ := meta[f].(string)
+	return v
+}
+
+// auditMetadata are the metadata fields set by Core.
+var auditMetadata = []string{"created", "updated", "created_by", "updated_by"}
+
+// cleanEntity returns e without the fields managed by Core.
+func cleanEntity(e map[string]interface{}, keepIDs bool) map[string]interface{} {
+	out := maps.Clone(e)
+	delete(out, "status")
+	delete(out, "user")
+	if !keepIDs {
+		delete(out, "id")
+		delete(out, "key")
+	}
+	if meta, ok := out["metadata"].(map[string]interface{}); ok {
+		meta = maps.Clone(meta)
+		for _, f := range auditMetadata {
+			delete(meta, f)
+		}
+		if len(meta) == 0 {
+			delete(out, "metadata")
+		} else {
+			out["metadata"] = meta
+		}
+	}
+	return out
+}
+
+// canonicalOrder is the order of the top-level fields of exported YAML.
+var canonicalOrder = []string{"id", "key", "kind", "name", "project", "metadata", "spec"}
+
+// marshalCanonical writes e as YAML with the fields of canonicalOrder first
+// and the others sorted; nested objects are sorted by key.
+func marshalCanonical(e map[string]interface{}) ([]byte, error) {
+	keys := slices.Sorted(maps.Keys(e))
+	slices.SortStableFunc(keys, func(a, b string) int {
+		return orderOf(a) - orderOf(b)
+	})
+	var buf bytes.Buffer
+	for _, k := range keys {
+		b, err := yaml.Marshal(map[string]interface{}{k: e[k]})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", k, err)
+		}
+		buf.Write(b)
+	}
+	return buf.Bytes(), nil
+}
+
+func orderOf(k string) int {
+	if i := slices.Index(canonicalOrder, k); i >= 0 {
+		return i
+	}
+	return len(canonicalOrder)
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// FieldsParam is the query param asking Core for a partial response.
+const FieldsParam = "fields"
+
+// withFields adds the fields param to params when fields are selected.
+func withFields(params url.Values, fields []string) url.Values {
+	if len(fields) > 0 {
+		params.Set(FieldsParam, strings.Join(fields, ","))
+	}
+	return params
+}
+
+// Project returns the fields of e selected by paths; a path is a field name
+// or a dotted path into nested objects, e.g. "status.state". Missing fields
+// are left out.
+func Project(e map[string]interface{}, paths []string) map[string]interface{} {
+	out := map[string]interface{}{}
+	for _, p := range paths {
+		parts := strings.Split(p, ".")
+		var v interface{} = e
+		for _, k := range parts {
+			m, ok := v.(map[string]interface{})
+			if v, ok = m[k]; !ok {
+				break
+			}
+		}
+		if v == nil {
+			continue
+		}
+		dst := out
+		for _, k := range parts[:len(parts)-1] {
+			sub, _ := dst[k].(map[string]interface{})
+			if sub == nil {
+				sub = map[string]interface{}{}
+				dst[k] = sub
+			}
+			dst = sub
+		}
+		dst[parts[len(parts)-1]] = v
+	}
+	return out
+}
+
+// projectItems applies Project to the entities of a list page.
+func projectItems(items []interface{}, fields []string) []interface{} {
+	if len(fields) == 0 {
+		return items
+	}
+	for i, it := range items {
+		if m, ok := it.(map[string]interface{}); ok {
+			items[i] = Project(m, fields)
+		}
+	}
+	return items
+}
+
+// projectBody applies Project to an entity, or to the entities of a page,
+// as returned by Get.
+func projectBody(body []byte, fields []string) ([]byte, error) {
+	if len(fields) == 0 {
+		return body, nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	if content, ok := m["content"].([]interface{}); ok {
+		m["content"] = projectItems(content, fields)
+	} else {
+		m = Project(m, fields)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"net/url"
+	"time"
+)
+
+// Query params of the list filters of Core.
+const (
+	FilterParamSearch        = "q"
+	FilterParamName          = "name"
+	FilterParamKind          = "kind"
+	FilterParamState         = "state"
+	FilterParamUser          = "user"
+	FilterParamLabels        = "labels"
+	FilterParamCreatedAfter  = "created_after"
+	FilterParamCreatedBefore = "created_before"
+	FilterParamUpdatedAfter  = "updated_after"
+	FilterParamUpdatedBefore = "updated_before"
+)
+
+// Filter builds the server-side filters of a list:
+//
+//	crud.NewFilter().Kind("python").State("COMPLETED").CreatedAfter(t)
+//
+// Conditions are combined with AND; setting one twice keeps the last value,
+// except Labels, which adds to them.
+type Filter struct {
+	values url.Values
+}
+
+// NewFilter returns an empty filter.
+func NewFilter() *Filter {
+	return &Filter{values: url.Values{}}
+}
+
+func (f *Filter) set(key, value string) *Filter {
+	if value == "" {
+		f.values.Del(key)
+	} else {
+		f.values.Set(key, value)
+	}
+	return f
+}
+
+func (f *Filter) setTime(key string, t time.Time) *Filter {
+	if t.IsZero() {
+		return f.set(key, "")
+	}
+	return f.set(key, t.UTC().Format(time.RFC3339))
+}
+
+// NameContains matches the entities whose name contains s.
+func (f *Filter) NameContains(s string) *Filter { return f.set(FilterParamSearch, s) }
+
+// Name matches the entities named name.
+func (f *Filter) Name(name string) *Filter { return f.set(FilterParamName, name) }
+
+// Kind matches the kind, e.g. "artifact" or "python+run".
+func (f *Filter) Kind(kind string) *Filter { return f.set(FilterParamKind, kind) }
+
+// State matches status.state, e.g. "COMPLETED".
+func (f *Filter) State(state string) *Filter { return f.set(FilterParamState, state) }
+
+// User matches the owner of the entities.
+func (f *Filter) User(user string) *Filter { return f.set(FilterParamUser, user) }
+
+// Labels matches the entities having all the labels.
+func (f *Filter) Labels(labels ...string) *Filter {
+	for _, l := range labels {
+		if l != "" {
+			f.values.Add(FilterParamLabels, l)
+		}
+	}
+	return f
+}
+
+// CreatedAfter and CreatedBefore bound metadata.created; the zero time
+// removes the bound.
+func (f *Filter) CreatedAfter(t time.Time) *Filter  { return f.setTime(FilterParamCreatedAfter, t) }
+func (f *Filter) CreatedBefore(t time.Time) *Filter { return f.setTime(FilterParamCreatedBefore, t) }
+
+// UpdatedAfter and UpdatedBefore bound metadata.updated; the zero time
+// removes the bound.
+func (f *Filter) UpdatedAfter(t time.Time) *Filter  { return f.setTime(FilterParamUpdatedAfter, t) }
+func (f *Filter) UpdatedBefore(t time.Time) *Filter { return f.setTime(FilterParamUpdatedBefore, t) }
+
+// Values returns the query params of the filter.
+func (f *Filter) Values() url.Values {
+	if f == nil {
+		return url.Values{}
+	}
+	out := make(url.Values, len(f.values))
+	for k, v := range f.values {
+		out[k] = append([]string(nil), v...)
+	}
+	return out
+}
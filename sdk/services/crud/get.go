@@ -7,9 +7,16 @@ package crud
 import (
 	"context"
 	"fmt"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
 )
 
-func (s *CrudService) Get(ctx context.Context, req GetRequest) ([]byte, int, error) {
+func (s *CrudService) Get(ctx context.Context, req GetRequest) (_ []byte, _ int, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.get", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
 	params := map[string]string{}
 
 	if req.ID == "" {
@@ -20,6 +27,15 @@ func (s *CrudService) Get(ctx context.Context, req GetRequest) ([]byte, int, err
 		params["versions"] = "latest"
 	}
 
+	if len(req.Fields) > 0 {
+		params[FieldsParam] = strings.Join(req.Fields, ",")
+	}
+
 	url := s.http.BuildURL(req.Project, req.Resource, req.ID, params)
-	return s.http.Do(ctx, "GET", url, nil)
+	body, status, err := s.http.Do(ctx, "GET", url, nil)
+	if err != nil {
+		return body, status, err
+	}
+	body, err = projectBody(body, req.Fields)
+	return body, status, err
 }
@@ -0,0 +1,221 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+type LabelRequest struct {
+	ResourceRequest
+
+	// ID, or else Name (latest version), is the entity to label
+	ID   string
+	Name string
+
+	Labels []string
+}
+
+// Labels returns metadata.labels of the entity req.ID, or of the latest
+// version of req.Name.
+func (s *CrudService) Labels(ctx context.Context, req GetRequest) (_ []string, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.labels", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	e, _, err := s.labelTarget(ctx, req.ResourceRequest, req.ID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return entityLabels(e), nil
+}
+
+// Label adds req.Labels to metadata.labels of the entity and returns the
+// resulting labels; labels it already has are left alone.
+func (s *CrudService) Label(ctx context.Context, req LabelRequest) (_ []string, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.label", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	return s.relabel(ctx, req, func(labels []string) []string {
+		for _, l := range req.Labels {
+			if l != "" && !slices.Contains(labels, l) {
+				labels = append(labels, l)
+			}
+		}
+		return labels
+	})
+}
+
+// Unlabel removes req.Labels from metadata.labels of the entity and returns
+// the resulting labels.
+func (s *CrudService) Unlabel(ctx context.Context, req LabelRequest) (_ []string, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.unlabel", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	return s.relabel(ctx, req, func(labels []string) []string {
+		return slices.DeleteFunc(labels, func(l string) bool { return slices.Contains(req.Labels, l) })
+	})
+}
+
+// relabel reads the labels of the entity and, when change modifies them,
+// writes them back with a merge patch of metadata.labels. The patch is
+// conditional on the version read, so a concurrent change of the entity
+// returns a *ConflictError instead of being overwritten.
+func (s *CrudService) relabel(ctx context.Context, req LabelRequest, change func([]string) []string) ([]string, error) {
+	if len(req.Labels) == 0 {
+		return nil, errors.New("labels are required")
+	}
+	e, etag, err := s.labelTarget(ctx, req.ResourceRequest, req.ID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	old := entityLabels(e)
+	labels := change(slices.Clone(old))
+	if slices.Equal(old, labels) {
+		return labels, nil
+	}
+
+	if err := s.patchMetadata(ctx, req.ResourceRequest, e, etag, "labels", labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// patchMetadata sets metadata.field of the entity e with a merge patch,
+// conditional on the version read: etag, the ETag Core sent with e, or else
+// its metadata.updated, checked as Update does.
+func (s *CrudService) patchMetadata(ctx context.Context, rr ResourceRequest, e map[string]interface{}, etag, field string, value interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{field: value}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %w", err)
+	}
+	id, _ := e["id"].(string)
+	ifMatch := etag
+	if etag != "" {
+		ctx = config.ContextWithRequestOptions(ctx, config.WithHeader("If-Match", quoteETag(etag)))
+	} else if b, err := json.Marshal(e); err == nil {
+		if ifMatch = ETag(b); ifMatch != "" {
+			var err error
+			if ctx, err = s.checkIfMatch(ctx, rr, id, ifMatch); err != nil {
+				return err
+			}
+		}
+	}
+	ctx = config.ContextWithRequestOptions(ctx, config.WithHeader("Content-Type", string(MergePatch)))
+	_, status, err := s.http.Do(ctx, "PATCH", s.http.BuildURL(rr.Project, rr.Resource, id, nil), patch)
+	if err != nil {
+		return fmt.Errorf("patch failed (status %d): %w", status, asConflict(err, rr, id, ifMatch))
+	}
+	return nil
+}
+
+// labelTarget reads the entity id, or the latest version of name, with the
+// ETag Core sent with it (none for lookups by name).
+func (s *CrudService) labelTarget(ctx context.Context, rr ResourceRequest, id, name string) (map[string]interface{}, string, error) {
+	if rr.Resource == "" {
+		return nil, "", errors.New("endpoint is required")
+	}
+	if !config.IsGlobalResource(rr.Resource) && rr.Project == "" {
+		return nil, "", errors.New("project is mandatory for non-project resources")
+	}
+	if id == "" && name == "" {
+		return nil, "", errors.New("you must specify id or name")
+	}
+	if id == "" && config.IsGlobalResource(rr.Resource) {
+		id = name
+	}
+	var header http.Header
+	list, err := s.fetchEntities(config.ContextWithResponseHeader(ctx, &header), ExportRequest{ResourceRequest: rr, ID: id, Name: name})
+	if err != nil {
+		return nil, "", err
+	}
+	var etag string
+	if id != "" {
+		etag = header.Get("ETag")
+	}
+	return list[0], etag, nil
+}
+
+func entityLabels(e map[string]interface{}) []string {
+	meta, _ := e["metadata"].(map[string]interface{})
+	raw, _ := meta["labels"].([]interface{})
+	labels := make([]string, 0, len(raw))
+	for _, l := range raw {
+		if s, ok := l.(string); ok {
+			labels = append(labels, s)
+		}
+	}
+	return labels
+}
+
+// LabelSelector selects entities by label: "a,b" matches those having
+// both a and b, "!c" those without c.
+type LabelSelector struct {
+	Has []string
+	Not []string
+}
+
+// ParseLabelSelector parses a comma-separated selector such as
+// "team-a,!deprecated".
+func ParseLabelSelector(s string) (LabelSelector, error) {
+	var sel LabelSelector
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		if l, ok := strings.CutPrefix(term, "!"); ok {
+			if l = strings.TrimSpace(l); l == "" {
+				return sel, fmt.Errorf("invalid label selector %q", s)
+			}
+			sel.Not = append(sel.Not, l)
+			continue
+		}
+		sel.Has = append(sel.Has, term)
+	}
+	return sel, nil
+}
+
+// Matches reports whether labels satisfy the selector.
+func (sel LabelSelector) Matches(labels []string) bool {
+	for _, l := range sel.Has {
+		if !slices.Contains(labels, l) {
+			return false
+		}
+	}
+	for _, l := range sel.Not {
+		if slices.Contains(labels, l) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchLabels drops the items of a list page that don't match the
+// selector of req: Core filters the required labels, the exclusions are
+// applied here.
+func matchLabels(req ListRequest, items []interface{}) ([]interface{}, error) {
+	if req.LabelSelector == "" {
+		return items, nil
+	}
+	sel, err := ParseLabelSelector(req.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(items, func(it interface{}) bool {
+		m, _ := it.(map[string]interface{})
+		return !sel.Matches(entityLabels(m))
+	}), nil
+}
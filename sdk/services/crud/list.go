@@ -8,67 +8,191 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"maps"
+	"net/url"
 	"reflect"
+	"slices"
 	"strconv"
-)
+	"sync"
 
-func (s *CrudService) ListAllPages(ctx context.Context, req ListRequest) ([]interface{}, int, error) {
-	var (
-		elements   []interface{}
-		currentPg  int
-		totalPages int
-	)
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
 
-	pageParams := map[string]string{}
-	if req.Params != nil {
-		maps.Copy(pageParams, req.Params)
-	}
+func (s *CrudService) ListAllPages(ctx context.Context, req ListRequest) (_ []interface{}, _ int, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.list", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
 
+	var elements []interface{}
+	params := listParams(req)
 	for {
-		url := s.http.BuildURL(req.Project, req.Resource, "", pageParams)
-		body, status, err := s.http.Do(ctx, "GET", url, nil)
+		pageList, currentPg, totalPages, err := s.listPage(ctx, req, params)
 		if err != nil {
 			return nil, 0, err
 		}
-		if status != 200 {
-			return nil, 0, fmt.Errorf("core responded with status %d", status)
+		elements = append(elements, pageList...)
+		if currentPg >= totalPages-1 {
+			return elements, totalPages, nil
 		}
-
-		m := map[string]interface{}{}
-		if err := json.Unmarshal(body, &m); err != nil {
-			return nil, 0, fmt.Errorf("json parsing failed: %w", err)
+		if req.Concurrency > 1 {
+			rest, err := s.listPages(ctx, req, params, currentPg+1, totalPages, req.Concurrency)
+			if err != nil {
+				return nil, 0, err
+			}
+			return append(elements, rest...), totalPages, nil
 		}
+		params.Set("page", strconv.Itoa(currentPg+1))
+	}
+}
 
-		pageList, _ := m["content"].([]interface{})
-		elements = append(elements, pageList...)
+// listPages fetches pages from to totalPages-1 with at most workers calls
+// in flight and returns their elements in page order. The first failure
+// cancels the other calls.
+func (s *CrudService) listPages(ctx context.Context, req ListRequest, params url.Values, from, totalPages, workers int) ([]interface{}, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		if pg, ok := m["pageable"].(map[string]interface{}); ok {
-			if v := reflect.ValueOf(pg["pageNumber"]); v.IsValid() {
-				switch v.Kind() {
-				case reflect.Float64:
-					currentPg = int(v.Float())
-				default:
-					currentPg = 0
+	var (
+		pages    = make([][]interface{}, totalPages-from)
+		firstErr error
+		errOnce  sync.Once
+		wg       sync.WaitGroup
+		next     = make(chan int)
+	)
+	for range min(workers, len(pages)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				p := maps.Clone(params)
+				p.Set("page", strconv.Itoa(from+i))
+				var err error
+				if pages[i], _, _, err = s.listPage(ctx, req, p); err != nil {
+					errOnce.Do(func() { firstErr = fmt.Errorf("page %d: %w", from+i, err) })
+					cancel()
 				}
 			}
+		}()
+	}
+feed:
+	for i := range pages {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break feed
 		}
-		if tp, ok := m["totalPages"]; ok {
-			switch v := tp.(type) {
-			case float64:
-				totalPages = int(v)
-			default:
-				totalPages = 1
+	}
+	close(next)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, pg := range pages {
+		out = append(out, pg...)
+	}
+	return out, nil
+}
+
+// ListIter yields the entities of req one at a time, fetching a page only
+// when the previous one is consumed, so memory doesn't grow with the size of
+// the list. A failure is yielded as the last element, with a nil map;
+// breaking out of the loop stops fetching.
+//
+//	for item, err := range svc.ListIter(ctx, req) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (s *CrudService) ListIter(ctx context.Context, req ListRequest) iter.Seq2[map[string]interface{}, error] {
+	return func(yield func(map[string]interface{}, error) bool) {
+		var err error
+		ctx, span := config.StartSpan(ctx, s.tracer, "crud.list", req.Project, req.Resource)
+		defer func() { config.EndSpan(span, err) }()
+		ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+		params := listParams(req)
+		for {
+			var (
+				pageList              []interface{}
+				currentPg, totalPages int
+			)
+			if pageList, currentPg, totalPages, err = s.listPage(ctx, req, params); err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, it := range pageList {
+				m, _ := it.(map[string]interface{})
+				if !yield(m, nil) {
+					return
+				}
+			}
+			if currentPg >= totalPages-1 {
+				return
 			}
-		} else {
-			totalPages = 1
+			params.Set("page", strconv.Itoa(currentPg+1))
 		}
+	}
+}
 
-		if currentPg >= totalPages-1 {
-			break
+// listParams merges the params, the filter, the required labels, the
+// fields and the order of req.
+func listParams(req ListRequest) url.Values {
+	params := url.Values{}
+	for k, v := range req.Params {
+		if v != "" {
+			params.Set(k, v)
+		}
+	}
+	maps.Copy(params, req.Filter.Values())
+	if sel, err := ParseLabelSelector(req.LabelSelector); err == nil {
+		for _, l := range sel.Has {
+			if !slices.Contains(params[FilterParamLabels], l) {
+				params.Add(FilterParamLabels, l)
+			}
 		}
-		pageParams["page"] = strconv.Itoa(currentPg + 1)
+	}
+	return withSort(withFields(params, req.Fields), req.Sort)
+}
+
+// listPage fetches a page of req and returns its elements, its number and
+// the number of pages.
+func (s *CrudService) listPage(ctx context.Context, req ListRequest, params url.Values) (_ []interface{}, currentPg, totalPages int, _ error) {
+	u := s.http.BuildURLValues(req.Project, req.Resource, "", params)
+	body, status, err := s.http.Do(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if status != 200 {
+		return nil, 0, 0, fmt.Errorf("core responded with status %d", status)
+	}
+
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, 0, 0, fmt.Errorf("json parsing failed: %w", err)
 	}
 
-	return elements, totalPages, nil
+	pageList, _ := m["content"].([]interface{})
+	if pageList, err = matchLabels(req, pageList); err != nil {
+		return nil, 0, 0, err
+	}
+	// Core releases without partial responses return whole entities
+	pageList = projectItems(pageList, req.Fields)
+
+	if pg, ok := m["pageable"].(map[string]interface{}); ok {
+		if v := reflect.ValueOf(pg["pageNumber"]); v.IsValid() && v.Kind() == reflect.Float64 {
+			currentPg = int(v.Float())
+		}
+	}
+	totalPages = 1
+	if v, ok := m["totalPages"].(float64); ok {
+		totalPages = int(v)
+	}
+	return pageList, currentPg, totalPages, nil
 }
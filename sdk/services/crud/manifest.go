@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// document is an entity read from a YAML manifest.
+type document struct {
+	// Source is "file#n" (n counts from 1), for messages
+	Source   string
+	Resource string
+	Entity   map[string]interface{}
+}
+
+var documentSeparator = regexp.MustCompile(`(?m)^---[ \t]*(#.*)?$`)
+
+// parseDocuments splits a YAML stream on "---" lines and converts each
+// non-empty document; source names the stream in errors.
+func parseDocuments(data []byte, source string) ([]document, error) {
+	var docs []document
+	for i, part := range documentSeparator.Split(string(data), -1) {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		src := fmt.Sprintf("%s#%d", source, i+1)
+		jsonBytes, err := yaml.YAMLToJSON([]byte(part))
+		if err != nil {
+			return nil, fmt.Errorf("%s: yaml to json failed: %w", src, err)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(jsonBytes, &m); err != nil {
+			return nil, fmt.Errorf("%s: failed to parse after JSON conversion: %w", src, err)
+		}
+		if m == nil {
+			continue
+		}
+		docs = append(docs, document{Source: src, Entity: m})
+	}
+	return docs, nil
+}
+
+// loadDocuments reads the manifests at path, or data when path is empty.
+// path is a file, a directory (its .yaml and .yml files, recursively) or a
+// glob pattern; files are read in lexical order.
+func loadDocuments(path string, data []byte) ([]document, error) {
+	if path == "" {
+		return parseDocuments(data, "manifest")
+	}
+	files, err := manifestFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	var docs []document
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read YAML file: %w", err)
+		}
+		d, err := parseDocuments(b, f)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, d...)
+	}
+	return docs, nil
+}
+
+func manifestFiles(path string) ([]string, error) {
+	if info, err := os.Stat(path); err == nil {
+		if !info.IsDir() {
+			return []string{path}, nil
+		}
+		var files []string
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if ext := strings.ToLower(filepath.Ext(p)); !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory: %w", err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no YAML files in %s", path)
+		}
+		return files, nil
+	}
+	files, err := filepath.Glob(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", path, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("failed to read YAML file: %s matches no file", path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// resourceRank orders the creation of resources so that entities are created
+// after those they refer to.
+var resourceRank = map[string]int{
+	"projects":  0,
+	"secrets":   1,
+	"artifacts": 1,
+	"dataitems": 1,
+	"models":    1,
+	"functions": 2,
+	"workflows": 2,
+	"tasks":     3,
+	"runs":      4,
+}
+
+// sortByDependency orders docs by resourceRank, keeping the order of the
+// documents of the same rank; unknown resources go with functions.
+func sortByDependency(docs []document) {
+	rank := func(r string) int {
+		if n, ok := resourceRank[r]; ok {
+			return n
+		}
+		return resourceRank["functions"]
+	}
+	sort.SliceStable(docs, func(i, j int) bool { return rank(docs[i].Resource) < rank(docs[j].Resource) })
+}
+
+// resolveResources sets the resource of each document: the entity type of
+// its key (e.g. "store://demo/artifact/..."), else its kind when it names a
+// resource ("project", "artifact", "...+run"), else def.
+func resolveResources(docs []document, def string) error {
+	for i := range docs {
+		r := documentResource(docs[i].Entity)
+		if r == "" {
+			r = def
+		}
+		if r == "" {
+			return fmt.Errorf("%s: cannot tell the resource of kind %q, set the resource", docs[i].Source, docs[i].Entity["kind"])
+		}
+		plural, err := config.ResolveResource(r)
+		if err != nil {
+			return fmt.Errorf("%s: %w", docs[i].Source, err)
+		}
+		docs[i].Resource = plural
+	}
+	return nil
+}
+
+// keyParts splits the key of e, store://<project>/<entity type>/<kind>/...;
+// nil when e has no such key.
+func keyParts(e map[string]interface{}) []string {
+	key, _ := e["key"].(string)
+	if !strings.HasPrefix(key, "store://") {
+		return nil
+	}
+	if parts := strings.Split(strings.TrimPrefix(key, "store://"), "/"); len(parts) > 2 {
+		return parts
+	}
+	return nil
+}
+
+// keyProject returns the project of the key of e, or "".
+func keyProject(e map[string]interface{}) string {
+	if parts := keyParts(e); parts != nil {
+		return parts[0]
+	}
+	return ""
+}
+
+func documentResource(e map[string]interface{}) string {
+	if parts := keyParts(e); parts != nil {
+		if _, ok := config.LookupResource(parts[1]); ok {
+			return parts[1]
+		}
+	}
+	kind, _ := e["kind"].(string)
+	if strings.HasSuffix(kind, "+run") {
+		return "runs"
+	}
+	if strings.Contains(kind, "+") {
+		return "tasks"
+	}
+	if _, ok := config.LookupResource(kind); ok && kind != "" {
+		return kind
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// Patch updates part of an entity with a JSON Merge Patch (default) or a
+// JSON Patch, without fetching and sending back the whole entity.
+func (s *CrudService) Patch(ctx context.Context, req PatchRequest) (err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.patch", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.Resource == "" {
+		return errors.New("endpoint is required")
+	}
+	if req.ID == "" {
+		return errors.New("id is required")
+	}
+	if !config.IsGlobalResource(req.Resource) && req.Project == "" {
+		return errors.New("project is mandatory for non-project resources")
+	}
+	if len(req.Patch) == 0 {
+		return errors.New("empty patch")
+	}
+
+	patchType := req.Type
+	if patchType == "" {
+		patchType = MergePatch
+	}
+	var v interface{}
+	if err := json.Unmarshal(req.Patch, &v); err != nil {
+		return fmt.Errorf("invalid patch: %w", err)
+	}
+	switch patchType {
+	case MergePatch:
+		if _, ok := v.(map[string]interface{}); !ok {
+			return errors.New("invalid patch: a merge patch must be a JSON object")
+		}
+	case JSONPatch:
+		if _, ok := v.([]interface{}); !ok {
+			return errors.New("invalid patch: a JSON patch must be a list of operations")
+		}
+	default:
+		return fmt.Errorf("unsupported patch type %q", patchType)
+	}
+
+	ctx = config.ContextWithRequestOptions(ctx, config.WithHeader("Content-Type", string(patchType)))
+	url := s.http.BuildURL(req.Project, req.Resource, req.ID, nil)
+	_, status, err := s.http.Do(ctx, "PATCH", url, req.Patch)
+	if err != nil {
+		return fmt.Errorf("patch failed (status %d): %w", status, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"context"
+	"errors"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+)
+
+type RelationshipRequest struct {
+	ResourceRequest
+
+	// ID, or else Name (latest version), is the source of the relationships
+	ID   string
+	Name string
+
+	// Relationships to add or, for Unrelate, to remove (an empty Dest
+	// removes all those of the type)
+	Relationships []utils.Relationship
+}
+
+// Relationships returns metadata.relationships of the entity req.ID, or of
+// the latest version of req.Name.
+func (s *CrudService) Relationships(ctx context.Context, req GetRequest) (_ []utils.Relationship, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.relationships", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	e, _, err := s.labelTarget(ctx, req.ResourceRequest, req.ID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return utils.EntityRelationships(e), nil
+}
+
+// Relate adds req.Relationships to the entity, checking their types (see
+// utils.ValidateRelationship), and returns the resulting relationships;
+// those it already has are left alone.
+func (s *CrudService) Relate(ctx context.Context, req RelationshipRequest) (_ []utils.Relationship, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.relate", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	for _, r := range req.Relationships {
+		if err := utils.ValidateRelationship(r); err != nil {
+			return nil, err
+		}
+	}
+	return s.relate(ctx, req, func(e map[string]interface{}) bool {
+		changed := false
+		for _, r := range req.Relationships {
+			added, _ := utils.AddRelationship(e, r)
+			changed = changed || added
+		}
+		return changed
+	})
+}
+
+// Unrelate removes req.Relationships from the entity and returns the
+// resulting relationships.
+func (s *CrudService) Unrelate(ctx context.Context, req RelationshipRequest) (_ []utils.Relationship, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.unrelate", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	return s.relate(ctx, req, func(e map[string]interface{}) bool {
+		removed := 0
+		for _, r := range req.Relationships {
+			removed += utils.RemoveRelationships(e, r)
+		}
+		return removed > 0
+	})
+}
+
+// relate reads the entity and, when change modifies its relationships,
+// writes them back as relabel does.
+func (s *CrudService) relate(ctx context.Context, req RelationshipRequest, change func(map[string]interface{}) bool) ([]utils.Relationship, error) {
+	if len(req.Relationships) == 0 {
+		return nil, errors.New("relationships are required")
+	}
+	e, etag, err := s.labelTarget(ctx, req.ResourceRequest, req.ID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !change(e) {
+		return utils.EntityRelationships(e), nil
+	}
+	rels := utils.EntityRelationships(e)
+	meta, _ := e["metadata"].(map[string]interface{})
+	if err := s.patchMetadata(ctx, req.ResourceRequest, e, etag, "relationships", meta["relationships"]); err != nil {
+		return nil, err
+	}
+	return rels, nil
+}
@@ -0,0 +1,290 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// schemasResource is the Core endpoint of the JSON schemas of entity specs,
+// /api/{version}/schemas/{ENTITY}[/{kind}].
+const schemasResource = "schemas"
+
+// Schema is the JSON schema Core publishes for the spec of a kind, e.g.
+// function "python", task "python+job" or run "python+run".
+type Schema struct {
+	ID      string          `json:"id,omitempty"`
+	Entity  string          `json:"entity"`
+	Kind    string          `json:"kind"`
+	Runtime string          `json:"runtime,omitempty"`
+	Schema  json.RawMessage `json:"schema"`
+}
+
+// compiledSchema is a cached schema, ready to validate.
+type compiledSchema struct {
+	schema   Schema
+	compiled *jsonschema.Schema
+}
+
+var schemaPrinter = message.NewPrinter(language.English)
+
+// schemaEntity is the entity name of resource in the schema URLs, e.g.
+// FUNCTION for functions.
+func schemaEntity(resource string) (string, error) {
+	plural, err := config.ResolveResource(resource)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(strings.TrimSuffix(plural, "s")), nil
+}
+
+func schemaCacheKey(resource, kind string) string { return resource + "/" + kind }
+
+// Schemas returns the schemas of all the kinds of resource (e.g. "functions")
+// known to Core, sorted by kind, and caches them for Schema and
+// ValidateSpec.
+func (s *CrudService) Schemas(ctx context.Context, resource string, opts ...config.RequestOption) (_ []Schema, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.schemas", "", resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, opts...)
+
+	plural, err := config.ResolveResource(resource)
+	if err != nil {
+		return nil, err
+	}
+	entity, err := schemaEntity(plural)
+	if err != nil {
+		return nil, err
+	}
+	var out []Schema
+	params := url.Values{}
+	for {
+		b, _, err := s.http.Do(ctx, "GET", s.http.BuildURLValues("", schemasResource, entity, params), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the schemas of %s: %w", plural, err)
+		}
+		var page struct {
+			Content  []Schema `json:"content"`
+			Pageable struct {
+				PageNumber int `json:"pageNumber"`
+			} `json:"pageable"`
+			TotalPages int `json:"totalPages"`
+		}
+		if err := json.Unmarshal(b, &page); err != nil {
+			return nil, fmt.Errorf("json parsing failed: %w", err)
+		}
+		out = append(out, page.Content...)
+		if page.Pageable.PageNumber >= page.TotalPages-1 {
+			break
+		}
+		params.Set("page", strconv.Itoa(page.Pageable.PageNumber+1))
+	}
+	for _, sc := range out {
+		if _, err := s.cacheSchema(plural, sc); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Kind < out[j].Kind })
+	return out, nil
+}
+
+// Schema returns the schema of the spec of kind of resource, from the cache
+// when it was already read.
+func (s *CrudService) Schema(ctx context.Context, resource, kind string, opts ...config.RequestOption) (_ *Schema, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.schema", "", resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, opts...)
+
+	c, err := s.schema(ctx, resource, kind)
+	if err != nil {
+		return nil, err
+	}
+	sc := c.schema
+	return &sc, nil
+}
+
+func (s *CrudService) schema(ctx context.Context, resource, kind string) (*compiledSchema, error) {
+	plural, err := config.ResolveResource(resource)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "" {
+		return nil, errors.New("kind is required")
+	}
+	if c, ok := s.schemas.Load(schemaCacheKey(plural, kind)); ok {
+		return c.(*compiledSchema), nil
+	}
+	entity, err := schemaEntity(plural)
+	if err != nil {
+		return nil, err
+	}
+	u := s.http.BuildURL("", schemasResource, entity, nil) + "/" + url.PathEscape(kind)
+	b, _, err := s.http.Do(ctx, "GET", u, nil)
+	if err != nil {
+		if config.IsNotFound(err) {
+			return nil, fmt.Errorf("no schema for %s of kind %q: %w", plural, kind, err)
+		}
+		return nil, fmt.Errorf("failed to read the schema of %s %q: %w", plural, kind, err)
+	}
+	var sc Schema
+	if err := json.Unmarshal(b, &sc); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	if sc.Kind == "" {
+		sc.Kind = kind
+	}
+	return s.cacheSchema(plural, sc)
+}
+
+// cacheSchema compiles sc and caches it under its kind.
+func (s *CrudService) cacheSchema(resource string, sc Schema) (*compiledSchema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(sc.Schema))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema of %s %q: %w", resource, sc.Kind, err)
+	}
+	loc := fmt.Sprintf("urn:dhcore:schemas:%s:%s", resource, sc.Kind)
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(loc, doc); err != nil {
+		return nil, fmt.Errorf("invalid schema of %s %q: %w", resource, sc.Kind, err)
+	}
+	compiled, err := c.Compile(loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema of %s %q: %w", resource, sc.Kind, err)
+	}
+	cs := &compiledSchema{schema: sc, compiled: compiled}
+	s.schemas.Store(schemaCacheKey(resource, sc.Kind), cs)
+	return cs, nil
+}
+
+// ValidateSpec checks the spec of the entity e of resource against the
+// schema of its kind. Violations are returned as a *ValidationError whose
+// problems start with the path of the offending field, e.g.
+// "spec.source.code: missing property".
+func (s *CrudService) ValidateSpec(ctx context.Context, resource string, e map[string]interface{}, opts ...config.RequestOption) (err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.validate_spec", "", resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, opts...)
+
+	return s.validateSpec(ctx, resource, e)
+}
+
+func (s *CrudService) validateSpec(ctx context.Context, resource string, e map[string]interface{}) error {
+	name, _ := e["name"].(string)
+	kind, _ := e["kind"].(string)
+	if kind == "" {
+		return &ValidationError{Resource: resource, Name: name, Problems: []string{"kind is required"}}
+	}
+	c, err := s.schema(ctx, resource, kind)
+	if err != nil {
+		return err
+	}
+	spec, ok := e["spec"]
+	if !ok || spec == nil {
+		spec = map[string]interface{}{}
+	}
+	err = c.compiled.Validate(spec)
+	if err == nil {
+		return nil
+	}
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	return &ValidationError{Resource: resource, Name: name, Problems: schemaProblems(verr), Err: err}
+}
+
+// schemaProblems lists the leaf errors of verr as "spec.<path>: <message>",
+// sorted by path.
+func schemaProblems(verr *jsonschema.ValidationError) []string {
+	var problems []string
+	var walk func(*jsonschema.ValidationError)
+	walk = func(v *jsonschema.ValidationError) {
+		if len(v.Causes) == 0 {
+			p := strings.Join(append([]string{"spec"}, v.InstanceLocation...), ".")
+			problems = append(problems, p+": "+v.ErrorKind.LocalizedString(schemaPrinter))
+			return
+		}
+		for _, c := range v.Causes {
+			walk(c)
+		}
+	}
+	walk(verr)
+	sort.Strings(problems)
+	return problems
+}
+
+type ValidateManifestRequest struct {
+	// Resource is used for the documents whose resource can't be told from
+	// key or kind
+	Resource string
+
+	// FilePath is a YAML file of one or more documents, a directory or a
+	// glob pattern; Data is the YAML itself
+	FilePath string
+	Data     []byte
+
+	// Options add headers and query params to the Core calls
+	// (config.WithHeader, config.WithQueryParam)
+	Options []config.RequestOption
+}
+
+// ValidateManifest checks every document of a manifest with the client-side
+// checks of dry runs and against the schema of its kind, without sending
+// the entities to Core, so manifests can be checked before they are
+// submitted. It returns nil when all are valid, otherwise the
+// *ValidationError of each invalid document, joined; their Name is
+// prefixed by the document source ("file#n").
+func (s *CrudService) ValidateManifest(ctx context.Context, req ValidateManifestRequest) (err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.validate_manifest", "", req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.FilePath == "" && len(req.Data) == 0 {
+		return errors.New("a file path or data is required")
+	}
+	docs, err := loadDocuments(req.FilePath, req.Data)
+	if err != nil {
+		return err
+	}
+	if err := resolveResources(docs, req.Resource); err != nil {
+		return err
+	}
+	var errs []error
+	for _, d := range docs {
+		name, _ := d.Entity["name"].(string)
+		if problems := validateEntity(d.Resource, d.Entity); len(problems) > 0 {
+			errs = append(errs, &ValidationError{Resource: d.Resource, Name: d.Source + " " + name, Problems: problems})
+			continue
+		}
+		if config.IsGlobalResource(d.Resource) || d.Resource == "secrets" {
+			continue
+		}
+		err := s.validateSpec(ctx, d.Resource, d.Entity)
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			verr.Name = strings.TrimSpace(d.Source + " " + verr.Name)
+		} else if err != nil {
+			err = fmt.Errorf("%s: %w", d.Source, err)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
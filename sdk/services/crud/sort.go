@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import "net/url"
+
+// SortParam is the query param of list ordering, "field,asc|desc",
+// repeated for each key.
+const SortParam = "sort"
+
+// SortField is a field lists can be ordered by.
+type SortField string
+
+const (
+	SortCreated SortField = "created"
+	SortUpdated SortField = "updated"
+	SortName    SortField = "name"
+)
+
+// Sort is a key of the list order.
+type Sort struct {
+	Field SortField
+	Desc  bool
+}
+
+// Asc and Desc build sort keys:
+//
+//	Sort: []crud.Sort{crud.Asc(crud.SortName), crud.Desc(crud.SortCreated)}
+func Asc(f SortField) Sort  { return Sort{Field: f} }
+func Desc(f SortField) Sort { return Sort{Field: f, Desc: true} }
+
+func (s Sort) String() string {
+	if s.Desc {
+		return string(s.Field) + ",desc"
+	}
+	return string(s.Field) + ",asc"
+}
+
+// withSort replaces the sort params with the keys of sort, in order.
+func withSort(params url.Values, sort []Sort) url.Values {
+	if len(sort) == 0 {
+		return params
+	}
+	params.Del(SortParam)
+	for _, s := range sort {
+		if s.Field != "" {
+			params.Add(SortParam, s.String())
+		}
+	}
+	return params
+}
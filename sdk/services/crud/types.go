@@ -4,18 +4,35 @@
 
 package crud
 
+import "github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+
 // usata embedded nelle altre request
 type ResourceRequest struct {
 	Project  string // obbligatorio per risorse != "projects"
 	Resource string // "projects", "artifacts", ...
+
+	// Options add headers and query params to the Core calls
+	// (config.WithHeader, config.WithQueryParam)
+	Options []config.RequestOption
 }
 
 type CreateRequest struct {
 	ResourceRequest
 
-	Name     string
+	Name string
+	// FilePath is a YAML file of one or more documents, a directory or a
+	// glob pattern; Data is the YAML itself (see CreateAll)
 	FilePath string
+	Data     []byte
 	ResetID  bool
+	// DryRun validates the entities (see ValidationError) and resolves and
+	// orders the documents, without creating anything
+	DryRun bool
+	// ContinueOnError creates the other documents after a failure
+	ContinueOnError bool
+	// IdempotencyKey makes a retried create return the entity created the
+	// first time; empty generates a new key per call
+	IdempotencyKey string
 }
 
 type DeleteRequest struct {
@@ -24,6 +41,9 @@ type DeleteRequest struct {
 	ID      string
 	Name    string
 	Cascade bool
+	// IfMatch deletes only if the entity is still at this version (see
+	// ETag); requires ID. A mismatch returns a *ConflictError.
+	IfMatch string
 }
 
 type GetRequest struct {
@@ -31,12 +51,30 @@ type GetRequest struct {
 
 	ID   string
 	Name string
+	// Fields selects the fields to return, e.g. "id", "name" or
+	// "status.state" (see Project)
+	Fields []string
 }
 
 type ListRequest struct {
 	ResourceRequest
 
 	Params map[string]string
+	// Filter adds server-side filters (see NewFilter); it wins over Params
+	Filter *Filter
+	// LabelSelector selects by label, e.g. "team-a,!deprecated" (see
+	// ParseLabelSelector)
+	LabelSelector string
+	// Fields selects the fields of the entities to return, e.g. "id",
+	// "name", "kind" or "status.state" (see Project)
+	Fields []string
+	// Sort orders the list by one or more keys (see Asc and Desc); it wins
+	// over a "sort" param
+	Sort []Sort
+	// Concurrency is the number of pages ListAllPages fetches in parallel
+	// once the first one tells how many there are; 0 or 1 fetches them
+	// one after the other
+	Concurrency int
 }
 
 type UpdateRequest struct {
@@ -44,4 +82,30 @@ type UpdateRequest struct {
 
 	ID   string
 	Body []byte
+	// IfMatch updates only if the entity is still at this version (see
+	// ETag). A mismatch returns a *ConflictError.
+	IfMatch string
+	// DryRun validates Body and checks that the entity exists (and IfMatch)
+	// without updating it
+	DryRun bool
+}
+
+// PatchType is the media type of a PatchRequest body.
+type PatchType string
+
+const (
+	// MergePatch is a JSON Merge Patch (RFC 7386): the body is a partial
+	// entity, null removes a field
+	MergePatch PatchType = "application/merge-patch+json"
+	// JSONPatch is a JSON Patch (RFC 6902): the body is a list of operations
+	JSONPatch PatchType = "application/json-patch+json"
+)
+
+type PatchRequest struct {
+	ResourceRequest
+
+	ID    string
+	Patch []byte
+	// Type defaults to MergePatch
+	Type PatchType
 }
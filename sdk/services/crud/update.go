@@ -6,28 +6,61 @@ package crud
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
 )
 
-func (s *CrudService) Update(ctx context.Context, req UpdateRequest) error {
+func (s *CrudService) Update(ctx context.Context, req UpdateRequest) (err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.update", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
 	if req.Resource == "" {
 		return errors.New("endpoint is required")
 	}
 	if req.ID == "" {
 		return errors.New("id is required")
 	}
-	if req.Resource != "projects" && req.Project == "" {
+	if !config.IsGlobalResource(req.Resource) && req.Project == "" {
 		return errors.New("project is mandatory for non-project resources")
 	}
 	if len(req.Body) == 0 {
 		return errors.New("empty body")
 	}
 
+	if req.IfMatch != "" {
+		if ctx, err = s.checkIfMatch(ctx, req.ResourceRequest, req.ID, req.IfMatch); err != nil {
+			return err
+		}
+	}
+
+	if req.DryRun {
+		return s.validateUpdate(ctx, req)
+	}
+
 	url := s.http.BuildURL(req.Project, req.Resource, req.ID, nil)
 	_, status, err := s.http.Do(ctx, "PUT", url, req.Body)
 	if err != nil {
-		return fmt.Errorf("update failed (status %d): %w", status, err)
+		return fmt.Errorf("update failed (status %d): %w", status, asConflict(err, req.ResourceRequest, req.ID, req.IfMatch))
 	}
 	return nil
 }
+
+// validateUpdate checks a dry-run Update: the entity must exist (checkIfMatch
+// already read it) and the body must validate.
+func (s *CrudService) validateUpdate(ctx context.Context, req UpdateRequest) error {
+	var e map[string]interface{}
+	if err := json.Unmarshal(req.Body, &e); err != nil {
+		return fmt.Errorf("json parsing failed: %w", err)
+	}
+	if req.IfMatch == "" {
+		url := s.http.BuildURL(req.Project, req.Resource, req.ID, nil)
+		if _, _, err := s.http.Do(ctx, "GET", url, nil); err != nil {
+			return err
+		}
+	}
+	return s.validate(ctx, req.Project, req.Resource, e)
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// namePattern is the pattern Core enforces on entity names.
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9._+-]+$`)
+
+// ValidationError reports why an entity would be rejected. Problems come
+// from the client-side checks, or hold the message of Core when its
+// validation endpoint rejected the entity (Err is then the *config.CoreError).
+type ValidationError struct {
+	Resource string
+	Name     string
+	Problems []string
+	Err      error
+}
+
+func (e *ValidationError) Error() string {
+	what := e.Resource
+	if e.Name != "" {
+		what += " " + e.Name
+	}
+	return fmt.Sprintf("invalid %s: %s", what, strings.Join(e.Problems, "; "))
+}
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// validate checks e for a dry run: first on the client (validateEntity),
+// then with the validation endpoint of the collection ({resource}/validate).
+// Core releases without that endpoint (404, 405 or 501) are remembered and
+// only checked on the client.
+func (s *CrudService) validate(ctx context.Context, project, resource string, e map[string]interface{}) error {
+	name, _ := e["name"].(string)
+	if problems := validateEntity(resource, e); len(problems) > 0 {
+		return &ValidationError{Resource: resource, Name: name, Problems: problems}
+	}
+	if !s.noValidateEndpoint.Load() {
+		body, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to marshal: %w", err)
+		}
+		u := s.http.BuildURL(project, resource, "", nil) + "/validate"
+		_, _, err = s.http.Do(ctx, "POST", u, body)
+		if err == nil {
+			return nil
+		}
+		ce, ok := config.AsCoreError(err)
+		if !ok {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		switch ce.StatusCode {
+		case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			s.noValidateEndpoint.Store(true)
+		case http.StatusBadRequest, http.StatusUnprocessableEntity:
+			msg := ce.Message
+			if msg == "" {
+				msg = ce.Status
+			}
+			return &ValidationError{Resource: resource, Name: name, Problems: []string{msg}, Err: err}
+		default:
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateEntity returns the problems Core would report about the structure
+// of e: a missing or malformed name or kind, non-object metadata, spec or
+// status, and a key of another project or resource.
+func validateEntity(resource string, e map[string]interface{}) []string {
+	var problems []string
+	global := config.IsGlobalResource(resource)
+
+	id, _ := e["id"].(string)
+	name, hasName := e["name"].(string)
+	switch {
+	case e["name"] != nil && !hasName:
+		problems = append(problems, "name must be a string")
+	case name == "" && id == "" && resource != "runs" && resource != "tasks":
+		problems = append(problems, "name is required")
+	case name != "" && !namePattern.MatchString(name):
+		problems = append(problems, fmt.Sprintf("name %q may only contain letters, digits and . _ + -", name))
+	}
+
+	if kind, ok := e["kind"].(string); e["kind"] != nil && !ok {
+		problems = append(problems, "kind must be a string")
+	} else if kind == "" && !global && resource != "secrets" {
+		problems = append(problems, "kind is required")
+	}
+
+	for _, f := range []string{"metadata", "spec", "status"} {
+		if v, ok := e[f]; ok && v != nil {
+			if _, ok := v.(map[string]interface{}); !ok {
+				problems = append(problems, f+" must be an object")
+			}
+		}
+	}
+
+	if parts := keyParts(e); parts != nil {
+		if p, _ := e["project"].(string); !global && p != "" && parts[0] != p {
+			problems = append(problems, fmt.Sprintf("key belongs to project %q, not %q", parts[0], p))
+		}
+		if r, err := config.ResolveResource(parts[1]); err == nil && r != resource {
+			problems = append(problems, fmt.Sprintf("key is of %s, not %s", r, resource))
+		}
+	}
+	return problems
+}
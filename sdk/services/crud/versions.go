@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package crud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/entities"
+)
+
+// Version is a version of a named entity.
+type Version struct {
+	ID       string
+	Kind     string
+	Key      string
+	Metadata entities.Metadata
+	// Latest marks the version returned by Get with the name
+	Latest bool
+}
+
+// ListVersions returns the versions of the entity name of resource in
+// project, newest (by metadata.created) first.
+func (s *CrudService) ListVersions(ctx context.Context, project, resource, name string, opts ...config.RequestOption) (_ []Version, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "crud.versions", project, resource)
+	defer func() { config.EndSpan(span, err) }()
+
+	if resource == "" {
+		return nil, errors.New("endpoint is required")
+	}
+	if !config.IsGlobalResource(resource) && project == "" {
+		return nil, errors.New("project is mandatory for non-project resources")
+	}
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	items, _, err := s.ListAllPages(ctx, ListRequest{
+		ResourceRequest: ResourceRequest{Project: project, Resource: resource, Options: opts},
+		Params:          map[string]string{"name": name, "versions": "all"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	list, err := entities.FromList[entities.Entity[map[string]interface{}]](items)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]Version, 0, len(list))
+	for _, e := range list {
+		if e.Name != name {
+			continue
+		}
+		versions = append(versions, Version{ID: e.ID, Kind: e.Kind, Key: e.Key, Metadata: e.Metadata})
+	}
+	// ISO 8601 timestamps of Core sort as strings
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Metadata.Created > versions[j].Metadata.Created
+	})
+	if len(versions) > 0 {
+		versions[0].Latest = true
+	}
+	return versions, nil
+}
+
+// GetVersion returns the version versionID of the entity name, failing if
+// versionID belongs to another entity.
+func (s *CrudService) GetVersion(ctx context.Context, project, resource, name, versionID string, opts ...config.RequestOption) (_ []byte, _ int, err error) {
+	if name == "" || versionID == "" {
+		return nil, 0, errors.New("name and version id are required")
+	}
+	b, status, err := s.Get(ctx, GetRequest{
+		ResourceRequest: ResourceRequest{Project: project, Resource: resource, Options: opts},
+		ID:              versionID,
+	})
+	if err != nil {
+		return nil, status, err
+	}
+	var e struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, status, fmt.Errorf("json parsing failed: %w", err)
+	}
+	if e.Name != name {
+		return nil, status, fmt.Errorf("%s %s is a version of %q, not %q", resource, versionID, e.Name, name)
+	}
+	return b, status, nil
+}
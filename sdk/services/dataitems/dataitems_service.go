@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package dataitems inspects dataitems without downloading them: the schema
+// (columns and types) from the spec, the first rows from the preview kept in
+// the status. Download fetches the data through the transfer service.
+package dataitems
+
+import (
+	"context"
+	"errors"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// resource is the Core endpoint of dataitems.
+const resource = "dataitems"
+
+type DataItemsService struct {
+	http   config.CoreHTTP
+	tracer trace.Tracer
+}
+
+// NewDataItemsService builds the service; opts customize HTTP client,
+// logger and retries (see config.ServiceOption).
+func NewDataItemsService(_ context.Context, conf config.Config, opts ...config.ServiceOption) (*DataItemsService, error) {
+	if conf.Core.BaseURL == "" || conf.Core.APIVersion == "" {
+		return nil, errors.New("invalid core config")
+	}
+	o := config.NewServiceOptions(opts...).ForConfig(conf)
+	return &DataItemsService{
+		http:   config.NewHTTPCoreWithOptions(conf.Core, o),
+		tracer: config.Tracer(o.TracerProvider),
+	}, nil
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dataitems_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/dhcoretest"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/s3test"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/dataitems"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/transfer"
+)
+
+func TestSchemaPreviewDownloadOffline(t *testing.T) {
+	ctx := context.Background()
+	srv := dhcoretest.NewServer()
+	defer srv.Close()
+	srv.Add("demo", "dataitems", map[string]interface{}{
+		"id": "d1", "name": "iris", "kind": "table",
+		"spec": map[string]interface{}{
+			"path": "s3://data/demo/iris.csv",
+			"schema": map[string]interface{}{
+				"fields": []interface{}{
+					map[string]interface{}{"name": "sepal_length", "type": "number"},
+					map[string]interface{}{"name": "species", "type": "string"},
+				},
+				"primaryKey": "species",
+			},
+		},
+		"status": map[string]interface{}{
+			"state": "READY",
+			"preview": []interface{}{
+				map[string]interface{}{"name": "sepal_length", "value": []interface{}{5.1, 4.9, 4.7}},
+				map[string]interface{}{"name": "species", "value": []interface{}{"setosa", "setosa", "setosa"}},
+			},
+		},
+	})
+	svc, err := dataitems.NewDataItemsService(ctx, srv.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := dataitems.DataItemRequest{Project: "demo", Name: "iris"}
+
+	sc, err := svc.Schema(ctx, req)
+	if err != nil || fmt.Sprint(sc.Fields) != "[{sepal_length number } {species string }]" || fmt.Sprint(sc.PrimaryKey) != "[species]" {
+		t.Fatalf("schema: %+v, %v", sc, err)
+	}
+	p, err := svc.Preview(ctx, req, 2)
+	if err != nil || len(p.Columns) != 2 || p.Columns[0].Type != "number" || fmt.Sprint(p.Rows) != "[[5.1 setosa] [4.9 setosa]]" {
+		t.Fatalf("preview: %+v, %v", p, err)
+	}
+
+	store := s3test.NewServer()
+	defer store.Close()
+	store.PutObject("data", "demo/iris.csv", []byte("sepal_length,species\n5.1,setosa\n"))
+	cfg := srv.Config()
+	cfg.S3 = store.Config()
+	tr, err := transfer.NewTransferService(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if _, err := svc.Download(ctx, dataitems.DownloadRequest{DataItemRequest: req, Transfer: tr, Destination: dir, Format: "parquet"}); err == nil {
+		t.Fatal("expected an error for a csv dataitem downloaded as parquet")
+	}
+	files, err := svc.Download(ctx, dataitems.DownloadRequest{DataItemRequest: req, Transfer: tr, Destination: dir, Format: "CSV"})
+	if err != nil || len(files) != 1 {
+		t.Fatalf("download: %+v, %v", files, err)
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "iris.csv")); err != nil || len(b) == 0 {
+		t.Fatalf("downloaded %q, %v", b, err)
+	}
+}
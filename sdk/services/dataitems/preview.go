@@ -0,0 +1,214 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dataitems
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/transfer"
+)
+
+// Get returns the dataitem req.ID or, by name, the latest version of
+// req.Name.
+func (s *DataItemsService) Get(ctx context.Context, req DataItemRequest) (_ *DataItem, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "dataitems.get", req.Project, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	return s.get(ctx, req)
+}
+
+// Schema returns the schema of the dataitem; an empty schema when it has
+// none.
+func (s *DataItemsService) Schema(ctx context.Context, req DataItemRequest) (_ *Schema, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "dataitems.schema", req.Project, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	d, err := s.get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return d.Schema(), nil
+}
+
+// Preview returns the first rows of the dataitem, at most rows (all those
+// of the preview when zero), as recorded by Core in status.preview; no data
+// is downloaded.
+func (s *DataItemsService) Preview(ctx context.Context, req DataItemRequest, rows int) (_ *Preview, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "dataitems.preview", req.Project, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	d, err := s.get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p, err := d.Preview()
+	if err != nil {
+		return nil, err
+	}
+	if rows > 0 && len(p.Rows) > rows {
+		p.Rows = p.Rows[:rows]
+	}
+	return p, nil
+}
+
+// Download downloads the data of the dataitem to req.Destination.
+func (s *DataItemsService) Download(ctx context.Context, req DownloadRequest) (_ []transfer.DownloadInfo, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "dataitems.download", req.Project, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.Transfer == nil {
+		return nil, errors.New("transfer service not specified")
+	}
+	d, err := s.get(ctx, req.DataItemRequest)
+	if err != nil {
+		return nil, err
+	}
+	if req.Format != "" {
+		if f := d.Format(); !strings.EqualFold(f, req.Format) {
+			return nil, fmt.Errorf("dataitem %s is stored as %q, not %s", d.Name, f, req.Format)
+		}
+	}
+	return req.Transfer.Download(ctx, resource, transfer.DownloadRequest{
+		Project:     req.Project,
+		Resource:    resource,
+		ID:          d.ID,
+		Destination: req.Destination,
+	})
+}
+
+// Format returns the format of the stored data: the extension of its path
+// (csv, parquet, ...), else that of the first file in status.files.
+func (d *DataItem) Format() string {
+	paths := []string{d.Path()}
+	files, _ := d.Status["files"].([]interface{})
+	for _, f := range files {
+		m, _ := f.(map[string]interface{})
+		p, _ := m["path"].(string)
+		paths = append(paths, p)
+	}
+	for _, p := range paths {
+		if ext := path.Ext(p); ext != "" && !strings.HasSuffix(p, "/") {
+			return strings.ToLower(strings.TrimPrefix(ext, "."))
+		}
+	}
+	return ""
+}
+
+// Schema parses spec.schema, a Frictionless table schema.
+func (d *DataItem) Schema() *Schema {
+	sc := &Schema{}
+	raw, _ := d.Spec["schema"].(map[string]interface{})
+	fields, _ := raw["fields"].([]interface{})
+	for _, f := range fields {
+		m, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		field := Field{}
+		field.Name, _ = m["name"].(string)
+		field.Type, _ = m["type"].(string)
+		field.Description, _ = m["description"].(string)
+		sc.Fields = append(sc.Fields, field)
+	}
+	switch pk := raw["primaryKey"].(type) {
+	case string:
+		sc.PrimaryKey = []string{pk}
+	case []interface{}:
+		for _, k := range pk {
+			if s, ok := k.(string); ok {
+				sc.PrimaryKey = append(sc.PrimaryKey, s)
+			}
+		}
+	}
+	return sc
+}
+
+// Preview parses status.preview: a list of columns {name, value: [...]},
+// also found under "cols", as the DigitalHub SDKs record it.
+func (d *DataItem) Preview() (*Preview, error) {
+	raw := d.Status["preview"]
+	if m, ok := raw.(map[string]interface{}); ok {
+		raw = m["cols"]
+	}
+	cols, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("dataitem %s has no preview", d.Name)
+	}
+	types := map[string]string{}
+	for _, f := range d.Schema().Fields {
+		types[f.Name] = f.Type
+	}
+	p := &Preview{}
+	var values [][]interface{}
+	for _, c := range cols {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid preview of dataitem %s", d.Name)
+		}
+		name, _ := m["name"].(string)
+		p.Columns = append(p.Columns, Field{Name: name, Type: types[name]})
+		v, _ := m["value"].([]interface{})
+		values = append(values, v)
+	}
+	n := 0
+	for _, v := range values {
+		n = max(n, len(v))
+	}
+	for i := range n {
+		row := make([]interface{}, len(values))
+		for j, v := range values {
+			if i < len(v) {
+				row[j] = v[i]
+			}
+		}
+		p.Rows = append(p.Rows, row)
+	}
+	return p, nil
+}
+
+func (s *DataItemsService) get(ctx context.Context, req DataItemRequest) (*DataItem, error) {
+	if req.Project == "" {
+		return nil, errors.New("project not specified")
+	}
+	if req.ID != "" {
+		b, status, err := s.http.Do(ctx, "GET", s.http.BuildURL(req.Project, resource, req.ID, nil), nil)
+		if err != nil {
+			return nil, fmt.Errorf("get dataitem failed (status %d): %w", status, err)
+		}
+		var d DataItem
+		if err := json.Unmarshal(b, &d); err != nil {
+			return nil, fmt.Errorf("json parsing failed: %w", err)
+		}
+		return &d, nil
+	}
+	if req.Name == "" {
+		return nil, errors.New("dataitem id or name not specified")
+	}
+	params := map[string]string{"name": req.Name, "versions": "latest"}
+	b, status, err := s.http.Do(ctx, "GET", s.http.BuildURL(req.Project, resource, "", params), nil)
+	if err != nil {
+		return nil, fmt.Errorf("get dataitem failed (status %d): %w", status, err)
+	}
+	var page struct {
+		Content []DataItem `json:"content"`
+	}
+	if err := json.Unmarshal(b, &page); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	if len(page.Content) == 0 {
+		return nil, fmt.Errorf("dataitem %s not found", req.Name)
+	}
+	return &page.Content[0], nil
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dataitems
+
+import (
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/transfer"
+)
+
+// DataItem is a dataitem entity.
+type DataItem struct {
+	ID       string                 `json:"id"`
+	Key      string                 `json:"key,omitempty"`
+	Kind     string                 `json:"kind"`
+	Project  string                 `json:"project"`
+	Name     string                 `json:"name"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Spec     map[string]interface{} `json:"spec,omitempty"`
+	Status   map[string]interface{} `json:"status,omitempty"`
+}
+
+// Path returns spec.path, where the data is stored.
+func (d *DataItem) Path() string {
+	s, _ := d.Spec["path"].(string)
+	return s
+}
+
+// DataItemRequest addresses a dataitem of Project by ID or, with Name, its
+// latest version.
+type DataItemRequest struct {
+	Project string
+	ID      string
+	Name    string
+
+	Options []config.RequestOption
+}
+
+// Field is a column of a table schema.
+type Field struct {
+	Name string
+	// Type as in Frictionless table schemas: string, integer, number,
+	// boolean, datetime, ...
+	Type        string
+	Description string
+}
+
+// Schema is the schema of a table dataitem (spec.schema).
+type Schema struct {
+	Fields     []Field
+	PrimaryKey []string
+}
+
+// Preview holds the first rows of a table dataitem.
+type Preview struct {
+	// Columns are in table order, with the types of the schema when known
+	Columns []Field
+	Rows    [][]interface{}
+}
+
+type DownloadRequest struct {
+	DataItemRequest
+
+	// Transfer downloads the files
+	Transfer    *transfer.TransferService
+	Destination string
+	// Format, when set (csv or parquet), must be the format of the stored
+	// data: Download doesn't convert
+	Format string
+}
@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/run"
+)
+
+// Get returns the function req.ID or, by name, the latest version of
+// req.Name.
+func (s *FunctionsService) Get(ctx context.Context, req FunctionRequest) (_ *Function, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "functions.get", req.Project, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	return s.get(ctx, req)
+}
+
+// Build runs the build task of a function (e.g. python+build, which bakes
+// its code and requirements into an image), waits for the run to end and
+// returns the image, the logs of the run and the function as updated by
+// Core. A failed build returns the result along with the error, so the logs
+// tell why.
+func (s *FunctionsService) Build(ctx context.Context, req BuildRequest) (_ *BuildResult, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "functions.build", req.Project, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	fn, err := s.get(ctx, req.FunctionRequest)
+	if err != nil {
+		return nil, err
+	}
+	taskKind := req.TaskKind
+	if taskKind == "" {
+		taskKind = fn.Kind + "+build"
+	}
+	r, runErr := s.runs.Run(ctx, run.RunRequest{
+		Project:        req.Project,
+		TaskKind:       taskKind,
+		FunctionID:     fn.ID,
+		InputSpec:      req.Spec,
+		IdempotencyKey: req.IdempotencyKey,
+		Wait: &run.WaitRequest{
+			Until:      run.StateIn(run.StateCompleted),
+			Interval:   req.Interval,
+			Timeout:    req.Timeout,
+			OnProgress: req.OnProgress,
+		},
+	})
+	if r == nil {
+		return nil, runErr
+	}
+
+	res := &BuildResult{Run: r}
+	res.Logs, err = s.runs.Logs(ctx, run.LogRequest{RunResourceRequest: run.RunResourceRequest{Project: req.Project, Resource: "runs", ID: r.ID}})
+	if err != nil && runErr == nil {
+		return res, err
+	}
+	if runErr != nil {
+		return res, fmt.Errorf("build of function %s failed: %w", fn.Name, runErr)
+	}
+
+	if res.Function, err = s.get(ctx, FunctionRequest{Project: req.Project, ID: fn.ID}); err != nil {
+		return res, err
+	}
+	if res.Image, _ = r.Status["image"].(string); res.Image == "" {
+		res.Image = res.Function.Image()
+	}
+	return res, nil
+}
+
+// Deploy runs the serve task of a function (e.g. python+serve) and waits
+// until the run is RUNNING; a run that ends first is an error.
+func (s *FunctionsService) Deploy(ctx context.Context, req DeployRequest) (_ *run.Run, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "functions.deploy", req.Project, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	fn, err := s.get(ctx, req.FunctionRequest)
+	if err != nil {
+		return nil, err
+	}
+	taskKind := req.TaskKind
+	if taskKind == "" {
+		taskKind = fn.Kind + "+serve"
+	}
+	return s.runs.Run(ctx, run.RunRequest{
+		Project:        req.Project,
+		TaskKind:       taskKind,
+		FunctionID:     fn.ID,
+		InputSpec:      req.Spec,
+		IdempotencyKey: req.IdempotencyKey,
+		Wait: &run.WaitRequest{
+			Until:      run.StateIn(run.StateRunning),
+			Interval:   req.Interval,
+			Timeout:    req.Timeout,
+			OnProgress: req.OnProgress,
+		},
+	})
+}
+
+func (s *FunctionsService) get(ctx context.Context, req FunctionRequest) (*Function, error) {
+	if req.Project == "" {
+		return nil, errors.New("project not specified")
+	}
+	if req.ID != "" {
+		b, status, err := s.http.Do(ctx, "GET", s.http.BuildURL(req.Project, resource, req.ID, nil), nil)
+		if err != nil {
+			return nil, fmt.Errorf("get function failed (status %d): %w", status, err)
+		}
+		var fn Function
+		if err := json.Unmarshal(b, &fn); err != nil {
+			return nil, fmt.Errorf("json parsing failed: %w", err)
+		}
+		return &fn, nil
+	}
+	if req.Name == "" {
+		return nil, errors.New("function id or name not specified")
+	}
+	params := map[string]string{"name": req.Name, "versions": "latest"}
+	b, status, err := s.http.Do(ctx, "GET", s.http.BuildURL(req.Project, resource, "", params), nil)
+	if err != nil {
+		return nil, fmt.Errorf("get function failed (status %d): %w", status, err)
+	}
+	var page struct {
+		Content []Function `json:"content"`
+	}
+	if err := json.Unmarshal(b, &page); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	if len(page.Content) == 0 {
+		return nil, fmt.Errorf("function %s not found", req.Name)
+	}
+	return &page.Content[0], nil
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package functions builds and deploys functions: it runs their +build and
+// +serve tasks through the run service, follows the runs and reports the
+// outcome (image, logs, function updated by Core).
+package functions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/run"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// resource is the Core endpoint of functions.
+const resource = "functions"
+
+type FunctionsService struct {
+	http   config.CoreHTTP
+	tracer trace.Tracer
+	runs   *run.RunService
+}
+
+// NewFunctionsService builds the service; opts customize HTTP client,
+// logger and retries (see config.ServiceOption).
+func NewFunctionsService(ctx context.Context, conf config.Config, opts ...config.ServiceOption) (*FunctionsService, error) {
+	if conf.Core.BaseURL == "" || conf.Core.APIVersion == "" {
+		return nil, errors.New("invalid core config")
+	}
+	runs, err := run.NewRunService(ctx, conf, opts...)
+	if err != nil {
+		return nil, err
+	}
+	o := config.NewServiceOptions(opts...).ForConfig(conf)
+	return &FunctionsService{
+		http:   config.NewHTTPCoreWithOptions(conf.Core, o),
+		tracer: config.Tracer(o.TracerProvider),
+		runs:   runs,
+	}, nil
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package functions_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/dhcoretest"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/functions"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/run"
+)
+
+func newOfflineService(t *testing.T) (*functions.FunctionsService, *dhcoretest.Server) {
+	t.Helper()
+	srv := dhcoretest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Add("demo", "functions", map[string]interface{}{
+		"id": "fn1", "name": "trainer", "kind": "python",
+		"spec": map[string]interface{}{"source": "main.py"},
+	})
+	svc, err := functions.NewFunctionsService(context.Background(), srv.Config())
+	if err != nil {
+		t.Fatalf("failed to init sdk: %v", err)
+	}
+	return svc, srv
+}
+
+func TestBuildOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+
+	// the fake core doesn't build: complete the run at the first poll
+	var states []string
+	res, err := svc.Build(context.Background(), functions.BuildRequest{
+		FunctionRequest: functions.FunctionRequest{Project: "demo", Name: "trainer"},
+		Spec:            map[string]interface{}{"instructions": []interface{}{"pip install torch"}},
+		Interval:        time.Millisecond,
+		OnProgress: func(r *run.Run) {
+			states = append(states, r.State())
+			if r.State() == run.StateCreated {
+				srv.SetLogs(r.ID, []interface{}{map[string]interface{}{
+					"content": "c3RlcCAxLzIK", // "step 1/2\n"
+					"status":  map[string]interface{}{"container": "c-pythonbuild-" + r.ID},
+				}})
+				srv.Patch("demo", "functions", "fn1", map[string]interface{}{"spec": map[string]interface{}{"image": "registry/trainer:1"}})
+				srv.Patch("demo", "runs", r.ID, map[string]interface{}{"status": map[string]interface{}{"state": run.StateCompleted}})
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if res.Run.Kind != "python+build:run" || strings.Join(states, " ") != "CREATED COMPLETED" {
+		t.Fatalf("unexpected run %+v (states %v)", res.Run, states)
+	}
+	if res.Image != "registry/trainer:1" || res.Function.Image() != res.Image {
+		t.Fatalf("unexpected image %q", res.Image)
+	}
+	if len(res.Logs) != 1 || res.Logs[0].Content != "step 1/2\n" {
+		t.Fatalf("unexpected logs %+v", res.Logs)
+	}
+	if spec := res.Run.Spec; spec["instructions"] == nil || !strings.HasPrefix(spec["function"].(string), "python://demo/trainer") {
+		t.Fatalf("unexpected run spec %v", spec)
+	}
+}
+
+func TestBuildFailureReturnsLogsOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+
+	res, err := svc.Build(context.Background(), functions.BuildRequest{
+		FunctionRequest: functions.FunctionRequest{Project: "demo", ID: "fn1"},
+		Interval:        time.Millisecond,
+		OnProgress: func(r *run.Run) {
+			if r.State() == run.StateCreated {
+				srv.SetLogs(r.ID, []interface{}{map[string]interface{}{"content": "ZXJyb3IK"}})
+				srv.Patch("demo", "runs", r.ID, map[string]interface{}{"status": map[string]interface{}{"state": run.StateError}})
+			}
+		},
+	})
+	if err == nil || res == nil || res.Run.State() != run.StateError || len(res.Logs) != 1 {
+		t.Fatalf("expected a failed build with logs, got %+v, %v", res, err)
+	}
+}
+
+func TestDeployOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+
+	r, err := svc.Deploy(context.Background(), functions.DeployRequest{
+		FunctionRequest: functions.FunctionRequest{Project: "demo", Name: "trainer"},
+		Interval:        time.Millisecond,
+		OnProgress: func(r *run.Run) {
+			srv.Patch("demo", "runs", r.ID, map[string]interface{}{"status": map[string]interface{}{"state": run.StateRunning}})
+		},
+	})
+	if err != nil || r.Kind != "python+serve:run" || r.State() != run.StateRunning {
+		t.Fatalf("deploy: %+v, %v", r, err)
+	}
+}
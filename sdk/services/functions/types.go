@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package functions
+
+import (
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/run"
+)
+
+// Function is a function entity.
+type Function struct {
+	ID       string                 `json:"id"`
+	Key      string                 `json:"key,omitempty"`
+	Kind     string                 `json:"kind"`
+	Project  string                 `json:"project"`
+	Name     string                 `json:"name"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Spec     map[string]interface{} `json:"spec,omitempty"`
+	Status   map[string]interface{} `json:"status,omitempty"`
+}
+
+// Image returns spec.image, the container image of the function.
+func (f *Function) Image() string {
+	s, _ := f.Spec["image"].(string)
+	return s
+}
+
+// FunctionRequest addresses a function of Project by ID or, with Name, its
+// latest version.
+type FunctionRequest struct {
+	Project string
+	ID      string
+	Name    string
+
+	Options []config.RequestOption
+}
+
+type BuildRequest struct {
+	FunctionRequest
+
+	// TaskKind defaults to <function kind>+build, e.g. python+build
+	TaskKind string
+	// Spec is the spec of the build run, e.g. {"instructions": [...]}
+	Spec map[string]interface{}
+	// Interval between polls of the run (2s when zero) and Timeout of the
+	// build (none when zero); OnProgress gets the run at each change of
+	// state
+	Interval   time.Duration
+	Timeout    time.Duration
+	OnProgress func(*run.Run)
+	// IdempotencyKey makes a retried Build return the run started the
+	// first time
+	IdempotencyKey string
+}
+
+// BuildResult is the outcome of a build.
+type BuildResult struct {
+	// Run is the build run, in its final state
+	Run *run.Run
+	// Function is the function read again after the build, with the
+	// changes made by Core
+	Function *Function
+	// Image is the image built: status.image of the run, else the image of
+	// the function
+	Image string
+	// Logs are the logs of the build run
+	Logs run.Logs
+}
+
+type DeployRequest struct {
+	FunctionRequest
+
+	// TaskKind defaults to <function kind>+serve, e.g. python+serve
+	TaskKind string
+	// Spec is the spec of the run, e.g. {"replicas": 2}
+	Spec map[string]interface{}
+	// see BuildRequest
+	Interval       time.Duration
+	Timeout        time.Duration
+	OnProgress     func(*run.Run)
+	IdempotencyKey string
+}
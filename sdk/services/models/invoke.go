@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/run"
+)
+
+// v2Runtimes are the serving runtimes speaking the Open Inference Protocol
+// (POST /v2/models/{name}/infer).
+var v2Runtimes = []string{"mlflowserve", "sklearnserve", "huggingfaceserve"}
+
+type InvokeRequest struct {
+	Project string
+	// RunID is the serving run, e.g. of sklearnserve or python+serve
+	RunID string
+	// Path on the service; by default /v2/models/{ModelName}/infer for the
+	// runtimes of the Open Inference Protocol, / otherwise
+	Path string
+	// ModelName defaults to spec.model_name of the run
+	ModelName string
+	// Method defaults to POST
+	Method string
+	// Input is sent as JSON; []byte and json.RawMessage are sent as they
+	// are
+	Input interface{}
+
+	// Options add headers and query params to the call
+	Options []config.RequestOption
+}
+
+// Invoke sends req.Input to the service of the serving run req.RunID and
+// returns the JSON response. The call carries the Core credentials and
+// follows the retry policy of the service; a status other than 2xx is a
+// *config.CoreError.
+func (s *ModelsService) Invoke(ctx context.Context, req InvokeRequest) (_ json.RawMessage, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "models.invoke", req.Project, "runs")
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.Project == "" {
+		return nil, errors.New("project not specified")
+	}
+	if req.RunID == "" {
+		return nil, errors.New("run id not specified")
+	}
+	u, err := s.InvokeURL(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	switch in := req.Input.(type) {
+	case nil:
+	case []byte:
+		body = in
+	case json.RawMessage:
+		body = in
+	default:
+		if body, err = json.Marshal(in); err != nil {
+			return nil, fmt.Errorf("failed to marshal: %w", err)
+		}
+	}
+	method := req.Method
+	if method == "" {
+		method = "POST"
+	}
+	b, status, err := s.http.Do(ctx, method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("invoke failed (status %d): %w", status, err)
+	}
+	return b, nil
+}
+
+// InvokeURL returns the URL Invoke calls for req.
+func (s *ModelsService) InvokeURL(ctx context.Context, req InvokeRequest) (string, error) {
+	b, status, err := s.http.Do(ctx, "GET", s.http.BuildURL(req.Project, "runs", req.RunID, nil), nil)
+	if err != nil {
+		return "", fmt.Errorf("get run failed (status %d): %w", status, err)
+	}
+	var r run.Run
+	if err := json.Unmarshal(b, &r); err != nil {
+		return "", fmt.Errorf("json parsing failed: %w", err)
+	}
+	if state := r.State(); state != run.StateRunning {
+		return "", fmt.Errorf("run %s is %s, not %s", r.ID, state, run.StateRunning)
+	}
+	ep, err := r.Endpoint()
+	if err != nil {
+		return "", err
+	}
+	base := ep.URL
+	if base == "" {
+		addr, err := ep.Address(0)
+		if err != nil {
+			return "", err
+		}
+		base = "http://" + addr
+	}
+
+	path := req.Path
+	if path == "" {
+		path = "/"
+		runtime, _, _ := strings.Cut(r.Kind, "+")
+		if slices.Contains(v2Runtimes, runtime) {
+			name := req.ModelName
+			if name == "" {
+				name, _ = r.Spec["model_name"].(string)
+			}
+			if name == "" {
+				return "", fmt.Errorf("model name not specified for run %s", r.ID)
+			}
+			path = "/v2/models/" + url.PathEscape(name) + "/infer"
+		}
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/"), nil
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package models calls deployed models: it finds the service of a serving
+// run in its status and sends inference requests to it, with the Core
+// credentials.
+package models
+
+import (
+	"context"
+	"errors"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ModelsService struct {
+	http   config.CoreHTTP
+	tracer trace.Tracer
+}
+
+// NewModelsService builds the service; opts customize HTTP client, logger
+// and retries (see config.ServiceOption).
+func NewModelsService(_ context.Context, conf config.Config, opts ...config.ServiceOption) (*ModelsService, error) {
+	if conf.Core.BaseURL == "" || conf.Core.APIVersion == "" {
+		return nil, errors.New("invalid core config")
+	}
+	o := config.NewServiceOptions(opts...).ForConfig(conf)
+	return &ModelsService{
+		http:   config.NewHTTPCoreWithOptions(conf.Core, o),
+		tracer: config.Tracer(o.TracerProvider),
+	}, nil
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/dhcoretest"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/models"
+)
+
+func TestInvokeOffline(t *testing.T) {
+	var path, auth string
+	var input map[string]interface{}
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &input)
+		if r.URL.Path == "/broken" {
+			http.Error(w, `{"error":"bad input"}`, http.StatusUnprocessableEntity)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"outputs":[{"name":"predict","data":[1]}]}`))
+	}))
+	defer backend.Close()
+
+	srv := dhcoretest.NewServer()
+	defer srv.Close()
+	srv.Token = "secret-token"
+	srv.Add("demo", "runs", map[string]interface{}{
+		"id": "srv1", "kind": "sklearnserve+serve:run",
+		"spec":   map[string]interface{}{"model_name": "iris"},
+		"status": map[string]interface{}{"state": "RUNNING", "service": map[string]interface{}{"url": backend.URL}},
+	})
+	srv.Add("demo", "runs", map[string]interface{}{
+		"id": "srv2", "kind": "python+serve:run",
+		"status": map[string]interface{}{"state": "PENDING"},
+	})
+	svc, err := models.NewModelsService(context.Background(), srv.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := svc.Invoke(context.Background(), models.InvokeRequest{
+		Project: "demo",
+		RunID:   "srv1",
+		Input:   map[string]interface{}{"inputs": []interface{}{map[string]interface{}{"name": "x", "data": []float64{5.1, 3.5}}}},
+	})
+	if err != nil {
+		t.Fatalf("invoke failed: %v", err)
+	}
+	if path != "/v2/models/iris/infer" || auth != "Bearer secret-token" || input["inputs"] == nil {
+		t.Fatalf("unexpected call: path %s, auth %q, input %v", path, auth, input)
+	}
+	var res struct {
+		Outputs []struct{ Name string } `json:"outputs"`
+	}
+	if err := json.Unmarshal(out, &res); err != nil || len(res.Outputs) != 1 || res.Outputs[0].Name != "predict" {
+		t.Fatalf("unexpected response %s (%v)", out, err)
+	}
+
+	_, err = svc.Invoke(context.Background(), models.InvokeRequest{Project: "demo", RunID: "srv1", Path: "/broken", Input: json.RawMessage(`{}`)})
+	if !config.HasStatus(err, http.StatusUnprocessableEntity) {
+		t.Fatalf("expected status 422, got %v", err)
+	}
+	if _, err := svc.Invoke(context.Background(), models.InvokeRequest{Project: "demo", RunID: "srv2"}); err == nil {
+		t.Fatal("expected an error for a run not RUNNING")
+	}
+}
@@ -0,0 +1,322 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package projects
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/crud"
+)
+
+// ArchiveVersion is the version of the archive layout written by Export.
+const ArchiveVersion = 1
+
+// manifestFile is the entry of the archive describing its content.
+const manifestFile = "manifest.json"
+
+// ExportResources are the resources Export saves besides the project, in
+// the order Import recreates them. Secrets are saved as references: their
+// values never leave Core and must be set again after an import.
+var ExportResources = []string{"secrets", "artifacts", "dataitems", "models", "functions", "workflows"}
+
+// Manifest describes an archive written by Export.
+type Manifest struct {
+	Version  int             `json:"version"`
+	Project  string          `json:"project"`
+	Exported time.Time       `json:"exported"`
+	Entries  []ManifestEntry `json:"entries"`
+}
+
+// ManifestEntry is an entity of the archive, stored as YAML in File.
+type ManifestEntry struct {
+	Resource string `json:"resource"`
+	Name     string `json:"name"`
+	ID       string `json:"id,omitempty"`
+	File     string `json:"file"`
+}
+
+type ImportOptions struct {
+	// Project is the name of the imported project; empty keeps the name of
+	// the archive
+	Project string
+	// Names renames entities (old name -> new name); references to them by
+	// key or by "kind://project/name" are rewritten too
+	Names map[string]string
+	// ResetIDs lets Core assign new ids, e.g. to import a copy into the
+	// Core it comes from. Entities are then matched by name, so only the
+	// last version of each is kept.
+	ResetIDs bool
+	// DryRun validates the entities and reports what would change (see
+	// crud.ApplyRequest)
+	DryRun bool
+	// Options add headers and query params to the Core calls
+	// (config.WithHeader, config.WithQueryParam)
+	Options []config.RequestOption
+}
+
+// Export writes the project name and all the versions of its entities (see
+// ExportResources) to w as a gzipped tarball: manifest.json, project.yaml
+// and a YAML file per entity, as written by crud.Export with ids kept.
+func (s *ProjectsService) Export(ctx context.Context, name string, w io.Writer, opts ...config.RequestOption) (err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "projects.export", name, resource)
+	defer func() { config.EndSpan(span, err) }()
+
+	if name == "" {
+		return errors.New("name is required")
+	}
+	m := Manifest{Version: ArchiveVersion, Project: name, Exported: time.Now().UTC()}
+	files := map[string][]byte{}
+
+	p, err := s.crud.Export(ctx, crud.ExportRequest{
+		ResourceRequest: crud.ResourceRequest{Resource: resource, Options: opts},
+		ID:              name,
+		KeepIDs:         true,
+	})
+	if err != nil {
+		return err
+	}
+	m.Entries = append(m.Entries, ManifestEntry{Resource: resource, Name: name, ID: name, File: "project.yaml"})
+	files["project.yaml"] = p.Bundle()
+
+	for _, r := range ExportResources {
+		res, err := s.crud.Export(ctx, crud.ExportRequest{
+			ResourceRequest: crud.ResourceRequest{Project: name, Resource: r, Options: opts},
+			Params:          map[string]string{"versions": "all"},
+			KeepIDs:         true,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", r, err)
+		}
+		for _, d := range res.Documents {
+			file := path.Join(r, d.Name+"-"+d.ID+".yaml")
+			m.Entries = append(m.Entries, ManifestEntry{Resource: r, Name: d.Name, ID: d.ID, File: file})
+			files[file] = d.YAML
+		}
+	}
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %w", err)
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	write := func(file string, data []byte) error {
+		hdr := &tar.Header{Name: file, Mode: 0o644, Size: int64(len(data)), ModTime: m.Exported}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+		return nil
+	}
+	if err := write(manifestFile, manifest); err != nil {
+		return err
+	}
+	for _, e := range m.Entries {
+		if err := write(e.File, files[e.File]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// Import recreates the project of an archive written by Export, e.g. in
+// another environment, with crud.Apply: entities that already exist are
+// updated, so an import can be repeated. The result lists the changes made
+// so far, also on failure.
+func (s *ProjectsService) Import(ctx context.Context, r io.Reader, opts ImportOptions) (_ *crud.ChangeSet, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "projects.import", opts.Project, resource)
+	defer func() { config.EndSpan(span, err) }()
+
+	m, files, err := readArchive(r)
+	if err != nil {
+		return nil, err
+	}
+	target := opts.Project
+	if target == "" {
+		target = m.Project
+	}
+
+	// one Apply per resource: the kind of functions doesn't tell their
+	// resource once keys are reset
+	var resources []string
+	docs := map[string][]byte{}
+	for _, e := range m.Entries {
+		data, ok := files[e.File]
+		if !ok {
+			return nil, fmt.Errorf("archive: %s is missing", e.File)
+		}
+		var entity map[string]interface{}
+		if err := yaml.Unmarshal(data, &entity); err != nil {
+			return nil, fmt.Errorf("archive: %s: %w", e.File, err)
+		}
+		remap(entity, e.Resource, m.Project, target, opts)
+		b, err := json.Marshal(entity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal: %w", err)
+		}
+		if !slices.Contains(resources, e.Resource) {
+			resources = append(resources, e.Resource)
+		}
+		docs[e.Resource] = append(append(docs[e.Resource], b...), "\n---\n"...)
+	}
+
+	res := &crud.ChangeSet{}
+	for _, r := range resources {
+		rr := crud.ResourceRequest{Project: target, Resource: r, Options: opts.Options}
+		if r == resource {
+			rr.Project = ""
+		}
+		cs, err := s.crud.Apply(ctx, crud.ApplyRequest{ResourceRequest: rr, Data: docs[r], DryRun: opts.DryRun})
+		if cs != nil {
+			res.Changes = append(res.Changes, cs.Changes...)
+		}
+		if err != nil {
+			return res, fmt.Errorf("%s: %w", r, err)
+		}
+	}
+	return res, nil
+}
+
+func readArchive(r io.Reader) (*Manifest, map[string][]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, tr); err != nil {
+			return nil, nil, fmt.Errorf("archive: %s: %w", hdr.Name, err)
+		}
+		files[path.Clean(hdr.Name)] = buf.Bytes()
+	}
+
+	data, ok := files[manifestFile]
+	if !ok {
+		return nil, nil, fmt.Errorf("archive: %s is missing", manifestFile)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, nil, fmt.Errorf("archive: %s: %w", manifestFile, err)
+	}
+	if m.Version != ArchiveVersion {
+		return nil, nil, fmt.Errorf("archive: unsupported version %d", m.Version)
+	}
+	if m.Project == "" {
+		return nil, nil, fmt.Errorf("archive: %s has no project", manifestFile)
+	}
+	return &m, files, nil
+}
+
+// remap moves entity from project from to project to, renames it and the
+// entities it refers to according to opts.Names, and drops its ids with
+// opts.ResetIDs.
+func remap(entity map[string]interface{}, res, from, to string, opts ImportOptions) {
+	if res == resource {
+		entity["name"] = to
+		if _, ok := entity["id"]; ok {
+			entity["id"] = to
+		}
+	} else {
+		entity["project"] = to
+		if name, _ := entity["name"].(string); opts.Names[name] != "" {
+			entity["name"] = opts.Names[name]
+		}
+	}
+	if meta, ok := entity["metadata"].(map[string]interface{}); ok {
+		if _, ok := meta["project"]; ok {
+			meta["project"] = to
+		}
+		if _, ok := meta["name"]; ok {
+			meta["name"] = entity["name"]
+		}
+	}
+	if opts.ResetIDs {
+		delete(entity, "key")
+		if res != resource {
+			delete(entity, "id")
+		}
+	}
+	for k, v := range entity {
+		if k != "metadata" {
+			entity[k] = rewriteRefs(v, from, to, opts.Names)
+		}
+	}
+}
+
+// storageSchemes are the URL schemes of data paths, whose first segment is
+// a bucket or host rather than a project.
+var storageSchemes = []string{"s3", "zip+s3", "http", "https", "ftp", "sql", "file", "gs"}
+
+// rewriteRefs rewrites the references to entities of project from in v
+// ("kind://project/name:id" and "store://project/type/kind/name:id").
+func rewriteRefs(v interface{}, from, to string, names map[string]string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, x := range t {
+			t[k] = rewriteRefs(x, from, to, names)
+		}
+	case []interface{}:
+		for i, x := range t {
+			t[i] = rewriteRefs(x, from, to, names)
+		}
+	case string:
+		scheme, rest, ok := strings.Cut(t, "://")
+		if !ok || slices.Contains(storageSchemes, scheme) {
+			return t
+		}
+		parts := strings.Split(rest, "/")
+		if parts[0] != from {
+			return t
+		}
+		parts[0] = to
+		if len(parts) > 1 {
+			last := len(parts) - 1
+			name, version, hasVersion := strings.Cut(parts[last], ":")
+			if n := names[name]; n != "" {
+				parts[last] = n
+				if hasVersion {
+					parts[last] += ":" + version
+				}
+			}
+		}
+		return scheme + "://" + strings.Join(parts, "/")
+	}
+	return v
+}
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/entities"
+)
+
+// Create creates a project and returns it as stored by Core.
+func (s *ProjectsService) Create(ctx context.Context, req CreateRequest) (_ *Project, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "projects.create", req.Name, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	spec, err := entities.FromMap[entities.ProjectSpec](req.Spec)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(Project{
+		Name: req.Name,
+		Kind: "project",
+		Metadata: Metadata{
+			Name:        req.Name,
+			Description: req.Description,
+			Labels:      req.Labels,
+		},
+		Spec: spec,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+
+	key := req.IdempotencyKey
+	if key == "" {
+		key = config.NewIdempotencyKey()
+	}
+	url := s.http.BuildURL("", resource, "", nil)
+	b, _, err := s.http.Do(config.ContextWithIdempotencyKey(ctx, key), "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	return decodeProject(b)
+}
+
+// Get returns the project name.
+func (s *ProjectsService) Get(ctx context.Context, name string, opts ...config.RequestOption) (_ *Project, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "projects.get", name, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, opts...)
+
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	b, _, err := s.http.Do(ctx, "GET", s.http.BuildURL("", resource, name, nil), nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeProject(b)
+}
+
+// List returns the projects visible to the caller, reading all pages.
+func (s *ProjectsService) List(ctx context.Context, req ListRequest) (_ []Project, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "projects.list", "", resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	params := map[string]string{}
+	if req.Params != nil {
+		maps.Copy(params, req.Params)
+	}
+	var out []Project
+	for {
+		b, _, err := s.http.Do(ctx, "GET", s.http.BuildURL("", resource, "", params), nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Content  []Project `json:"content"`
+			Pageable struct {
+				PageNumber int `json:"pageNumber"`
+			} `json:"pageable"`
+			TotalPages int `json:"totalPages"`
+		}
+		if err := json.Unmarshal(b, &page); err != nil {
+			return nil, fmt.Errorf("json parsing failed: %w", err)
+		}
+		out = append(out, page.Content...)
+		if page.Pageable.PageNumber >= page.TotalPages-1 {
+			return out, nil
+		}
+		params["page"] = strconv.Itoa(page.Pageable.PageNumber + 1)
+	}
+}
+
+// Update replaces the project p.Name with p, e.g. as returned by Get with
+// its metadata or spec changed, and returns the stored project.
+func (s *ProjectsService) Update(ctx context.Context, p *Project, opts ...config.RequestOption) (_ *Project, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "projects.update", projectName(p), resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, opts...)
+
+	name := projectName(p)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+	b, status, err := s.http.Do(ctx, "PUT", s.http.BuildURL("", resource, name, nil), body)
+	if err != nil {
+		return nil, fmt.Errorf("update failed (status %d): %w", status, err)
+	}
+	return decodeProject(b)
+}
+
+// Delete deletes the project name; cascade deletes its entities too.
+func (s *ProjectsService) Delete(ctx context.Context, name string, cascade bool, opts ...config.RequestOption) (err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "projects.delete", name, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, opts...)
+
+	if name == "" {
+		return errors.New("name is required")
+	}
+	url := s.http.BuildURL("", resource, name, map[string]string{"cascade": strconv.FormatBool(cascade)})
+	_, status, err := s.http.Do(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("delete failed (status %d): %w", status, err)
+	}
+	return nil
+}
+
+// Config returns the configuration of the project name, the "config" map
+// of its spec; nil when the project has none.
+func (s *ProjectsService) Config(ctx context.Context, name string, opts ...config.RequestOption) (map[string]interface{}, error) {
+	p, err := s.Get(ctx, name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cfg, _ := p.Spec.Extra["config"].(map[string]interface{})
+	return cfg, nil
+}
+
+func projectName(p *Project) string {
+	if p == nil {
+		return ""
+	}
+	if p.ID != "" {
+		return p.ID
+	}
+	return p.Name
+}
+
+func decodeProject(b []byte) (*Project, error) {
+	var p Project
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	return &p, nil
+}
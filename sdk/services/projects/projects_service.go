@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package projects manages DigitalHub projects with typed operations:
+// lifecycle, metadata, sharing with other users, project configuration and
+// export/import of whole projects.
+package projects
+
+import (
+	"context"
+	"errors"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/crud"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// resource is the Core endpoint of projects.
+const resource = "projects"
+
+type ProjectsService struct {
+	http   config.CoreHTTP
+	tracer trace.Tracer
+	// crud exports and applies the entities of a project (Export, Import)
+	crud *crud.CrudService
+}
+
+// NewProjectsService builds the service; opts customize HTTP client, logger
+// and retries (see config.ServiceOption).
+func NewProjectsService(ctx context.Context, conf config.Config, opts ...config.ServiceOption) (*ProjectsService, error) {
+	if conf.Core.BaseURL == "" || conf.Core.APIVersion == "" {
+		return nil, errors.New("invalid core config")
+	}
+	c, err := crud.NewCrudService(ctx, conf, opts...)
+	if err != nil {
+		return nil, err
+	}
+	o := config.NewServiceOptions(opts...).ForConfig(conf)
+	return &ProjectsService{
+		http:   config.NewHTTPCoreWithOptions(conf.Core, o),
+		tracer: config.Tracer(o.TracerProvider),
+		crud:   c,
+	}, nil
+}
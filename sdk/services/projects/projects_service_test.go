@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package projects_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/dhcoretest"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/crud"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/projects"
+)
+
+func newOfflineService(t *testing.T) (*projects.ProjectsService, *dhcoretest.Server) {
+	t.Helper()
+	srv := dhcoretest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Token = "test-token"
+
+	svc, err := projects.NewProjectsService(context.Background(), srv.Config())
+	if err != nil {
+		t.Fatalf("failed to init sdk: %v", err)
+	}
+	return svc, srv
+}
+
+func TestProjectLifecycleOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+
+	p, err := svc.Create(ctx, projects.CreateRequest{
+		Name:        "demo",
+		Description: "a demo",
+		Labels:      []string{"test"},
+		Spec:        map[string]interface{}{"config": map[string]interface{}{"bucket": "datalake"}},
+	})
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if p.ID != "demo" || p.Metadata.Description != "a demo" || p.Metadata.Created == "" {
+		t.Fatalf("unexpected project %+v", p)
+	}
+	srv.Add("", "projects", map[string]interface{}{"name": "other"})
+
+	list, err := svc.List(ctx, projects.ListRequest{Params: map[string]string{"size": "1"}})
+	if err != nil || len(list) != 2 {
+		t.Fatalf("list: %v, %v", list, err)
+	}
+
+	p.Metadata.Labels = append(p.Metadata.Labels, "updated")
+	if p, err = svc.Update(ctx, p); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	got, err := svc.Get(ctx, "demo")
+	if err != nil || len(got.Metadata.Labels) != 2 {
+		t.Fatalf("get: %+v, %v", got, err)
+	}
+
+	cfg, err := svc.Config(ctx, "demo")
+	if err != nil || cfg["bucket"] != "datalake" {
+		t.Fatalf("config: %v, %v", cfg, err)
+	}
+
+	if err := svc.Delete(ctx, "other", true); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := svc.Get(ctx, "other"); err == nil {
+		t.Fatal("expected deleted project to be gone")
+	}
+}
+
+func TestShareOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	srv.Add("", "projects", map[string]interface{}{"name": "demo"})
+
+	m, err := svc.Share(ctx, "demo", "alice", "editor")
+	if err != nil {
+		t.Fatalf("share failed: %v", err)
+	}
+	if m.ID == "" || m.User != "alice" || m.Role != "editor" {
+		t.Fatalf("unexpected member %+v", m)
+	}
+	if _, err := svc.Share(ctx, "demo", "bob", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	members, err := svc.ListMembers(ctx, "demo")
+	if err != nil || len(members) != 2 {
+		t.Fatalf("members: %+v, %v", members, err)
+	}
+
+	if err := svc.Unshare(ctx, "demo", "alice"); err != nil {
+		t.Fatalf("unshare failed: %v", err)
+	}
+	if members, _ = svc.ListMembers(ctx, "demo"); len(members) != 1 || members[0].User != "bob" {
+		t.Fatalf("unexpected members %+v", members)
+	}
+	if err := svc.Unshare(ctx, "demo", "alice"); err == nil {
+		t.Fatal("expected an error for a user without shares")
+	}
+}
+
+func TestExportImportOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	srv.Add("", "projects", map[string]interface{}{"name": "demo", "metadata": map[string]interface{}{"description": "a demo"}})
+	srv.Add("demo", "functions", map[string]interface{}{"id": "f1", "name": "train", "kind": "python"})
+	srv.Add("demo", "workflows", map[string]interface{}{
+		"name": "pipeline", "kind": "kfp",
+		"spec": map[string]interface{}{"steps": []interface{}{"python://demo/train:f1"}, "source": "s3://demo/pipeline.py"},
+	})
+	for _, path := range []string{"s3://datalake/v1.csv", "s3://datalake/v2.csv"} {
+		srv.Add("demo", "artifacts", map[string]interface{}{"name": "dataset", "kind": "artifact", "spec": map[string]interface{}{"path": path}})
+	}
+	srv.Add("demo", "secrets", map[string]interface{}{"name": "token", "kind": "kubernetes"})
+	srv.Add("demo", "runs", map[string]interface{}{"kind": "python+run"})
+
+	var buf bytes.Buffer
+	if err := svc.Export(ctx, "demo", &buf); err != nil {
+		t.Fatal(err)
+	}
+	archive := buf.Bytes()
+
+	opts := projects.ImportOptions{Project: "prod", Names: map[string]string{"train": "train-prod"}}
+	res, err := svc.Import(ctx, bytes.NewReader(archive), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count(crud.ChangeCreated) != 6 {
+		t.Fatalf("unexpected changes %+v", res.Changes)
+	}
+	if p, err := svc.Get(ctx, "prod"); err != nil || p.Metadata.Description != "a demo" {
+		t.Fatalf("project not imported: %+v, %v", p, err)
+	}
+	fns := srv.List("prod", "functions")
+	if len(fns) != 1 || fns[0]["name"] != "train-prod" || fns[0]["id"] != "f1" {
+		t.Fatalf("unexpected functions %v", fns)
+	}
+	wf := srv.List("prod", "workflows")
+	spec := wf[0]["spec"].(map[string]interface{})
+	if spec["steps"].([]interface{})[0] != "python://prod/train-prod:f1" || spec["source"] != "s3://demo/pipeline.py" {
+		t.Fatalf("references not rewritten: %v", spec)
+	}
+	if len(srv.List("prod", "artifacts")) != 2 || len(srv.List("prod", "secrets")) != 1 || len(srv.List("prod", "runs")) != 0 {
+		t.Fatal("unexpected entities imported")
+	}
+
+	// importing again changes nothing
+	if res, err = svc.Import(ctx, bytes.NewReader(archive), opts); err != nil {
+		t.Fatal(err)
+	}
+	if res.Count(crud.ChangeUnchanged) != 6 {
+		t.Fatalf("unexpected changes %+v", res.Changes)
+	}
+
+	if _, err := svc.Import(ctx, bytes.NewReader([]byte("not an archive")), opts); err == nil {
+		t.Fatal("expected an error for an invalid archive")
+	}
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// shareURL is {base}/projects/{name}/share, the sharing endpoint of Core.
+func (s *ProjectsService) shareURL(name string, params map[string]string) string {
+	q := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	u := s.http.BuildURL("", resource, name, nil) + "/share"
+	if enc := q.Encode(); enc != "" {
+		u += "?" + enc
+	}
+	return u
+}
+
+// Share shares the project name with user; role may be empty for the
+// default role of Core. It returns the new member.
+func (s *ProjectsService) Share(ctx context.Context, name, user, role string, opts ...config.RequestOption) (_ *Member, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "projects.share", name, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, opts...)
+
+	if name == "" || user == "" {
+		return nil, errors.New("project name and user are required")
+	}
+	b, status, err := s.http.Do(ctx, "POST", s.shareURL(name, map[string]string{"user": user, "role": role}), nil)
+	if err != nil {
+		return nil, fmt.Errorf("share failed (status %d): %w", status, err)
+	}
+	var m Member
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	return &m, nil
+}
+
+// Unshare removes the shares of the project name with user; it fails if
+// there are none.
+func (s *ProjectsService) Unshare(ctx context.Context, name, user string, opts ...config.RequestOption) (err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "projects.unshare", name, resource)
+	defer func() { config.EndSpan(span, err) }()
+
+	members, err := s.ListMembers(ctx, name, opts...)
+	if err != nil {
+		return err
+	}
+	ctx = config.ContextWithRequestOptions(ctx, opts...)
+	found := false
+	for _, m := range members {
+		if m.User != user {
+			continue
+		}
+		found = true
+		if _, status, err := s.http.Do(ctx, "DELETE", s.shareURL(name, map[string]string{"id": m.ID}), nil); err != nil {
+			return fmt.Errorf("unshare failed (status %d): %w", status, err)
+		}
+	}
+	if !found {
+		return fmt.Errorf("project %s is not shared with %s", name, user)
+	}
+	return nil
+}
+
+// ListMembers returns the users the project name is shared with.
+func (s *ProjectsService) ListMembers(ctx context.Context, name string, opts ...config.RequestOption) (_ []Member, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "projects.members", name, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, opts...)
+
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	b, _, err := s.http.Do(ctx, "GET", s.shareURL(name, nil), nil)
+	if err != nil {
+		return nil, err
+	}
+	var members []Member
+	if err := json.Unmarshal(b, &members); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	return members, nil
+}
@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package projects
+
+import (
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/entities"
+)
+
+// Project is a project entity; its id is its name.
+type Project = entities.Project
+
+// Metadata are the descriptive fields of a project.
+type Metadata = entities.Metadata
+
+// Member is a user the project is shared with.
+type Member struct {
+	// ID identifies the share
+	ID      string `json:"id,omitempty"`
+	Project string `json:"project,omitempty"`
+	User    string `json:"user"`
+	// Role is defined by Core; empty is the default role
+	Role string `json:"role,omitempty"`
+}
+
+type CreateRequest struct {
+	Name        string
+	Description string
+	Labels      []string
+	Spec        map[string]interface{}
+
+	// IdempotencyKey makes a retried create return the project created the
+	// first time; empty generates a new key per call
+	IdempotencyKey string
+	// Options add headers and query params to the Core calls
+	// (config.WithHeader, config.WithQueryParam)
+	Options []config.RequestOption
+}
+
+type ListRequest struct {
+	// Params are query params of the list, e.g. "name" or "size"
+	Params  map[string]string
+	Options []config.RequestOption
+}
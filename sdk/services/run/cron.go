@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed cron expression.
+type Cron struct {
+	expr    string
+	seconds uint64 // bit i: second i
+	minutes uint64
+	hours   uint64
+	dom     uint64 // 1-31
+	months  uint64 // 1-12
+	dow     uint64 // 0-6, Sunday is 0
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	dayNames   = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+)
+
+// ParseCron parses a cron expression: the 5 fields minute, hour, day of
+// month, month and day of week, with a leading seconds field in the 6-field
+// form of Core (Spring), or a macro such as @daily. Fields take *, ?,
+// lists, ranges, steps and the names of months and days. As in Spring, a
+// day must match both the day of month and the day of week: "0 0 0 13 *
+// FRI" fires on Friday the 13th only.
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) == 1 {
+		if m, ok := cronMacros[strings.ToLower(fields[0])]; ok {
+			fields = strings.Fields(m)
+		}
+	}
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 or 6 fields", expr)
+	}
+	c := &Cron{expr: expr}
+	var err error
+	parse := func(f string, min, max int, names []string, nameBase int) uint64 {
+		if err != nil {
+			return 0
+		}
+		var bits uint64
+		bits, err = parseCronField(f, min, max, names, nameBase)
+		if err != nil {
+			err = fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		return bits
+	}
+	c.seconds = parse(fields[0], 0, 59, nil, 0)
+	c.minutes = parse(fields[1], 0, 59, nil, 0)
+	c.hours = parse(fields[2], 0, 23, nil, 0)
+	c.dom = parse(fields[3], 1, 31, nil, 0)
+	c.months = parse(fields[4], 1, 12, monthNames, 1)
+	c.dow = parse(fields[5], 0, 7, dayNames, 0)
+	if err != nil {
+		return nil, err
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	return c, nil
+}
+
+func (c *Cron) String() string { return c.expr }
+
+func parseCronField(f string, min, max int, names []string, nameBase int) (uint64, error) {
+	value := func(s string) (int, error) {
+		for i, n := range names {
+			if strings.EqualFold(s, n) {
+				return i + nameBase, nil
+			}
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil || v < min || v > max {
+			return 0, fmt.Errorf("%q is not in %d-%d", s, min, max)
+		}
+		return v, nil
+	}
+	var bits uint64
+	for _, part := range strings.Split(f, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+		lo, hi := min, max
+		switch {
+		case rng == "*" || rng == "?":
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = value(a); err != nil {
+				return 0, err
+			}
+			if hi, err = value(b); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q", rng)
+			}
+		default:
+			v, err := value(rng)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if !hasStep {
+				hi = v
+			}
+		}
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time after t the expression fires, in the
+// location of t; the zero time when it never does (e.g. February 30).
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Second).Add(time.Second)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case c.months&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case c.hours&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case c.minutes&(1<<uint(t.Minute())) == 0:
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		case c.seconds&(1<<uint(t.Second())) == 0:
+			t = t.Add(time.Second)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *Cron) dayMatches(t time.Time) bool {
+	return c.dom&(1<<uint(t.Day())) != 0 && c.dow&(1<<uint(t.Weekday())) != 0
+}
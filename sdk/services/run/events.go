@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// Event kinds.
+const (
+	// EventTransition is a change of state of the run (status.transitions)
+	EventTransition = "transition"
+	// EventKubernetes is an event of the Kubernetes objects of the run
+	// (status.k8s.events), e.g. a failed scheduling or image pull
+	EventKubernetes = "k8s"
+)
+
+// Event is a step in the life of a run.
+type Event struct {
+	// Time is zero when Core didn't record it
+	Time time.Time
+	Kind string
+	// State is the state entered, for transitions
+	State string
+	// Type is Normal or Warning, for Kubernetes events
+	Type    string
+	Reason  string
+	Message string
+	// Object is the Kubernetes object the event is about, e.g. Pod/run1-abc
+	Object string
+	// Count is how many times Kubernetes saw the event
+	Count int
+}
+
+// Events are the events of a run, in time order.
+type Events []Event
+
+// Warnings returns the Kubernetes events of type Warning, the usual reason
+// of a run stuck in PENDING.
+func (e Events) Warnings() Events {
+	var out Events
+	for _, ev := range e {
+		if ev.Kind == EventKubernetes && ev.Type == "Warning" {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+type EventsRequest struct {
+	RunResourceRequest
+}
+
+// Events returns the state transitions of the run req.ID and the events of
+// its Kubernetes objects, in time order. Resource defaults to "runs".
+func (s *RunService) Events(ctx context.Context, req EventsRequest) (_ Events, err error) {
+	if req.Resource == "" {
+		req.Resource = "runs"
+	}
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.events", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.Project == "" {
+		return nil, errors.New("project not specified")
+	}
+	if req.ID == "" {
+		return nil, errors.New("id not specified")
+	}
+	r, err := s.getRun(ctx, req.RunResourceRequest)
+	if err != nil {
+		return nil, err
+	}
+	return r.Events(), nil
+}
+
+// Events extracts the events from the status of the run (see
+// RunService.Events). Events without a time come last.
+func (r *Run) Events() Events {
+	var out Events
+	if list, ok := r.Status["transitions"].([]interface{}); ok {
+		for _, it := range list {
+			m, ok := it.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ev := Event{
+				Kind:    EventTransition,
+				Time:    eventTime(m["time"]),
+				State:   stringField(m, "status"),
+				Message: stringField(m, "message"),
+			}
+			if ev.State == "" {
+				ev.State = stringField(m, "state")
+			}
+			out = append(out, ev)
+		}
+	}
+	k8s, _ := r.Status["k8s"].(map[string]interface{})
+	if list, ok := k8s["events"].([]interface{}); ok {
+		for _, it := range list {
+			m, ok := it.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ev := Event{
+				Kind:    EventKubernetes,
+				Type:    stringField(m, "type"),
+				Reason:  stringField(m, "reason"),
+				Message: stringField(m, "message"),
+			}
+			for _, f := range []string{"lastTimestamp", "eventTime", "firstTimestamp", "timestamp"} {
+				if ev.Time = eventTime(m[f]); !ev.Time.IsZero() {
+					break
+				}
+			}
+			if c, ok := m["count"].(float64); ok {
+				ev.Count = int(c)
+			}
+			if obj, ok := m["involvedObject"].(map[string]interface{}); ok {
+				ev.Object = strings.Trim(stringField(obj, "kind")+"/"+stringField(obj, "name"), "/")
+			}
+			out = append(out, ev)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		ti, tj := out[i].Time, out[j].Time
+		if ti.IsZero() || tj.IsZero() {
+			return !ti.IsZero() && tj.IsZero()
+		}
+		return ti.Before(tj)
+	})
+	return out
+}
+
+// eventTime parses an RFC 3339 string or epoch milliseconds; the zero time
+// otherwise.
+func eventTime(v interface{}) time.Time {
+	switch t := v.(type) {
+	case string:
+		if ts, err := time.Parse(time.RFC3339Nano, t); err == nil {
+			return ts
+		}
+	case float64:
+		return time.UnixMilli(int64(t)).UTC()
+	}
+	return time.Time{}
+}
+
+func stringField(m map[string]interface{}, k string) string {
+	s, _ := m[k].(string)
+	return s
+}
+
+// FormatEvents writes events as a table of time, kind, state or reason, and
+// message.
+func FormatEvents(w io.Writer, events Events) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tKIND\tSTATE/REASON\tOBJECT\tMESSAGE")
+	for _, ev := range events {
+		ts := "-"
+		if !ev.Time.IsZero() {
+			ts = ev.Time.Format(time.RFC3339)
+		}
+		what := ev.State
+		if ev.Kind == EventKubernetes {
+			what = ev.Type + " " + ev.Reason
+			if ev.Count > 1 {
+				what += fmt.Sprintf(" (x%d)", ev.Count)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", ts, ev.Kind, what, orDash(ev.Object), ev.Message)
+	}
+	return tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
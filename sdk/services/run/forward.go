@@ -0,0 +1,273 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// ServicePort is a port exposed by the service of a run.
+type ServicePort struct {
+	Name       string
+	Port       int
+	TargetPort int
+	NodePort   int
+	Protocol   string
+}
+
+// Endpoint is the Kubernetes service of a run exposing one (notebooks,
+// model serving, ...), as reported in status.service.
+type Endpoint struct {
+	Name      string
+	Namespace string
+	// Type is ClusterIP, NodePort or LoadBalancer
+	Type string
+	// URL as reported by Core (http:// is added when it has no scheme);
+	// empty when Core reports none, see Address
+	URL   string
+	Ports []ServicePort
+}
+
+// Address returns host:port of the endpoint for port: by default the port
+// of the URL, else the first one of the service.
+func (e *Endpoint) Address(port int) (string, error) {
+	host := ""
+	if u, err := url.Parse(e.URL); err == nil && u.Host != "" {
+		host = u.Hostname()
+		if port == 0 {
+			port, _ = strconv.Atoi(u.Port())
+		}
+		if port == 0 && len(e.Ports) == 0 {
+			port = defaultPort(u.Scheme)
+		}
+	} else if e.Name != "" {
+		host = e.Name
+		if e.Namespace != "" {
+			host += "." + e.Namespace + ".svc.cluster.local"
+		}
+	}
+	if port == 0 && len(e.Ports) > 0 {
+		port = e.Ports[0].Port
+	}
+	if host == "" || port == 0 {
+		return "", errors.New("service address not available")
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
+
+func defaultPort(scheme string) int {
+	if scheme == "https" {
+		return 443
+	}
+	return 80
+}
+
+// Endpoint returns the service of the run, from status.service; an error
+// when the run exposes none (yet).
+func (r *Run) Endpoint() (*Endpoint, error) {
+	svc, ok := r.Status["service"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("run %s exposes no service (state %s)", r.ID, r.State())
+	}
+	e := &Endpoint{
+		Name:      stringField(svc, "name"),
+		Namespace: stringField(svc, "namespace"),
+		Type:      stringField(svc, "type"),
+		URL:       stringField(svc, "url"),
+	}
+	if urls, ok := svc["urls"].([]interface{}); ok && e.URL == "" && len(urls) > 0 {
+		e.URL, _ = urls[0].(string)
+	}
+	if e.URL != "" && !hasScheme(e.URL) {
+		e.URL = "http://" + e.URL
+	}
+	ports, _ := svc["ports"].([]interface{})
+	for _, it := range ports {
+		m, ok := it.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		p := ServicePort{Name: stringField(m, "name"), Protocol: stringField(m, "protocol")}
+		p.Port = intField(m, "port")
+		if p.TargetPort = intField(m, "targetPort"); p.TargetPort == 0 {
+			p.TargetPort = intField(m, "target_port")
+		}
+		if p.NodePort = intField(m, "nodePort"); p.NodePort == 0 {
+			p.NodePort = intField(m, "node_port")
+		}
+		e.Ports = append(e.Ports, p)
+	}
+	return e, nil
+}
+
+func hasScheme(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func intField(m map[string]interface{}, k string) int {
+	switch v := m[k].(type) {
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+// Endpoint returns the service of the run req.ID. Resource defaults to
+// "runs".
+func (s *RunService) Endpoint(ctx context.Context, req RunResourceRequest) (_ *Endpoint, err error) {
+	if req.Resource == "" {
+		req.Resource = "runs"
+	}
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.endpoint", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.Project == "" {
+		return nil, errors.New("project not specified")
+	}
+	if req.ID == "" {
+		return nil, errors.New("id not specified")
+	}
+	r, err := s.getRun(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return r.Endpoint()
+}
+
+type ForwardRequest struct {
+	RunResourceRequest
+
+	// Port of the service, the first one when zero
+	Port int
+	// LocalAddr to listen on, 127.0.0.1:0 (a free port) when empty
+	LocalAddr string
+	// Dial connects to the service; a net.Dialer by default, which needs
+	// the cluster network. Outside of it, plug a tunnel here (kubectl
+	// port-forward, SSH, ...).
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// Forwarder relays the connections to a local address to the service of a
+// run, until closed.
+type Forwarder struct {
+	// Addr is the local address, e.g. 127.0.0.1:41234
+	Addr string
+	// Target is the address of the service
+	Target string
+
+	ln     net.Listener
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// URL returns http://Addr.
+func (f *Forwarder) URL() string {
+	return "http://" + f.Addr
+}
+
+// Close stops listening and closes the open connections.
+func (f *Forwarder) Close() error {
+	f.cancel()
+	err := f.ln.Close()
+	f.wg.Wait()
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// Forward listens on a local address and relays each connection to the
+// service of the run req.ID, so that it can be used as if it were local
+// (e.g. a notebook at Forwarder.URL()). It lasts until Close or the end of
+// ctx.
+func (s *RunService) Forward(ctx context.Context, req ForwardRequest) (_ *Forwarder, err error) {
+	ep, err := s.Endpoint(ctx, req.RunResourceRequest)
+	if err != nil {
+		return nil, err
+	}
+	target, err := ep.Address(req.Port)
+	if err != nil {
+		return nil, err
+	}
+	local := req.LocalAddr
+	if local == "" {
+		local = "127.0.0.1:0"
+	}
+	ln, err := net.Listen("tcp", local)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s failed: %w", local, err)
+	}
+	dial := req.Dial
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	f := &Forwarder{Addr: ln.Addr().String(), Target: target, ln: ln, cancel: cancel}
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			f.wg.Add(1)
+			go func() {
+				defer f.wg.Done()
+				s.relay(ctx, c, target, dial)
+			}()
+		}
+	}()
+	return f, nil
+}
+
+// relay copies between c and a new connection to target until either side
+// closes, or ctx ends.
+func (s *RunService) relay(ctx context.Context, c net.Conn, target string, dial func(context.Context, string, string) (net.Conn, error)) {
+	defer c.Close()
+	up, err := dial(ctx, "tcp", target)
+	if err != nil {
+		config.LoggerOr(s.logger).Warn("forward: dial failed", "target", target, "error", err)
+		return
+	}
+	defer up.Close()
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.Close()
+		_ = up.Close()
+	})
+	defer stop()
+
+	done := make(chan struct{}, 2)
+	pipe := func(dst, src net.Conn) {
+		_, _ = io.Copy(dst, src)
+		if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+			_ = cw.CloseWrite()
+		}
+		done <- struct{}{}
+	}
+	go pipe(up, c)
+	go pipe(c, up)
+	<-done
+	<-done
+}
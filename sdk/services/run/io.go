@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/transfer"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+)
+
+// EntityKey is a parsed entity key, either
+// store://<project>/<entity type>/<kind>/<name>[:<id>] or
+// <kind>://<project>/<name>[:<id>]. Without an id the key refers to the
+// latest version of name.
+type EntityKey struct {
+	Project  string
+	Resource string
+	Kind     string
+	Name     string
+	ID       string
+}
+
+// ParseKey parses an entity key; for <kind>://... keys the kind must name a
+// resource (e.g. artifact://demo/dataset:1234).
+func ParseKey(key string) (EntityKey, error) {
+	scheme, rest, ok := strings.Cut(key, "://")
+	if !ok || scheme == "" {
+		return EntityKey{}, fmt.Errorf("invalid entity key %q", key)
+	}
+	parts := strings.Split(rest, "/")
+	var k EntityKey
+	switch {
+	case scheme == "store" && len(parts) == 4:
+		k = EntityKey{Project: parts[0], Resource: parts[1], Kind: parts[2], Name: parts[3]}
+	case scheme != "store" && len(parts) == 2:
+		k = EntityKey{Project: parts[0], Resource: scheme, Kind: scheme, Name: parts[1]}
+	default:
+		return EntityKey{}, fmt.Errorf("invalid entity key %q", key)
+	}
+	k.Name, k.ID, _ = strings.Cut(k.Name, ":")
+	plural, err := config.ResolveResource(k.Resource)
+	if err != nil {
+		return EntityKey{}, fmt.Errorf("invalid entity key %q: %w", key, err)
+	}
+	k.Resource = plural
+	if k.Project == "" || k.Name == "" {
+		return EntityKey{}, fmt.Errorf("invalid entity key %q", key)
+	}
+	return k, nil
+}
+
+// RunEntity is an entity a run reads (spec.inputs) or writes
+// (status.outputs).
+type RunEntity struct {
+	// Param is the name of the input or output
+	Param string
+	Key   string
+	EntityKey
+	// Entity is the entity the key refers to
+	Entity map[string]interface{}
+	// Files are the downloaded files, with IORequest.Download
+	Files []transfer.DownloadInfo
+}
+
+// IORequest reads the inputs or outputs of the run ID.
+type IORequest struct {
+	RunResourceRequest
+
+	// Download, when set, downloads the data of the entities to
+	// Destination/<param>/
+	Download    *transfer.TransferService
+	Destination string
+}
+
+// Inputs returns the entities in spec.inputs of the run req.ID, sorted by
+// param.
+func (s *RunService) Inputs(ctx context.Context, req IORequest) (_ []RunEntity, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.inputs", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	return s.runEntities(ctx, req, func(r *Run) interface{} { return r.Spec["inputs"] })
+}
+
+// Outputs returns the entities in status.outputs of the run req.ID, sorted
+// by param.
+func (s *RunService) Outputs(ctx context.Context, req IORequest) (_ []RunEntity, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.outputs", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	return s.runEntities(ctx, req, func(r *Run) interface{} { return r.Status["outputs"] })
+}
+
+func (s *RunService) runEntities(ctx context.Context, req IORequest, field func(*Run) interface{}) ([]RunEntity, error) {
+	if req.Project == "" {
+		return nil, errors.New("project not specified")
+	}
+	if req.ID == "" {
+		return nil, errors.New("id not specified")
+	}
+	if req.Resource == "" {
+		req.Resource = "runs"
+	}
+	if req.Download != nil && req.Destination == "" {
+		return nil, errors.New("destination not specified")
+	}
+	r, err := s.getRun(ctx, req.RunResourceRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []RunEntity
+	for param, keys := range entityKeys(field(r)) {
+		for _, key := range keys {
+			k, err := ParseKey(key)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", param, err)
+			}
+			out = append(out, RunEntity{Param: param, Key: key, EntityKey: k})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Param < out[j].Param })
+
+	berr := utils.NewBatchError("download")
+	for i := range out {
+		e := &out[i]
+		if e.Entity, err = s.getEntity(ctx, e.EntityKey); err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Param, err)
+		}
+		if req.Download == nil {
+			continue
+		}
+		id, _ := e.Entity["id"].(string)
+		e.Files, err = req.Download.Download(ctx, e.Resource, transfer.DownloadRequest{
+			Project:     e.Project,
+			Resource:    e.Resource,
+			ID:          id,
+			Destination: filepath.Join(req.Destination, e.Param) + string(filepath.Separator),
+		})
+		if err != nil {
+			berr.Add(e.Param, err)
+		}
+	}
+	return out, berr.ErrorOrNil()
+}
+
+// entityKeys returns the keys of a map of param to key, or to a list of
+// keys, or to an object with a key.
+func entityKeys(v interface{}) map[string][]string {
+	m, _ := v.(map[string]interface{})
+	out := map[string][]string{}
+	var add func(param string, v interface{})
+	add = func(param string, v interface{}) {
+		switch t := v.(type) {
+		case string:
+			if t != "" {
+				out[param] = append(out[param], t)
+			}
+		case []interface{}:
+			for _, it := range t {
+				add(param, it)
+			}
+		case map[string]interface{}:
+			add(param, t["key"])
+		}
+	}
+	for param, v := range m {
+		add(param, v)
+	}
+	return out
+}
+
+// getEntity reads the entity of k, by id or else the latest version of
+// its name.
+func (s *RunService) getEntity(ctx context.Context, k EntityKey) (map[string]interface{}, error) {
+	var u string
+	if k.ID != "" {
+		u = s.http.BuildURL(k.Project, k.Resource, k.ID, nil)
+	} else {
+		u = s.http.BuildURL(k.Project, k.Resource, "", map[string]string{"name": k.Name, "versions": "latest"})
+	}
+	b, status, err := s.http.Do(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get %s %s failed (status %d): %w", k.Resource, k.Name, status, err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	if content, ok := m["content"].([]interface{}); ok {
+		if len(content) == 0 {
+			return nil, fmt.Errorf("%s %s not found", k.Resource, k.Name)
+		}
+		m, _ = content[0].(map[string]interface{})
+	}
+	return m, nil
+}
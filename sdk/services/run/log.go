@@ -8,10 +8,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
 )
 
 // GetLogs performs GET {base}/{project}/{endpoint}/{id}/logs
-func (s *RunService) GetLogs(ctx context.Context, req LogRequest) ([]byte, int, error) {
+func (s *RunService) GetLogs(ctx context.Context, req LogRequest) (_ []byte, _ int, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.logs", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
 	if req.Project == "" {
 		return nil, 0, errors.New("project not specified")
 	}
@@ -30,9 +37,38 @@ func (s *RunService) GetLogs(ctx context.Context, req LogRequest) ([]byte, int,
 	return b, status, nil
 }
 
+// StreamLogs is GetLogs without buffering the whole payload, for large logs:
+// the caller reads and closes the returned body.
+func (s *RunService) StreamLogs(ctx context.Context, req LogRequest) (_ io.ReadCloser, _ int, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.logs.stream", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.Project == "" {
+		return nil, 0, errors.New("project not specified")
+	}
+	if req.Resource == "" {
+		return nil, 0, errors.New("endpoint not specified")
+	}
+	if req.ID == "" {
+		return nil, 0, errors.New("id not specified")
+	}
+
+	url := s.http.BuildURL(req.Project, req.Resource, req.ID, nil) + "/logs"
+	body, status, err := s.http.DoStream(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, status, fmt.Errorf("get logs failed (status %d): %w", status, err)
+	}
+	return body, status, nil
+}
+
 // GetResource performs GET {base}/{project}/{endpoint}/{id}
 // usato per leggere la risorsa run e derivare spec.task, ecc.
-func (s *RunService) GetResource(ctx context.Context, req LogRequest) ([]byte, int, error) {
+func (s *RunService) GetResource(ctx context.Context, req LogRequest) (_ []byte, _ int, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.get", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
 	if req.Project == "" {
 		return nil, 0, errors.New("project not specified")
 	}
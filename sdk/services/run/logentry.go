@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// LogEntry is the log of one container of a run.
+type LogEntry struct {
+	ID        string
+	Container string
+	Pod       string
+	Namespace string
+	// Timestamp is when the log was last collected (metadata.updated, else
+	// metadata.created)
+	Timestamp time.Time
+	// Content is the log text, decoded from base64
+	Content string
+	// Status is the whole status of the entry (metrics, ...)
+	Status map[string]interface{}
+}
+
+// Logs are the log entries of a run, one per container.
+type Logs []LogEntry
+
+// LogLine is a line of a merged log.
+type LogLine struct {
+	Container string
+	// Time is the timestamp the line starts with, else that of the
+	// previous line or of the entry
+	Time time.Time
+	Text string
+}
+
+// ParseLogs parses the body of GET .../{id}/logs.
+func ParseLogs(body []byte) (Logs, error) {
+	var raw []struct {
+		ID       string                 `json:"id"`
+		Metadata map[string]interface{} `json:"metadata"`
+		Status   map[string]interface{} `json:"status"`
+		Content  string                 `json:"content"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	logs := make(Logs, 0, len(raw))
+	for _, r := range raw {
+		e := LogEntry{ID: r.ID, Status: r.Status, Content: r.Content}
+		e.Container, _ = r.Status["container"].(string)
+		e.Pod, _ = r.Status["pod"].(string)
+		e.Namespace, _ = r.Status["namespace"].(string)
+		if b, err := base64.StdEncoding.DecodeString(r.Content); err == nil {
+			e.Content = string(b)
+		}
+		for _, f := range []string{"updated", "created"} {
+			if v, ok := r.Metadata[f].(string); ok {
+				if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+					e.Timestamp = t
+					break
+				}
+			}
+		}
+		logs = append(logs, e)
+	}
+	return logs, nil
+}
+
+// Logs returns the log entries of the run req.ID.
+func (s *RunService) Logs(ctx context.Context, req LogRequest) (_ Logs, err error) {
+	body, _, err := s.GetLogs(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLogs(body)
+}
+
+// Containers returns the container names, in order.
+func (l Logs) Containers() []string {
+	names := make([]string, len(l))
+	for i, e := range l {
+		names[i] = e.Container
+	}
+	return names
+}
+
+// Container returns the entry of the container name.
+func (l Logs) Container(name string) (*LogEntry, bool) {
+	for i := range l {
+		if l[i].Container == name {
+			return &l[i], true
+		}
+	}
+	return nil, false
+}
+
+// Select returns the entry of a container by name or, when sel is a
+// number, by its index in Containers.
+func (l Logs) Select(sel string) (*LogEntry, error) {
+	if e, ok := l.Container(sel); ok {
+		return e, nil
+	}
+	if i, err := strconv.Atoi(sel); err == nil {
+		if i < 0 || i >= len(l) {
+			return nil, fmt.Errorf("container index %d out of range (%d containers)", i, len(l))
+		}
+		return &l[i], nil
+	}
+	return nil, fmt.Errorf("container %q not found (have %s)", sel, strings.Join(l.Containers(), ", "))
+}
+
+// Merge interleaves the lines of all containers chronologically, by the
+// RFC 3339 timestamp lines start with (as with kubectl logs --timestamps);
+// lines without one keep their place after the previous line of their
+// container. Lines with equal times keep the container order.
+func (l Logs) Merge() []LogLine {
+	var lines []LogLine
+	for _, e := range l {
+		t := e.Timestamp
+		for _, text := range strings.Split(strings.TrimRight(e.Content, "\n"), "\n") {
+			if text == "" {
+				continue
+			}
+			if ts, _, ok := strings.Cut(text, " "); ok {
+				if pt, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+					t = pt
+				}
+			}
+			lines = append(lines, LogLine{Container: e.Container, Time: t, Text: text})
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+	return lines
+}
+
+// MainContainer returns the name of the main container of the run req.ID,
+// c-<task kind without "+">-<id>, as the CLI shows by default.
+func (s *RunService) MainContainer(ctx context.Context, req RunResourceRequest) (_ string, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.main_container", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	return s.mainContainer(ctx, req)
+}
+
+func (s *RunService) mainContainer(ctx context.Context, req RunResourceRequest) (string, error) {
+	urlRes := s.http.BuildURL(req.Project, req.Resource, req.ID, nil)
+	resBody, status, err := s.http.Do(ctx, "GET", urlRes, nil)
+	if err != nil {
+		return "", fmt.Errorf("resource request failed (status %d): %w", status, err)
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(resBody, &m); err != nil {
+		return "", err
+	}
+
+	spec, ok := m["spec"].(map[string]interface{})
+	if !ok {
+		return "", errors.New("invalid resource: missing spec")
+	}
+	task, ok := spec["task"].(string)
+	if !ok {
+		return "", errors.New("invalid resource: missing task in spec")
+	}
+
+	idx := strings.Index(task, ":")
+	if idx == -1 {
+		return "", errors.New("invalid task format in spec")
+	}
+	taskFormatted := strings.ReplaceAll(task[:idx], "+", "")
+
+	return fmt.Sprintf("c-%v-%v", taskFormatted, req.ID), nil
+}
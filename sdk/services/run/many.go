@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// DefaultRunConcurrency is the number of parallel submissions of RunMany
+// when RunManyOptions.Concurrency is not set.
+const DefaultRunConcurrency = 4
+
+type RunManyOptions struct {
+	Concurrency int
+	// Wait, when set, waits for every run once all are submitted (see
+	// WaitRequest; its project, resource and id are those of each run).
+	// RunRequest.Wait is ignored.
+	Wait *WaitRequest
+	// OnProgress is called after each submission, and after each wait,
+	// from the submitting goroutines, with the number of runs done out
+	// of total
+	OnProgress func(r RunResult, done, total int)
+}
+
+// RunResult is the outcome of one request of RunMany.
+type RunResult struct {
+	// Index of the request
+	Index int
+	// Run is the created run or, when waiting, the run in the state
+	// reached
+	Run *Run
+	Err error
+}
+
+// Failed reports whether the run couldn't be submitted or waited for, or
+// ended in ERROR.
+func (r RunResult) Failed() bool {
+	return r.Err != nil || (r.Run != nil && r.Run.State() == StateError)
+}
+
+// RunResults are the outcomes of RunMany, in request order.
+type RunResults []RunResult
+
+// Failed returns the failed results (see RunResult.Failed).
+func (r RunResults) Failed() RunResults {
+	var out RunResults
+	for _, x := range r {
+		if x.Failed() {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+// IDs returns the ids of the created runs, in request order.
+func (r RunResults) IDs() []string {
+	var ids []string
+	for _, x := range r {
+		if x.Run != nil {
+			ids = append(ids, x.Run.ID)
+		}
+	}
+	return ids
+}
+
+// States counts the runs by state; runs not created count as "".
+func (r RunResults) States() map[string]int {
+	out := map[string]int{}
+	for _, x := range r {
+		state := ""
+		if x.Run != nil {
+			state = x.Run.State()
+		}
+		out[state]++
+	}
+	return out
+}
+
+// Err joins the errors of the failed runs; nil when none failed.
+func (r RunResults) Err() error {
+	var errs []error
+	for _, x := range r.Failed() {
+		err := x.Err
+		if err == nil {
+			err = fmt.Errorf("run %s ended in state %s", x.Run.ID, StateError)
+		}
+		errs = append(errs, fmt.Errorf("run request %d: %w", x.Index, err))
+	}
+	return errors.Join(errs...)
+}
+
+// RunMany submits reqs concurrently, e.g. the runs of a parameter sweep,
+// and reports the outcome of each one; with opts.Wait it then waits for
+// all the runs. Failed submissions don't stop the others: they are in the
+// results (see RunResults.Err).
+func (s *RunService) RunMany(ctx context.Context, reqs []RunRequest, opts RunManyOptions) (_ RunResults, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.create_many", "", "runs")
+	defer func() { config.EndSpan(span, err) }()
+
+	results := make(RunResults, len(reqs))
+	for i := range results {
+		results[i].Index = i
+	}
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = DefaultRunConcurrency
+	}
+	total := len(reqs)
+	if opts.Wait != nil {
+		total *= 2
+	}
+	var (
+		mu   sync.Mutex
+		done int
+	)
+	progress := func(r RunResult) {
+		if opts.OnProgress == nil {
+			return
+		}
+		mu.Lock()
+		done++
+		n := done
+		mu.Unlock()
+		opts.OnProgress(r, n, total)
+	}
+
+	forEach(len(reqs), workers, func(i int) {
+		r := &results[i]
+		if r.Err = ctx.Err(); r.Err == nil {
+			req := reqs[i]
+			req.Wait = nil
+			r.Run, r.Err = s.Run(ctx, req)
+		}
+		progress(*r)
+	})
+	if opts.Wait == nil {
+		return results, nil
+	}
+
+	forEach(len(reqs), workers, func(i int) {
+		r := &results[i]
+		if r.Run == nil {
+			progress(*r)
+			return
+		}
+		w := *opts.Wait
+		w.Project, w.ID = reqs[i].Project, r.Run.ID
+		if w.Resource = reqs[i].ResolvedRunsEndpoint; w.Resource == "" {
+			w.Resource = "runs"
+		}
+		if w.Options == nil {
+			w.Options = reqs[i].Options
+		}
+		waited, err := s.Wait(ctx, w)
+		if waited != nil {
+			r.Run = waited
+		}
+		r.Err = err
+		progress(*r)
+	})
+	return results, nil
+}
+
+// forEach calls fn for 0..n-1 from at most workers goroutines.
+func forEach(n, workers int, fn func(i int)) {
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for range min(workers, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	for i := range n {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
@@ -11,7 +11,10 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/i18n"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
 )
 
 // PrintMetrics replica MetricsService.PrintMetrics:
@@ -19,50 +22,83 @@ import (
 // - prende status.metrics
 // - se non ci sono metrics, stampa "No metrics for this run."
 // - altrimenti pretty-print JSON.
-func (s *RunService) PrintMetrics(ctx context.Context, req MetricsRequest) error {
+// GetMetrics restituisce invece le serie tipizzate.
+func (s *RunService) PrintMetrics(ctx context.Context, req MetricsRequest) (err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.metrics", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	metricsSlice, err := s.rawMetrics(ctx, req)
+	if err != nil {
+		return err
+	}
+	if metricsSlice == nil {
+		log.Println(i18n.Message(i18n.MsgRunNoMetrics))
+		return nil
+	}
+
+	jsonData, err := json.Marshal(metricsSlice)
+	if err != nil {
+		return err
+	}
+
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, jsonData, "", "    "); err != nil {
+		return err
+	}
+	fmt.Println(pretty.String())
+
+	return nil
+}
+
+// GetMetrics returns the metrics of the container req.Container of the run
+// (by default its main container) as series; none when the run has no
+// metrics. FormatMetrics prints them.
+func (s *RunService) GetMetrics(ctx context.Context, req MetricsRequest) (_ []MetricSeries, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.metrics.get", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	raw, err := s.rawMetrics(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMetrics(raw)
+}
+
+// rawMetrics returns status.metrics of the log entry of the container;
+// nil when there are none.
+func (s *RunService) rawMetrics(ctx context.Context, req MetricsRequest) ([]interface{}, error) {
 	if req.Project == "" {
-		return errors.New("project not specified")
+		return nil, errors.New("project not specified")
 	}
 	if req.Resource == "" {
-		return errors.New("endpoint not specified")
+		return nil, errors.New("endpoint not specified")
 	}
 	if req.ID == "" {
-		return errors.New("resource id not specified")
+		return nil, errors.New("resource id not specified")
 	}
 
 	containerLog, err := s.getContainerLog(ctx, req.RunResourceRequest, req.Container)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	statusMap, ok := containerLog["status"].(map[string]interface{})
 	if !ok {
-		return errors.New("invalid log entry: missing status")
+		return nil, errors.New("invalid log entry: missing status")
 	}
 
 	metricsVal := statusMap["metrics"]
 	if metricsVal == nil {
-		log.Println("No metrics for this run.")
-		return nil
+		return nil, nil
 	}
 
 	metricsSlice, ok := metricsVal.([]interface{})
 	if !ok {
-		return errors.New("invalid metrics format")
-	}
-
-	jsonData, err := json.Marshal(metricsSlice)
-	if err != nil {
-		return err
-	}
-
-	var pretty bytes.Buffer
-	if err := json.Indent(&pretty, jsonData, "", "    "); err != nil {
-		return err
+		return nil, errors.New("invalid metrics format")
 	}
-	fmt.Println(pretty.String())
-
-	return nil
+	return metricsSlice, nil
 }
 
 // getContainerLog replica la logica originale:
@@ -90,33 +126,9 @@ func (s *RunService) getContainerLog(
 	// 2) Determine container name (se non specificato)
 	containerName := container
 	if containerName == "" {
-		urlRes := s.http.BuildURL(req.Project, req.Resource, req.ID, nil)
-		resBody, status, err := s.http.Do(ctx, "GET", urlRes, nil)
-		if err != nil {
-			return nil, fmt.Errorf("resource request failed (status %d): %w", status, err)
-		}
-
-		var m map[string]interface{}
-		if err := json.Unmarshal(resBody, &m); err != nil {
+		if containerName, err = s.mainContainer(ctx, req); err != nil {
 			return nil, err
 		}
-
-		spec, ok := m["spec"].(map[string]interface{})
-		if !ok {
-			return nil, errors.New("invalid resource: missing spec")
-		}
-		task, ok := spec["task"].(string)
-		if !ok {
-			return nil, errors.New("invalid resource: missing task in spec")
-		}
-
-		idx := strings.Index(task, ":")
-		if idx == -1 {
-			return nil, errors.New("invalid task format in spec")
-		}
-		taskFormatted := strings.ReplaceAll(task[:idx], "+", "")
-
-		containerName = fmt.Sprintf("c-%v-%v", taskFormatted, req.ID)
 	}
 
 	// 3) Find matching log entry
@@ -8,11 +8,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
 )
 
 // Resume performs POST {base}/{project}/{endpoint}/{id}/resume
 // Ritorna body e status per far stampare lo stato all'adapter.
-func (s *RunService) Resume(ctx context.Context, req ResumeRequest) ([]byte, int, error) {
+func (s *RunService) Resume(ctx context.Context, req ResumeRequest) (_ []byte, _ int, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.resume", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
 	if req.Project == "" {
 		return nil, 0, errors.New("project not specified")
 	}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+)
+
+// RelationshipRetryOf is the type of the relationship from a run to the
+// run it retries (see Retry).
+const RelationshipRetryOf = utils.RelationshipRetryOf
+
+// Retry submits a new run with the spec of the run req.ID, with the
+// overrides of req, and returns it. The new run has a metadata
+// relationship of type RelationshipRetryOf to the retried run. Only
+// failed or stopped runs are retried, unless req.AnyState.
+func (s *RunService) Retry(ctx context.Context, req RetryRequest) (_ *Run, err error) {
+	if req.Resource == "" {
+		req.Resource = "runs"
+	}
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.retry", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.Project == "" {
+		return nil, errors.New("project not specified")
+	}
+	if req.ID == "" {
+		return nil, errors.New("id not specified")
+	}
+
+	src, err := s.getRun(ctx, req.RunResourceRequest)
+	if err != nil {
+		return nil, err
+	}
+	if state := src.State(); !req.AnyState && state != StateError && state != StateStopped {
+		return nil, fmt.Errorf("run %s is %s: only failed or stopped runs are retried", req.ID, state)
+	}
+
+	spec := maps.Clone(src.Spec)
+	if spec == nil {
+		spec = map[string]interface{}{}
+	}
+	mergeSpecField(spec, "inputs", req.Inputs)
+	mergeSpecField(spec, "parameters", req.Parameters)
+	maps.Copy(spec, req.Spec)
+
+	dest := src.Key
+	if dest == "" {
+		dest = fmt.Sprintf("store://%s/run/%s/%s", req.Project, src.Kind, src.ID)
+	}
+	body := map[string]interface{}{
+		"kind":    src.Kind,
+		"project": req.Project,
+		"spec":    spec,
+		"metadata": map[string]interface{}{
+			"relationships": []interface{}{
+				map[string]interface{}{"type": RelationshipRetryOf, "dest": dest},
+			},
+		},
+	}
+
+	key := req.IdempotencyKey
+	if key == "" {
+		key = config.NewIdempotencyKey()
+	}
+	return s.submit(ctx, req.Project, req.Resource, key, body, req.Wait)
+}
+
+// mergeSpecField sets the keys of values in the object spec[field].
+func mergeSpecField(spec map[string]interface{}, field string, values map[string]interface{}) {
+	if len(values) == 0 {
+		return
+	}
+	m, _ := spec[field].(map[string]interface{})
+	m = maps.Clone(m)
+	if m == nil {
+		m = map[string]interface{}{}
+	}
+	maps.Copy(m, values)
+	spec[field] = m
+}
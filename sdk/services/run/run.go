@@ -10,6 +10,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
 )
 
 // taskToRunKind converte task kind ("python+job", "python+job:task")
@@ -26,31 +28,46 @@ func taskToRunKind(task string) string {
 	return task + ":run"
 }
 
-// Run crea un run, mantenendo la logica originale
-func (s *RunService) Run(ctx context.Context, req RunRequest) error {
+// Run crea un run, mantenendo la logica originale, e restituisce il run
+// creato; con req.Wait attende poi lo stato richiesto e restituisce il run
+// in quello stato.
+func (s *RunService) Run(ctx context.Context, req RunRequest) (_ *Run, err error) {
+	endpoint := req.ResolvedRunsEndpoint
+	if endpoint == "" {
+		endpoint = "runs"
+	}
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.create", req.Project, endpoint)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
 	if req.Project == "" {
-		return errors.New("project not specified")
+		return nil, errors.New("project not specified")
 	}
 	if req.TaskKind == "" {
-		return errors.New("task kind not specified")
+		return nil, errors.New("task kind not specified")
 	}
 
 	// IMPORTANT: manteniamo esattamente l'handling dell'originale
 	origTaskKind := req.TaskKind
 	runKind := taskToRunKind(origTaskKind)
 
+	key := req.IdempotencyKey
+	if key == "" {
+		key = config.NewIdempotencyKey()
+	}
+
 	// Resolve function (ritorna kind e key; ci serve il key per spec)
 	_, fnKey, err := s.resolveFunction(ctx, req.Project, req.FunctionID, req.FunctionName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Get o create TASK usando l'ORIGINAL task kind (exact match)
 	taskKey, err := s.getTaskKey(ctx, req.Project, fnKey, origTaskKind)
 	if err != nil {
-		taskKey, err = s.createTask(ctx, req.Project, fnKey, origTaskKind)
+		taskKey, err = s.createTask(config.ContextWithIdempotencyKey(ctx, key+"-task"), req.Project, fnKey, origTaskKind)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -69,23 +86,35 @@ func (s *RunService) Run(ctx context.Context, req RunRequest) error {
 		"project": req.Project,
 		"spec":    spec,
 	}
+	return s.submit(ctx, req.Project, endpoint, key, body, req.Wait)
+}
+
+// submit crea il run body con la idempotency key key e, se wait è
+// impostato, ne attende lo stato.
+func (s *RunService) submit(ctx context.Context, project, endpoint, key string, body map[string]interface{}, wait *WaitRequest) (*Run, error) {
 	data, err := json.Marshal(body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	endpoint := req.ResolvedRunsEndpoint
-	if endpoint == "" {
-		endpoint = "runs"
-	}
-	url := s.http.BuildURL(req.Project, endpoint, "", nil)
-	fmt.Printf("POST %s\n", url)
+	url := s.http.BuildURL(project, endpoint, "", nil)
+	config.LoggerOr(s.logger).Debug("creating run", "url", url)
 
-	_, status, err := s.http.Do(ctx, "POST", url, data)
+	b, status, err := s.http.Do(config.ContextWithIdempotencyKey(ctx, key), "POST", url, data)
 	if err != nil {
-		return fmt.Errorf("run creation failed (status %d): %w", status, err)
+		return nil, fmt.Errorf("run creation failed (status %d): %w", status, err)
+	}
+	var created Run
+	if err := json.Unmarshal(b, &created); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
 	}
-	return nil
+	if wait == nil {
+		return &created, nil
+	}
+
+	w := *wait
+	w.Project, w.Resource, w.ID = project, endpoint, created.ID
+	return s.Wait(ctx, w)
 }
 
 func (s *RunService) resolveFunction(ctx context.Context, project, id, name string) (string, string, error) {
@@ -6,21 +6,30 @@ package run
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"go.opentelemetry.io/otel/trace"
 
 	"errors"
 )
 
 type RunService struct {
-	http config.CoreHTTP
+	http   config.CoreHTTP
+	logger *slog.Logger
+	tracer trace.Tracer
 }
 
-func NewRunService(ctx context.Context, conf config.Config) (*RunService, error) {
+// NewRunService builds the service; opts customize HTTP client, logger and
+// retries (see config.ServiceOption).
+func NewRunService(ctx context.Context, conf config.Config, opts ...config.ServiceOption) (*RunService, error) {
 	if conf.Core.BaseURL == "" || conf.Core.APIVersion == "" {
 		return nil, errors.New("invalid core config")
 	}
+	o := config.NewServiceOptions(opts...).ForConfig(conf)
 	return &RunService{
-		http: config.NewHTTPCore(nil, conf.Core),
+		http:   config.NewHTTPCoreWithOptions(conf.Core, o),
+		logger: conf.Logger,
+		tracer: config.Tracer(o.TracerProvider),
 	}, nil
 }
@@ -0,0 +1,630 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/dhcoretest"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/s3test"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/run"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/transfer"
+)
+
+func newOfflineService(t *testing.T) (*run.RunService, *dhcoretest.Server) {
+	t.Helper()
+	srv := dhcoretest.NewServer()
+	t.Cleanup(srv.Close)
+	if err := srv.LoadFixtureFS(os.DirFS("testdata"), "*.yaml"); err != nil {
+		t.Fatalf("failed to load fixtures: %v", err)
+	}
+
+	svc, err := run.NewRunService(context.Background(), srv.Config())
+	if err != nil {
+		t.Fatalf("failed to init sdk: %v", err)
+	}
+	return svc, srv
+}
+
+func TestRunCreatesTaskAndRunOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+
+	r, err := svc.Run(context.Background(), run.RunRequest{
+		Project:      "demo",
+		TaskKind:     "python+job",
+		FunctionName: "trainer",
+		InputSpec:    map[string]interface{}{"parameters": map[string]interface{}{"epochs": 3}},
+	})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if r.ID == "" || r.Kind != "python+job:run" || r.State() != "CREATED" {
+		t.Fatalf("unexpected run %+v", r)
+	}
+
+	tasks := srv.List("demo", "tasks")
+	if len(tasks) != 1 || tasks[0]["kind"] != "python+job" {
+		t.Fatalf("expected one python+job task, got %v", tasks)
+	}
+
+	var created map[string]interface{}
+	for _, r := range srv.List("demo", "runs") {
+		if r["id"] != "run1" {
+			created = r
+		}
+	}
+	if created == nil || created["id"] != r.ID {
+		t.Fatalf("run %s not created", r.ID)
+	}
+	if created["kind"] != "python+job:run" {
+		t.Fatalf("unexpected run kind %v", created["kind"])
+	}
+	spec := created["spec"].(map[string]interface{})
+	if spec["function"] != "python://demo/trainer:fn1" || spec["task"] == "" || spec["parameters"] == nil {
+		t.Fatalf("unexpected run spec %v", spec)
+	}
+
+	var seen []string
+	waited, err := svc.Run(context.Background(), run.RunRequest{
+		Project:      "demo",
+		TaskKind:     "python+job",
+		FunctionName: "trainer",
+		Wait: &run.WaitRequest{
+			Until:      run.StateIn(run.StateCreated),
+			OnProgress: func(r *run.Run) { seen = append(seen, r.ID) },
+		},
+	})
+	if err != nil || waited.ID == r.ID || fmt.Sprint(seen) != "["+waited.ID+"]" {
+		t.Fatalf("run and wait: %+v, %v, %v", waited, seen, err)
+	}
+}
+
+func TestStopResumeAndLogsOffline(t *testing.T) {
+	svc, _ := newOfflineService(t)
+	ctx := context.Background()
+	req := run.RunResourceRequest{Project: "demo", Resource: "runs", ID: "run1"}
+
+	body, _, err := svc.Stop(ctx, run.StopRequest{RunResourceRequest: req})
+	if err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if state := stateOf(t, body); state != "STOPPED" {
+		t.Fatalf("expected STOPPED, got %s", state)
+	}
+
+	body, _, err = svc.Resume(ctx, run.ResumeRequest{RunResourceRequest: req})
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if state := stateOf(t, body); state != "RUNNING" {
+		t.Fatalf("expected RUNNING, got %s", state)
+	}
+
+	logs, _, err := svc.GetLogs(ctx, run.LogRequest{RunResourceRequest: req})
+	if err != nil {
+		t.Fatalf("logs failed: %v", err)
+	}
+	var entries []interface{}
+	if err := json.Unmarshal(logs, &entries); err != nil || len(entries) != 2 {
+		t.Fatalf("unexpected logs %s (%v)", logs, err)
+	}
+
+	stream, _, err := svc.StreamLogs(ctx, run.LogRequest{RunResourceRequest: req})
+	if err != nil {
+		t.Fatalf("stream logs failed: %v", err)
+	}
+	streamed, err := io.ReadAll(stream)
+	_ = stream.Close()
+	if err != nil || string(streamed) != string(logs) {
+		t.Fatalf("streamed logs differ: %s (%v)", streamed, err)
+	}
+	missing := run.RunResourceRequest{Project: "demo", Resource: "runs", ID: "missing"}
+	if _, _, err := svc.StreamLogs(ctx, run.LogRequest{RunResourceRequest: missing}); !config.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	if err := svc.PrintMetrics(ctx, run.MetricsRequest{RunResourceRequest: req}); err != nil {
+		t.Fatalf("metrics failed: %v", err)
+	}
+}
+
+func TestWaitOffline(t *testing.T) {
+	svc, _ := newOfflineService(t)
+	ctx := context.Background()
+	req := run.RunResourceRequest{Project: "demo", Resource: "runs", ID: "run1"}
+
+	_, err := svc.Wait(ctx, run.WaitRequest{RunResourceRequest: req, Interval: 5 * time.Millisecond, Timeout: 30 * time.Millisecond})
+	var terr *run.WaitTimeoutError
+	if !errors.As(err, &terr) || terr.State != run.StateRunning {
+		t.Fatalf("expected a timeout in RUNNING, got %v", err)
+	}
+
+	// the run is stopped while waiting: the progress reports both states
+	var seen []string
+	r, err := svc.Wait(ctx, run.WaitRequest{
+		RunResourceRequest: req,
+		Interval:           5 * time.Millisecond,
+		OnProgress: func(r *run.Run) {
+			seen = append(seen, r.State())
+			if r.State() == run.StateRunning {
+				if _, _, err := svc.Stop(ctx, run.StopRequest{RunResourceRequest: req}); err != nil {
+					t.Error(err)
+				}
+			}
+		},
+	})
+	if err != nil || r.State() != run.StateStopped || fmt.Sprint(seen) != "[RUNNING STOPPED]" {
+		t.Fatalf("wait: %v, %v, %v", r, seen, err)
+	}
+
+	// a final state the predicate doesn't accept won't change anymore
+	_, err = svc.Wait(ctx, run.WaitRequest{RunResourceRequest: req, Until: run.StateIn(run.StateCompleted)})
+	if err == nil || !strings.Contains(err.Error(), "ended in state STOPPED") {
+		t.Fatalf("expected an error for the stopped run, got %v", err)
+	}
+}
+
+func TestLogEntriesOffline(t *testing.T) {
+	svc, _ := newOfflineService(t)
+	ctx := context.Background()
+	req := run.RunResourceRequest{Project: "demo", Resource: "runs", ID: "run1"}
+
+	logs, err := svc.Logs(ctx, run.LogRequest{RunResourceRequest: req})
+	if err != nil || fmt.Sprint(logs.Containers()) != "[c-pythonjob-run1 sidecar]" {
+		t.Fatalf("logs: %+v, %v", logs, err)
+	}
+	main, err := svc.MainContainer(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := logs.Select(main)
+	if err != nil || e.Pod != "run1-pod" || !strings.HasSuffix(e.Content, "epoch 2\n") || e.Timestamp.IsZero() {
+		t.Fatalf("main container: %+v, %v", e, err)
+	}
+	if e, err := logs.Select("1"); err != nil || e.Container != "sidecar" {
+		t.Fatalf("select by index: %+v, %v", e, err)
+	}
+	if _, err := logs.Select("5"); err == nil {
+		t.Fatal("expected an error for a missing container")
+	}
+
+	var merged []string
+	for _, l := range logs.Merge() {
+		merged = append(merged, l.Container+" "+l.Text[len("2025-01-01T10:00:00Z "):])
+	}
+	if want := "[c-pythonjob-run1 epoch 1 sidecar ready c-pythonjob-run1 epoch 2]"; fmt.Sprint(merged) != want {
+		t.Fatalf("merged logs %v, want %v", merged, want)
+	}
+}
+
+func TestRetryOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	req := run.RunResourceRequest{Project: "demo", Resource: "runs", ID: "run1"}
+
+	if _, err := svc.Retry(ctx, run.RetryRequest{RunResourceRequest: req}); err == nil {
+		t.Fatal("expected an error for a running run")
+	}
+	if _, _, err := svc.Stop(ctx, run.StopRequest{RunResourceRequest: req}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := svc.Retry(ctx, run.RetryRequest{
+		RunResourceRequest: req,
+		Parameters:         map[string]interface{}{"epochs": 5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, ok := srv.Get("demo", "runs", r.ID)
+	if !ok || r.ID == "run1" || created["kind"] != "python+job:run" {
+		t.Fatalf("unexpected retry %+v", created)
+	}
+	spec := created["spec"].(map[string]interface{})
+	if spec["task"] != "python+job://demo/task1" || fmt.Sprint(spec["parameters"]) != "map[epochs:5]" {
+		t.Fatalf("unexpected spec %v", spec)
+	}
+	rel := created["metadata"].(map[string]interface{})["relationships"].([]interface{})[0].(map[string]interface{})
+	if rel["type"] != run.RelationshipRetryOf || !strings.Contains(rel["dest"].(string), "run1") {
+		t.Fatalf("unexpected relationship %v", rel)
+	}
+}
+
+func TestInputsOutputsOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	req := run.RunResourceRequest{Project: "demo", Resource: "runs", ID: "run2"}
+
+	in, err := svc.Inputs(ctx, run.IORequest{RunResourceRequest: req})
+	if err != nil || len(in) != 1 || in[0].Param != "dataset" || in[0].Resource != "artifacts" || in[0].Entity["id"] != "a1" {
+		t.Fatalf("inputs: %+v, %v", in, err)
+	}
+
+	store := s3test.NewServer()
+	t.Cleanup(store.Close)
+	store.PutObject("data", "demo/model.bin", []byte("weights"))
+	cfg := srv.Config()
+	cfg.S3 = store.Config()
+	tr, err := transfer.NewTransferService(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	out, err := svc.Outputs(ctx, run.IORequest{RunResourceRequest: req, Download: tr, Destination: dir})
+	if err != nil || len(out) != 1 || out[0].Name != "model-file" || out[0].ID != "" || out[0].Entity["id"] != "a2" {
+		t.Fatalf("outputs: %+v, %v", out, err)
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "model", "model.bin")); err != nil || string(b) != "weights" || len(out[0].Files) != 1 {
+		t.Fatalf("downloaded %q, %v (%+v)", b, err, out[0].Files)
+	}
+
+	if _, err := run.ParseKey("store://demo/artifact/x"); err == nil {
+		t.Fatal("expected an error for a short key")
+	}
+}
+
+func TestRunFromTemplateOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	tpl := filepath.Join("testdata", "templates", "train.yaml")
+
+	if _, err := svc.RunFromTemplate(context.Background(), tpl, nil); err == nil || !strings.Contains(err.Error(), "lr is required") {
+		t.Fatalf("expected a missing parameter, got %v", err)
+	}
+	if _, err := svc.RunFromTemplate(context.Background(), tpl, map[string]interface{}{"lr": "fast"}); err == nil {
+		t.Fatal("expected an error for a non-float lr")
+	}
+
+	r, err := svc.RunFromTemplate(context.Background(), tpl, map[string]interface{}{"lr": "0.01", "epochs": "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, _ := srv.Get("demo", "runs", r.ID)
+	params := created["spec"].(map[string]interface{})["parameters"]
+	if want := "map[epochs:3 lr:0.01 shuffle:false tag:run-3-x]"; fmt.Sprint(params) != want {
+		t.Fatalf("parameters %v, want %v", params, want)
+	}
+}
+
+func TestRunManyOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+
+	var reqs []run.RunRequest
+	for _, lr := range []float64{0.1, 0.01} {
+		reqs = append(reqs, run.RunRequest{
+			Project: "demo", TaskKind: "python+job", FunctionName: "trainer",
+			InputSpec: map[string]interface{}{"parameters": map[string]interface{}{"lr": lr}},
+		})
+	}
+	reqs = append(reqs, run.RunRequest{Project: "demo", TaskKind: "python+job"})
+
+	var calls atomic.Int32
+	res, err := svc.RunMany(context.Background(), reqs, run.RunManyOptions{
+		Concurrency: 2,
+		Wait:        &run.WaitRequest{Until: run.StateIn(run.StateCreated)},
+		OnProgress:  func(run.RunResult, int, int) { calls.Add(1) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.IDs()) != 2 || len(res.Failed()) != 1 || res.Failed()[0].Index != 2 || res.Err() == nil {
+		t.Fatalf("unexpected results %+v", res)
+	}
+	if st := res.States(); st[run.StateCreated] != 2 || st[""] != 1 || calls.Load() != 6 {
+		t.Fatalf("states %v, %d progress calls", st, calls.Load())
+	}
+	if n := len(srv.List("demo", "runs")); n != 4 {
+		t.Fatalf("expected 2 new runs, have %d", n)
+	}
+}
+
+func TestTasksOffline(t *testing.T) {
+	svc, _ := newOfflineService(t)
+	ctx := context.Background()
+	for _, kind := range []string{"python+job", "python+serve"} {
+		if _, err := svc.Run(ctx, run.RunRequest{Project: "demo", TaskKind: kind, FunctionName: "trainer"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tasks, err := svc.ListTasks(ctx, run.ListTasksRequest{Project: "demo", FunctionName: "trainer", Kind: "python+job"})
+	if err != nil || len(tasks) != 1 || tasks[0].Function() != "python://demo/trainer:fn1" {
+		t.Fatalf("tasks: %+v, %v", tasks, err)
+	}
+	req := run.TaskRequest{Project: "demo", ID: tasks[0].ID}
+
+	up, err := svc.UpdateTask(ctx, run.UpdateTaskRequest{TaskRequest: req, Spec: map[string]interface{}{
+		"resources":     map[string]interface{}{"cpu": map[string]interface{}{"requests": "2"}},
+		"node_selector": []interface{}{map[string]interface{}{"key": "gpu", "value": "true"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := svc.GetTask(ctx, req)
+	if err != nil || got.Function() != up.Function() || fmt.Sprint(got.Spec["resources"]) != "map[cpu:map[requests:2]]" {
+		t.Fatalf("updated task: %+v, %v", got, err)
+	}
+	if _, err := svc.UpdateTask(ctx, run.UpdateTaskRequest{TaskRequest: req, Spec: map[string]interface{}{"resources": nil}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := svc.GetTask(ctx, req); got.Spec["resources"] != nil || got.Spec["node_selector"] == nil {
+		t.Fatalf("resources not removed: %v", got.Spec)
+	}
+
+	if err := svc.DeleteTask(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetTask(ctx, req); !config.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if tasks, _ := svc.ListTasks(ctx, run.ListTasksRequest{Project: "demo"}); len(tasks) != 1 {
+		t.Fatalf("expected 1 task left, got %+v", tasks)
+	}
+}
+
+func TestScheduleOffline(t *testing.T) {
+	svc, _ := newOfflineService(t)
+	ctx := context.Background()
+	if _, err := svc.Run(ctx, run.RunRequest{Project: "demo", TaskKind: "python+job", FunctionName: "trainer"}); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := svc.ListTasks(ctx, run.ListTasksRequest{Project: "demo"})
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("tasks: %+v, %v", tasks, err)
+	}
+	req := run.TaskRequest{Project: "demo", ID: tasks[0].ID}
+
+	if _, err := svc.SetSchedule(ctx, run.ScheduleRequest{TaskRequest: req, Cron: "61 * * * *"}); err == nil {
+		t.Fatal("expected an invalid cron error")
+	}
+	if _, err := svc.SetSchedule(ctx, run.ScheduleRequest{TaskRequest: req, Cron: "30 2 * * MON-FRI"}); err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC) // a Friday
+	info, err := svc.Schedule(ctx, run.ScheduleInfoRequest{TaskRequest: req, From: from, Upcoming: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Cron != "30 2 * * MON-FRI" || len(info.Next) != 2 ||
+		!info.Next[0].Equal(time.Date(2025, 1, 6, 2, 30, 0, 0, time.UTC)) ||
+		!info.Next[1].Equal(time.Date(2025, 1, 7, 2, 30, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected schedule %+v", info)
+	}
+	if len(info.Runs) != 1 {
+		t.Fatalf("expected the run of the task, got %+v", info.Runs)
+	}
+
+	if _, err := svc.RemoveSchedule(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := svc.Schedule(ctx, run.ScheduleInfoRequest{TaskRequest: req}); err != nil || info.Cron != "" || info.Next != nil {
+		t.Fatalf("schedule not removed: %+v, %v", info, err)
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	from := time.Date(2025, 1, 31, 23, 59, 30, 0, time.UTC)
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"@hourly", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"*/15 * * * * *", time.Date(2025, 1, 31, 23, 59, 45, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1 * SUN", time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{"0 0 0 13 * FRI", time.Date(2025, 6, 13, 0, 0, 0, 0, time.UTC)},
+		{"0 9 ? JAN-MAR 7", time.Date(2025, 2, 2, 9, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, c := range cases {
+		cron, err := run.ParseCron(c.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", c.expr, err)
+		}
+		if got := cron.Next(from); !got.Equal(c.want) {
+			t.Errorf("%s: next %v, want %v", c.expr, got, c.want)
+		}
+	}
+	for _, bad := range []string{"* * *", "5-1 * * * *", "*/0 * * * *", "* * * 13 *"} {
+		if _, err := run.ParseCron(bad); err == nil {
+			t.Errorf("%s: expected an error", bad)
+		}
+	}
+}
+
+func TestGetMetricsOffline(t *testing.T) {
+	svc, _ := newOfflineService(t)
+	req := run.MetricsRequest{RunResourceRequest: run.RunResourceRequest{Project: "demo", Resource: "runs", ID: "run1"}}
+
+	series, err := svc.GetMetrics(context.Background(), req)
+	if err != nil || len(series) != 1 || series[0].Name != "accuracy" {
+		t.Fatalf("metrics: %+v, %v", series, err)
+	}
+	if p, ok := series[0].Last(); !ok || p.Value != 0.9 {
+		t.Fatalf("unexpected value %+v", p)
+	}
+
+	usage, err := run.ParseMetrics([]interface{}{
+		map[string]interface{}{"timestamp": "2025-01-01T10:01:00Z", "usage": map[string]interface{}{"cpu": "500m", "memory": "2Mi"}},
+		map[string]interface{}{"timestamp": "2025-01-01T10:00:00Z", "usage": map[string]interface{}{"cpu": "250000000n", "memory": "1Mi"}},
+		map[string]interface{}{"name": "loss", "value": []interface{}{0.5, 0.25}},
+	})
+	if err != nil || len(usage) != 3 {
+		t.Fatalf("usage: %+v, %v", usage, err)
+	}
+	if cpu := usage[0]; cpu.Name != "cpu" || cpu.Points[0].Value != 0.25 || cpu.Points[1].Value != 0.5 {
+		t.Fatalf("unexpected cpu series %+v", cpu)
+	}
+	var out strings.Builder
+	if err := run.FormatMetrics(&out, usage); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "memory  2025-01-01T10:00:00Z  1048576") || !strings.Contains(out.String(), "loss    -                     0.25") {
+		t.Fatalf("unexpected table:\n%s", out.String())
+	}
+}
+
+func stateOf(t *testing.T, body []byte) string {
+	t.Helper()
+	var m map[string]interface{}
+	if err := json.Unmarshal(body, &m); err != nil {
+		t.Fatal(err)
+	}
+	status, _ := m["status"].(map[string]interface{})
+	s, _ := status["state"].(string)
+	return s
+}
+
+func TestEventsOffline(t *testing.T) {
+	svc, _ := newOfflineService(t)
+
+	events, err := svc.Events(context.Background(), run.EventsRequest{RunResourceRequest: run.RunResourceRequest{Project: "demo", ID: "run1"}})
+	if err != nil || len(events) != 4 {
+		t.Fatalf("events: %+v, %v", events, err)
+	}
+	var order []string
+	for _, ev := range events {
+		order = append(order, ev.State+ev.Reason)
+	}
+	if want := "[READY FailedScheduling Scheduled RUNNING]"; fmt.Sprint(order) != want {
+		t.Fatalf("order = %v, want %s", order, want)
+	}
+	w := events.Warnings()
+	if len(w) != 1 || w[0].Object != "Pod/run1-pod" || w[0].Count != 2 {
+		t.Fatalf("unexpected warnings %+v", w)
+	}
+	var out strings.Builder
+	if err := run.FormatEvents(&out, events); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Warning FailedScheduling (x2)") {
+		t.Fatalf("unexpected table:\n%s", out.String())
+	}
+}
+
+func TestStopWaitAndForceOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+	req := run.RunResourceRequest{Project: "demo", Resource: "runs", ID: "run1"}
+
+	body, _, err := svc.Stop(ctx, run.StopRequest{RunResourceRequest: req, Wait: true, Interval: time.Millisecond})
+	if err != nil || stateOf(t, body) != run.StateStopped {
+		t.Fatalf("stop and wait: %s, %v", body, err)
+	}
+
+	srv.StopState = run.StateStopping
+	_, _, err = svc.Stop(ctx, run.StopRequest{RunResourceRequest: req, Wait: true, GracePeriod: 30 * time.Millisecond, Interval: 5 * time.Millisecond})
+	var timeout *run.WaitTimeoutError
+	if !errors.As(err, &timeout) || timeout.State != run.StateStopping {
+		t.Fatalf("expected a timeout in STOPPING, got %v", err)
+	}
+	if _, ok := srv.Get("demo", "runs", "run1"); !ok {
+		t.Fatal("run deleted without Force")
+	}
+
+	if _, _, err := svc.Stop(ctx, run.StopRequest{RunResourceRequest: req, Force: true, GracePeriod: 20 * time.Millisecond, Interval: 5 * time.Millisecond}); err != nil {
+		t.Fatalf("force stop failed: %v", err)
+	}
+	if _, ok := srv.Get("demo", "runs", "run1"); ok {
+		t.Fatal("run not deleted by Force")
+	}
+	reqs := srv.Requests()
+	last := reqs[len(reqs)-1]
+	if last.Method != "DELETE" || last.Query.Get("cascade") != "true" {
+		t.Fatalf("unexpected last request %s %s?%s", last.Method, last.Path, last.Query.Encode())
+	}
+
+	// a run that completes while being stopped is stopped
+	srv.StopState = run.StateCompleted
+	srv.Add("demo", "runs", map[string]interface{}{"id": "run2", "kind": "python+job:run", "status": map[string]interface{}{"state": "RUNNING"}})
+	req.ID = "run2"
+	for _, sr := range []run.StopRequest{
+		{RunResourceRequest: req, Wait: true, Interval: time.Millisecond},
+		{RunResourceRequest: req, Force: true, GracePeriod: 20 * time.Millisecond, Interval: 5 * time.Millisecond},
+		{RunResourceRequest: req, Force: true},
+	} {
+		body, _, err := svc.Stop(ctx, sr)
+		if err != nil || stateOf(t, body) != run.StateCompleted {
+			t.Fatalf("stop of a completed run: %s, %v", body, err)
+		}
+	}
+	if _, ok := srv.Get("demo", "runs", "run2"); !ok {
+		t.Fatal("completed run deleted by Force")
+	}
+}
+
+func TestEndpointAndForwardOffline(t *testing.T) {
+	svc, srv := newOfflineService(t)
+	ctx := context.Background()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "notebook ", r.URL.Path)
+	}))
+	defer backend.Close()
+	srv.Add("demo", "runs", map[string]interface{}{
+		"id": "nb1", "kind": "jupyter+serve:run",
+		"status": map[string]interface{}{
+			"state": "RUNNING",
+			"service": map[string]interface{}{
+				"name": "nb1", "namespace": "dh", "url": strings.TrimPrefix(backend.URL, "http://"),
+				"ports": []interface{}{map[string]interface{}{"name": "http", "port": 8888, "target_port": 8888}},
+			},
+		},
+	})
+
+	req := run.RunResourceRequest{Project: "demo", ID: "nb1"}
+	ep, err := svc.Endpoint(ctx, req)
+	if err != nil || ep.URL != backend.URL || len(ep.Ports) != 1 || ep.Ports[0].TargetPort != 8888 {
+		t.Fatalf("endpoint: %+v, %v", ep, err)
+	}
+	if addr, _ := (&run.Endpoint{Name: "nb1", Namespace: "dh", Ports: ep.Ports}).Address(0); addr != "nb1.dh.svc.cluster.local:8888" {
+		t.Fatalf("unexpected cluster address %s", addr)
+	}
+	if _, err := svc.Endpoint(ctx, run.RunResourceRequest{Project: "demo", ID: "run2"}); err == nil {
+		t.Fatal("expected an error for a run without service")
+	}
+
+	// Dial runs on the goroutine of the forwarded connection
+	var dialed atomic.Value
+	dialed.Store("")
+	f, err := svc.Forward(ctx, run.ForwardRequest{
+		RunResourceRequest: req,
+		Dial: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dialed.Store(addr)
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	})
+	if err != nil {
+		t.Fatalf("forward failed: %v", err)
+	}
+	resp, err := http.Get(f.URL() + "/lab")
+	if err != nil {
+		t.Fatalf("get via forwarder failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "notebook /lab" || dialed.Load() != f.Target || f.Target != backend.Listener.Addr().String() {
+		t.Fatalf("unexpected response %q (dialed %s, target %s)", body, dialed.Load(), f.Target)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if _, err := http.Get(f.URL()); err == nil {
+		t.Fatal("forwarder still listening after Close")
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// scheduleField is the field of the task spec holding its cron schedule.
+const scheduleField = "schedule"
+
+type ScheduleRequest struct {
+	TaskRequest
+
+	// Cron is the schedule, see ParseCron
+	Cron string
+}
+
+type ScheduleInfoRequest struct {
+	TaskRequest
+
+	// Upcoming is the number of next fire times to compute, 5 when zero;
+	// they follow From, or now
+	Upcoming int
+	From     time.Time
+	// Past is the number of the latest runs of the task to list, 10 when
+	// zero
+	Past int
+}
+
+// ScheduleInfo describes the schedule of a task.
+type ScheduleInfo struct {
+	// Cron is the schedule, empty when the task has none
+	Cron string
+	// Next are the upcoming fire times
+	Next []time.Time
+	// Runs are the latest runs of the task, newest first
+	Runs []Run
+}
+
+// SetSchedule sets the cron schedule of the task req.ID, checking the
+// expression first, and returns the task.
+func (s *RunService) SetSchedule(ctx context.Context, req ScheduleRequest) (*Task, error) {
+	if _, err := ParseCron(req.Cron); err != nil {
+		return nil, err
+	}
+	return s.UpdateTask(ctx, UpdateTaskRequest{TaskRequest: req.TaskRequest, Spec: map[string]interface{}{scheduleField: req.Cron}})
+}
+
+// RemoveSchedule removes the schedule of the task req.ID and returns the
+// task.
+func (s *RunService) RemoveSchedule(ctx context.Context, req TaskRequest) (*Task, error) {
+	return s.UpdateTask(ctx, UpdateTaskRequest{TaskRequest: req, Spec: map[string]interface{}{scheduleField: nil}})
+}
+
+// Schedule returns the schedule of the task req.ID, with its upcoming fire
+// times and its latest runs.
+func (s *RunService) Schedule(ctx context.Context, req ScheduleInfoRequest) (_ *ScheduleInfo, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.tasks.schedule", req.Project, tasksResource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if err := req.check(); err != nil {
+		return nil, err
+	}
+	t, err := s.getTask(ctx, req.TaskRequest)
+	if err != nil {
+		return nil, err
+	}
+	info := &ScheduleInfo{}
+	info.Cron, _ = t.Spec[scheduleField].(string)
+	if info.Cron != "" {
+		c, err := ParseCron(info.Cron)
+		if err != nil {
+			return nil, err
+		}
+		n := req.Upcoming
+		if n <= 0 {
+			n = 5
+		}
+		from := req.From
+		if from.IsZero() {
+			from = time.Now()
+		}
+		for range n {
+			if from = c.Next(from); from.IsZero() {
+				break
+			}
+			info.Next = append(info.Next, from)
+		}
+	}
+
+	past := req.Past
+	if past <= 0 {
+		past = 10
+	}
+	params := map[string]string{
+		"task": fmt.Sprintf("%s://%s/%s", t.Kind, req.Project, t.ID),
+		"sort": "created,desc",
+		"size": strconv.Itoa(past),
+	}
+	b, status, err := s.http.Do(ctx, "GET", s.http.BuildURL(req.Project, "runs", "", params), nil)
+	if err != nil {
+		return nil, fmt.Errorf("list runs failed (status %d): %w", status, err)
+	}
+	var page struct {
+		Content []Run `json:"content"`
+	}
+	if err := json.Unmarshal(b, &page); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	if len(page.Content) > past {
+		page.Content = page.Content[:past]
+	}
+	info.Runs = page.Content
+	return info, nil
+}
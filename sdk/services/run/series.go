@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// MetricPoint is a value of a metric; Time is zero when the metric has no
+// timestamp.
+type MetricPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// MetricSeries are the values of a metric, in time order.
+type MetricSeries struct {
+	Name   string
+	Points []MetricPoint
+}
+
+// Last returns the latest value of the series.
+func (m MetricSeries) Last() (MetricPoint, bool) {
+	if len(m.Points) == 0 {
+		return MetricPoint{}, false
+	}
+	return m.Points[len(m.Points)-1], true
+}
+
+// ParseMetrics converts the metrics of a log entry (status.metrics) to
+// series sorted by name. Two shapes are understood: metrics of the run,
+// {name, value[, timestamp]} where value may be a list, and resource usage
+// samples, {timestamp, usage: {cpu: "250m", memory: "64Mi"}}, whose
+// Kubernetes quantities become the series cpu and memory (in cores and
+// bytes).
+func ParseMetrics(raw []interface{}) ([]MetricSeries, error) {
+	byName := map[string]*MetricSeries{}
+	add := func(name string, t time.Time, v float64) {
+		s := byName[name]
+		if s == nil {
+			s = &MetricSeries{Name: name}
+			byName[name] = s
+		}
+		s.Points = append(s.Points, MetricPoint{Time: t, Value: v})
+	}
+	for i, it := range raw {
+		m, ok := it.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid metric %d: not an object", i)
+		}
+		var t time.Time
+		if ts, ok := m["timestamp"].(string); ok {
+			t, _ = time.Parse(time.RFC3339Nano, ts)
+		}
+		if usage, ok := m["usage"].(map[string]interface{}); ok {
+			for name, q := range usage {
+				v, err := metricValue(q)
+				if err != nil {
+					return nil, fmt.Errorf("invalid metric %s: %w", name, err)
+				}
+				add(name, t, v)
+			}
+			continue
+		}
+		name, _ := m["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("invalid metric %d: no name", i)
+		}
+		values, ok := m["value"].([]interface{})
+		if !ok {
+			values = []interface{}{m["value"]}
+		}
+		for _, val := range values {
+			v, err := metricValue(val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid metric %s: %w", name, err)
+			}
+			add(name, t, v)
+		}
+	}
+
+	out := make([]MetricSeries, 0, len(byName))
+	for _, s := range byName {
+		sort.SliceStable(s.Points, func(i, j int) bool { return s.Points[i].Time.Before(s.Points[j].Time) })
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// metricValue converts a number, a bool or a numeric string, including
+// Kubernetes quantities such as 250m or 64Mi.
+func metricValue(v interface{}) (float64, error) {
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case int:
+		return float64(t), nil
+	case bool:
+		if t {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		return parseQuantity(t)
+	}
+	return 0, fmt.Errorf("%v is not a number", v)
+}
+
+var quantitySuffixes = []struct {
+	suffix string
+	factor float64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40}, {"Pi", 1 << 50}, {"Ei", 1 << 60},
+	{"n", 1e-9}, {"u", 1e-6}, {"m", 1e-3},
+	{"k", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12}, {"P", 1e15}, {"E", 1e18},
+}
+
+func parseQuantity(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v, nil
+	}
+	for _, q := range quantitySuffixes {
+		if num, ok := strings.CutSuffix(s, q.suffix); ok {
+			if v, err := strconv.ParseFloat(num, 64); err == nil {
+				return v * q.factor, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%q is not a number", s)
+}
+
+// FormatMetrics writes series as a table of name, time and value.
+func FormatMetrics(w io.Writer, series []MetricSeries) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTIME\tVALUE")
+	for _, s := range series {
+		for _, p := range s.Points {
+			ts := "-"
+			if !p.Time.IsZero() {
+				ts = p.Time.Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Name, ts, formatValue(p.Value))
+		}
+	}
+	return tw.Flush()
+}
+
+func formatValue(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -6,13 +6,24 @@ package run
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
 )
 
 // Stop performs POST {base}/{project}/{endpoint}/{id}/stop
 // Ritorna body e status per far stampare lo stato all'adapter.
-func (s *RunService) Stop(ctx context.Context, req StopRequest) ([]byte, int, error) {
+// Con Wait (o Force) attende uno stato finale (STOPPED, o COMPLETED/ERROR
+// se il run termina nel frattempo) e ritorna il run letto per ultimo; con
+// Force, se il run non si ferma entro GracePeriod, lo cancella e ritorna
+// body e status della DELETE.
+func (s *RunService) Stop(ctx context.Context, req StopRequest) (_ []byte, _ int, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.stop", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
 	if req.Project == "" {
 		return nil, 0, errors.New("project not specified")
 	}
@@ -28,5 +39,48 @@ func (s *RunService) Stop(ctx context.Context, req StopRequest) ([]byte, int, er
 	if err != nil {
 		return nil, status, fmt.Errorf("stop request failed (status %d): %w", status, err)
 	}
+	if !req.Wait && !req.Force {
+		return b, status, nil
+	}
+
+	// senza grace period il force non attende: basta lo stato ritornato
+	if req.Force && req.GracePeriod <= 0 {
+		var r Run
+		if err := json.Unmarshal(b, &r); err != nil {
+			return nil, status, fmt.Errorf("json parsing failed: %w", err)
+		}
+		if IsFinal(r.State()) {
+			return b, status, nil
+		}
+		return s.forceDelete(ctx, req)
+	}
+
+	r, err := s.Wait(ctx, WaitRequest{
+		RunResourceRequest: req.RunResourceRequest,
+		Until:              func(r *Run) bool { return IsFinal(r.State()) },
+		Interval:           req.Interval,
+		Timeout:            req.GracePeriod,
+	})
+	var timeout *WaitTimeoutError
+	switch {
+	case err == nil:
+		body, err := json.Marshal(r)
+		if err != nil {
+			return nil, status, fmt.Errorf("failed to marshal: %w", err)
+		}
+		return body, status, nil
+	case req.Force && errors.As(err, &timeout):
+		return s.forceDelete(ctx, req)
+	}
+	return nil, status, err
+}
+
+// forceDelete cancella il run con le sue risorse.
+func (s *RunService) forceDelete(ctx context.Context, req StopRequest) ([]byte, int, error) {
+	params := map[string]string{"cascade": "true"}
+	b, status, err := s.http.Do(ctx, "DELETE", s.http.BuildURL(req.Project, req.Resource, req.ID, params), nil)
+	if err != nil {
+		return nil, status, fmt.Errorf("force delete failed (status %d): %w", status, err)
+	}
 	return b, status, nil
 }
@@ -0,0 +1,263 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strconv"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// tasksResource is the Core endpoint of tasks.
+const tasksResource = "tasks"
+
+// Task is a task entity: how a function is executed (resources, node
+// selectors, schedule, ...), shared by its runs.
+type Task struct {
+	ID       string                 `json:"id"`
+	Key      string                 `json:"key,omitempty"`
+	Kind     string                 `json:"kind"`
+	Project  string                 `json:"project"`
+	Name     string                 `json:"name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Spec     map[string]interface{} `json:"spec,omitempty"`
+	Status   map[string]interface{} `json:"status,omitempty"`
+
+	// etag is the ETag Core sent with the task, for the If-Match of putTask
+	etag string
+}
+
+// Function returns spec.function, the key of the function of the task.
+func (t *Task) Function() string {
+	f, _ := t.Spec["function"].(string)
+	return f
+}
+
+// ListTasksRequest selects the tasks of a project; with a function (by ID
+// or name, latest version) only those of the function, with Kind only
+// those of that kind (e.g. python+job).
+type ListTasksRequest struct {
+	Project      string
+	FunctionID   string
+	FunctionName string
+	Kind         string
+
+	Options []config.RequestOption
+}
+
+// TaskRequest addresses the task ID of Project.
+type TaskRequest struct {
+	Project string
+	ID      string
+
+	Options []config.RequestOption
+}
+
+// UpdateTaskRequest changes the spec of a task.
+type UpdateTaskRequest struct {
+	TaskRequest
+
+	// Spec is merged into the spec of the task: objects are merged key by
+	// key, a null value removes the key, other values replace the old ones.
+	// With Replace, Spec replaces the whole spec (spec.function is kept).
+	Spec    map[string]interface{}
+	Replace bool
+}
+
+// ListTasks returns the tasks matching req, reading all pages.
+func (s *RunService) ListTasks(ctx context.Context, req ListTasksRequest) (_ []Task, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.tasks.list", req.Project, tasksResource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.Project == "" {
+		return nil, errors.New("project not specified")
+	}
+	params := map[string]string{}
+	if req.FunctionID != "" || req.FunctionName != "" {
+		_, fnKey, err := s.resolveFunction(ctx, req.Project, req.FunctionID, req.FunctionName)
+		if err != nil {
+			return nil, err
+		}
+		params["function"] = fnKey
+	}
+	if req.Kind != "" {
+		params["kind"] = req.Kind
+	}
+
+	var out []Task
+	for {
+		b, status, err := s.http.Do(ctx, "GET", s.http.BuildURL(req.Project, tasksResource, "", params), nil)
+		if err != nil {
+			return nil, fmt.Errorf("list tasks failed (status %d): %w", status, err)
+		}
+		var page struct {
+			Content  []Task `json:"content"`
+			Pageable struct {
+				PageNumber int `json:"pageNumber"`
+			} `json:"pageable"`
+			TotalPages int `json:"totalPages"`
+		}
+		if err := json.Unmarshal(b, &page); err != nil {
+			return nil, fmt.Errorf("json parsing failed: %w", err)
+		}
+		for _, t := range page.Content {
+			// older Core releases ignore the kind filter
+			if req.Kind == "" || t.Kind == req.Kind {
+				out = append(out, t)
+			}
+		}
+		if page.Pageable.PageNumber >= page.TotalPages-1 {
+			return out, nil
+		}
+		params["page"] = strconv.Itoa(page.Pageable.PageNumber + 1)
+	}
+}
+
+// GetTask returns the task req.ID.
+func (s *RunService) GetTask(ctx context.Context, req TaskRequest) (_ *Task, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.tasks.get", req.Project, tasksResource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if err := req.check(); err != nil {
+		return nil, err
+	}
+	return s.getTask(ctx, req)
+}
+
+// UpdateTask changes the spec of the task req.ID, e.g. its resources or
+// node selector, and returns the updated task. The update is conditional
+// on the version read, so a concurrent change fails with status 412
+// (config.HasStatus) instead of being overwritten.
+func (s *RunService) UpdateTask(ctx context.Context, req UpdateTaskRequest) (_ *Task, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.tasks.update", req.Project, tasksResource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if err := req.check(); err != nil {
+		return nil, err
+	}
+	t, err := s.getTask(ctx, req.TaskRequest)
+	if err != nil {
+		return nil, err
+	}
+	if req.Replace {
+		spec := maps.Clone(req.Spec)
+		if spec == nil {
+			spec = map[string]interface{}{}
+		}
+		if f := t.Function(); f != "" {
+			spec["function"] = f
+		}
+		t.Spec = spec
+	} else {
+		if t.Spec == nil {
+			t.Spec = map[string]interface{}{}
+		}
+		mergeSpec(t.Spec, req.Spec)
+	}
+	return s.putTask(ctx, t)
+}
+
+// DeleteTask deletes the task req.ID.
+func (s *RunService) DeleteTask(ctx context.Context, req TaskRequest) (err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.tasks.delete", req.Project, tasksResource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if err := req.check(); err != nil {
+		return err
+	}
+	if _, status, err := s.http.Do(ctx, "DELETE", s.http.BuildURL(req.Project, tasksResource, req.ID, nil), nil); err != nil {
+		return fmt.Errorf("delete task failed (status %d): %w", status, err)
+	}
+	return nil
+}
+
+func (r TaskRequest) check() error {
+	if r.Project == "" {
+		return errors.New("project not specified")
+	}
+	if r.ID == "" {
+		return errors.New("id not specified")
+	}
+	return nil
+}
+
+func (s *RunService) getTask(ctx context.Context, req TaskRequest) (*Task, error) {
+	var header http.Header
+	b, status, err := s.http.Do(config.ContextWithResponseHeader(ctx, &header), "GET", s.http.BuildURL(req.Project, tasksResource, req.ID, nil), nil)
+	if err != nil {
+		return nil, fmt.Errorf("get task failed (status %d): %w", status, err)
+	}
+	var t Task
+	if err := json.Unmarshal(b, &t); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	t.etag = header.Get("ETag")
+	return &t, nil
+}
+
+// putTask stores t, if it wasn't changed since it was read: Core checks
+// the ETag it sent with t or, when it sent none, metadata.updated is
+// compared with the current one.
+func (s *RunService) putTask(ctx context.Context, t *Task) (*Task, error) {
+	body, err := json.Marshal(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+	if t.etag != "" {
+		ctx = config.ContextWithRequestOptions(ctx, config.WithHeader("If-Match", t.etag))
+	} else if v, _ := t.Metadata["updated"].(string); v != "" {
+		cur, err := s.getTask(ctx, TaskRequest{Project: t.Project, ID: t.ID})
+		if err != nil {
+			return nil, err
+		}
+		if u, _ := cur.Metadata["updated"].(string); u != v {
+			return nil, fmt.Errorf("task %s was modified concurrently", t.ID)
+		}
+		if cur.etag != "" {
+			ctx = config.ContextWithRequestOptions(ctx, config.WithHeader("If-Match", cur.etag))
+		}
+	}
+	b, status, err := s.http.Do(ctx, "PUT", s.http.BuildURL(t.Project, tasksResource, t.ID, nil), body)
+	if err != nil {
+		return nil, fmt.Errorf("update task failed (status %d): %w", status, err)
+	}
+	var out Task
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	return &out, nil
+}
+
+// mergeSpec merges patch into spec as a JSON merge patch does.
+func mergeSpec(spec, patch map[string]interface{}) {
+	for k, v := range patch {
+		if v == nil {
+			delete(spec, k)
+			continue
+		}
+		if pm, ok := v.(map[string]interface{}); ok {
+			sm, ok := spec[k].(map[string]interface{})
+			if !ok {
+				sm = map[string]interface{}{}
+			} else {
+				sm = maps.Clone(sm)
+			}
+			mergeSpec(sm, pm)
+			spec[k] = sm
+			continue
+		}
+		spec[k] = v
+	}
+}
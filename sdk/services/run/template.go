@@ -0,0 +1,250 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Template is a run template, a YAML document such as:
+//
+//	parameters:
+//	  epochs: {type: int, default: 10}
+//	  lr: {type: float, required: true}
+//	project: demo
+//	task: python+job
+//	function: trainer
+//	spec:
+//	  parameters:
+//	    epochs: ${epochs}
+//	    lr: ${lr}
+//	    tag: run-${epochs}-${suffix:-x}
+//
+// Values that are a single ${param} take the typed value of the param;
+// placeholders within text are replaced by its text. ${param:-default}
+// sets a default in place.
+type Template struct {
+	Parameters map[string]TemplateParam `json:"parameters,omitempty"`
+
+	Project string `json:"project"`
+	// Task is the task kind, e.g. python+job
+	Task string `json:"task"`
+	// Function is the function name, FunctionID its id
+	Function   string                 `json:"function,omitempty"`
+	FunctionID string                 `json:"function_id,omitempty"`
+	Spec       map[string]interface{} `json:"spec,omitempty"`
+}
+
+// TemplateParam declares a template parameter.
+type TemplateParam struct {
+	// Type is string, int, float or bool; values are converted to it
+	// (e.g. "5" to 5). Empty keeps values as given.
+	Type     string      `json:"type,omitempty"`
+	Default  interface{} `json:"default,omitempty"`
+	Required bool        `json:"required,omitempty"`
+}
+
+var placeholder = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.-]*)(:-([^}]*))?\}`)
+
+// RunFromTemplate renders the template at templatePath with params (see
+// RenderTemplate) and submits the run.
+func (s *RunService) RunFromTemplate(ctx context.Context, templatePath string, params map[string]interface{}) (*Run, error) {
+	data, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	req, err := RenderTemplate(data, params)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", templatePath, err)
+	}
+	return s.Run(ctx, *req)
+}
+
+// RenderTemplate replaces the placeholders of the YAML template data with
+// params, the defaults of the declared parameters and the inline
+// defaults, and returns the resulting request. Missing required
+// parameters, placeholders without a value and values that don't convert
+// to the declared type are errors.
+func RenderTemplate(data []byte, params map[string]interface{}) (*RunRequest, error) {
+	jsonBytes, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("yaml to json failed: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &doc); err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	var declared struct {
+		Parameters map[string]TemplateParam `json:"parameters"`
+	}
+	if err := json.Unmarshal(jsonBytes, &declared); err != nil {
+		return nil, fmt.Errorf("invalid template parameters: %w", err)
+	}
+	delete(doc, "parameters")
+
+	values, err := templateValues(declared.Parameters, params)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	rendered := renderValue(doc, values, &missing)
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("no value for template parameters %s", strings.Join(missing, ", "))
+	}
+
+	b, err := json.Marshal(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+	var t Template
+	if err := json.Unmarshal(b, &t); err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	if t.Task == "" {
+		return nil, errors.New("template has no task")
+	}
+	return &RunRequest{
+		Project:      t.Project,
+		TaskKind:     t.Task,
+		FunctionID:   t.FunctionID,
+		FunctionName: t.Function,
+		InputSpec:    t.Spec,
+	}, nil
+}
+
+// templateValues merges params over the defaults of the declared
+// parameters and converts them to the declared types.
+func templateValues(declared map[string]TemplateParam, params map[string]interface{}) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for k, v := range params {
+		values[k] = v
+	}
+	var errs []error
+	for name, p := range declared {
+		v, ok := values[name]
+		if !ok && p.Default != nil {
+			v, ok = p.Default, true
+		}
+		if !ok {
+			if p.Required {
+				errs = append(errs, fmt.Errorf("parameter %s is required", name))
+			}
+			continue
+		}
+		cv, err := coerce(v, p.Type)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("parameter %s: %w", name, err))
+			continue
+		}
+		values[name] = cv
+	}
+	return values, errors.Join(errs...)
+}
+
+// coerce converts v to typ.
+func coerce(v interface{}, typ string) (interface{}, error) {
+	s, isString := v.(string)
+	switch typ {
+	case "":
+		return v, nil
+	case "string":
+		if isString {
+			return s, nil
+		}
+		return fmt.Sprint(v), nil
+	case "int":
+		switch n := v.(type) {
+		case int:
+			return n, nil
+		case int64:
+			return int(n), nil
+		case float64:
+			if n == float64(int(n)) {
+				return int(n), nil
+			}
+		case string:
+			if i, err := strconv.Atoi(strings.TrimSpace(n)); err == nil {
+				return i, nil
+			}
+		}
+	case "float":
+		switch n := v.(type) {
+		case int:
+			return float64(n), nil
+		case float64:
+			return n, nil
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(n), 64); err == nil {
+				return f, nil
+			}
+		}
+	case "bool":
+		switch b := v.(type) {
+		case bool:
+			return b, nil
+		case string:
+			if pb, err := strconv.ParseBool(strings.TrimSpace(b)); err == nil {
+				return pb, nil
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q", typ)
+	}
+	return nil, fmt.Errorf("%v is not a valid %s", v, typ)
+}
+
+// renderValue replaces the placeholders in v, recording the names without
+// a value in missing.
+func renderValue(v interface{}, values map[string]interface{}, missing *[]string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, it := range t {
+			out[k] = renderValue(it, values, missing)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, it := range t {
+			out[i] = renderValue(it, values, missing)
+		}
+		return out
+	case string:
+		lookup := func(m []string) (interface{}, bool) {
+			if v, ok := values[m[1]]; ok {
+				return v, true
+			}
+			if m[2] != "" {
+				return m[3], true
+			}
+			*missing = append(*missing, m[1])
+			return nil, false
+		}
+		// a whole placeholder keeps the type of the value
+		if m := placeholder.FindStringSubmatch(t); m != nil && m[0] == t {
+			val, _ := lookup(m)
+			return val
+		}
+		return placeholder.ReplaceAllStringFunc(t, func(s string) string {
+			val, ok := lookup(placeholder.FindStringSubmatch(s))
+			if !ok {
+				return s
+			}
+			return fmt.Sprint(val)
+		})
+	}
+	return v
+}
@@ -4,11 +4,21 @@
 
 package run
 
+import (
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
 // Base comune per tutte le operazioni su una risorsa "run-like"
 type RunResourceRequest struct {
 	Project  string
 	Resource string
 	ID       string
+
+	// Options add headers and query params to the Core calls
+	// (config.WithHeader, config.WithQueryParam)
+	Options []config.RequestOption
 }
 
 // Request per logs e get resource
@@ -25,6 +35,18 @@ type MetricsRequest struct {
 // Request per stop
 type StopRequest struct {
 	RunResourceRequest
+
+	// Wait polls the run after the stop until it is STOPPED (or has
+	// completed or failed meanwhile), for at most GracePeriod when set: its
+	// expiry is a *WaitTimeoutError
+	Wait        bool
+	GracePeriod time.Duration
+	// Force deletes the run, and with it its Kubernetes resources, when it
+	// hasn't ended at the end of GracePeriod (right after the stop when
+	// zero); it implies Wait
+	Force bool
+	// Interval between polls, 2s when zero
+	Interval time.Duration
 }
 
 // Request per resume
@@ -42,4 +64,67 @@ type RunRequest struct {
 
 	// endpoint per i runs, già risolto dall'adapter (es. "runs")
 	ResolvedRunsEndpoint string
+
+	// IdempotencyKey makes a retried Run return the run (and task) created
+	// the first time; empty generates a new key per call
+	IdempotencyKey string
+	// Wait, when set, waits for the new run as Wait does (its project,
+	// resource and id are those of the run)
+	Wait *WaitRequest
+	// Options add headers and query params to the Core calls
+	Options []config.RequestOption
+}
+
+// Run is a run entity as returned by Core.
+type Run struct {
+	ID       string                 `json:"id"`
+	Key      string                 `json:"key,omitempty"`
+	Kind     string                 `json:"kind"`
+	Project  string                 `json:"project"`
+	Name     string                 `json:"name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Spec     map[string]interface{} `json:"spec,omitempty"`
+	Status   map[string]interface{} `json:"status,omitempty"`
+}
+
+// State returns status.state, e.g. RUNNING.
+func (r *Run) State() string {
+	s, _ := r.Status["state"].(string)
+	return s
+}
+
+// WaitRequest polls the run ID until Until reports true, by default until
+// the run reaches a final state (see IsFinal).
+type WaitRequest struct {
+	RunResourceRequest
+
+	// Until is checked on each poll; nil waits for a final state
+	Until func(*Run) bool
+	// Interval between polls, 2s when zero
+	Interval time.Duration
+	// Timeout bounds the whole wait, which otherwise lasts as long as ctx
+	Timeout time.Duration
+	// OnProgress, when set, receives the run on the first poll and each
+	// time its state changes
+	OnProgress func(*Run)
+}
+
+// RetryRequest retries the run ID (see RunService.Retry).
+type RetryRequest struct {
+	RunResourceRequest
+
+	// Inputs and Parameters are merged into spec.inputs and
+	// spec.parameters; Spec replaces any other field of the spec
+	Inputs     map[string]interface{}
+	Parameters map[string]interface{}
+	Spec       map[string]interface{}
+
+	// AnyState retries runs in any state, not only ERROR and STOPPED ones
+	AnyState bool
+
+	// IdempotencyKey makes a retried call return the run created the first
+	// time; empty generates a new key per call
+	IdempotencyKey string
+	// Wait, when set, waits for the new run as Wait does
+	Wait *WaitRequest
 }
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// Run states, as reported in status.state.
+const (
+	StateCreated   = "CREATED"
+	StateBuilt     = "BUILT"
+	StateReady     = "READY"
+	StatePending   = "PENDING"
+	StateRunning   = "RUNNING"
+	StateStopping  = "STOPPING"
+	StateCompleted = "COMPLETED"
+	StateError     = "ERROR"
+	StateStopped   = "STOPPED"
+)
+
+const defaultWaitInterval = 2 * time.Second
+
+// IsFinal reports whether a run in state won't change state by itself.
+func IsFinal(state string) bool {
+	return state == StateCompleted || state == StateError || state == StateStopped
+}
+
+// StateIn returns a WaitRequest.Until that holds once the run is in one of
+// states.
+func StateIn(states ...string) func(*Run) bool {
+	return func(r *Run) bool { return slices.Contains(states, r.State()) }
+}
+
+// WaitTimeoutError is returned by Wait when the timeout expires; State is
+// the last state seen.
+type WaitTimeoutError struct {
+	Project string
+	ID      string
+	State   string
+	Timeout time.Duration
+}
+
+func (e *WaitTimeoutError) Error() string {
+	return fmt.Sprintf("run %s/%s still %s after %s", e.Project, e.ID, e.State, e.Timeout)
+}
+
+// Wait polls the run req.ID until req.Until (by default a final state) holds
+// and returns it. A run that ends in a state Until doesn't accept is an
+// error, as it won't get there anymore; so is the expiry of req.Timeout
+// (*WaitTimeoutError) or of ctx.
+func (s *RunService) Wait(ctx context.Context, req WaitRequest) (_ *Run, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "run.wait", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.Project == "" {
+		return nil, errors.New("project not specified")
+	}
+	if req.ID == "" {
+		return nil, errors.New("id not specified")
+	}
+	if req.Resource == "" {
+		req.Resource = "runs"
+	}
+	until := req.Until
+	if until == nil {
+		until = func(r *Run) bool { return IsFinal(r.State()) }
+	}
+	interval := req.Interval
+	if interval <= 0 {
+		interval = defaultWaitInterval
+	}
+	var deadline <-chan time.Time
+	if req.Timeout > 0 {
+		t := time.NewTimer(req.Timeout)
+		defer t.Stop()
+		deadline = t.C
+	}
+
+	last := ""
+	for first := true; ; first = false {
+		r, err := s.getRun(ctx, req.RunResourceRequest)
+		if err != nil {
+			return nil, err
+		}
+		state := r.State()
+		if req.OnProgress != nil && (first || state != last) {
+			req.OnProgress(r)
+		}
+		last = state
+		if until(r) {
+			return r, nil
+		}
+		if IsFinal(state) {
+			return r, fmt.Errorf("run %s/%s ended in state %s", req.Project, req.ID, state)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for run %s/%s interrupted in state %s: %w", req.Project, req.ID, last, ctx.Err())
+		case <-deadline:
+			return nil, &WaitTimeoutError{Project: req.Project, ID: req.ID, State: last, Timeout: req.Timeout}
+		case <-time.After(interval):
+		}
+	}
+}
+
+// getRun reads the run req.ID.
+func (s *RunService) getRun(ctx context.Context, req RunResourceRequest) (*Run, error) {
+	b, status, err := s.http.Do(ctx, "GET", s.http.BuildURL(req.Project, req.Resource, req.ID, nil), nil)
+	if err != nil {
+		return nil, fmt.Errorf("get run failed (status %d): %w", status, err)
+	}
+	var r Run
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, fmt.Errorf("json parsing failed: %w", err)
+	}
+	return &r, nil
+}
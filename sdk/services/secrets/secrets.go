@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// dataURL is {base}/-/{project}/secrets/data, which reads and writes the
+// values.
+func (s *SecretsService) dataURL(project string, params map[string]string) string {
+	return s.http.BuildURL(project, resource, "data", params)
+}
+
+// CreateSecret stores the value of the secret name in project, creating the
+// secret or replacing its value, and returns the secret.
+func (s *SecretsService) CreateSecret(ctx context.Context, req CreateSecretRequest) (_ *Secret, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "secrets.create", req.Project, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.Project == "" || req.Name == "" {
+		return nil, errors.New("project and name are required")
+	}
+	v, err := req.Source.read()
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(map[string]string{req.Name: v.Reveal()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+	if _, status, err := s.http.Do(config.ContextWithSensitiveBodies(ctx), "PUT", s.dataURL(req.Project, nil), body); err != nil {
+		return nil, fmt.Errorf("create secret failed (status %d): %w", status, err)
+	}
+	return s.GetSecret(ctx, req.Project, req.Name)
+}
+
+// read returns the value of the source.
+func (src Source) read() (Value, error) {
+	set := 0
+	for _, ok := range []bool{src.Value != "", src.File != "", src.Env != ""} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return "", errors.New("set exactly one of value, file and env")
+	}
+	switch {
+	case src.File != "":
+		b, err := os.ReadFile(src.File)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		return Value(b), nil
+	case src.Env != "":
+		v, ok := os.LookupEnv(src.Env)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", src.Env)
+		}
+		return Value(v), nil
+	}
+	return src.Value, nil
+}
+
+// ListSecrets returns the secrets of project, reading all pages.
+func (s *SecretsService) ListSecrets(ctx context.Context, project string, opts ...config.RequestOption) (_ []Secret, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "secrets.list", project, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, opts...)
+
+	if project == "" {
+		return nil, errors.New("project is required")
+	}
+	return s.list(ctx, project, map[string]string{})
+}
+
+func (s *SecretsService) list(ctx context.Context, project string, params map[string]string) ([]Secret, error) {
+	var out []Secret
+	for {
+		b, _, err := s.http.Do(ctx, "GET", s.http.BuildURL(project, resource, "", params), nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Content  []Secret `json:"content"`
+			Pageable struct {
+				PageNumber int `json:"pageNumber"`
+			} `json:"pageable"`
+			TotalPages int `json:"totalPages"`
+		}
+		if err := json.Unmarshal(b, &page); err != nil {
+			return nil, fmt.Errorf("json parsing failed: %w", err)
+		}
+		out = append(out, page.Content...)
+		if page.Pageable.PageNumber >= page.TotalPages-1 {
+			return out, nil
+		}
+		params["page"] = strconv.Itoa(page.Pageable.PageNumber + 1)
+	}
+}
+
+// GetSecret returns the secret name of project, without its value.
+func (s *SecretsService) GetSecret(ctx context.Context, project, name string, opts ...config.RequestOption) (_ *Secret, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "secrets.get", project, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, opts...)
+
+	if project == "" || name == "" {
+		return nil, errors.New("project and name are required")
+	}
+	found, err := s.list(ctx, project, map[string]string{"name": name})
+	if err != nil {
+		return nil, err
+	}
+	for _, sec := range found {
+		if sec.Name == name {
+			return &sec, nil
+		}
+	}
+	return nil, notFound(name)
+}
+
+// GetSecretValue returns the value of the secret name of project.
+func (s *SecretsService) GetSecretValue(ctx context.Context, project, name string, opts ...config.RequestOption) (_ Value, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "secrets.value", project, resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, opts...)
+
+	if project == "" || name == "" {
+		return "", errors.New("project and name are required")
+	}
+	b, _, err := s.http.Do(config.ContextWithSensitiveBodies(ctx), "GET", s.dataURL(project, map[string]string{"keys": name}), nil)
+	if err != nil {
+		return "", err
+	}
+	var values map[string]string
+	if err := json.Unmarshal(b, &values); err != nil {
+		return "", errors.New("json parsing failed")
+	}
+	v, ok := values[name]
+	if !ok {
+		return "", notFound(name)
+	}
+	return Value(v), nil
+}
+
+// DeleteSecret deletes the secret name of project and its value.
+func (s *SecretsService) DeleteSecret(ctx context.Context, project, name string, opts ...config.RequestOption) (err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "secrets.delete", project, resource)
+	defer func() { config.EndSpan(span, err) }()
+
+	sec, err := s.GetSecret(ctx, project, name, opts...)
+	if err != nil {
+		return err
+	}
+	ctx = config.ContextWithRequestOptions(ctx, opts...)
+	if _, status, err := s.http.Do(ctx, "DELETE", s.http.BuildURL(project, resource, sec.ID, nil), nil); err != nil {
+		return fmt.Errorf("delete secret failed (status %d): %w", status, err)
+	}
+	return nil
+}
+
+// notFound is the error of a missing secret, matched by config.IsNotFound.
+func notFound(name string) error {
+	return &config.CoreError{
+		StatusCode: http.StatusNotFound,
+		Status:     "404 Not Found",
+		Message:    fmt.Sprintf("secret %s not found", name),
+	}
+}
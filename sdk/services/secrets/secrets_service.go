@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package secrets manages the secrets of a project, which run specs
+// reference by name. Secret values are never written to logs: calls that
+// carry them are marked with config.ContextWithSensitiveBodies and Value
+// prints as config.Redacted.
+package secrets
+
+import (
+	"context"
+	"errors"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// resource is the Core endpoint of secrets.
+const resource = "secrets"
+
+type SecretsService struct {
+	http   config.CoreHTTP
+	tracer trace.Tracer
+}
+
+// NewSecretsService builds the service; opts customize HTTP client, logger
+// and retries (see config.ServiceOption).
+func NewSecretsService(_ context.Context, conf config.Config, opts ...config.ServiceOption) (*SecretsService, error) {
+	if conf.Core.BaseURL == "" || conf.Core.APIVersion == "" {
+		return nil, errors.New("invalid core config")
+	}
+	o := config.NewServiceOptions(opts...).ForConfig(conf)
+	return &SecretsService{
+		http:   config.NewHTTPCoreWithOptions(conf.Core, o),
+		tracer: config.Tracer(o.TracerProvider),
+	}, nil
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/dhcoretest"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/secrets"
+)
+
+func TestSecretsLifecycleOffline(t *testing.T) {
+	srv := dhcoretest.NewServer()
+	t.Cleanup(srv.Close)
+
+	var logs bytes.Buffer
+	cfg := srv.Config()
+	cfg.Core.Debug, cfg.Core.DebugBodies = true, true
+	cfg.Core.DebugLogger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	svc, err := secrets.NewSecretsService(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to init sdk: %v", err)
+	}
+	ctx := context.Background()
+
+	file := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(file, []byte("from-file"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_DB_PASSWORD", "from-env")
+
+	sec, err := svc.CreateSecret(ctx, secrets.CreateSecretRequest{Project: "demo", Name: "db", Source: secrets.Source{Env: "TEST_DB_PASSWORD"}})
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if sec.ID == "" || sec.Name != "db" {
+		t.Fatalf("unexpected secret %+v", sec)
+	}
+	if _, err := svc.CreateSecret(ctx, secrets.CreateSecretRequest{Project: "demo", Name: "token", Source: secrets.Source{File: file}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateSecret(ctx, secrets.CreateSecretRequest{Project: "demo", Name: "x", Source: secrets.Source{Value: "a", Env: "B"}}); err == nil {
+		t.Fatal("expected an error for two sources")
+	}
+
+	list, err := svc.ListSecrets(ctx, "demo")
+	if err != nil || len(list) != 2 {
+		t.Fatalf("list: %+v, %v", list, err)
+	}
+	v, err := svc.GetSecretValue(ctx, "demo", "token")
+	if err != nil || v.Reveal() != "from-file" {
+		t.Fatalf("value: %v", err)
+	}
+	if s := fmt.Sprintf("%v %s %#v", v, v, v); strings.Contains(s, "from-file") {
+		t.Fatalf("value printed: %s", s)
+	}
+	if strings.Contains(logs.String(), "from-") {
+		t.Fatalf("value in debug log:\n%s", logs.String())
+	}
+
+	if err := svc.DeleteSecret(ctx, "demo", "db"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := svc.GetSecret(ctx, "demo", "db"); !config.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if _, err := svc.GetSecretValue(ctx, "demo", "db"); !config.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"log/slog"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// Secret is the entity of a secret; it doesn't hold the value (see
+// GetSecretValue).
+type Secret struct {
+	ID       string                 `json:"id,omitempty"`
+	Name     string                 `json:"name"`
+	Kind     string                 `json:"kind,omitempty"`
+	Key      string                 `json:"key,omitempty"`
+	Project  string                 `json:"project,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Spec     map[string]interface{} `json:"spec,omitempty"`
+}
+
+// Value is a secret value. It prints and logs as config.Redacted; Reveal
+// returns it.
+type Value string
+
+// Reveal returns the value.
+func (v Value) Reveal() string { return string(v) }
+
+func (v Value) String() string { return config.Redacted }
+
+func (v Value) GoString() string { return config.Redacted }
+
+// LogValue implements slog.LogValuer.
+func (v Value) LogValue() slog.Value { return slog.StringValue(config.Redacted) }
+
+// Source is where CreateSecret reads the value: set exactly one field.
+type Source struct {
+	Value Value
+	// File is read as is
+	File string
+	// Env is the name of an environment variable, which must be set
+	Env string
+}
+
+type CreateSecretRequest struct {
+	Project string
+	Name    string
+	Source  Source
+
+	// Options add headers and query params to the Core calls
+	// (config.WithHeader, config.WithQueryParam)
+	Options []config.RequestOption
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transfer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+)
+
+// extractArchive downloads the archive at p (parsed as pp) and extracts it
+// next to target, the local path the archive itself would have had. A
+// tar.gz on S3 is extracted while it streams and checked against the
+// SHA-256 of expected at the end; other archives are downloaded to target,
+// checked, extracted and removed. Files already extracted are removed when
+// the check fails.
+func (s *TransferService) extractArchive(
+	ctx context.Context,
+	p string,
+	pp *utils.ParsedPath,
+	target string,
+	expected utils.Checksums,
+	verify bool,
+	opts utils.DownloadOptions,
+) ([]DownloadInfo, error) {
+	dir := filepath.Dir(target)
+	format := utils.ArchiveFormatOf(pp.Filename)
+
+	var files []string
+	var err error
+	if pp.Scheme == "s3" && format == utils.ArchiveTarGz {
+		files, err = s.extractS3Stream(ctx, p, pp, dir, expected, verify)
+	} else {
+		files, err = s.extractFile(ctx, pp, target, dir, format, expected, verify, opts)
+	}
+	if err != nil {
+		for _, f := range files {
+			_ = os.Remove(f)
+		}
+		return nil, err
+	}
+
+	out := make([]DownloadInfo, 0, len(files))
+	for _, f := range files {
+		st, err := os.Stat(f)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, DownloadInfo{Filename: filepath.Base(f), Size: st.Size(), Path: f})
+	}
+	return out, nil
+}
+
+func (s *TransferService) extractS3Stream(
+	ctx context.Context,
+	p string,
+	pp *utils.ParsedPath,
+	dir string,
+	expected utils.Checksums,
+	verify bool,
+) ([]string, error) {
+	rc, _, err := s.s3.GetObjectStream(ctx, pp.Host, strings.TrimPrefix(pp.Path, "/"))
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	r := io.TeeReader(rc, h)
+	files, err := utils.ExtractTarGz(r, dir)
+	if err != nil {
+		return files, err
+	}
+	// padding after the end of the tar still counts for the checksum
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return files, fmt.Errorf("stream failed: %w", err)
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); verify && expected.SHA256 != "" && expected.SHA256 != actual {
+		return files, &utils.ChecksumError{Path: p, Algorithm: "sha256", Expected: expected.SHA256, Actual: actual}
+	}
+	return files, nil
+}
+
+func (s *TransferService) extractFile(
+	ctx context.Context,
+	pp *utils.ParsedPath,
+	target, dir, format string,
+	expected utils.Checksums,
+	verify bool,
+	opts utils.DownloadOptions,
+) ([]string, error) {
+	var err error
+	if pp.Scheme == "s3" {
+		err = utils.DownloadS3FileOrDirWithOptions(s.s3, ctx, pp, target, opts)
+	} else {
+		err = utils.DownloadHTTPFileWithOptions(ctx, pp.Path, target, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(target)
+
+	if verify && !expected.IsZero() {
+		if _, err := utils.VerifyFile(target, expected); err != nil {
+			return nil, err
+		}
+	}
+	if format == utils.ArchiveZip {
+		return utils.ExtractZip(target, dir)
+	}
+	f, err := os.Open(target)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return utils.ExtractTarGz(f, dir)
+}
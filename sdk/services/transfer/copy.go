@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Copy copies the object at src, or every object under it when src ends
+// with "/", to dst with server-side S3 copies; both are s3:// paths. A file
+// copied to a dst ending with "/" keeps its name; a directory is copied
+// under dst. It implements crud.Copier.
+func (s *TransferService) Copy(ctx context.Context, src, dst string) (err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "transfer.copy", "", "",
+		attribute.String("transfer.source", src), attribute.String("transfer.destination", dst))
+	defer func() { config.EndSpan(span, err) }()
+
+	from, err := s3Location(src)
+	if err != nil {
+		return err
+	}
+	to, err := s3Location(dst)
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasSuffix(from.Path, "/") {
+		key := to.Path
+		if key == "" || strings.HasSuffix(key, "/") {
+			key += from.Filename
+		}
+		return s.s3.CopyObject(ctx, from.Host, from.Path, to.Host, key)
+	}
+
+	files, err := s.s3.ListFilesAll(ctx, from.Host, from.Path)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no objects under %s", src)
+	}
+	prefix := to.Path
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	berr := utils.NewBatchError("copy")
+	for _, f := range files {
+		if err := s.s3.CopyObject(ctx, from.Host, f.Path, to.Host, prefix+f.Name); err != nil {
+			berr.Add(f.Path, err)
+		}
+	}
+	return berr.ErrorOrNil()
+}
+
+func s3Location(p string) (*utils.ParsedPath, error) {
+	pp, err := utils.ParsePath(p)
+	if err != nil {
+		return nil, err
+	}
+	if pp.Scheme != "s3" || pp.Host == "" {
+		return nil, fmt.Errorf("%s is not an s3://bucket/key path", p)
+	}
+	return pp, nil
+}
@@ -10,113 +10,205 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
 	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func (s *TransferService) Download(ctx context.Context, endpoint string, req DownloadRequest) ([]DownloadInfo, error) {
-	if req.Resource != "projects" && req.Project == "" {
-		return nil, errors.New("project is mandatory for non-project resources")
-	}
-	if req.ID == "" && req.Name == "" {
-		return nil, errors.New("you must specify id or name")
-	}
+func (s *TransferService) Download(ctx context.Context, endpoint string, req DownloadRequest) (_ []DownloadInfo, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "transfer.download", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
 
-	params := map[string]string{}
-	id := req.ID
-	if id == "" {
-		params["name"] = req.Name
-		params["versions"] = "latest"
-	}
-
-	url := s.http.BuildURL(req.Project, endpoint, id, params)
-	body, _, err := s.http.Do(ctx, "GET", url, nil)
+	body, paths, err := s.fetchPaths(ctx, endpoint, req)
 	if err != nil {
 		return nil, err
 	}
+	expected := expectedChecksums(body)
+	verify := !req.NoVerify || req.VerifyOnly
 
-	paths, err := extractPaths(body)
-	if err != nil {
-		return nil, err
+	dlOpts := utils.DownloadOptions{
+		Verbose:        req.Verbose,
+		ProgressFormat: req.ProgressFormat,
+		ProgressOutput: req.ProgressOutput,
+		BufferSize:     s.tune.BufferSize,
+		Logger:         s.logger,
+		Concurrency:    req.Concurrency,
+		PartSize:       req.PartSize,
+		Parallelism:    req.Parallelism,
 	}
 
+	// un path fallito non interrompe gli altri: i file scaricati sono riportati
+	// in out e i fallimenti restituiti come *utils.BatchError
+	berr := utils.NewBatchError("download")
 	var out []DownloadInfo
+	// report verifica (se richiesto) e riporta un file scaricato; obj è
+	// l'oggetto il cui ETag sostituisce i checksum mancanti (nil se non serve)
+	report := func(ctx context.Context, item, local string, size int64, sums utils.Checksums, obj *etagObject) {
+		st, err := os.Stat(local)
+		if err != nil || st.IsDir() {
+			if req.VerifyOnly {
+				berr.Add(item, fmt.Errorf("local file missing: %w", err))
+			}
+			return
+		}
+		info := DownloadInfo{Filename: filepath.Base(local), Size: st.Size(), Path: local}
+		if verify {
+			err := checkFile(&info, size, sums)
+			if err != nil && obj != nil && s.opaqueETag(ctx, obj.bucket, obj.key, obj.etag) {
+				// l'ETag di un oggetto cifrato con SSE-KMS o SSE-C non è l'MD5
+				err = checkFile(&info, size, utils.Checksums{})
+			}
+			if err != nil {
+				berr.Add(item, err)
+				return
+			}
+		}
+		out = append(out, info)
+	}
 	for _, p := range paths {
 		pp, err := utils.ParsePath(p)
 		if err != nil {
+			berr.Add(p, err)
 			continue
 		}
 		target, createdDir, err := chooseLocalTarget(req.Destination, pp.Filename)
 		if err != nil {
+			berr.Add(p, err)
 			continue
 		}
 		_ = createdDir
+		files := expected[p]
+
+		pctx, pspan := config.StartSpan(ctx, s.tracer, "transfer.fetch", req.Project, req.Resource,
+			attribute.String("url.full", p))
+		failures := berr.Len()
+		archive := req.Extract && !req.VerifyOnly && utils.ArchiveFormatOf(pp.Filename) != "" && !strings.HasSuffix(pp.Path, "/")
+		switch {
+		case archive:
+			// archivio: estratto in Destination al posto del file
+			infos, xerr := s.extractArchive(pctx, p, pp, target, files[pp.Filename], verify, dlOpts)
+			out = append(out, infos...)
+			berr.Add(p, xerr)
 
-		switch pp.Scheme {
-		case "s3":
+		case pp.Scheme == "s3":
 			key := strings.TrimPrefix(pp.Path, "/")
 			if strings.HasSuffix(key, "/") {
-				// Directory (paginata): in caso di errore, NON fallire tutto → skip
-				if derr := utils.DownloadS3FileOrDir(s.s3, ctx, pp, target, req.Verbose); derr != nil {
-					// skip dir (log a livello CLI se vuoi)
-					continue
+				// Directory (paginata): i file falliti sono riportati, gli altri restano
+				if !req.VerifyOnly {
+					derr := utils.DownloadS3FileOrDirWithOptions(s.s3, pctx, pp, target, dlOpts)
+					if derr != nil {
+						var partial *utils.BatchError
+						if !errors.As(derr, &partial) {
+							berr.Add(p, derr)
+							break
+						}
+						for _, f := range partial.Failures() {
+							berr.Add(p+f.Item, f.Err)
+						}
+					}
 				}
 				// reporting
-				files, lerr := s.s3.ListFilesAll(ctx, pp.Host, key)
+				remote, lerr := s.s3.ListFilesAll(pctx, pp.Host, key)
 				if lerr != nil {
-					// warning/skip reporting, ma NON fallire
-					continue
+					berr.Add(p, fmt.Errorf("downloaded but listing for report failed: %w", lerr))
+					break
 				}
 				base := dirBaseForLocalTarget(target)
-				for _, f := range files {
-					local := filepath.Join(base, strings.TrimPrefix(f.Path, key))
-					if st, err := os.Stat(local); err == nil && !st.IsDir() {
-						out = append(out, DownloadInfo{
-							Filename: filepath.Base(local),
-							Size:     st.Size(),
-							Path:     local,
-						})
+				failed := map[string]bool{}
+				for _, f := range berr.Failures() {
+					failed[f.Item] = true
+				}
+				for _, f := range remote {
+					rel := strings.TrimPrefix(f.Path, key)
+					if failed[p+rel] {
+						continue
 					}
+					local := filepath.Join(base, filepath.FromSlash(rel))
+					sums, obj := expectedOrETag(files[rel], pp.Host, f)
+					report(pctx, p+rel, local, f.Size, sums, obj)
 				}
 			} else {
-				// File singolo: su errore, NON fallire → skip
-				if ferr := utils.DownloadS3FileOrDir(s.s3, ctx, pp, target, req.Verbose); ferr != nil {
-					continue
+				if !req.VerifyOnly {
+					if ferr := utils.DownloadS3FileOrDirWithOptions(s.s3, pctx, pp, target, dlOpts); ferr != nil {
+						berr.Add(p, ferr)
+						break
+					}
 				}
-				if st, err := os.Stat(target); err == nil && !st.IsDir() {
-					out = append(out, DownloadInfo{
-						Filename: filepath.Base(target),
-						Size:     st.Size(),
-						Path:     target,
-					})
+				size, sums := int64(-1), files[path.Base(key)]
+				var obj *etagObject
+				if verify {
+					remote, lerr := s.s3.ListFilesAll(pctx, pp.Host, key)
+					if lerr != nil {
+						berr.Add(p, fmt.Errorf("listing for verification failed: %w", lerr))
+						break
+					}
+					for _, f := range remote {
+						if f.Path == key {
+							size = f.Size
+							sums, obj = expectedOrETag(sums, pp.Host, f)
+						}
+					}
 				}
+				report(pctx, p, target, size, sums, obj)
 			}
 
-		case "http", "https":
-			// Su errore HTTP, skip (come original)
-			if herr := utils.DownloadHTTPFile(pp.Path, target); herr != nil {
-				continue
-			}
-			if st, err := os.Stat(target); err == nil && !st.IsDir() {
-				out = append(out, DownloadInfo{
-					Filename: filepath.Base(target),
-					Size:     st.Size(),
-					Path:     target,
-				})
+		case pp.Scheme == "http" || pp.Scheme == "https":
+			if !req.VerifyOnly {
+				if herr := utils.DownloadHTTPFileWithOptions(pctx, pp.Path, target, dlOpts); herr != nil {
+					berr.Add(p, herr)
+					break
+				}
 			}
+			report(pctx, p, target, -1, files[pp.Filename], nil)
 
 		default:
-			// unsupported → skip (come original)
-			continue
+			berr.Add(p, fmt.Errorf("unsupported scheme %q", pp.Scheme))
 		}
+		var perr error
+		if f := berr.Failures(); len(f) > failures {
+			perr = f[len(f)-1].Err
+		}
+		config.EndSpan(pspan, perr)
 	}
-	return out, nil
+	return out, berr.ErrorOrNil()
 }
 
 // --- helpers ---
 
+// fetchPaths gets the entity (or the latest version by name) to download and
+// its spec.path.
+func (s *TransferService) fetchPaths(ctx context.Context, endpoint string, req DownloadRequest) ([]byte, []string, error) {
+	if !config.IsGlobalResource(req.Resource) && req.Project == "" {
+		return nil, nil, errors.New("project is mandatory for non-project resources")
+	}
+	if req.ID == "" && req.Name == "" {
+		return nil, nil, errors.New("you must specify id or name")
+	}
+
+	params := map[string]string{}
+	id := req.ID
+	if id == "" {
+		params["name"] = req.Name
+		params["versions"] = "latest"
+	}
+
+	url := s.http.BuildURL(req.Project, endpoint, id, params)
+	body, _, err := s.http.Do(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	paths, err := extractPaths(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, paths, nil
+}
+
 // chooseLocalTarget replica l’originale:
 // - se dst è vuoto → usa filename nella cwd
 // - se dst esiste ed è directory → dst/filename
@@ -144,13 +236,10 @@ func chooseLocalTarget(dst, filename string) (target string, createdDir bool, er
 	return "", false, statErr
 }
 
+// dirBaseForLocalTarget must match the base used by utils.DownloadS3FileOrDir,
+// otherwise the reported paths don't point at the downloaded files.
 func dirBaseForLocalTarget(localPath string) string {
-	clean := filepath.Clean(localPath)
-	parent := filepath.Dir(clean)
-	if parent == "." || parent == string(os.PathSeparator) {
-		return ""
-	}
-	return parent
+	return utils.LocalParentDir(localPath)
 }
 
 func extractPaths(body []byte) ([]string, error) {
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transfer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChooseLocalTarget(t *testing.T) {
+	root := t.TempDir()
+
+	existingDir := filepath.Join(root, "existing")
+	if err := os.Mkdir(existingDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	existingFile := filepath.Join(root, "file.bin")
+	if err := os.WriteFile(existingFile, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(root, "missing", "nested")
+
+	cases := []struct {
+		name    string
+		dst     string
+		want    string
+		created bool
+	}{
+		{name: "empty destination", dst: "", want: "data.csv"},
+		{name: "existing directory", dst: existingDir, want: filepath.Join(existingDir, "data.csv")},
+		{name: "existing directory with trailing separator", dst: existingDir + string(os.PathSeparator), want: filepath.Join(existingDir, "data.csv")},
+		{name: "existing file", dst: existingFile, want: existingFile},
+		{name: "missing directory", dst: missing, want: filepath.Join(missing, "data.csv"), created: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, created, err := chooseLocalTarget(c.dst, "data.csv")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != c.want || created != c.created {
+				t.Fatalf("got (%q, %v), want (%q, %v)", got, created, c.want, c.created)
+			}
+		})
+	}
+
+	if st, err := os.Stat(missing); err != nil || !st.IsDir() {
+		t.Fatalf("expected %s to be created", missing)
+	}
+}
+
+func TestDirBaseForLocalTarget(t *testing.T) {
+	abs := filepath.Join(t.TempDir(), "out", "data")
+	if got, want := dirBaseForLocalTarget(abs), filepath.Dir(abs); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got := dirBaseForLocalTarget("data"); got != "" {
+		t.Fatalf("got %q, want empty", got)
+	}
+}
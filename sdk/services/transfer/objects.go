@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transfer
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Stat returns the size, type and metadata of the object at an s3:// path;
+// a missing object is an error matching config.ErrObjectNotFound.
+func (s *TransferService) Stat(ctx context.Context, p string) (*config.ObjectInfo, error) {
+	pp, err := s3Location(p)
+	if err != nil {
+		return nil, err
+	}
+	return s.s3.HeadObject(ctx, pp.Host, pp.Path)
+}
+
+// Exists tells whether the object at an s3:// path exists.
+func (s *TransferService) Exists(ctx context.Context, p string) (bool, error) {
+	pp, err := s3Location(p)
+	if err != nil {
+		return false, err
+	}
+	return s.s3.Exists(ctx, pp.Host, pp.Path)
+}
+
+// Remove deletes the object at an s3:// path, or every object under it when
+// it ends with "/", and returns how many were deleted.
+func (s *TransferService) Remove(ctx context.Context, p string) (_ int, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "transfer.remove", "", "", attribute.String("transfer.path", p))
+	defer func() { config.EndSpan(span, err) }()
+
+	pp, err := s3Location(p)
+	if err != nil {
+		return 0, err
+	}
+	if strings.HasSuffix(pp.Path, "/") {
+		return s.s3.DeletePrefix(ctx, pp.Host, pp.Path)
+	}
+	if err := s.s3.DeleteObject(ctx, pp.Host, pp.Path); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// Move copies src to dst as Copy does, e.g. to promote an artifact to
+// another bucket, then removes src once every object is copied.
+func (s *TransferService) Move(ctx context.Context, src, dst string) error {
+	if err := s.Copy(ctx, src, dst); err != nil {
+		return err
+	}
+	_, err := s.Remove(ctx, src)
+	return err
+}
+
+type DeleteFilesRequest struct {
+	Project  string
+	Resource string
+	ID       string
+	// Options add headers and query params to the Core calls
+	Options []config.RequestOption
+}
+
+// DeleteFiles removes from S3 the files stored at spec.path of the entity
+// req.ID (every object under it when it is a directory), which deleting
+// the entity on Core leaves behind; http paths are skipped. The endpoint
+// is resolved from req.Resource. It returns how many objects were deleted.
+func (s *TransferService) DeleteFiles(ctx context.Context, req DeleteFilesRequest) (_ int, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "transfer.delete_files", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	if req.ID == "" {
+		return 0, errors.New("id not specified")
+	}
+	endpoint, err := config.ResolveResource(req.Resource)
+	if err != nil {
+		return 0, err
+	}
+	_, paths, err := s.fetchPaths(ctx, endpoint, DownloadRequest{Project: req.Project, Resource: req.Resource, ID: req.ID})
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	berr := utils.NewBatchError("delete")
+	for _, p := range paths {
+		if pp, err := utils.ParsePath(p); err != nil || pp.Scheme != "s3" {
+			continue
+		}
+		n, err := s.Remove(ctx, p)
+		deleted += n
+		berr.Add(p, err)
+	}
+	return deleted, berr.ErrorOrNil()
+}
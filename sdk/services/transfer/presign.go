@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+)
+
+type PresignRequest struct {
+	Project  string
+	Resource string
+	ID       string
+	Name     string // latest version by name when ID is empty
+	// TTL is the validity of the URLs, config.DefaultPresignTTL when 0
+	TTL time.Duration
+	// Options add headers and query params to the Core calls
+	Options []config.RequestOption
+}
+
+// PresignedURL is a shareable download URL of a file of an entity.
+type PresignedURL struct {
+	// Path is the s3:// (or http) path of the file
+	Path string `json:"path" yaml:"path"`
+	URL  string `json:"url" yaml:"url"`
+	// Expires is zero for http paths, returned as they are
+	Expires time.Time `json:"expires,omitempty" yaml:"expires,omitempty"`
+}
+
+// Presign returns a presigned GET URL for every file of the entity (each
+// object under spec.path when it is a directory), to hand downloads to
+// browsers or other systems without distributing credentials. The endpoint
+// is resolved from req.Resource.
+func (s *TransferService) Presign(ctx context.Context, req PresignRequest) (_ []PresignedURL, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "transfer.presign", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	endpoint, err := config.ResolveResource(req.Resource)
+	if err != nil {
+		return nil, err
+	}
+	_, paths, err := s.fetchPaths(ctx, endpoint, DownloadRequest{
+		Project: req.Project, Resource: req.Resource, ID: req.ID, Name: req.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ttl := req.TTL
+	if ttl == 0 {
+		ttl = config.DefaultPresignTTL
+	}
+
+	var out []PresignedURL
+	presign := func(bucket, key string) error {
+		u, err := s.s3.PresignGet(ctx, bucket, key, ttl)
+		if err != nil {
+			return err
+		}
+		out = append(out, PresignedURL{Path: "s3://" + bucket + "/" + key, URL: u, Expires: time.Now().Add(ttl)})
+		return nil
+	}
+	for _, p := range paths {
+		pp, err := utils.ParsePath(p)
+		if err != nil {
+			return nil, err
+		}
+		switch pp.Scheme {
+		case "s3":
+			key := strings.TrimPrefix(pp.Path, "/")
+			if key != "" && !strings.HasSuffix(key, "/") {
+				if err := presign(pp.Host, key); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			files, err := s.s3.ListFilesAll(ctx, pp.Host, key)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range files {
+				if strings.HasSuffix(f.Path, "/") {
+					continue
+				}
+				if err := presign(pp.Host, f.Path); err != nil {
+					return nil, err
+				}
+			}
+		case "http", "https":
+			out = append(out, PresignedURL{Path: p, URL: p})
+		default:
+			return nil, fmt.Errorf("unsupported scheme %q", pp.Scheme)
+		}
+	}
+	return out, nil
+}
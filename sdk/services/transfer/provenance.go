@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transfer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+// provenance raccoglie (best effort) i metadati di origine di un artefatto
+// creato in upload; ogni informazione non disponibile viene omessa (con
+// input vuoto, upload da reader, anche local_path).
+func (s *TransferService) provenance(input string) map[string]interface{} {
+	prov := map[string]interface{}{
+		"sdk_version": config.SDKVersion(),
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		prov["hostname"] = host
+	}
+	if abs, err := filepath.Abs(input); input != "" && err == nil {
+		prov["local_path"] = abs
+		if commit := gitCommit(abs); commit != "" {
+			prov["git_commit"] = commit
+		}
+	}
+	return prov
+}
+
+// userLookupTimeout bounds the lookup of createdBy, which doesn't follow the
+// cancellation of the upload.
+const userLookupTimeout = 5 * time.Second
+
+// createdBy returns the current user: the basic auth username or, with
+// token credentials, the preferred_username (or email/sub) of the OIDC
+// userinfo endpoint. Without credentials, or when the lookup fails, it is
+// empty; only a successful lookup is kept for the later uploads.
+func (s *TransferService) createdBy(ctx context.Context) string {
+	s.userMu.Lock()
+	defer s.userMu.Unlock()
+	if s.userKnown {
+		return s.user
+	}
+	// il risultato resta in cache: non deve dipendere dalla cancellazione
+	// del primo upload, ma nemmeno bloccarlo su un endpoint che non risponde
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), userLookupTimeout)
+	defer cancel()
+	user, err := s.lookupUser(ctx)
+	if err != nil {
+		logger := s.logger
+		if logger == nil {
+			logger = config.DefaultLogger()
+		}
+		logger.Debug("created_by lookup failed", "error", err)
+		return ""
+	}
+	s.user, s.userKnown = user, true
+	return user
+}
+
+func (s *TransferService) lookupUser(ctx context.Context) (string, error) {
+	auth, err := s.core.AuthProvider()
+	if err != nil || auth == nil {
+		return "", err
+	}
+	if basic, ok := auth.(config.BasicAuth); ok {
+		return basic.Username, nil
+	}
+
+	// una sola chiamata per endpoint, senza retry: è un'informazione accessoria
+	ctx = config.ContextWithRetry(ctx, config.RetryPolicy{MaxAttempts: 1})
+	body, _, err := s.http.Do(ctx, "GET", strings.TrimRight(s.core.BaseURL, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return "", err
+	}
+	var oidc map[string]interface{}
+	if err := json.Unmarshal(body, &oidc); err != nil {
+		return "", fmt.Errorf("invalid openid configuration: %w", err)
+	}
+	endpoint, _ := oidc["userinfo_endpoint"].(string)
+	if endpoint == "" {
+		return "", nil
+	}
+
+	body, _, err = s.http.Do(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	var info map[string]interface{}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return "", fmt.Errorf("invalid userinfo: %w", err)
+	}
+	for _, k := range []string{"preferred_username", "username", "email", "sub"} {
+		if v, _ := info[k].(string); v != "" {
+			return v, nil
+		}
+	}
+	return "", nil
+}
+
+// gitCommit returns the HEAD commit of the git repository containing path,
+// reading .git directly (no git binary needed). "" if not in a repository.
+func gitCommit(path string) string {
+	dir := path
+	if st, err := os.Stat(dir); err == nil && !st.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	for {
+		gitDir := filepath.Join(dir, ".git")
+		if st, err := os.Stat(gitDir); err == nil {
+			if !st.IsDir() {
+				// worktree/submodule: ".git" file with "gitdir: <path>"
+				data, err := os.ReadFile(gitDir)
+				if err != nil {
+					return ""
+				}
+				ref := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(data)), "gitdir:"))
+				if !filepath.IsAbs(ref) {
+					ref = filepath.Join(dir, ref)
+				}
+				gitDir = ref
+			}
+			return readGitHead(gitDir)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func readGitHead(gitDir string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	head := strings.TrimSpace(string(data))
+	ref, ok := strings.CutPrefix(head, "ref: ")
+	if !ok {
+		return head // detached HEAD
+	}
+	// worktrees keep refs in the common dir
+	dirs := []string{gitDir}
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		c := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(c) {
+			c = filepath.Join(gitDir, c)
+		}
+		dirs = append(dirs, c)
+	}
+	for _, d := range dirs {
+		if b, err := os.ReadFile(filepath.Join(d, filepath.FromSlash(ref))); err == nil {
+			return strings.TrimSpace(string(b))
+		}
+		if f, err := os.Open(filepath.Join(d, "packed-refs")); err == nil {
+			sc := bufio.NewScanner(f)
+			for sc.Scan() {
+				if hash, name, ok := strings.Cut(sc.Text(), " "); ok && name == ref {
+					_ = f.Close()
+					return hash
+				}
+			}
+			_ = f.Close()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transfer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+)
+
+// DownloadStream writes the content of a single-file entity to w (e.g.
+// os.Stdout, or the stdin of a process) without touching the disk, and
+// returns the bytes written. The endpoint is resolved from req.Resource;
+// Destination and the options of directory and parallel downloads are
+// ignored. Unless req.NoVerify, the SHA-256 recorded in status.files is
+// checked once the content is written: a mismatch is a *utils.ChecksumError,
+// too late to take back what w received, but in time to fail the pipeline.
+func (s *TransferService) DownloadStream(ctx context.Context, req DownloadRequest, w io.Writer) (_ int64, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "transfer.download_stream", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+
+	endpoint, err := config.ResolveResource(req.Resource)
+	if err != nil {
+		return 0, err
+	}
+	body, paths, err := s.fetchPaths(ctx, endpoint, req)
+	if err != nil {
+		return 0, err
+	}
+	if len(paths) != 1 {
+		return 0, fmt.Errorf("expected a single path to stream, got %d", len(paths))
+	}
+	p := paths[0]
+	pp, err := utils.ParsePath(p)
+	if err != nil {
+		return 0, err
+	}
+
+	var r io.ReadCloser
+	switch pp.Scheme {
+	case "s3":
+		key := strings.TrimPrefix(pp.Path, "/")
+		if key == "" || strings.HasSuffix(key, "/") {
+			return 0, fmt.Errorf("%s is a directory: only single files can be streamed", p)
+		}
+		if r, _, err = s.s3.GetObjectStream(ctx, pp.Host, key); err != nil {
+			return 0, err
+		}
+	case "http", "https":
+		hreq, err := http.NewRequestWithContext(ctx, http.MethodGet, p, nil)
+		if err != nil {
+			return 0, err
+		}
+		resp, err := http.DefaultClient.Do(hreq)
+		if err != nil {
+			return 0, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return 0, fmt.Errorf("download failed (status %d)", resp.StatusCode)
+		}
+		r = resp.Body
+	default:
+		return 0, fmt.Errorf("unsupported scheme %q", pp.Scheme)
+	}
+	defer r.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, h), r)
+	if err != nil {
+		return n, fmt.Errorf("stream failed: %w", err)
+	}
+	if req.NoVerify {
+		return n, nil
+	}
+	expected := expectedChecksums(body)[p][path.Base(pp.Path)]
+	if actual := hex.EncodeToString(h.Sum(nil)); expected.SHA256 != "" && expected.SHA256 != actual {
+		return n, &utils.ChecksumError{Path: p, Algorithm: "sha256", Expected: expected.SHA256, Actual: actual}
+	}
+	return n, nil
+}
@@ -0,0 +1,298 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Sync directions.
+const (
+	// SyncUpload makes the S3 prefix match the local directory
+	SyncUpload = "upload"
+	// SyncDownload makes the local directory match the S3 prefix
+	SyncDownload = "download"
+)
+
+// Sync actions.
+const (
+	ActionUpload   = "upload"
+	ActionDownload = "download"
+	ActionDelete   = "delete"
+)
+
+type SyncRequest struct {
+	// Direction is SyncUpload or SyncDownload
+	Direction string
+	// Local is the local directory
+	Local string
+	// Remote is the S3 prefix, s3://bucket/prefix/
+	Remote string
+	// Delete removes the files of the destination missing from the source
+	Delete bool
+	// Checksum compares the content (MD5 against the ETag) of files of the
+	// same size instead of their modification times; objects uploaded in
+	// parts have no MD5 ETag and fall back to the times
+	Checksum bool
+	// DryRun only reports what would be done
+	DryRun bool
+	// Parallelism is the number of files transferred at once (default
+	// utils.DefaultParallelism)
+	Parallelism int
+	// ContentTypes overrides the content type of uploads per extension
+	ContentTypes map[string]string
+	// Objects sets the encryption, ACL, storage class and tags of uploads
+	Objects config.ObjectOptions
+}
+
+// SyncAction is a transfer or deletion decided by Sync.
+type SyncAction struct {
+	// Path is relative to Local and Remote, with forward slashes
+	Path string `json:"path" yaml:"path"`
+	// Op is ActionUpload, ActionDownload or ActionDelete
+	Op string `json:"op" yaml:"op"`
+	// Reason is why: new, size, modified, checksum or stale (deletions)
+	Reason string `json:"reason" yaml:"reason"`
+	Size   int64  `json:"size" yaml:"size"`
+}
+
+// SyncReport lists what Sync did, or would do with DryRun.
+type SyncReport struct {
+	Actions []SyncAction `json:"actions" yaml:"actions"`
+	// Unchanged counts the files left alone
+	Unchanged int `json:"unchanged" yaml:"unchanged"`
+	// Bytes is the size of the files transferred
+	Bytes  int64 `json:"bytes" yaml:"bytes"`
+	DryRun bool  `json:"dry_run" yaml:"dry_run"`
+}
+
+// Sync transfers the files of req.Local or req.Remote, according to
+// req.Direction, that are missing or changed on the other side: by size,
+// then modification time or checksum. Files that fail don't stop the others;
+// they are returned as a *utils.BatchError along with the report.
+func (s *TransferService) Sync(ctx context.Context, req SyncRequest) (_ *SyncReport, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "transfer.sync", "", "",
+		attribute.String("transfer.direction", req.Direction),
+		attribute.String("transfer.local", req.Local), attribute.String("transfer.remote", req.Remote))
+	defer func() { config.EndSpan(span, err) }()
+
+	if req.Direction != SyncUpload && req.Direction != SyncDownload {
+		return nil, fmt.Errorf("invalid sync direction %q", req.Direction)
+	}
+	if err := req.Objects.Validate(); err != nil {
+		return nil, err
+	}
+	ctx = config.ContextWithObjectOptions(ctx, req.Objects)
+	req.ContentTypes = config.NormalizeContentTypes(req.ContentTypes)
+	if req.Local == "" {
+		return nil, errors.New("local directory not specified")
+	}
+	pp, err := s3Location(req.Remote)
+	if err != nil {
+		return nil, err
+	}
+	bucket, prefix := pp.Host, pp.Path
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	remote := map[string]config.S3File{}
+	listed, err := s.s3.ListFilesAll(ctx, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range listed {
+		if rel := strings.TrimPrefix(f.Path, prefix); rel != "" && !strings.HasSuffix(rel, "/") {
+			remote[rel] = f
+		}
+	}
+	local, err := localFiles(req.Local, req.Direction == SyncDownload)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SyncReport{DryRun: req.DryRun}
+	src, dst := keys(local), keys(remote)
+	op := ActionUpload
+	if req.Direction == SyncDownload {
+		src, dst, op = dst, src, ActionDownload
+	}
+	for _, rel := range src {
+		l, inLocal := local[rel]
+		r, inRemote := remote[rel]
+		size := r.Size
+		if op == ActionUpload {
+			size = l.Size()
+		}
+		reason := "new"
+		if inLocal && inRemote {
+			if reason, err = s.compareFiles(ctx, bucket, filepath.Join(req.Local, filepath.FromSlash(rel)), l, r, op, req.Checksum); err != nil {
+				return nil, err
+			}
+		}
+		if reason == "" {
+			report.Unchanged++
+			continue
+		}
+		report.Actions = append(report.Actions, SyncAction{Path: rel, Op: op, Reason: reason, Size: size})
+		report.Bytes += size
+	}
+	if req.Delete {
+		for _, rel := range dst {
+			_, inLocal := local[rel]
+			_, inRemote := remote[rel]
+			if inLocal && inRemote {
+				continue
+			}
+			size := remote[rel].Size
+			if l, ok := local[rel]; ok {
+				size = l.Size()
+			}
+			report.Actions = append(report.Actions, SyncAction{Path: rel, Op: ActionDelete, Reason: "stale", Size: size})
+		}
+	}
+	if req.DryRun {
+		return report, nil
+	}
+
+	berr := utils.NewBatchError("sync")
+	parallelism := req.Parallelism
+	if parallelism <= 0 {
+		parallelism = utils.DefaultParallelism
+	}
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for _, a := range report.Actions {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return report, ctx.Err()
+		}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			berr.Add(a.Path, s.syncFile(ctx, req, bucket, prefix, a))
+		}()
+	}
+	wg.Wait()
+	return report, berr.ErrorOrNil()
+}
+
+// syncFile carries out one action of Sync.
+func (s *TransferService) syncFile(ctx context.Context, req SyncRequest, bucket, prefix string, a SyncAction) error {
+	// keys such as "a/../../x" must not write or delete outside req.Local
+	if !filepath.IsLocal(filepath.FromSlash(a.Path)) {
+		return fmt.Errorf("object key %q escapes the local directory", prefix+a.Path)
+	}
+	localPath := filepath.Join(req.Local, filepath.FromSlash(a.Path))
+	key := prefix + a.Path
+	switch {
+	case a.Op == ActionUpload:
+		f, err := os.Open(localPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		contentType, err := config.DetectContentType(f, req.ContentTypes)
+		if err != nil {
+			return err
+		}
+		_, err = s.s3.UploadFileWithContentType(ctx, bucket, key, f, contentType, nil)
+		return err
+	case a.Op == ActionDownload:
+		if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+			return fmt.Errorf("failed to create local directory: %w", err)
+		}
+		return s.s3.DownloadFileWithProgress(ctx, bucket, key, localPath, nil)
+	case req.Direction == SyncUpload:
+		return s.s3.DeleteObject(ctx, bucket, key)
+	default:
+		return os.Remove(localPath)
+	}
+}
+
+// compareFiles returns why the source of op differs from its copy, or ""
+// when it doesn't. Objects whose ETag isn't their MD5 are compared by
+// modification time.
+func (s *TransferService) compareFiles(ctx context.Context, bucket, localPath string, l fs.FileInfo, r config.S3File, op string, checksum bool) (string, error) {
+	if l.Size() != r.Size {
+		return "size", nil
+	}
+	if checksum {
+		if etag := utils.ChecksumsFromETag(r.ETag); !etag.IsZero() {
+			sums, err := utils.FileChecksums(localPath)
+			if err != nil {
+				return "", err
+			}
+			switch {
+			case sums.MD5 == etag.MD5:
+				return "", nil
+			case !s.opaqueETag(ctx, bucket, r.Path, r.ETag):
+				return "checksum", nil
+			}
+		}
+	}
+	remoteTime, err := time.Parse(time.RFC3339, r.LastModified)
+	if err != nil {
+		return "modified", nil
+	}
+	// S3 keeps seconds only
+	localTime := l.ModTime().Truncate(time.Second)
+	if op == ActionUpload && localTime.After(remoteTime) || op == ActionDownload && remoteTime.After(localTime) {
+		return "modified", nil
+	}
+	return "", nil
+}
+
+// localFiles returns the files under dir by slash-separated relative path;
+// a missing dir is empty when allowMissing.
+func localFiles(dir string, allowMissing bool) (map[string]fs.FileInfo, error) {
+	out := map[string]fs.FileInfo{}
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) && allowMissing {
+		return out, nil
+	}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		out[filepath.ToSlash(rel)] = info
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enumerate local directory: %w", err)
+	}
+	return out, nil
+}
+
+func keys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
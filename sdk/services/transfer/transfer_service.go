@@ -6,8 +6,11 @@ package transfer
 
 import (
 	"context"
+	"log/slog"
+	"sync"
 
 	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"go.opentelemetry.io/otel/trace"
 
 	"fmt"
 )
@@ -15,21 +18,66 @@ import (
 type TransferService struct {
 	http config.CoreHTTP
 	s3   *config.S3Client
+	core config.CoreConfig
+	tune config.TransferConfig
+
+	logger     *slog.Logger
+	tracer     trace.Tracer
+	validators []UploadValidator
+
+	// user is the created_by of new artifacts, see createdBy
+	userMu    sync.Mutex
+	user      string
+	userKnown bool
 }
 
-func NewTransferService(ctx context.Context, conf config.Config) (*TransferService, error) {
-	httpc := config.NewHTTPCore(nil, conf.Core)
-
-	s3c, err := config.NewS3Client(ctx, config.S3Config{
-		AccessKey:   conf.S3.AccessKey,
-		SecretKey:   conf.S3.SecretKey,
-		AccessToken: conf.S3.AccessToken,
-		Region:      conf.S3.Region,
-		EndpointURL: conf.S3.EndpointURL,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("S3 init failed: %w", err)
+// NewTransferService builds the service; opts customize HTTP client, logger,
+// retries and the S3 client (see config.ServiceOption). With
+// conf.S3.CoreCredentials, S3 credentials are obtained from Core.
+func NewTransferService(ctx context.Context, conf config.Config, opts ...config.ServiceOption) (*TransferService, error) {
+	o := config.NewServiceOptions(opts...).ForConfig(conf)
+	httpc := config.NewHTTPCoreWithOptions(conf.Core, o)
+
+	// Config.Transfer wins over the (lower level) S3Config.Transfer
+	tune := conf.Transfer
+	if tune == (config.TransferConfig{}) {
+		tune = conf.S3.Transfer
+	}
+
+	s3c := o.S3Client
+	if s3c == nil {
+		creds := conf.S3.Credentials
+		if creds == nil && conf.S3.CoreCredentials {
+			// temporary credentials, renewed from Core before they expire
+			var initial *config.S3Credentials
+			if conf.S3.AccessKey != "" {
+				initial = &config.S3Credentials{
+					AccessKey:    conf.S3.AccessKey,
+					SecretKey:    conf.S3.SecretKey,
+					SessionToken: conf.S3.AccessToken,
+					Expiration:   conf.S3.Expiration,
+				}
+			}
+			p := config.NewCoreCredentialsProvider(httpc, conf.Core, initial)
+			p.OnRefresh = conf.S3.OnCredentialsRefresh
+			creds = p
+		}
+		var err error
+		s3c, err = config.NewS3Client(ctx, config.S3Config{
+			AccessKey:   conf.S3.AccessKey,
+			SecretKey:   conf.S3.SecretKey,
+			AccessToken: conf.S3.AccessToken,
+			Region:      conf.S3.Region,
+			EndpointURL: conf.S3.EndpointURL,
+			Credentials: creds,
+			HTTPClient:  conf.S3.HTTPClient,
+			Transfer:    tune,
+			Objects:     conf.S3.Objects,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("S3 init failed: %w", err)
+		}
 	}
 
-	return &TransferService{http: httpc, s3: s3c}, nil
+	return &TransferService{http: httpc, s3: s3c, core: conf.Core, tune: tune.WithDefaults(), logger: conf.Logger, tracer: config.Tracer(o.TracerProvider)}, nil
 }
@@ -0,0 +1,967 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transfer_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/dhcoretest"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/s3test"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/services/transfer"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+)
+
+func newOfflineService(t *testing.T) (*transfer.TransferService, *dhcoretest.Server, *s3test.Server) {
+	t.Helper()
+	core := dhcoretest.NewServer()
+	t.Cleanup(core.Close)
+	store := s3test.NewServer()
+	t.Cleanup(store.Close)
+
+	cfg := core.Config()
+	cfg.S3 = store.Config()
+	svc, err := transfer.NewTransferService(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to init sdk: %v", err)
+	}
+	return svc, core, store
+}
+
+func TestUploadDownloadFileOffline(t *testing.T) {
+	svc, core, store := newOfflineService(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	input := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(input, []byte("a,b\n1,2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project:  "demo",
+		Resource: "artifact",
+		Name:     "dataset",
+		Input:    input,
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	artifact, ok := core.Get("demo", "artifacts", res.ArtifactID)
+	if !ok {
+		t.Fatal("artifact not created")
+	}
+	if state := artifact["status"].(map[string]interface{})["state"]; state != "READY" {
+		t.Fatalf("expected READY, got %v", state)
+	}
+	key := "demo/artifact/" + res.ArtifactID + "/data.csv"
+	obj, ok := store.GetObject("datalake", key)
+	if !ok || string(obj.Data) != "a,b\n1,2\n" || obj.ContentType != "text/csv" {
+		t.Fatalf("unexpected object at %s: %+v", key, obj)
+	}
+
+	out := filepath.Join(dir, "out")
+	infos, err := svc.Download(ctx, "artifacts", transfer.DownloadRequest{
+		Project:     "demo",
+		Resource:    "artifacts",
+		Name:        "dataset",
+		Destination: out,
+	})
+	if err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Size != obj.Size() {
+		t.Fatalf("unexpected download report %+v", infos)
+	}
+	got, err := os.ReadFile(filepath.Join(out, "data.csv"))
+	if err != nil || string(got) != "a,b\n1,2\n" {
+		t.Fatalf("downloaded content mismatch: %q (%v)", got, err)
+	}
+}
+
+func TestUploadDownloadDirectoryOffline(t *testing.T) {
+	svc, _, store := newOfflineService(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	input := filepath.Join(dir, "model")
+	for _, rel := range []string{"weights.bin", "conf/params.yaml", "conf/labels.txt"} {
+		p := filepath.Join(input, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(rel), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := svc.Upload(ctx, "models", transfer.UploadRequest{
+		Project:  "demo",
+		Resource: "model",
+		Name:     "clf",
+		Input:    input,
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if len(res.Files) != 3 || len(store.Keys("datalake")) != 3 {
+		t.Fatalf("expected 3 files, got %v / %v", res.Files, store.Keys("datalake"))
+	}
+
+	out := filepath.Join(dir, "out")
+	infos, err := svc.Download(ctx, "models", transfer.DownloadRequest{
+		Project:     "demo",
+		Resource:    "models",
+		ID:          res.ArtifactID,
+		Destination: out,
+	})
+	if err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if len(infos) != 3 {
+		t.Fatalf("expected 3 downloaded files, got %+v", infos)
+	}
+	got, err := os.ReadFile(filepath.Join(out, "conf", "params.yaml"))
+	if err != nil || string(got) != "conf/params.yaml" {
+		t.Fatalf("downloaded content mismatch: %q (%v)", got, err)
+	}
+}
+
+func TestDirectoryTransferParallel(t *testing.T) {
+	svc, _, store := newOfflineService(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	input := filepath.Join(dir, "shards")
+	want := map[string]string{}
+	for i := 0; i < 40; i++ {
+		rel := fmt.Sprintf("part-%02d/data-%02d.csv", i%4, i)
+		want[rel] = fmt.Sprintf("id\n%d\n", i)
+		p := filepath.Join(input, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(want[rel]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project:     "demo",
+		Resource:    "artifact",
+		Name:        "shards",
+		Input:       input,
+		Parallelism: 8,
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, f := range res.Files {
+		seen[f["path"].(string)] = true
+	}
+	if len(seen) != len(want) || len(store.Keys("datalake")) != len(want) {
+		t.Fatalf("expected %d distinct files, got %d (%d objects)", len(want), len(seen), len(store.Keys("datalake")))
+	}
+
+	out := filepath.Join(dir, "out")
+	infos, err := svc.Download(ctx, "artifacts", transfer.DownloadRequest{
+		Project:     "demo",
+		Resource:    "artifacts",
+		ID:          res.ArtifactID,
+		Destination: out,
+		Parallelism: 8,
+	})
+	if err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if len(infos) != len(want) {
+		t.Fatalf("expected %d downloaded files, got %d", len(want), len(infos))
+	}
+	for rel, content := range want {
+		got, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(rel)))
+		if err != nil || string(got) != content {
+			t.Fatalf("%s: content mismatch %q (%v)", rel, got, err)
+		}
+	}
+}
+
+func TestDownloadVerifiesChecksums(t *testing.T) {
+	svc, _, store := newOfflineService(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	input := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(input, []byte("a,b\n1,2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project:  "demo",
+		Resource: "artifact",
+		Name:     "dataset",
+		Input:    input,
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if h, _ := res.Files[0]["hash"].(string); !strings.HasPrefix(h, "sha256:") || res.Files[0]["md5"] == "" {
+		t.Fatalf("expected checksums in the file infos, got %v", res.Files[0])
+	}
+
+	out := filepath.Join(dir, "out")
+	req := transfer.DownloadRequest{Project: "demo", Resource: "artifacts", ID: res.ArtifactID, Destination: out}
+	infos, err := svc.Download(ctx, "artifacts", req)
+	if err != nil || len(infos) != 1 || !infos[0].Verified || infos[0].Hash != res.Files[0]["hash"] {
+		t.Fatalf("expected a verified download, got %+v (%v)", infos, err)
+	}
+
+	// VerifyOnly checks the local copy without downloading it
+	local := filepath.Join(out, "data.csv")
+	if err := os.WriteFile(local, []byte("a,b\n1,3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	before := len(store.Requests())
+	req.VerifyOnly = true
+	_, err = svc.Download(ctx, "artifacts", req)
+	var mismatch *utils.ChecksumError
+	if !errors.As(err, &mismatch) || mismatch.Algorithm != "sha256" {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+	for _, r := range store.Requests()[before:] {
+		if strings.HasPrefix(r, "GET /datalake/demo/") {
+			t.Fatalf("VerifyOnly should not download, got %s", r)
+		}
+	}
+
+	// a corrupted object fails the download
+	key := "demo/artifact/" + res.ArtifactID + "/data.csv"
+	store.PutObject("datalake", key, []byte("a,b\n9,9\n"))
+	req.VerifyOnly = false
+	if _, err := svc.Download(ctx, "artifacts", req); !errors.As(err, &mismatch) {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+	req.NoVerify = true
+	if _, err := svc.Download(ctx, "artifacts", req); err != nil {
+		t.Fatalf("NoVerify download failed: %v", err)
+	}
+}
+
+func TestTransferJSONProgressEvents(t *testing.T) {
+	svc, _, _ := newOfflineService(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	input := filepath.Join(dir, "report.json")
+	if err := os.WriteFile(input, []byte(`{"ok":true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var events bytes.Buffer
+	res, err := svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project:        "demo",
+		Resource:       "artifact",
+		Name:           "report",
+		Input:          input,
+		ProgressFormat: utils.ProgressJSON,
+		ProgressOutput: &events,
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if _, err := svc.Download(ctx, "artifacts", transfer.DownloadRequest{
+		Project:        "demo",
+		Resource:       "artifacts",
+		ID:             res.ArtifactID,
+		Destination:    filepath.Join(dir, "out"),
+		ProgressFormat: utils.ProgressJSON,
+		ProgressOutput: &events,
+	}); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+
+	var phases []string
+	sc := bufio.NewScanner(&events)
+	for sc.Scan() {
+		var ev utils.ProgressEvent
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			t.Fatalf("invalid event line %q: %v", sc.Text(), err)
+		}
+		if ev.Phase == utils.PhaseDone && (ev.Bytes != 11 || ev.Total != 11) {
+			t.Fatalf("unexpected done event %+v", ev)
+		}
+		phases = append(phases, ev.Op+":"+ev.Phase)
+	}
+	want := []string{"upload:start", "upload:done", "download:start", "download:done"}
+	if len(phases) < len(want) || phases[0] != want[0] || phases[len(phases)-1] != want[3] {
+		t.Fatalf("unexpected events %v", phases)
+	}
+}
+
+func TestDownloadReportsSkippedPaths(t *testing.T) {
+	svc, core, store := newOfflineService(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store.PutObject("datalake", "demo/data/present.csv", []byte("x"))
+	core.Add("demo", "dataitems", map[string]interface{}{"id": "present", "name": "present", "kind": "table",
+		"spec": map[string]interface{}{"path": "s3://datalake/demo/data/present.csv"}})
+	core.Add("demo", "dataitems", map[string]interface{}{"id": "missing", "name": "missing", "kind": "table",
+		"spec": map[string]interface{}{"path": "s3://datalake/demo/data/missing.csv"}})
+
+	infos, err := svc.Download(ctx, "dataitems", transfer.DownloadRequest{
+		Project: "demo", Resource: "dataitems", ID: "present", Destination: filepath.Join(dir, "ok"),
+	})
+	if err != nil || len(infos) != 1 {
+		t.Fatalf("expected one file and no error, got %+v, %v", infos, err)
+	}
+
+	infos, err = svc.Download(ctx, "dataitems", transfer.DownloadRequest{
+		Project: "demo", Resource: "dataitems", ID: "missing", Destination: filepath.Join(dir, "ko"),
+	})
+	var berr *utils.BatchError
+	if !errors.As(err, &berr) {
+		t.Fatalf("expected a BatchError, got %v", err)
+	}
+	failures := berr.Failures()
+	if len(infos) != 0 || len(failures) != 1 || failures[0].Item != "s3://datalake/demo/data/missing.csv" {
+		t.Fatalf("unexpected report %+v / %+v", infos, failures)
+	}
+}
+
+func TestUploadLinksProducingRun(t *testing.T) {
+	svc, core, _ := newOfflineService(t)
+	ctx := context.Background()
+
+	core.Add("demo", "runs", map[string]interface{}{"id": "r1", "name": "r1", "kind": "python+run"})
+	run, _ := core.Get("demo", "runs", "r1")
+	runKey := run["key"]
+	input := filepath.Join(t.TempDir(), "out.txt")
+	if err := os.WriteFile(input, []byte("result"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project:  "demo",
+		Resource: "artifact",
+		Name:     "output",
+		Input:    input,
+		RunID:    "r1",
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	artifact, _ := core.Get("demo", "artifacts", res.ArtifactID)
+	meta, _ := artifact["metadata"].(map[string]interface{})
+	rels, _ := meta["relationships"].([]interface{})
+	if len(rels) != 1 {
+		t.Fatalf("expected one relationship, got %v", meta)
+	}
+	rel := rels[0].(map[string]interface{})
+	if rel["type"] != "produced_by" || rel["dest"] != runKey {
+		t.Fatalf("unexpected relationship %v (run key %v)", rel, runKey)
+	}
+}
+
+func TestUploadRecordsProvenance(t *testing.T) {
+	ctx := context.Background()
+	core := dhcoretest.NewServer()
+	defer core.Close()
+	core.Token = "secret"
+	store := s3test.NewServer()
+	defer store.Close()
+	cfg := core.Config()
+	cfg.S3 = store.Config()
+	// the user is looked up with any token credentials, not only AccessToken
+	cfg.Core.AccessToken = ""
+	cfg.Core.TokenSource = config.NewRefreshTokenSource("", "", config.Token{AccessToken: core.Token})
+	svc, err := transfer.NewTransferService(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lookups atomic.Int32
+	userinfo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if lookups.Add(1) == 1 {
+			http.Error(w, "unavailable", http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"sub":"42","preferred_username":"alice"}`))
+	}))
+	defer userinfo.Close()
+	core.SetOpenID(map[string]interface{}{"userinfo_endpoint": userinfo.URL})
+
+	// fake repository: .git/HEAD -> refs/heads/main
+	repo := t.TempDir()
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	for name, content := range map[string]string{
+		".git/HEAD":            "ref: refs/heads/main\n",
+		".git/refs/heads/main": sha + "\n",
+		"data/out.txt":         "result",
+	} {
+		p := filepath.Join(repo, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	input := filepath.Join(repo, "data", "out.txt")
+
+	// a failed lookup leaves created_by out and is tried again next time
+	res, err := svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project: "demo", Resource: "artifact", Name: "first", Input: input,
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	artifact, _ := core.Get("demo", "artifacts", res.ArtifactID)
+	if meta, _ := artifact["metadata"].(map[string]interface{}); meta["created_by"] != nil {
+		t.Fatalf("unexpected created_by %v after a failed lookup", meta["created_by"])
+	}
+
+	res, err = svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project: "demo", Resource: "artifact", Name: "output", Input: input,
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	artifact, _ = core.Get("demo", "artifacts", res.ArtifactID)
+	meta, _ := artifact["metadata"].(map[string]interface{})
+	if meta["created_by"] != "alice" {
+		t.Fatalf("expected created_by alice, got %v", meta["created_by"])
+	}
+	prov, _ := meta["provenance"].(map[string]interface{})
+	if prov["local_path"] != input || prov["git_commit"] != sha || prov["sdk_version"] == "" {
+		t.Fatalf("unexpected provenance %v", prov)
+	}
+	if host, _ := os.Hostname(); prov["hostname"] != host {
+		t.Fatalf("expected hostname %q, got %v", host, prov["hostname"])
+	}
+
+	// a successful lookup is kept
+	res, err = svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project: "demo", Resource: "artifact", Name: "again", Input: input,
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	artifact, _ = core.Get("demo", "artifacts", res.ArtifactID)
+	if meta, _ := artifact["metadata"].(map[string]interface{}); meta["created_by"] != "alice" || lookups.Load() != 2 {
+		t.Fatalf("expected created_by alice from 2 lookups, got %v from %d", meta["created_by"], lookups.Load())
+	}
+
+	res, err = svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project: "demo", Resource: "artifact", Name: "plain", Input: input, NoProvenance: true,
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	artifact, _ = core.Get("demo", "artifacts", res.ArtifactID)
+	if meta, _ := artifact["metadata"].(map[string]interface{}); meta["provenance"] != nil {
+		t.Fatalf("provenance recorded despite NoProvenance: %v", meta)
+	}
+}
+
+func TestUploadValidationRunsBeforeTransfer(t *testing.T) {
+	svc, core, store := newOfflineService(t)
+	ctx := context.Background()
+	svc.AddUploadValidator(transfer.UploadPolicy{
+		MaxFileSize:         4,
+		ForbiddenExtensions: []string{"EXE"},
+		RejectEmptyDirs:     true,
+	})
+
+	input := filepath.Join(t.TempDir(), "model")
+	for rel, content := range map[string]string{"ok.txt": "abc", "big.bin": "abcdef", "tool.exe": "x"} {
+		if err := os.MkdirAll(input, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(input, rel), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(input, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project: "demo", Resource: "artifact", Name: "model", Input: input,
+	})
+	var berr *utils.BatchError
+	if !errors.As(err, &berr) || berr.Len() != 3 {
+		t.Fatalf("expected 3 violations, got %v", err)
+	}
+	for _, target := range []error{transfer.ErrFileTooLarge, transfer.ErrForbiddenExtension, transfer.ErrEmptyDir} {
+		if !errors.Is(err, target) {
+			t.Fatalf("expected %v in %v", target, err)
+		}
+	}
+	if n := len(core.List("demo", "artifacts")); n != 0 {
+		t.Fatalf("artifact created despite validation failure (%d)", n)
+	}
+	if n := len(store.Keys("datalake")); n != 0 {
+		t.Fatalf("objects uploaded despite validation failure (%d)", n)
+	}
+}
+
+func TestUploadRenewsExpiredS3Credentials(t *testing.T) {
+	core := dhcoretest.NewServer()
+	t.Cleanup(core.Close)
+	store := s3test.NewServer()
+	t.Cleanup(store.Close)
+	store.RequireAccessKey = "fresh"
+	core.SetS3Credentials(map[string]interface{}{
+		"aws_access_key_id":          "fresh",
+		"aws_secret_access_key":      "secret",
+		"aws_session_token":          "session",
+		"aws_credentials_expiration": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+	})
+
+	cfg := core.Config()
+	cfg.S3 = store.Config()
+	cfg.S3.AccessKey = "stale"
+	cfg.S3.Expiration = time.Now().Add(-time.Minute)
+	cfg.S3.CoreCredentials = true
+	var renewed config.S3Credentials
+	cfg.S3.OnCredentialsRefresh = func(c config.S3Credentials) { renewed = c }
+	svc, err := transfer.NewTransferService(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to init sdk: %v", err)
+	}
+
+	input := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(input, []byte("a,b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Upload(context.Background(), "artifacts", transfer.UploadRequest{
+		Project: "demo", Resource: "artifact", Name: "dataset", Input: input,
+	}); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if renewed.AccessKey != "fresh" || renewed.SessionToken != "session" || renewed.Expiration.IsZero() {
+		t.Fatalf("unexpected renewed credentials %+v", renewed)
+	}
+}
+
+func TestCopyOffline(t *testing.T) {
+	svc, _, store := newOfflineService(t)
+	ctx := context.Background()
+	store.PutObject("datalake", "src/dir/a.csv", []byte("a"))
+	store.PutObject("datalake", "src/dir/sub/b.csv", []byte("b"))
+
+	if err := svc.Copy(ctx, "s3://datalake/src/dir/", "s3://backup/copy"); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"copy/a.csv": "a", "copy/sub/b.csv": "b"} {
+		if obj, ok := store.GetObject("backup", key); !ok || string(obj.Data) != want {
+			t.Fatalf("%s not copied", key)
+		}
+	}
+	if err := svc.Copy(ctx, "s3://datalake/src/dir/a.csv", "s3://backup/one/"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.GetObject("backup", "one/a.csv"); !ok {
+		t.Fatal("file not copied")
+	}
+	if err := svc.Copy(ctx, "/tmp/local", "s3://backup/x"); err == nil {
+		t.Fatal("expected an error for a local path")
+	}
+}
+
+func TestSyncOffline(t *testing.T) {
+	svc, _, store := newOfflineService(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	src := filepath.Join(dir, "src")
+	write := func(rel, content string) {
+		p := filepath.Join(src, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.csv", "a")
+	write("b.csv", "b")
+	write("sub/c.csv", "c")
+
+	up := transfer.SyncRequest{Direction: transfer.SyncUpload, Local: src, Remote: "s3://datalake/sync/"}
+	rep, err := svc.Sync(ctx, up)
+	if err != nil || len(rep.Actions) != 3 || len(store.Keys("datalake")) != 3 {
+		t.Fatalf("expected 3 uploads, got %+v (%v)", rep, err)
+	}
+	if rep, err = svc.Sync(ctx, up); err != nil || len(rep.Actions) != 0 || rep.Unchanged != 3 {
+		t.Fatalf("expected nothing to do, got %+v (%v)", rep, err)
+	}
+
+	write("a.csv", "a changed")
+	if err := os.Remove(filepath.Join(src, "b.csv")); err != nil {
+		t.Fatal(err)
+	}
+	up.Delete, up.DryRun = true, true
+	before := len(store.Requests())
+	rep, err = svc.Sync(ctx, up)
+	want := []transfer.SyncAction{
+		{Path: "a.csv", Op: transfer.ActionUpload, Reason: "size", Size: 9},
+		{Path: "b.csv", Op: transfer.ActionDelete, Reason: "stale", Size: 1},
+	}
+	if err != nil || fmt.Sprint(rep.Actions) != fmt.Sprint(want) {
+		t.Fatalf("unexpected dry run report %+v (%v)", rep, err)
+	}
+	for _, r := range store.Requests()[before:] {
+		if !strings.HasPrefix(r, "GET /datalake?") {
+			t.Fatalf("dry run should only list, got %s", r)
+		}
+	}
+	up.DryRun = false
+	if _, err := svc.Sync(ctx, up); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if obj, _ := store.GetObject("datalake", "sync/a.csv"); string(obj.Data) != "a changed" {
+		t.Fatalf("a.csv not updated: %q", obj.Data)
+	}
+	if _, ok := store.GetObject("datalake", "sync/b.csv"); ok {
+		t.Fatal("b.csv should be deleted")
+	}
+
+	dst := filepath.Join(dir, "dst")
+	down := transfer.SyncRequest{Direction: transfer.SyncDownload, Local: dst, Remote: "s3://datalake/sync", Checksum: true}
+	if rep, err = svc.Sync(ctx, down); err != nil || len(rep.Actions) != 2 {
+		t.Fatalf("expected 2 downloads, got %+v (%v)", rep, err)
+	}
+	store.PutObject("datalake", "sync/sub/c.csv", []byte("C"))
+	rep, err = svc.Sync(ctx, down)
+	if err != nil || len(rep.Actions) != 1 || rep.Actions[0].Reason != "checksum" {
+		t.Fatalf("expected a checksum change, got %+v (%v)", rep, err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dst, "sub", "c.csv")); string(got) != "C" {
+		t.Fatalf("c.csv not updated: %q", got)
+	}
+
+	// a key escaping the prefix is never written outside Local
+	store.PutObject("datalake", "sync/../escape.csv", []byte("x"))
+	if _, err := svc.Sync(ctx, down); err == nil {
+		t.Fatal("expected an error for a key escaping the local directory")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape.csv")); !os.IsNotExist(err) {
+		t.Fatalf("file written outside the local directory: %v", err)
+	}
+}
+
+func TestDownloadStreamOffline(t *testing.T) {
+	svc, _, store := newOfflineService(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	input := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(input, []byte("a,b\n1,2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project:  "demo",
+		Resource: "artifact",
+		Name:     "dataset",
+		Input:    input,
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	req := transfer.DownloadRequest{Project: "demo", Resource: "artifacts", Name: "dataset"}
+	n, err := svc.DownloadStream(ctx, req, &buf)
+	if err != nil || n != 8 || buf.String() != "a,b\n1,2\n" {
+		t.Fatalf("unexpected stream %q (%d bytes, %v)", buf.String(), n, err)
+	}
+
+	store.PutObject("datalake", "demo/artifact/"+res.ArtifactID+"/data.csv", []byte("a,b\n9,9\n"))
+	var mismatch *utils.ChecksumError
+	if _, err := svc.DownloadStream(ctx, req, io.Discard); !errors.As(err, &mismatch) {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+}
+
+func TestUploadFromReaderOffline(t *testing.T) {
+	svc, core, store := newOfflineService(t)
+	ctx := context.Background()
+
+	content := "id,score\n1,0.9\n2,0.7\n"
+	res, err := svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project:  "demo",
+		Resource: "artifact",
+		Name:     "scores",
+		Reader:   io.MultiReader(strings.NewReader(content)),
+		Filename: "scores.csv",
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	obj, ok := store.GetObject("datalake", "demo/artifact/"+res.ArtifactID+"/scores.csv")
+	if !ok || string(obj.Data) != content || obj.ContentType != "text/csv" {
+		t.Fatalf("unexpected object %+v", obj)
+	}
+	artifact, _ := core.Get("demo", "artifacts", res.ArtifactID)
+	if prov := artifact["metadata"].(map[string]interface{})["provenance"].(map[string]interface{}); prov["local_path"] != nil {
+		t.Fatalf("reader upload should record no local path, got %v", prov["local_path"])
+	}
+	if len(res.Files) != 1 || res.Files[0]["size"] != int64(len(content)) || res.Files[0]["hash"] == "" {
+		t.Fatalf("unexpected file infos %v", res.Files)
+	}
+
+	// the recorded checksums verify the download
+	var buf bytes.Buffer
+	if _, err := svc.DownloadStream(ctx, transfer.DownloadRequest{Project: "demo", Resource: "artifacts", ID: res.ArtifactID}, &buf); err != nil || buf.String() != content {
+		t.Fatalf("unexpected download %q (%v)", buf.String(), err)
+	}
+
+	if _, err := svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project: "demo", Resource: "artifact", Name: "noname", Reader: strings.NewReader(content),
+	}); err == nil {
+		t.Fatal("expected an error without filename")
+	}
+
+	// a reader of unknown size is stopped once over the limits
+	svc.AddUploadValidator(transfer.UploadPolicy{MaxFileSize: 1024})
+	_, err = svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project: "demo", Resource: "artifact", Name: "big",
+		Reader: io.MultiReader(bytes.NewReader(make([]byte, 4096))), Filename: "big.bin",
+	})
+	if !errors.Is(err, transfer.ErrFileTooLarge) {
+		t.Fatalf("expected ErrFileTooLarge, got %v", err)
+	}
+	for _, k := range store.Keys("datalake") {
+		if strings.HasSuffix(k, "/big.bin") {
+			t.Fatalf("rejected reader stored as %s", k)
+		}
+	}
+}
+
+func TestArchiveUploadAndExtractOffline(t *testing.T) {
+	svc, _, store := newOfflineService(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	input := filepath.Join(dir, "model")
+	for _, rel := range []string{"weights.bin", "conf/params.yaml", "conf/labels.txt"} {
+		p := filepath.Join(input, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(rel), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, format := range []string{utils.ArchiveTarGz, utils.ArchiveZip} {
+		t.Run(format, func(t *testing.T) {
+			res, err := svc.Upload(ctx, "models", transfer.UploadRequest{
+				Project:  "demo",
+				Resource: "model",
+				Name:     "clf-" + strings.ReplaceAll(format, ".", ""),
+				Input:    input,
+				Archive:  format,
+			})
+			if err != nil {
+				t.Fatalf("upload failed: %v", err)
+			}
+			key := "demo/model/" + res.ArtifactID + "/model." + format
+			if _, ok := store.GetObject("datalake", key); !ok || len(res.Files) != 1 {
+				t.Fatalf("expected a single archive at %s, got %v", key, res.Files)
+			}
+
+			out := filepath.Join(dir, "out-"+format)
+			infos, err := svc.Download(ctx, "models", transfer.DownloadRequest{
+				Project:     "demo",
+				Resource:    "models",
+				ID:          res.ArtifactID,
+				Destination: out,
+				Extract:     true,
+			})
+			if err != nil {
+				t.Fatalf("download failed: %v", err)
+			}
+			if len(infos) != 3 {
+				t.Fatalf("expected 3 extracted files, got %+v", infos)
+			}
+			if got, err := os.ReadFile(filepath.Join(out, "conf", "labels.txt")); err != nil || string(got) != "conf/labels.txt" {
+				t.Fatalf("unexpected extracted content %q (%v)", got, err)
+			}
+			if _, err := os.Stat(filepath.Join(out, "model."+format)); !os.IsNotExist(err) {
+				t.Fatalf("archive should not be kept, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPresignOffline(t *testing.T) {
+	svc, _, store := newOfflineService(t)
+	ctx := context.Background()
+
+	input := filepath.Join(t.TempDir(), "model")
+	for _, rel := range []string{"weights.bin", "conf/params.yaml"} {
+		p := filepath.Join(input, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(rel), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := svc.Upload(ctx, "models", transfer.UploadRequest{
+		Project: "demo", Resource: "model", Name: "clf", Input: input,
+	}); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	urls, err := svc.Presign(ctx, transfer.PresignRequest{Project: "demo", Resource: "models", Name: "clf", TTL: time.Hour})
+	if err != nil || len(urls) != 2 {
+		t.Fatalf("expected 2 urls, got %+v (%v)", urls, err)
+	}
+	for _, u := range urls {
+		if time.Until(u.Expires) <= 0 {
+			t.Fatalf("url already expired: %+v", u)
+		}
+		resp, err := store.Client().Get(u.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if !strings.HasSuffix(u.Path, string(body)) {
+			t.Fatalf("%s returned %q", u.Path, body)
+		}
+	}
+}
+
+func TestObjectOperationsOffline(t *testing.T) {
+	svc, core, store := newOfflineService(t)
+	ctx := context.Background()
+
+	input := filepath.Join(t.TempDir(), "model")
+	for _, rel := range []string{"weights.bin", "conf/params.yaml"} {
+		p := filepath.Join(input, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(rel), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	res, err := svc.Upload(ctx, "models", transfer.UploadRequest{Project: "demo", Resource: "model", Name: "clf", Input: input})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	prefix := "s3://datalake/demo/model/" + res.ArtifactID + "/"
+
+	info, err := svc.Stat(ctx, prefix+"weights.bin")
+	if err != nil || info.Size != int64(len("weights.bin")) {
+		t.Fatalf("unexpected stat %+v (%v)", info, err)
+	}
+	if ok, err := svc.Exists(ctx, prefix+"missing.bin"); ok || err != nil {
+		t.Fatalf("expected a missing object, got %v (%v)", ok, err)
+	}
+
+	if err := svc.Move(ctx, prefix+"conf/", "s3://release/clf/conf/"); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	if _, ok := store.GetObject("release", "clf/conf/params.yaml"); !ok {
+		t.Fatal("file not moved")
+	}
+	if ok, _ := svc.Exists(ctx, prefix+"conf/params.yaml"); ok {
+		t.Fatal("source not removed by move")
+	}
+
+	n, err := svc.DeleteFiles(ctx, transfer.DeleteFilesRequest{Project: "demo", Resource: "models", ID: res.ArtifactID})
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 file deleted, got %d (%v)", n, err)
+	}
+	if keys := store.Keys("datalake"); len(keys) != 0 {
+		t.Fatalf("unexpected keys left %v", keys)
+	}
+	if _, ok := core.Get("demo", "models", res.ArtifactID); !ok {
+		t.Fatal("the entity should be left on Core")
+	}
+}
+
+func TestUploadObjectOptionsOffline(t *testing.T) {
+	svc, _, store := newOfflineService(t)
+	ctx := context.Background()
+
+	input := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(input, []byte("a,b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project:  "demo",
+		Resource: "artifact",
+		Name:     "dataset",
+		Input:    input,
+		Objects: config.ObjectOptions{
+			SSE: config.SSEKMS, KMSKeyID: "alias/compliance", StorageClass: "STANDARD_IA",
+			Tags: map[string]string{"classification": "internal"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	obj, _ := store.GetObject("datalake", "demo/artifact/"+res.ArtifactID+"/data.csv")
+	if obj.SSE != config.SSEKMS || obj.KMSKeyID != "alias/compliance" || obj.StorageClass != "STANDARD_IA" ||
+		obj.Tags["classification"] != "internal" {
+		t.Fatalf("unexpected attributes %+v", obj)
+	}
+
+	if _, err := svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project: "demo", Resource: "artifact", Name: "bad", Input: input,
+		Objects: config.ObjectOptions{SSE: "des"},
+	}); err == nil {
+		t.Fatal("expected an error for an invalid encryption")
+	}
+
+	// the ETag of an SSE-KMS object is not its MD5: without recorded
+	// checksums the download is checked by size only
+	res, err = svc.Upload(ctx, "artifacts", transfer.UploadRequest{
+		Project: "demo", Resource: "artifact", Name: "nosums", Input: input, NoChecksums: true,
+		Objects: config.ObjectOptions{SSE: config.SSEKMS},
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	infos, err := svc.Download(ctx, "artifacts", transfer.DownloadRequest{
+		Project: "demo", Resource: "artifacts", ID: res.ArtifactID, Destination: t.TempDir(),
+	})
+	if err != nil || len(infos) != 1 || infos[0].Verified {
+		t.Fatalf("unexpected download of an SSE-KMS object %+v (%v)", infos, err)
+	}
+
+	// and Sync compares it by modification time
+	dst := t.TempDir()
+	down := transfer.SyncRequest{Direction: transfer.SyncDownload, Local: dst,
+		Remote: "s3://datalake/demo/artifact/" + res.ArtifactID + "/", Checksum: true}
+	if rep, err := svc.Sync(ctx, down); err != nil || len(rep.Actions) != 1 {
+		t.Fatalf("expected 1 download, got %+v (%v)", rep, err)
+	}
+	if rep, err := svc.Sync(ctx, down); err != nil || len(rep.Actions) != 0 || rep.Unchanged != 1 {
+		t.Fatalf("expected nothing to do, got %+v (%v)", rep, err)
+	}
+}
@@ -4,6 +4,13 @@
 
 package transfer
 
+import (
+	"io"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+)
+
 type DownloadRequest struct {
 	Project     string
 	Resource    string
@@ -11,12 +18,42 @@ type DownloadRequest struct {
 	Name        string
 	Destination string
 	Verbose     bool
+	// Optional: utils.ProgressJSON writes JSON-lines progress events
+	// to ProgressOutput (default stderr) instead of text
+	ProgressFormat utils.ProgressFormat
+	ProgressOutput io.Writer
+	// Concurrency above 1 splits S3 objects larger than PartSize in byte
+	// ranges downloaded in parallel; PartSize defaults to the Transfer
+	// config of the service
+	Concurrency int
+	PartSize    int64
+	// Parallelism is the number of files of a directory downloaded at once
+	// (default utils.DefaultParallelism)
+	Parallelism int
+	// NoVerify skips the check of the downloaded files against the
+	// checksums in status.files (or the MD5 ETag of S3 objects) and the size
+	NoVerify bool
+	// VerifyOnly checks the files already at Destination instead of
+	// downloading them: mismatching and missing files are the errors
+	VerifyOnly bool
+	// Extract unpacks archived files (.tar.gz, .tgz, .zip) into Destination
+	// instead of saving the archive; a tar.gz on S3 is extracted while it
+	// streams, other archives are removed once extracted. The archive is
+	// verified, not the files it contains
+	Extract bool
+	// Options add headers and query params to the Core calls
+	// (config.WithHeader, config.WithQueryParam)
+	Options []config.RequestOption
 }
 
 type DownloadInfo struct {
 	Filename string `json:"filename" yaml:"filename"`
 	Size     int64  `json:"size"     yaml:"size"`
 	Path     string `json:"path"     yaml:"path"`
+	// Hash is the SHA-256 of the file ("sha256:<hex>") when Verified
+	Hash string `json:"hash,omitempty" yaml:"hash,omitempty"`
+	// Verified tells that the file matched a known checksum
+	Verified bool `json:"verified,omitempty" yaml:"verified,omitempty"`
 }
 
 // -------- Upload --------
@@ -26,10 +63,51 @@ type UploadRequest struct {
 	Resource string
 	ID       string // opzionale; se vuoto -> crea nuovo artefatto
 	Name     string // obbligatorio se ID vuoto (creazione)
-	Input    string // file o directory locale (obbligatorio)
+	Input    string // file o directory locale (obbligatorio senza Reader)
 	Verbose  bool
+	// Reader uploads its content instead of Input, as a single file named
+	// Filename (required); Size is its length, 0 when unknown, and
+	// ContentType is resolved from Filename when empty
+	Reader      io.Reader
+	Filename    string
+	Size        int64
+	ContentType string
+	// Archive packs Input into a single object of this format
+	// (utils.ArchiveTarGz, utils.ArchiveZip), named after Input and
+	// compressed while it uploads; faster for many small files
+	Archive string
+	// Objects sets the encryption, ACL, storage class and tags of the
+	// stored objects, over the S3Config.Objects of the service
+	Objects config.ObjectOptions
 	// Opzionale: override del bucket (default = "datalake" per compatibilità)
 	Bucket string
+	// Optional: run producing the artifact, linked as "produced_by";
+	// empty falls back to the run_id of the CLI environment
+	RunID string
+	// NoProvenance disables the metadata.provenance / metadata.created_by
+	// recorded on artifacts created by the upload
+	NoProvenance bool
+	// Optional: content type per extension (e.g. ".onnx" -> "application/onnx"),
+	// takes precedence over the builtin detection
+	ContentTypes map[string]string
+	// Optional: utils.ProgressJSON writes JSON-lines progress events
+	// to ProgressOutput (default stderr) instead of text
+	ProgressFormat utils.ProgressFormat
+	ProgressOutput io.Writer
+	// NoPreScan starts a directory upload without counting files first
+	// (see utils.UploadOptions.NoPreScan)
+	NoPreScan bool
+	// Parallelism is the number of files of a directory uploaded at once
+	// (default utils.DefaultParallelism)
+	Parallelism int
+	// NoChecksums skips the SHA-256 and MD5 of the files recorded in
+	// status.files (see utils.UploadOptions.NoChecksums)
+	NoChecksums bool
+	// IdempotencyKey makes a retried artifact creation return the artifact
+	// created the first time; empty generates a new key per call
+	IdempotencyKey string
+	// Options add headers and query params to the Core calls
+	Options []config.RequestOption
 }
 
 type UploadResult struct {
@@ -9,12 +9,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
 	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Upload esegue:
@@ -22,22 +25,60 @@ import (
 // - transizione a UPLOADING
 // - upload file/dir verso s3://<bucket>/<project>/<resource>/<id>/...
 // - transizione a READY con files[] allegati
-func (s *TransferService) Upload(ctx context.Context, endpoint string, req UploadRequest) (*UploadResult, error) {
-	if req.Input == "" {
+func (s *TransferService) Upload(ctx context.Context, endpoint string, req UploadRequest) (_ *UploadResult, err error) {
+	ctx, span := config.StartSpan(ctx, s.tracer, "transfer.upload", req.Project, req.Resource)
+	defer func() { config.EndSpan(span, err) }()
+	ctx = config.ContextWithRequestOptions(ctx, req.Options...)
+	ctx = config.ContextWithObjectOptions(ctx, req.Objects)
+
+	if req.Reader != nil {
+		if req.Filename == "" {
+			return nil, errors.New("filename is required when uploading from a reader")
+		}
+	} else if req.Input == "" {
 		return nil, errors.New("missing required input file or directory")
 	}
-	if endpoint != "projects" && req.Project == "" {
+	if err := req.Objects.Validate(); err != nil {
+		return nil, err
+	}
+	var archiveName string
+	if req.Archive != "" {
+		if req.Reader != nil {
+			return nil, errors.New("archive applies to Input only, not to a reader")
+		}
+		ext, err := utils.ArchiveExt(req.Archive)
+		if err != nil {
+			return nil, err
+		}
+		abs, err := filepath.Abs(req.Input)
+		if err != nil {
+			return nil, fmt.Errorf("cannot access input: %w", err)
+		}
+		archiveName = filepath.Base(abs) + ext
+	}
+	if !config.IsGlobalResource(endpoint) && req.Project == "" {
 		return nil, errors.New("project is mandatory for non-project resources")
 	}
+	if err := s.validateUpload(ctx, req); err != nil {
+		return nil, err
+	}
 
 	// getRunKey func...retrieve the key from the run
 	getRunKey := func() (string, error) {
-		runID := viper.GetString(utils.RunId)
+		runID := req.RunID
+		if runID == "" {
+			// compat: run id from the CLI environment
+			runID = viper.GetString(utils.RunId)
+		}
 		if runID == "" {
 			return "", nil
 		}
 
-		url := s.http.BuildURL(req.Project, utils.TranslateEndpoint("run"), runID, nil)
+		runs, err := config.ResolveResource("run")
+		if err != nil {
+			return "", err
+		}
+		url := s.http.BuildURL(req.Project, runs, runID, nil)
 		bodyRun, _, err := s.http.Do(ctx, "GET", url, nil)
 		if err != nil {
 			return "", err
@@ -54,30 +95,6 @@ func (s *TransferService) Upload(ctx context.Context, endpoint string, req Uploa
 		return "", fmt.Errorf("run key not found in response")
 	}
 
-	// add a new relations in metadata
-	addRelationship := func(artifactMap map[string]interface{}, relType, dest string) {
-		// assicurati che metadata esista
-		meta, ok := artifactMap["metadata"].(map[string]interface{})
-		if !ok {
-			meta = make(map[string]interface{})
-			artifactMap["metadata"] = meta
-		}
-
-		// assicurati che relationships esista
-		rels, ok := meta["relationships"].([]map[string]interface{})
-		if !ok {
-			rels = []map[string]interface{}{}
-		}
-
-		// aggiungi nuova relazione
-		rels = append(rels, map[string]interface{}{
-			"type": relType,
-			"dest": dest,
-		})
-
-		meta["relationships"] = rels
-	}
-
 	runKey, err := getRunKey()
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve run: %w", err)
@@ -94,18 +111,24 @@ func (s *TransferService) Upload(ctx context.Context, endpoint string, req Uploa
 			bucket = "datalake" // retro-compat
 		}
 
-		st, err := os.Stat(req.Input)
-		if err != nil {
-			return nil, fmt.Errorf("cannot access input: %w", err)
-		}
-
 		artifactID = utils.UUIDv4NoDash()
 
 		var path string
-		if st.IsDir() {
-			path = fmt.Sprintf("s3://%s/%s/%s/%s/", bucket, req.Project, req.Resource, artifactID)
-		} else {
-			path = fmt.Sprintf("s3://%s/%s/%s/%s/%s", bucket, req.Project, req.Resource, artifactID, st.Name())
+		switch {
+		case req.Reader != nil:
+			path = fmt.Sprintf("s3://%s/%s/%s/%s/%s", bucket, req.Project, req.Resource, artifactID, req.Filename)
+		case archiveName != "":
+			path = fmt.Sprintf("s3://%s/%s/%s/%s/%s", bucket, req.Project, req.Resource, artifactID, archiveName)
+		default:
+			st, err := os.Stat(req.Input)
+			if err != nil {
+				return nil, fmt.Errorf("cannot access input: %w", err)
+			}
+			if st.IsDir() {
+				path = fmt.Sprintf("s3://%s/%s/%s/%s/", bucket, req.Project, req.Resource, artifactID)
+			} else {
+				path = fmt.Sprintf("s3://%s/%s/%s/%s/%s", bucket, req.Project, req.Resource, artifactID, st.Name())
+			}
 		}
 
 		entity := map[string]interface{}{
@@ -120,6 +143,19 @@ func (s *TransferService) Upload(ctx context.Context, endpoint string, req Uploa
 				"state": "CREATED",
 			},
 		}
+		if !req.NoProvenance {
+			input := req.Input
+			if req.Reader != nil {
+				input = ""
+			}
+			metadata := map[string]interface{}{
+				"provenance": s.provenance(input),
+			}
+			if user := s.createdBy(ctx); user != "" {
+				metadata["created_by"] = user
+			}
+			entity["metadata"] = metadata
+		}
 		payload, err := json.Marshal(entity)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal artifact creation payload: %w", err)
@@ -127,7 +163,11 @@ func (s *TransferService) Upload(ctx context.Context, endpoint string, req Uploa
 
 		createURL := s.http.BuildURL(req.Project, endpoint, "", nil)
 
-		if _, _, err = s.http.Do(ctx, "POST", createURL, payload); err != nil {
+		key := req.IdempotencyKey
+		if key == "" {
+			key = config.NewIdempotencyKey()
+		}
+		if _, _, err = s.http.Do(config.ContextWithIdempotencyKey(ctx, key), "POST", createURL, payload); err != nil {
 			return nil, fmt.Errorf("failed to create artifact: %w", err)
 		}
 	}
@@ -169,7 +209,9 @@ func (s *TransferService) Upload(ctx context.Context, endpoint string, req Uploa
 
 	// Add lineage relationship
 	if runKey != "" {
-		addRelationship(artifact, "produced_by", runKey)
+		if _, err := utils.AddRelationship(artifact, utils.Relationship{Type: utils.RelationshipProducedBy, Dest: runKey}); err != nil {
+			return nil, err
+		}
 	}
 
 	// 5) Helper: update status sul Core (merge preservando altri campi)
@@ -198,33 +240,61 @@ func (s *TransferService) Upload(ctx context.Context, endpoint string, req Uploa
 	}
 
 	// 7) Upload
-	st, err := os.Stat(req.Input)
-	if err != nil {
-		_ = updateStatus("status", map[string]interface{}{"state": "ERROR"})
-		return nil, fmt.Errorf("cannot access input: %w", err)
+	var st os.FileInfo
+	if req.Reader == nil {
+		if st, err = os.Stat(req.Input); err != nil {
+			_ = updateStatus("status", map[string]interface{}{"state": "ERROR"})
+			return nil, fmt.Errorf("cannot access input: %w", err)
+		}
 	}
 
 	var files []map[string]interface{}
-	ctxUp := ctx
+	upOpts := utils.UploadOptions{
+		Verbose:        req.Verbose,
+		ContentTypes:   req.ContentTypes,
+		ProgressFormat: req.ProgressFormat,
+		ProgressOutput: req.ProgressOutput,
+		NoPreScan:      req.NoPreScan,
+		Logger:         s.logger,
+		Parallelism:    req.Parallelism,
+		NoChecksums:    req.NoChecksums,
+	}
 
-	if st.IsDir() {
-		_, files, err = utils.UploadS3Dir(s.s3, ctxUp, parsedPath, req.Input, req.Verbose)
-		if err != nil {
-			_ = updateStatus("status", map[string]interface{}{"state": "ERROR"})
-			return nil, fmt.Errorf("upload failed: %w", err)
+	ctxUp, s3span := config.StartSpan(ctx, s.tracer, "transfer.s3.upload", req.Project, req.Resource,
+		attribute.String("s3.bucket", parsedPath.Host), attribute.String("s3.key", parsedPath.Path))
+	switch {
+	case req.Reader != nil:
+		targetKey := parsedPath.Path
+		if strings.HasSuffix(targetKey, "/") {
+			targetKey += req.Filename
+		}
+		_, files, err = utils.UploadS3Reader(s.s3, ctxUp, parsedPath.Host, targetKey, s.limitReader(req), req.Size, req.ContentType, upOpts)
+	case archiveName != "":
+		targetKey := parsedPath.Path
+		if strings.HasSuffix(targetKey, "/") {
+			targetKey += archiveName
 		}
-	} else {
+		// l'archivio è scritto in una pipe mentre viene caricato: nessun file temporaneo
+		pr, pw := io.Pipe()
+		go func() { pw.CloseWithError(utils.WriteArchive(pw, req.Input, req.Archive)) }()
+		_, files, err = utils.UploadS3Reader(s.s3, ctxUp, parsedPath.Host, targetKey, pr, 0, "", upOpts)
+		pr.CloseWithError(err)
+	case st.IsDir():
+		_, files, err = utils.UploadS3DirWithOptions(s.s3, ctxUp, parsedPath, req.Input, upOpts)
+	default:
 		var targetKey string
 		if strings.HasSuffix(parsedPath.Path, "/") {
 			targetKey = filepath.ToSlash(filepath.Join(parsedPath.Path, st.Name()))
 		} else {
 			targetKey = parsedPath.Path
 		}
-		_, files, err = utils.UploadS3File(s.s3, ctxUp, parsedPath.Host, targetKey, req.Input, req.Verbose)
-		if err != nil {
-			_ = updateStatus("status", map[string]interface{}{"state": "ERROR"})
-			return nil, fmt.Errorf("upload failed: %w", err)
-		}
+		_, files, err = utils.UploadS3FileWithOptions(s.s3, ctxUp, parsedPath.Host, targetKey, req.Input, upOpts)
+	}
+	s3span.SetAttributes(attribute.Int("s3.files", len(files)))
+	config.EndSpan(s3span, err)
+	if err != nil {
+		_ = updateStatus("status", map[string]interface{}{"state": "ERROR"})
+		return nil, fmt.Errorf("upload failed: %w", err)
 	}
 
 	// 8) Stato → READY + files
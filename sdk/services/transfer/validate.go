@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/humanize"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+)
+
+// Validation failures reported by UploadPolicy; match them with errors.Is.
+var (
+	ErrFileTooLarge       = errors.New("file exceeds the maximum size")
+	ErrUploadTooLarge     = errors.New("upload exceeds the maximum total size")
+	ErrForbiddenExtension = errors.New("file extension is not allowed")
+	ErrEmptyDir           = errors.New("directory is empty")
+)
+
+// UploadFile is a local file about to be uploaded.
+type UploadFile struct {
+	Path    string // local path
+	RelPath string // slash-separated path relative to the upload input
+	Size    int64
+}
+
+// UploadSummary describes a whole upload once every file has been checked.
+type UploadSummary struct {
+	Request UploadRequest
+	Files   int
+	Bytes   int64
+	// EmptyDirs lists the directories (relative, slash-separated, "." for the
+	// input itself) containing no entries; they are not stored on S3.
+	EmptyDirs []string
+}
+
+// UploadValidator is a policy check run by Upload before anything is created
+// on Core or sent to S3. Files are passed one at a time, so validators must
+// not assume the whole listing fits in memory.
+type UploadValidator interface {
+	// CheckFile is called for every file of the upload.
+	CheckFile(ctx context.Context, f UploadFile) error
+	// CheckUpload is called once, after all the files.
+	CheckUpload(ctx context.Context, s UploadSummary) error
+}
+
+// UploadPolicy is the builtin UploadValidator; zero fields are not enforced.
+type UploadPolicy struct {
+	MaxFileSize  int64
+	MaxTotalSize int64
+	// ForbiddenExtensions are matched case-insensitively, with or without
+	// the leading dot (e.g. ".exe", "sh").
+	ForbiddenExtensions []string
+	// RejectEmptyDirs fails uploads of empty directories, or containing
+	// empty subdirectories that would be silently dropped.
+	RejectEmptyDirs bool
+}
+
+func (p UploadPolicy) CheckFile(ctx context.Context, f UploadFile) error {
+	if p.MaxFileSize > 0 && f.Size > p.MaxFileSize {
+		return fmt.Errorf("%w (%s > %s)", ErrFileTooLarge, humanize.Bytes(f.Size), humanize.Bytes(p.MaxFileSize))
+	}
+	ext := strings.ToLower(filepath.Ext(f.Path))
+	for _, forbidden := range p.ForbiddenExtensions {
+		forbidden = strings.ToLower(forbidden)
+		if !strings.HasPrefix(forbidden, ".") {
+			forbidden = "." + forbidden
+		}
+		if ext == forbidden {
+			return fmt.Errorf("%w: %s", ErrForbiddenExtension, ext)
+		}
+	}
+	return nil
+}
+
+func (p UploadPolicy) CheckUpload(ctx context.Context, s UploadSummary) error {
+	berr := utils.NewBatchError("validate")
+	if p.MaxTotalSize > 0 && s.Bytes > p.MaxTotalSize {
+		berr.Add(s.Request.Input, fmt.Errorf("%w (%s > %s)", ErrUploadTooLarge, humanize.Bytes(s.Bytes), humanize.Bytes(p.MaxTotalSize)))
+	}
+	if p.RejectEmptyDirs {
+		for _, dir := range s.EmptyDirs {
+			berr.Add(dir, ErrEmptyDir)
+		}
+	}
+	return berr.ErrorOrNil()
+}
+
+// AddUploadValidator registers validators run before every Upload.
+func (s *TransferService) AddUploadValidator(v ...UploadValidator) {
+	s.validators = append(s.validators, v...)
+}
+
+// validateUpload walks the input and runs the validators; every violation is
+// collected in a *utils.BatchError.
+func (s *TransferService) validateUpload(ctx context.Context, req UploadRequest) error {
+	if len(s.validators) == 0 {
+		return nil
+	}
+
+	sum := UploadSummary{Request: req}
+	berr := utils.NewBatchError("validate")
+	checkFile := func(f UploadFile) {
+		sum.Files++
+		sum.Bytes += f.Size
+		for _, v := range s.validators {
+			berr.Add(f.RelPath, v.CheckFile(ctx, f))
+		}
+	}
+
+	input := req.Input
+	if req.Reader != nil {
+		input = req.Filename
+		// the size of a reader is only known when declared, else the
+		// limits are enforced while it is read (see limitReader)
+		checkFile(UploadFile{Path: req.Filename, RelPath: req.Filename, Size: max(req.Size, 0)})
+	} else if err := s.walkUpload(ctx, req, &sum, checkFile); err != nil {
+		return fmt.Errorf("failed to enumerate input: %w", err)
+	}
+
+	for _, v := range s.validators {
+		berr.Add(input, v.CheckUpload(ctx, sum))
+	}
+	if err := berr.ErrorOrNil(); err != nil {
+		return fmt.Errorf("upload rejected: %w", err)
+	}
+	return nil
+}
+
+// limitReader returns the reader of req, failing once it exceeds the
+// MaxFileSize or MaxTotalSize of the UploadPolicy validators when its size
+// was not declared.
+func (s *TransferService) limitReader(req UploadRequest) io.Reader {
+	if req.Size > 0 {
+		return req.Reader
+	}
+	lr := &sizeLimitReader{r: req.Reader, name: req.Filename}
+	for _, v := range s.validators {
+		var p UploadPolicy
+		switch v := v.(type) {
+		case UploadPolicy:
+			p = v
+		case *UploadPolicy:
+			p = *v
+		default:
+			continue
+		}
+		lr.maxFile = minLimit(lr.maxFile, p.MaxFileSize)
+		lr.maxTotal = minLimit(lr.maxTotal, p.MaxTotalSize)
+	}
+	if lr.maxFile == 0 && lr.maxTotal == 0 {
+		return req.Reader
+	}
+	return lr
+}
+
+// minLimit returns the stricter of two limits, 0 meaning none.
+func minLimit(a, b int64) int64 {
+	if a == 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+type sizeLimitReader struct {
+	r                 io.Reader
+	name              string
+	n                 int64
+	maxFile, maxTotal int64
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	switch {
+	case l.maxFile > 0 && l.n > l.maxFile:
+		return n, fmt.Errorf("upload rejected: %s: %w (more than %s)", l.name, ErrFileTooLarge, humanize.Bytes(l.maxFile))
+	case l.maxTotal > 0 && l.n > l.maxTotal:
+		return n, fmt.Errorf("upload rejected: %s: %w (more than %s)", l.name, ErrUploadTooLarge, humanize.Bytes(l.maxTotal))
+	}
+	return n, err
+}
+
+// walkUpload passes every file of req.Input to checkFile and records the
+// empty directories in sum, streaming the listing like the upload does.
+func (s *TransferService) walkUpload(ctx context.Context, req UploadRequest, sum *UploadSummary, checkFile func(UploadFile)) error {
+	rel := func(path string) (string, error) {
+		rel, err := filepath.Rel(req.Input, path)
+		return filepath.ToSlash(rel), err
+	}
+	return utils.WalkFiles(ctx, req.Input, func(path string, d fs.DirEntry) error {
+		r, err := rel(path)
+		if err != nil {
+			return err
+		}
+		if r == "." {
+			r = d.Name()
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		checkFile(UploadFile{Path: path, RelPath: r, Size: info.Size()})
+		return nil
+	}, func(path string) error {
+		r, err := rel(path)
+		if err != nil {
+			return err
+		}
+		sum.EmptyDirs = append(sum.EmptyDirs, r)
+		return nil
+	})
+}
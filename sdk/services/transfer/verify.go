@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/utils"
+)
+
+// expectedChecksums returns the checksums recorded in status.files of the
+// entities in body (one entity or a page), by spec.path and then by file
+// path; a single file uploaded alone is recorded by name.
+func expectedChecksums(body []byte) map[string]map[string]utils.Checksums {
+	var raw struct {
+		Content []json.RawMessage `json:"content"`
+	}
+	entities := []json.RawMessage{body}
+	if err := json.Unmarshal(body, &raw); err == nil && raw.Content != nil {
+		entities = raw.Content
+	}
+	out := map[string]map[string]utils.Checksums{}
+	for _, e := range entities {
+		var ent struct {
+			Spec struct {
+				Path string `json:"path"`
+			} `json:"spec"`
+			Status struct {
+				Files []map[string]interface{} `json:"files"`
+			} `json:"status"`
+		}
+		if json.Unmarshal(e, &ent) != nil || ent.Spec.Path == "" {
+			continue
+		}
+		files := map[string]utils.Checksums{}
+		for _, f := range ent.Status.Files {
+			sums := utils.ChecksumsFromFileInfo(f)
+			if sums.IsZero() {
+				continue
+			}
+			name, _ := f["path"].(string)
+			if name == "" {
+				name, _ = f["name"].(string)
+			}
+			files[name] = sums
+		}
+		out[ent.Spec.Path] = files
+	}
+	return out
+}
+
+// etagObject is the S3 object whose ETag stands in for the checksums of a
+// file missing from status.files.
+type etagObject struct {
+	bucket, key, etag string
+}
+
+// expectedOrETag returns sums or, when status.files has no checksum for the
+// file, the MD5 of the ETag of the object f of bucket, with the object to
+// check on a mismatch (see opaqueETag).
+func expectedOrETag(sums utils.Checksums, bucket string, f config.S3File) (utils.Checksums, *etagObject) {
+	if !sums.IsZero() {
+		return sums, nil
+	}
+	return utils.ChecksumsFromETag(f.ETag), &etagObject{bucket: bucket, key: f.Path, etag: f.ETag}
+}
+
+// opaqueETag tells whether the ETag of an object, which a listing reports
+// without the encryption, is not its MD5 because it is encrypted with
+// SSE-KMS or SSE-C. It costs a HEAD, so it is asked only on a mismatch.
+func (s *TransferService) opaqueETag(ctx context.Context, bucket, key, etag string) bool {
+	info, err := s.s3.HeadObject(ctx, bucket, key)
+	return err == nil && utils.ChecksumsFromETag(etag, info.SSE).IsZero()
+}
+
+// checkFile verifies the local file of info against the remote size (when
+// not negative) and the expected checksums (when known), and records the
+// outcome in info.
+func checkFile(info *DownloadInfo, size int64, expected utils.Checksums) error {
+	if size >= 0 && info.Size != size {
+		return fmt.Errorf("%s: size mismatch (expected %d, got %d)", info.Path, size, info.Size)
+	}
+	if expected.IsZero() {
+		return nil
+	}
+	sums, err := utils.VerifyFile(info.Path, expected)
+	if err != nil {
+		return err
+	}
+	info.Hash = sums.Hash()
+	info.Verified = true
+	return nil
+}
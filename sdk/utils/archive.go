@@ -0,0 +1,240 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/* ------------ archives (tar.gz / zip) ------------ */
+
+// Archive formats of directories uploaded as a single object.
+const (
+	ArchiveTarGz = "tar.gz"
+	ArchiveZip   = "zip"
+)
+
+// ArchiveExt returns the file extension of format (".tar.gz", ".zip"), or
+// an error when the format is not supported.
+func ArchiveExt(format string) (string, error) {
+	switch format {
+	case ArchiveTarGz, ArchiveZip:
+		return "." + format, nil
+	}
+	return "", fmt.Errorf("unsupported archive format %q", format)
+}
+
+// ArchiveFormatOf returns the archive format of a file name by extension
+// (.tar.gz, .tgz, .zip), "" when it isn't an archive.
+func ArchiveFormatOf(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return ArchiveTarGz
+	case strings.HasSuffix(name, ".zip"):
+		return ArchiveZip
+	}
+	return ""
+}
+
+// WriteArchive streams the files under root (a directory, or a single file)
+// to w as an archive of format, with paths relative to root. Directories are
+// kept, empty ones included; symlinks and other special files are skipped.
+func WriteArchive(w io.Writer, root, format string) error {
+	if _, err := ArchiveExt(format); err != nil {
+		return err
+	}
+	var add func(rel string, info fs.FileInfo, path string) error
+	var closeAll func() error
+	if format == ArchiveZip {
+		zw := zip.NewWriter(w)
+		add = func(rel string, info fs.FileInfo, path string) error {
+			hdr, err := zip.FileInfoHeader(info)
+			if err != nil {
+				return err
+			}
+			hdr.Name = rel
+			if info.IsDir() {
+				hdr.Name += "/"
+			} else {
+				hdr.Method = zip.Deflate
+			}
+			dst, err := zw.CreateHeader(hdr)
+			if err != nil || info.IsDir() {
+				return err
+			}
+			return copyFile(dst, path)
+		}
+		closeAll = zw.Close
+	} else {
+		gz := gzip.NewWriter(w)
+		tw := tar.NewWriter(gz)
+		add = func(rel string, info fs.FileInfo, path string) error {
+			hdr, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			hdr.Name = rel
+			if info.IsDir() {
+				hdr.Name += "/"
+			}
+			if err := tw.WriteHeader(hdr); err != nil || info.IsDir() {
+				return err
+			}
+			return copyFile(tw, path)
+		}
+		closeAll = func() error {
+			if err := tw.Close(); err != nil {
+				return err
+			}
+			return gz.Close()
+		}
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			if d.IsDir() {
+				return nil
+			}
+			rel = d.Name()
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return add(filepath.ToSlash(rel), info, path)
+	})
+	if err != nil {
+		return fmt.Errorf("archive of %s failed: %w", root, err)
+	}
+	return closeAll()
+}
+
+func copyFile(dst io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(dst, f)
+	return err
+}
+
+// ExtractTarGz extracts a tar.gz read from r into dest as it streams, and
+// returns the paths of the files written. Entries escaping dest are an
+// error; links and special files are skipped.
+func ExtractTarGz(r io.Reader, dest string) ([]string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tar.gz: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	var files []string
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return files, fmt.Errorf("invalid tar.gz: %w", err)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if _, err := extractEntry(dest, hdr.Name, 0, true, nil); err != nil {
+				return files, err
+			}
+		case tar.TypeReg:
+			p, err := extractEntry(dest, hdr.Name, hdr.FileInfo().Mode(), false, tr)
+			if err != nil {
+				return files, err
+			}
+			files = append(files, p)
+		}
+	}
+}
+
+// ExtractZip extracts the zip archive at path into dest, and returns the
+// paths of the files written. Entries escaping dest are an error.
+func ExtractZip(path, dest string) ([]string, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip: %w", err)
+	}
+	defer zr.Close()
+	var files []string
+	for _, f := range zr.File {
+		mode := f.Mode()
+		if !mode.IsDir() && !mode.IsRegular() {
+			continue
+		}
+		p, err := func() (string, error) {
+			if mode.IsDir() {
+				return extractEntry(dest, f.Name, 0, true, nil)
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return "", err
+			}
+			defer rc.Close()
+			return extractEntry(dest, f.Name, mode, false, rc)
+		}()
+		if err != nil {
+			return files, err
+		}
+		if !mode.IsDir() {
+			files = append(files, p)
+		}
+	}
+	return files, nil
+}
+
+// extractEntry writes the archive entry name under dest: a directory, or a
+// file with the content of r.
+func extractEntry(dest, name string, mode fs.FileMode, dir bool, r io.Reader) (string, error) {
+	rel := filepath.FromSlash(strings.TrimSuffix(name, "/"))
+	if rel == "" || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("archive entry %q escapes the destination", name)
+	}
+	p := filepath.Join(dest, rel)
+	if dir {
+		return p, os.MkdirAll(p, 0o755)
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return "", err
+	}
+	perm := mode.Perm()
+	if perm == 0 {
+		perm = 0o644
+	}
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	return p, f.Close()
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "model")
+	for _, rel := range []string{"weights.bin", "conf/params.yaml"} {
+		p := filepath.Join(src, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(rel), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(src, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []string{ArchiveTarGz, ArchiveZip} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteArchive(&buf, src, format); err != nil {
+				t.Fatalf("archive failed: %v", err)
+			}
+			dest := t.TempDir()
+			var files []string
+			var err error
+			if format == ArchiveZip {
+				path := filepath.Join(t.TempDir(), "model.zip")
+				if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				files, err = ExtractZip(path, dest)
+			} else {
+				files, err = ExtractTarGz(&buf, dest)
+			}
+			if err != nil {
+				t.Fatalf("extract failed: %v", err)
+			}
+			sort.Strings(files)
+			if len(files) != 2 || files[0] != filepath.Join(dest, "conf", "params.yaml") {
+				t.Fatalf("unexpected files %v", files)
+			}
+			if got, _ := os.ReadFile(filepath.Join(dest, "weights.bin")); string(got) != "weights.bin" {
+				t.Fatalf("unexpected content %q", got)
+			}
+			if st, err := os.Stat(filepath.Join(dest, "empty")); err != nil || !st.IsDir() {
+				t.Fatalf("empty directory not kept: %v", err)
+			}
+		})
+	}
+
+	if ArchiveFormatOf("data.TGZ") != ArchiveTarGz || ArchiveFormatOf("data.csv") != "" {
+		t.Fatal("unexpected archive format detection")
+	}
+}
+
+func TestExtractRejectsEscapingEntries(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("../evil.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("x"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "evil.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(dir, "out")
+	if _, err := ExtractZip(path, dest); err == nil || !strings.Contains(err.Error(), "escapes") {
+		t.Fatalf("expected an escaping entry error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "evil.txt")); !os.IsNotExist(err) {
+		t.Fatal("escaping entry was written")
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ItemError is the failure of a single item (file, key, entity) of a batch.
+type ItemError struct {
+	Item string
+	Err  error
+}
+
+func (e ItemError) Error() string { return fmt.Sprintf("%s: %v", e.Item, e.Err) }
+func (e ItemError) Unwrap() error { return e.Err }
+
+// BatchError collects the per-item failures of a batch operation (directory
+// transfers, multi-artifact downloads, bulk deletes) that keeps going after an
+// item fails. It is safe for concurrent use; the zero value is ready to use.
+//
+// errors.Is / errors.As see every wrapped item error.
+type BatchError struct {
+	// Op names the operation, e.g. "download".
+	Op string
+
+	mu       sync.Mutex
+	failures []ItemError
+}
+
+// NewBatchError returns an empty BatchError for op.
+func NewBatchError(op string) *BatchError {
+	return &BatchError{Op: op}
+}
+
+// Add records a failure; nil errors are ignored. Nested batch errors are
+// flattened so the caller sees one list of items.
+func (e *BatchError) Add(item string, err error) {
+	if err == nil {
+		return
+	}
+	var nested *BatchError
+	if errors.As(err, &nested) && nested != e {
+		for _, f := range nested.Failures() {
+			e.Add(f.Item, f.Err)
+		}
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures = append(e.failures, ItemError{Item: item, Err: err})
+}
+
+// Failures returns a copy of the recorded failures, in insertion order.
+func (e *BatchError) Failures() []ItemError {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]ItemError(nil), e.failures...)
+}
+
+// Len returns the number of failed items.
+func (e *BatchError) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.failures)
+}
+
+// ErrorOrNil returns e when at least one item failed, nil otherwise, so it
+// can be returned directly.
+func (e *BatchError) ErrorOrNil() error {
+	if e == nil || e.Len() == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *BatchError) Error() string {
+	failures := e.Failures()
+	var b strings.Builder
+	if e.Op != "" {
+		b.WriteString(e.Op)
+		b.WriteString(": ")
+	}
+	if len(failures) == 1 {
+		b.WriteString("1 item failed: ")
+	} else {
+		fmt.Fprintf(&b, "%d items failed: ", len(failures))
+	}
+	for i, f := range failures {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(f.Error())
+	}
+	return b.String()
+}
+
+// Unwrap exposes the item errors to errors.Is / errors.As.
+func (e *BatchError) Unwrap() []error {
+	failures := e.Failures()
+	errs := make([]error, len(failures))
+	for i, f := range failures {
+		errs[i] = f
+	}
+	return errs
+}
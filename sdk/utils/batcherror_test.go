@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func TestBatchError(t *testing.T) {
+	berr := NewBatchError("download")
+	if berr.ErrorOrNil() != nil {
+		t.Fatal("empty batch should be nil")
+	}
+	berr.Add("ok.csv", nil)
+	berr.Add("a.csv", fs.ErrNotExist)
+
+	nested := NewBatchError("download")
+	nested.Add("dir/b.csv", context.DeadlineExceeded)
+	berr.Add("dir/", nested)
+
+	err := berr.ErrorOrNil()
+	if err == nil || berr.Len() != 2 {
+		t.Fatalf("expected 2 failures, got %v", berr.Failures())
+	}
+	if got := err.Error(); got != "download: 2 items failed: a.csv: file does not exist; dir/b.csv: context deadline exceeded" {
+		t.Fatalf("unexpected message %q", got)
+	}
+	if !errors.Is(err, fs.ErrNotExist) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("item errors should be visible to errors.Is")
+	}
+	var item ItemError
+	if !errors.As(err, &item) || item.Item != "a.csv" {
+		t.Fatalf("errors.As ItemError: %+v", item)
+	}
+}
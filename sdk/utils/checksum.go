@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+/* ------------ checksums of transferred files ------------ */
+
+// Checksums are the digests of a file, hex encoded; empty when unknown.
+type Checksums struct {
+	SHA256 string
+	MD5    string
+}
+
+// IsZero reports whether no digest is known.
+func (c Checksums) IsZero() bool {
+	return c.SHA256 == "" && c.MD5 == ""
+}
+
+// Hash returns the SHA-256 as recorded in status.files ("sha256:<hex>").
+func (c Checksums) Hash() string {
+	if c.SHA256 == "" {
+		return ""
+	}
+	return "sha256:" + c.SHA256
+}
+
+// ComputeChecksums reads r to the end and returns its SHA-256 and MD5.
+func ComputeChecksums(r io.Reader) (Checksums, error) {
+	s, m := sha256.New(), md5.New()
+	if _, err := io.Copy(io.MultiWriter(s, m), r); err != nil {
+		return Checksums{}, err
+	}
+	return Checksums{SHA256: hex.EncodeToString(s.Sum(nil)), MD5: hex.EncodeToString(m.Sum(nil))}, nil
+}
+
+// FileChecksums returns the checksums of the file at path.
+func FileChecksums(path string) (Checksums, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Checksums{}, err
+	}
+	defer f.Close()
+	c, err := ComputeChecksums(f)
+	if err != nil {
+		return Checksums{}, fmt.Errorf("checksum of %s failed: %w", path, err)
+	}
+	return c, nil
+}
+
+// ChecksumsFromFileInfo reads the checksums of a status.files entry: "hash"
+// ("sha256:<hex>", or a bare hex SHA-256) and "md5".
+func ChecksumsFromFileInfo(info map[string]interface{}) Checksums {
+	var c Checksums
+	if h, _ := info["hash"].(string); h != "" {
+		algo, sum, ok := strings.Cut(h, ":")
+		switch {
+		case !ok:
+			c.SHA256 = strings.ToLower(h)
+		case strings.EqualFold(algo, "sha256"):
+			c.SHA256 = strings.ToLower(sum)
+		case strings.EqualFold(algo, "md5"):
+			c.MD5 = strings.ToLower(sum)
+		}
+	}
+	if m, _ := info["md5"].(string); m != "" {
+		c.MD5 = strings.ToLower(m)
+	}
+	return c
+}
+
+// ChecksumsFromETag returns the MD5 carried by an S3 ETag. The ETag of a
+// multipart object, or of one encrypted with SSE-KMS or SSE-C, isn't the MD5
+// of its content: it yields no checksum. sse is the encryption of the
+// object (config.ObjectInfo.SSE); listings don't report it, so when it is
+// omitted a mismatch should be confirmed with a HEAD of the object.
+func ChecksumsFromETag(etag string, sse ...string) Checksums {
+	var enc string
+	if len(sse) > 0 {
+		enc = sse[0]
+	}
+	if !config.ETagIsMD5(etag, enc) {
+		return Checksums{}
+	}
+	return Checksums{MD5: strings.ToLower(strings.Trim(etag, `"`))}
+}
+
+// ChecksumError reports a file whose content doesn't match the expected
+// checksum.
+type ChecksumError struct {
+	Path      string
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s: %s mismatch (expected %s, got %s)", e.Path, e.Algorithm, e.Expected, e.Actual)
+}
+
+// VerifyFile checks the file at path against expected, with the SHA-256 when
+// known, else the MD5; a *ChecksumError tells which one failed. It returns
+// the checksums of the file.
+func VerifyFile(path string, expected Checksums) (Checksums, error) {
+	actual, err := FileChecksums(path)
+	if err != nil {
+		return actual, err
+	}
+	switch {
+	case expected.SHA256 != "" && expected.SHA256 != actual.SHA256:
+		return actual, &ChecksumError{Path: path, Algorithm: "sha256", Expected: expected.SHA256, Actual: actual.SHA256}
+	case expected.SHA256 == "" && expected.MD5 != "" && expected.MD5 != actual.MD5:
+		return actual, &ChecksumError{Path: path, Algorithm: "md5", Expected: expected.MD5, Actual: actual.MD5}
+	}
+	return actual, nil
+}
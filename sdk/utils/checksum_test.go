@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestChecksums(t *testing.T) {
+	sums, err := ComputeChecksums(strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sums.SHA256 != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" || sums.MD5 != "5d41402abc4b2a76b9719d911017c592" {
+		t.Fatalf("unexpected checksums %+v", sums)
+	}
+
+	info := map[string]interface{}{"hash": "SHA256:" + strings.ToUpper(sums.SHA256), "md5": sums.MD5}
+	if got := ChecksumsFromFileInfo(info); got != sums {
+		t.Fatalf("file info checksums %+v, want %+v", got, sums)
+	}
+	if got := ChecksumsFromETag(`"` + sums.MD5 + `"`); got.MD5 != sums.MD5 {
+		t.Fatalf("expected the md5 of a simple etag, got %+v", got)
+	}
+	if got := ChecksumsFromETag(`"` + sums.MD5 + `-3"`); !got.IsZero() {
+		t.Fatalf("a multipart etag carries no md5, got %+v", got)
+	}
+	for _, sse := range []string{config.SSEKMS, config.SSECustomer} {
+		if got := ChecksumsFromETag(`"`+sums.MD5+`"`, sse); !got.IsZero() {
+			t.Fatalf("the etag of a %s object carries no md5, got %+v", sse, got)
+		}
+	}
+	if got := ChecksumsFromETag(`"`+sums.MD5+`"`, config.SSES3); got.MD5 != sums.MD5 {
+		t.Fatalf("expected the md5 of an SSE-S3 etag, got %+v", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "f.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyFile(path, Checksums{MD5: sums.MD5}); err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	_, err = VerifyFile(path, Checksums{SHA256: strings.Repeat("0", 64)})
+	var mismatch *ChecksumError
+	if !errors.As(err, &mismatch) || mismatch.Algorithm != "sha256" {
+		t.Fatalf("expected a sha256 mismatch, got %v", err)
+	}
+}
@@ -7,6 +7,7 @@ package utils
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -18,24 +19,20 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/spf13/viper"
 	"gopkg.in/ini.v1"
-)
 
-func getIniPath() string {
-	iniPath, err := os.UserHomeDir()
-	if err != nil {
-		iniPath = "."
-	}
-	return iniPath + string(os.PathSeparator) + IniName
-}
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/i18n"
+)
 
 func LoadIni(createOnMissing bool) *ini.File {
 	cfg, err := ini.Load(getIniPath())
 	if err != nil {
 		if !createOnMissing {
-			log.Printf("Failed to read ini file: %v\n", err)
+			log.Println(i18n.Messagef(i18n.MsgIniReadFailed, err))
 			os.Exit(1)
 		}
 		return ini.Empty()
@@ -44,8 +41,17 @@ func LoadIni(createOnMissing bool) *ini.File {
 }
 
 func SaveIni(cfg *ini.File) {
-	if err := cfg.SaveTo(getIniPath()); err != nil {
-		log.Printf("Failed to update ini file: %v\n", err)
+	if err := protectSecrets(cfg); err != nil {
+		log.Println(i18n.Messagef(i18n.MsgIniUpdateFailed, err))
+		os.Exit(1)
+	}
+	path := writableIniPath()
+	if err := ensureIniDir(path); err != nil {
+		log.Println(i18n.Messagef(i18n.MsgIniUpdateFailed, err))
+		os.Exit(1)
+	}
+	if err := cfg.SaveTo(path); err != nil {
+		log.Println(i18n.Messagef(i18n.MsgIniUpdateFailed, err))
 		os.Exit(1)
 	}
 }
@@ -88,13 +94,27 @@ func TranslateFormat(format string) string {
 	}
 }
 
+// ResourceOptions is an alias of config.ResourceOptions.
+type ResourceOptions = config.ResourceOptions
+
+// RegisterResource registers a custom resource kind (e.g. "triggers") and
+// its aliases in the config registry, so it can be addressed without
+// forking the SDK.
+func RegisterResource(plural string, aliases []string, opts ResourceOptions) error {
+	return config.RegisterResource(plural, aliases, opts)
+}
+
 func TranslateEndpoint(resource string) string {
+	if plural, err := config.ResolveResource(resource); err == nil {
+		return plural
+	}
+	// entries added to Resources directly
 	for key, val := range Resources {
 		if key == resource || slices.Contains(val, resource) {
 			return key
 		}
 	}
-	log.Printf("Resource '%v' is not supported.\n", resource)
+	log.Println(i18n.Messagef(i18n.MsgResourceNotSupported, resource))
 	os.Exit(1)
 	return ""
 }
@@ -111,23 +131,65 @@ func GetFirstIfList(m map[string]interface{}) (map[string]interface{}, error) {
 }
 
 func WaitForConfirmation(msg string) {
+	ok, err := WaitForConfirmationContext(context.Background(), msg)
+	if err != nil {
+		log.Println(i18n.Messagef(i18n.MsgInputReadFailed, err))
+		os.Exit(1)
+	}
+	if !ok {
+		log.Println(i18n.Message(i18n.MsgInputCancelling))
+		os.Exit(0)
+	}
+}
+
+// stdinLines delivers the lines of stdin, read by a single goroutine for the
+// whole process: a prompt given up on cancellation leaves the next line to
+// the following prompt instead of a reader of its own. After the first read
+// error the channel is closed and the error kept in stdinErr.
+var (
+	stdinErr   error
+	stdinLines = sync.OnceValue(func() <-chan string {
+		ch := make(chan string)
+		go func() {
+			defer close(ch)
+			buf := bufio.NewReader(os.Stdin)
+			for {
+				line, err := buf.ReadString('\n')
+				if err != nil && line == "" {
+					stdinErr = err
+					return
+				}
+				ch <- line
+			}
+		}()
+		return ch
+	})
+)
+
+// WaitForConfirmationContext asks msg on stdin until the answer is y (or empty)
+// or n, and reports whether the user confirmed. It returns ctx.Err() as soon as
+// ctx is done; a line typed later goes to the next prompt.
+func WaitForConfirmationContext(ctx context.Context, msg string) (bool, error) {
+	lines := stdinLines()
 	for {
-		buf := bufio.NewReader(os.Stdin)
 		log.Printf("%s", msg)
-		userInput, err := buf.ReadBytes('\n')
-		if err != nil {
-			log.Printf("Error in reading user input: %v\n", err)
-			os.Exit(1)
+		var line string
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case l, ok := <-lines:
+			if !ok {
+				return false, stdinErr
+			}
+			line = l
 		}
-		yn := strings.TrimSpace(string(userInput))
-		switch strings.ToLower(yn) {
+		switch strings.ToLower(strings.TrimSpace(line)) {
 		case "y", "":
-			return
+			return true, nil
 		case "n":
-			log.Println("Cancelling.")
-			os.Exit(0)
+			return false, nil
 		default:
-			log.Println("Invalid input, must be y or n")
+			log.Println(i18n.Message(i18n.MsgInputInvalidYesNo))
 		}
 	}
 }
@@ -143,45 +205,48 @@ func PrintCommentForYaml(args ...string) {
 		}
 	}
 	if len(parts) > 0 {
-		fmt.Printf("#   with parameters: %v\n", strings.Join(parts, " "))
+		fmt.Println(i18n.Messagef(i18n.MsgYamlCommentWithParameters, strings.Join(parts, " ")))
 	}
 }
 
+// CheckApiLevel prints and exits when the configured API level is outside
+// [min, max].
+//
+// Deprecated: use CrudService.Capabilities and Capabilities.Supports /
+// CheckLevel, or ApiLevelInRange, which return instead of exiting.
 func CheckApiLevel(apiLevelKey string, min, max int) {
-	fmt.Printf("Checking API level for %v command...\n", viper.GetString(apiLevelKey))
+	fmt.Println(i18n.Messagef(i18n.MsgApiLevelChecking, viper.GetString(apiLevelKey)))
 
 	apiLevelStr := viper.GetString(apiLevelKey)
 	if apiLevelStr == "" {
-		log.Println("ERROR: Unable to check compatibility, environment does not specify API level.")
+		log.Println(i18n.Message(i18n.MsgApiLevelMissing))
 		os.Exit(1)
 	}
 
 	apiLevel, err := strconv.Atoi(apiLevelStr)
 	if err != nil {
-		log.Printf("ERROR: API level %v is not an integer.\n", apiLevelStr)
+		log.Println(i18n.Messagef(i18n.MsgApiLevelNotInteger, apiLevelStr))
 		os.Exit(1)
 	}
 
-	inRange := true
-	if min != 0 && apiLevel < min {
-		inRange = false
-	}
-	if max != 0 && apiLevel > max {
-		inRange = false
-	}
-	if !inRange {
-		interval := "level"
-		if min != 0 {
-			interval = fmt.Sprintf("%v <= %s", min, interval)
-		}
-		if max != 0 {
-			interval = fmt.Sprintf("%s <= %v", interval, max)
-		}
-		log.Printf("ERROR: API level %v is not within the supported interval: %v\n", apiLevel, interval)
+	r := config.LevelRange{Min: min, Max: max}
+	if !r.Contains(apiLevel) {
+		log.Println(i18n.Messagef(i18n.MsgApiLevelOutOfRange, apiLevel, r))
 		os.Exit(1)
 	}
 }
 
+// ApiLevelInRange checks the API level stored under apiLevelKey (as written
+// by the environment refresh) against [min, max] without exiting.
+func ApiLevelInRange(apiLevelKey string, min, max int) error {
+	apiLevel, err := config.ParseAPILevel(viper.GetString(apiLevelKey))
+	if err != nil {
+		return err
+	}
+	caps := config.Capabilities{APILevel: apiLevel}
+	return caps.CheckLevel(config.LevelRange{Min: min, Max: max})
+}
+
 func GetStringValue(m map[string]interface{}, key string) string {
 	if v, ok := m[key]; ok {
 		if s, ok := v.(string); ok {
@@ -192,7 +257,19 @@ func GetStringValue(m map[string]interface{}, key string) string {
 }
 
 func FetchConfig(configURL string) (map[string]interface{}, error) {
-	resp, err := http.Get(configURL)
+	return FetchConfigContext(context.Background(), configURL)
+}
+
+// FetchConfigContext is FetchConfig honoring ctx cancellation and deadlines.
+func FetchConfigContext(ctx context.Context, configURL string) (map[string]interface{}, error) {
+	if Offline() {
+		return nil, &config.OfflineError{Op: "GET " + configURL}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, configURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -221,11 +298,11 @@ func PrintResponseState(resp []byte) error {
 	}
 	if status, ok := m["status"].(map[string]interface{}); ok {
 		if state, ok := status["state"].(string); ok {
-			log.Printf("Core response successful, new state: %v\n", state)
+			log.Println(i18n.Messagef(i18n.MsgStateUpdated, state))
 			return nil
 		}
 	}
-	log.Println("WARNING: core response successful, but unable to confirm new state.")
+	log.Println(i18n.Message(i18n.MsgStateUnconfirmed))
 	return nil
 }
 
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBlockingHelpersHonorContext(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := FetchConfigContext(ctx, srv.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("FetchConfigContext: expected deadline exceeded, got %v", err)
+	}
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel2()
+	dest := filepath.Join(t.TempDir(), "out.bin")
+	if err := DownloadHTTPFileWithOptions(ctx2, srv.URL, dest, DownloadOptions{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("DownloadHTTPFileWithOptions: expected deadline exceeded, got %v", err)
+	}
+}
+
+// testStdin replaces stdin with a pipe for the whole test binary, as stdin
+// is read by a single goroutine once the first prompt is asked.
+var testStdin = sync.OnceValue(func() *os.File {
+	r, w, err := os.Pipe()
+	if err != nil {
+		panic(err)
+	}
+	os.Stdin = r
+	return w
+})
+
+func TestWaitForConfirmationContext(t *testing.T) {
+	w := testStdin()
+
+	// answers are read until a valid one arrives
+	if _, err := w.WriteString("maybe\nN\n"); err != nil {
+		t.Fatal(err)
+	}
+	ok, err := WaitForConfirmationContext(context.Background(), "continue? ")
+	if err != nil || ok {
+		t.Fatalf("expected refusal, got %v, %v", ok, err)
+	}
+
+	// nothing typed: cancellation wins
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := WaitForConfirmationContext(ctx, "continue? "); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled, got %v", err)
+	}
+
+	// the line typed after the cancellation goes to the next prompt
+	if _, err := w.WriteString("y\n"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if ok, err := WaitForConfirmationContext(ctx, "continue? "); err != nil || !ok {
+		t.Fatalf("expected confirmation, got %v, %v", ok, err)
+	}
+}
+
+func TestRegisterResourceConcurrentLookup(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 50 {
+			if err := RegisterResource(fmt.Sprintf("widgets%d", i), []string{fmt.Sprintf("widget%d", i)}, ResourceOptions{}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for range 50 {
+		if got := TranslateEndpoint("artifact"); got != "artifacts" {
+			t.Fatalf("unexpected endpoint %q", got)
+		}
+	}
+	<-done
+	if got := TranslateEndpoint("widget7"); got != "widgets7" {
+		t.Fatalf("registered alias not resolved: %q", got)
+	}
+}
@@ -4,8 +4,10 @@
 
 package utils
 
+import "github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+
 const (
-	IniName                                 = ".dhcore.ini"
+	IniName                                 = config.LegacyIniName
 	IniSource                               = "ini_source"
 	CurrentEnvironment                      = "current_environment"
 	UpdatedEnvKey                           = "updated_environment"
@@ -19,6 +21,16 @@ const (
 	DhCoreUser                              = "dhcore_user"
 	DhCorePassword                          = "dhcore_password"
 	DhCoreRefreshToken                      = "dhcore_refresh_token"
+	DhCoreExpiresIn                         = "dhcore_expires_in"
+	CredentialStoreKey                      = "credential_store"
+	EnvironmentTTLKey                       = "environment_ttl"
+	OfflineKey                              = "dhcore_offline"
+	AwsAccessKeyId                          = "aws_access_key_id"
+	AwsSecretAccessKey                      = "aws_secret_access_key"
+	AwsSessionToken                         = "aws_session_token"
+	AwsCredentialsExpiration                = "aws_credentials_expiration"
+	AwsRegion                               = "aws_region"
+	AwsEndpointURL                          = "aws_endpoint_url"
 	Oauth2TokenEndpoint                     = "oauth2_token_endpoint"
 	Oauth2UserinfoEndpoint                  = "oauth2_userinfo_endpoint"
 	Oauth2AuthorizationEndpoint             = "oauth2_authorization_endpoint"
@@ -30,8 +42,6 @@ const (
 	Oauth2TokenEndpointAuthMethodsSupported = "oauth2_token_endpoint_auth_methods_supported"
 	RunId                                   = "run_id"
 
-	outdatedAfterHours = 1
-
 	// API level the current version of the CLI was developed for
 	MinApiLevel = 10
 
@@ -67,6 +77,10 @@ var DhCoreMap = map[string]string{
 	"refresh_token":      DhCoreRefreshToken,
 }
 
+// Resources maps the builtin resource plurals to their aliases. Kinds added
+// with RegisterResource are only in the config registry, which is safe for
+// concurrent use: read it with config.LookupResource /
+// config.RegisteredResources.
 var Resources = map[string][]string{
 	"artifacts": {"artifact"},
 	"dataitems": {"dataitem"},
@@ -74,6 +88,8 @@ var Resources = map[string][]string{
 	"models":    {"model"},
 	"projects":  {"project"},
 	"runs":      {"run"},
+	"secrets":   {"secret"},
+	"tasks":     {"task"},
 	"workflows": {"workflow"},
 	"logs":      {"log"},
 }
@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	"github.com/zalando/go-keyring"
+	"gopkg.in/ini.v1"
+)
+
+// CredentialStore keeps the secret keys of an environment (the Config fields
+// tagged secret:"true") out of the INI file.
+type CredentialStore interface {
+	// Get returns ErrCredentialNotFound for unknown keys.
+	Get(env, key string) (string, error)
+	Set(env, key, value string) error
+	Delete(env, key string) error
+}
+
+// ErrCredentialNotFound is returned by CredentialStore.Get.
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// CredentialStoreKeyring is the value of credential_store enabling the OS
+// keyring.
+const CredentialStoreKeyring = "keyring"
+
+var (
+	credStoreMu sync.RWMutex
+	credStore   CredentialStore
+)
+
+// SetCredentialStore sets where secrets are written; nil (the default)
+// keeps them in plaintext in the INI. Secrets already in the INI are moved
+// to the store the next time the file is saved.
+func SetCredentialStore(s CredentialStore) {
+	credStoreMu.Lock()
+	defer credStoreMu.Unlock()
+	credStore = s
+}
+
+// GetCredentialStore returns the store set by SetCredentialStore.
+func GetCredentialStore() CredentialStore {
+	credStoreMu.RLock()
+	defer credStoreMu.RUnlock()
+	return credStore
+}
+
+// KeyringStore stores credentials in the OS keyring: macOS Keychain, Windows
+// Credential Manager or Secret Service. Items are named "<env>/<key>".
+type KeyringStore struct {
+	Service string
+}
+
+// NewKeyringStore returns the keyring store of the CLI.
+func NewKeyringStore() *KeyringStore {
+	return &KeyringStore{Service: "dhcore"}
+}
+
+func (k *KeyringStore) Get(env, key string) (string, error) {
+	v, err := keyring.Get(k.Service, env+"/"+key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrCredentialNotFound
+	}
+	return v, err
+}
+
+func (k *KeyringStore) Set(env, key, value string) error {
+	return keyring.Set(k.Service, env+"/"+key, value)
+}
+
+func (k *KeyringStore) Delete(env, key string) error {
+	err := keyring.Delete(k.Service, env+"/"+key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// useCredentialStore enables the keyring when mode (the credential_store
+// setting) asks for it and no store was set by the caller.
+func useCredentialStore(mode string) {
+	if mode == CredentialStoreKeyring && GetCredentialStore() == nil {
+		SetCredentialStore(NewKeyringStore())
+	}
+}
+
+// protectSecrets moves the secret keys of every section of cfg to the
+// credential store or, without one, encrypts them when an INI passphrase is
+// configured (see SetIniPassphrase). It runs before cfg is saved.
+func protectSecrets(cfg *ini.File) error {
+	store := GetCredentialStore()
+	var pass []byte
+	if store == nil {
+		var err error
+		if pass, err = iniPassphrase(); err != nil || pass == nil {
+			return err
+		}
+	}
+	for _, sec := range cfg.Sections() {
+		for _, key := range SecretKeys() {
+			if !sec.HasKey(key) {
+				continue
+			}
+			v := sec.Key(key).String()
+			if strings.HasPrefix(v, encryptedPrefix) {
+				continue
+			}
+			if store == nil {
+				if v == "" {
+					continue
+				}
+				enc, err := encryptSecret(pass, v)
+				if err != nil {
+					return fmt.Errorf("failed to encrypt %s: %w", key, err)
+				}
+				sec.Key(key).SetValue(enc)
+				continue
+			}
+			if v != "" {
+				if err := store.Set(sec.Name(), key, v); err != nil {
+					return fmt.Errorf("failed to store %s: %w", key, err)
+				}
+			}
+			sec.DeleteKey(key)
+		}
+	}
+	return nil
+}
+
+// loadSecrets adds to values the secrets of env kept in the credential
+// store; values already set (e.g. a plaintext INI) win.
+func loadSecrets(env string, values map[string]string) {
+	store := GetCredentialStore()
+	if store == nil {
+		return
+	}
+	for _, key := range SecretKeys() {
+		if values[key] != "" {
+			continue
+		}
+		if v, err := store.Get(env, key); err == nil && v != "" {
+			values[key] = v
+		}
+	}
+}
+
+// ResolveSecrets decrypts the encrypted values of an INI section merged
+// with [DEFAULT], and fills in the secret keys kept in the credential store
+// for env and for DEFAULT. It is the config.LoadOptions.ResolveSecrets of
+// the CLI environment.
+func ResolveSecrets(env string, values map[string]string) error {
+	if err := decryptSecrets(values); err != nil {
+		return err
+	}
+	if env != "" && env != "DEFAULT" {
+		loadSecrets(env, values)
+	}
+	loadSecrets("DEFAULT", values)
+	return nil
+}
+
+// ClearSecrets removes the secret keys of the current environment from
+// Viper, the INI and the credential store, e.g. on logout.
+func ClearSecrets() error {
+	env := viper.GetString(CurrentEnvironment)
+	if env == "" {
+		env = resolveEnvName()
+	}
+	for _, key := range SecretKeys() {
+		viper.Set(key, "")
+	}
+
+	var errs []error
+	if store := GetCredentialStore(); store != nil {
+		for _, key := range SecretKeys() {
+			if err := store.Delete(env, key); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete %s: %w", key, err))
+			}
+		}
+	}
+	cfg, err := ini.Load(getIniPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("failed to read ini: %w", err))
+		}
+		return errors.Join(errs...)
+	}
+	if cfg.HasSection(env) {
+		sec := cfg.Section(env)
+		for _, key := range SecretKeys() {
+			sec.DeleteKey(key)
+		}
+		if err := cfg.SaveTo(writableIniPath()); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save ini: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/zalando/go-keyring"
+	"gopkg.in/ini.v1"
+)
+
+func TestKeyringCredentialStore(t *testing.T) {
+	keyring.MockInit()
+	SetCredentialStore(NewKeyringStore())
+	t.Cleanup(func() { SetCredentialStore(nil) })
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	iniPath := filepath.Join(t.TempDir(), IniName)
+	viper.Set(DhCoreEndpoint, "https://core.example")
+	viper.Set(DhCoreRefreshToken, "rt-secret")
+	viper.Set("aws_secret_access_key", "s3-secret")
+	if err := WriteIniFromStruct(iniPath, "prod"); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(iniPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "secret") || !strings.Contains(string(raw), "core.example") {
+		t.Fatalf("secrets written in plaintext or settings lost:\n%s", raw)
+	}
+	if v, err := NewKeyringStore().Get("prod", DhCoreRefreshToken); err != nil || v != "rt-secret" {
+		t.Fatalf("refresh token not in keyring: %q %v", v, err)
+	}
+
+	viper.Reset()
+	cfg, err := ini.Load(iniPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := loadIniSectionIntoViper(cfg, "prod"); err != nil {
+		t.Fatal(err)
+	}
+	if viper.GetString(DhCoreRefreshToken) != "rt-secret" || viper.GetString("aws_secret_access_key") != "s3-secret" {
+		t.Fatalf("secrets not loaded from keyring")
+	}
+}
@@ -8,9 +8,12 @@ import (
 	"context"
 
 	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/humanize"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/i18n"
 
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -21,38 +24,131 @@ import (
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
-/* ------------ logging helpers (stderr) ------------ */
+/* ------------ logging helpers (config.LoggerOr) ------------ */
 
-func infof(format string, a ...any) {
-	fmt.Fprintf(os.Stderr, "[INFO] "+format+"\n", a...)
+func infof(l *slog.Logger, id i18n.MessageID, a ...any) {
+	config.LoggerOr(l).Info(i18n.Messagef(id, a...))
 }
-func warnf(format string, a ...any) {
-	fmt.Fprintf(os.Stderr, "[WARN] "+format+"\n", a...)
+func warnf(l *slog.Logger, id i18n.MessageID, a ...any) {
+	config.LoggerOr(l).Warn(i18n.Messagef(id, a...))
+}
+
+// DownloadOptions tunes DownloadS3FileOrDirWithOptions / DownloadHTTPFileWithOptions.
+type DownloadOptions struct {
+	Verbose bool
+	// ProgressFormat selects text (default) or JSON-lines progress.
+	ProgressFormat ProgressFormat
+	// ProgressOutput receives JSON-lines events; nil means stderr.
+	ProgressOutput io.Writer
+	// BufferSize is the copy buffer of HTTP downloads; 0 means
+	// config.DefaultBufferSize. S3 downloads use the S3Client setting.
+	BufferSize int
+	// Logger receives the info and warning lines; nil means
+	// config.DefaultLogger.
+	Logger *slog.Logger
+	// Concurrency above 1 downloads S3 objects larger than PartSize as byte
+	// ranges fetched in parallel (see config.S3Client.DownloadFileRanged);
+	// PartSize 0 means the S3Client setting.
+	Concurrency int
+	PartSize    int64
+	// Parallelism is the number of files of a directory downloaded at once
+	// (default DefaultParallelism); per-file progress bars of verbose mode
+	// are drawn only when it is 1.
+	Parallelism int
 }
 
 /* ------------ HTTP (con progress “silenzioso” se possibile) ------------ */
 
 func DownloadHTTPFile(url string, destination string) error {
-	resp, err := http.Get(url)
+	return DownloadHTTPFileWithOptions(context.Background(), url, destination, DownloadOptions{})
+}
+
+func DownloadHTTPFileWithOptions(ctx context.Context, url string, destination string, opts DownloadOptions) error {
+	var jp *jsonProgress
+	if opts.ProgressFormat == ProgressJSON {
+		jp = newJSONProgress(opts.ProgressOutput, "download")
+	}
+	err := downloadHTTPFile(ctx, url, destination, jp, opts.BufferSize)
+	if err != nil && jp != nil {
+		jp.fail(destination, url, err)
+	}
+	return err
+}
+
+func downloadHTTPFile(ctx context.Context, url string, destination string, jp *jsonProgress, bufSize int) error {
+	// il download passa da destination.part, rinominato alla fine: se esiste
+	// già (download interrotto) riprende dal suo offset con una Range GET
+	// condizionata (If-Range) al validatore della prima risposta, salvato
+	// in destination.part.etag; se la risorsa è cambiata riparte da zero
+	part := destination + config.PartSuffix
+	var offset int64
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if st, err := os.Stat(part); err == nil && st.Mode().IsRegular() && st.Size() > 0 {
+		if b, err := os.ReadFile(part + config.PartETagSuffix); err == nil && len(b) > 0 {
+			offset = st.Size()
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			req.Header.Set("If-Range", string(b))
+		}
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return err
 	}
 	defer func(Body io.ReadCloser) { _ = Body.Close() }(resp.Body)
 
-	out, err := os.Create(destination)
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		flags = os.O_WRONLY | os.O_APPEND
+	case resp.StatusCode == http.StatusOK:
+		// range ignorato o risorsa cambiata: si riparte da zero, salvando il
+		// validatore per una ripresa successiva (senza, niente ripresa)
+		offset = 0
+		_ = os.Remove(part + config.PartETagSuffix)
+		if v := rangeValidator(resp.Header); v != "" {
+			if err := os.WriteFile(part+config.PartETagSuffix, []byte(v), 0o644); err != nil {
+				return err
+			}
+		}
+	case offset > 0 && (resp.StatusCode == http.StatusRequestedRangeNotSatisfiable || resp.StatusCode == http.StatusPreconditionFailed):
+		// parte completa o non più valida: si riparte da zero
+		_ = resp.Body.Close()
+		if err := os.Remove(part); err != nil {
+			return err
+		}
+		_ = os.Remove(part + config.PartETagSuffix)
+		return downloadHTTPFile(ctx, url, destination, jp, bufSize)
+	default:
+		return fmt.Errorf("download failed (status %d)", resp.StatusCode)
+	}
+
+	out, err := os.OpenFile(part, flags, 0o644)
 	if err != nil {
 		return err
 	}
 	defer out.Close()
 
 	// progress line unica anche senza verbose
-	gp := &globalProgress{}
+	gp := &globalProgress{doneBytes: offset}
 	if resp.ContentLength > 0 {
 		gp.totalKnown = true
-		gp.totalBytes = resp.ContentLength
+		gp.totalBytes = offset + resp.ContentLength
+	}
+	var hook *config.ProgressHook
+	if jp != nil {
+		hook = jp.hook(destination, url)
+		hook.OnStart(destination, gp.totalBytes)
 	}
 
-	buf := make([]byte, 1024*128) // 128KB
+	start := time.Now()
+	lastEvent := start
+	if bufSize <= 0 {
+		bufSize = config.DefaultBufferSize
+	}
+	buf := make([]byte, bufSize)
 	for {
 		n, readErr := resp.Body.Read(buf)
 		if n > 0 {
@@ -60,7 +156,12 @@ func DownloadHTTPFile(url string, destination string) error {
 				return werr
 			}
 			gp.add(int64(n))
-			gp.render(false)
+			if hook == nil {
+				gp.render(false)
+			} else if time.Since(lastEvent) >= 250*time.Millisecond {
+				lastEvent = time.Now()
+				hook.OnProgress(destination, gp.doneBytes, gp.totalBytes)
+			}
 		}
 		if readErr != nil {
 			if readErr == io.EOF {
@@ -69,10 +170,44 @@ func DownloadHTTPFile(url string, destination string) error {
 			return readErr
 		}
 	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if offset > 0 {
+		// file ripreso: verifica con l'ETag quando è l'MD5 del contenuto
+		sse := resp.Header.Get("X-Amz-Server-Side-Encryption")
+		if resp.Header.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm") != "" {
+			sse = config.SSECustomer
+		}
+		if sums := ChecksumsFromETag(resp.Header.Get("ETag"), sse); !sums.IsZero() {
+			if _, err := VerifyFile(part, sums); err != nil {
+				_ = os.Remove(part)
+				_ = os.Remove(part + config.PartETagSuffix)
+				return err
+			}
+		}
+	}
+	if err := os.Rename(part, destination); err != nil {
+		return err
+	}
+	_ = os.Remove(part + config.PartETagSuffix)
+	if hook != nil {
+		hook.OnDone(destination, gp.doneBytes, time.Since(start))
+		return nil
+	}
 	gp.done()
 	return nil
 }
 
+// rangeValidator returns the validator of a response usable in If-Range: a
+// strong ETag, else Last-Modified; empty when there is neither.
+func rangeValidator(h http.Header) string {
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return h.Get("Last-Modified")
+}
+
 /* ------------ S3: file o directory (with continuation token) ------------ */
 
 func DownloadS3FileOrDir(
@@ -82,6 +217,21 @@ func DownloadS3FileOrDir(
 	localPath string,
 	verbose bool,
 ) error {
+	return DownloadS3FileOrDirWithOptions(s3Client, ctx, parsedPath, localPath, DownloadOptions{Verbose: verbose})
+}
+
+func DownloadS3FileOrDirWithOptions(
+	s3Client *config.S3Client,
+	ctx context.Context,
+	parsedPath *ParsedPath,
+	localPath string,
+	opts DownloadOptions,
+) error {
+	verbose := opts.Verbose
+	var jp *jsonProgress
+	if opts.ProgressFormat == ProgressJSON {
+		jp = newJSONProgress(opts.ProgressOutput, "download")
+	}
 
 	bucket := parsedPath.Host
 	// normalizza: rimuovi eventuale leading "/" (alcuni artifact salvano "/xxx/..")
@@ -98,8 +248,10 @@ func DownloadS3FileOrDir(
 		// Calcolo totals SEMPRE se possibile (serve per la percentuale globale)
 		all, err := s3Client.ListFilesAll(ctx, bucket, path)
 		if err != nil {
-			warnf("Listing failed, proceeding without totals: %v", err)
-			infof("Preparing download s3://%s/%s → %s", bucket, path, displayPath(localBase))
+			if jp == nil {
+				warnf(opts.Logger, i18n.MsgDownloadListingFailed, err)
+				infof(opts.Logger, i18n.MsgDownloadPreparing, bucket, path, displayPath(localBase))
+			}
 			totalsKnown = false
 		} else {
 			totalFiles = len(all)
@@ -107,11 +259,14 @@ func DownloadS3FileOrDir(
 				totalBytes += f.Size
 			}
 			totalsKnown = totalFiles > 0 && totalBytes > 0
-			if verbose {
-				infof("Preparing download s3://%s/%s → %s (%d files, %.2f MB)",
-					bucket, path, displayPath(localBase), totalFiles, float64(totalBytes)/(1024*1024))
-			} else {
-				infof("Preparing download s3://%s/%s → %s", bucket, path, displayPath(localBase))
+			switch {
+			case jp != nil:
+				// no banners in JSON mode
+			case verbose:
+				infof(opts.Logger, i18n.MsgDownloadPreparingTotals,
+					bucket, path, displayPath(localBase), totalFiles, humanize.Bytes(totalBytes))
+			default:
+				infof(opts.Logger, i18n.MsgDownloadPreparing, bucket, path, displayPath(localBase))
 			}
 		}
 
@@ -121,59 +276,63 @@ func DownloadS3FileOrDir(
 
 		// Progress globale SOLO quando non-verbose (in verbose mantieni i dettagli per file)
 		var gp *globalProgress
-		if !verbose {
+		if !verbose && jp == nil {
 			gp = &globalProgress{
 				totalKnown: totalsKnown,
 				totalBytes: totalBytes,
 			}
 		}
 
-		err = s3Client.WalkPrefix(ctx, bucket, path, pageSize, func(obj s3types.Object) error {
-			idx++
+		// un file fallito non interrompe gli altri: gli errori sono raccolti in berr
+		berr := NewBatchError("download")
+		frames := opts.Parallelism == 1
+		downloadObject := func(n int, obj s3types.Object) {
 			key := aws.ToString(obj.Key)
 			relativePath := strings.TrimPrefix(key, path)
-			targetPath := filepath.Join(localBase, relativePath)
+			targetPath := filepath.Join(localBase, filepath.FromSlash(relativePath))
+			remote := "s3://" + bucket + "/" + key
 
 			if err := os.MkdirAll(filepath.Dir(targetPath), 0o755); err != nil {
-				return fmt.Errorf("failed to create local directory: %w", err)
+				berr.Add(relativePath, fmt.Errorf("failed to create local directory: %w", err))
+				return
 			}
 
-			if verbose {
+			var hook *config.ProgressHook
+			if jp != nil {
+				hook = jp.hook(targetPath, remote)
+			} else if verbose {
 				if totalFiles > 0 {
-					fmt.Fprintf(os.Stderr, "   [%d/%d] %s\n", idx, totalFiles, relativePath)
+					fmt.Fprintf(os.Stderr, "   [%d/%d] %s\n", n, totalFiles, relativePath)
 				} else {
-					fmt.Fprintf(os.Stderr, "   [%d] %s\n", idx, relativePath)
+					fmt.Fprintf(os.Stderr, "   [%d] %s\n", n, relativePath)
 				}
 
 				// barra di avanzamento per-file (già presente)
-				hook := &config.ProgressHook{
+				hook = &config.ProgressHook{
 					OnStart: func(k string, total int64) {
 						if total > 0 {
-							fmt.Fprintf(os.Stderr, "      └─ size: %.2f MB\n", float64(total)/(1024*1024))
+							fmt.Fprintf(os.Stderr, "      └─ %s\n", i18n.Messagef(i18n.MsgTransferSize, humanize.Bytes(total)))
 						}
 					},
 					OnProgress: func(k string, written, total int64) {
-						if total <= 0 {
+						if total <= 0 || !frames || !progressInteractive() {
 							return
 						}
 						pct := float64(written) / float64(total) * 100
-						fmt.Fprintf(os.Stderr, "\r      └─ downloading: %6.2f%%", pct)
+						fmt.Fprintf(os.Stderr, "\r      └─ %s", i18n.Messagef(i18n.MsgDownloading, pct))
 					},
 					OnDone: func(k string, total int64, took time.Duration) {
 						if total > 0 {
-							fmt.Fprintf(os.Stderr, "\r      └─ done:        100.00%% in %s\n", took.Truncate(100*time.Millisecond))
+							progressDone("      └─ ", i18n.Messagef(i18n.MsgDownloadDone, humanize.Duration(took)), frames)
 						} else {
-							fmt.Fprintf(os.Stderr, "      └─ done in %s\n", took.Truncate(100*time.Millisecond))
+							progressDone("      └─ ", i18n.Messagef(i18n.MsgTransferDoneIn, humanize.Duration(took)), false)
 						}
 					},
 				}
-				if err := s3Client.DownloadFileWithProgress(ctx, bucket, key, targetPath, hook); err != nil {
-					return fmt.Errorf("failed to download file: %w", err)
-				}
 			} else {
 				// non-verbose: progress GLOBALE su una riga
 				var prevWritten int64
-				hook := &config.ProgressHook{
+				hook = &config.ProgressHook{
 					OnProgress: func(k string, written, total int64) {
 						delta := written - prevWritten
 						if delta > 0 && gp != nil {
@@ -190,55 +349,74 @@ func DownloadS3FileOrDir(
 						}
 					},
 				}
-				if err := s3Client.DownloadFileWithProgress(ctx, bucket, key, targetPath, hook); err != nil {
-					return fmt.Errorf("failed to download file: %w", err)
+			}
+			if err := downloadS3Object(s3Client, ctx, bucket, key, targetPath, opts, hook); err != nil {
+				if jp != nil {
+					jp.fail(targetPath, remote, err)
 				}
+				berr.Add(relativePath, fmt.Errorf("failed to download file: %w", err))
 			}
+		}
 
-			return nil
+		pool := newWorkerPool(opts.Parallelism)
+		err = s3Client.WalkPrefix(ctx, bucket, path, pageSize, func(obj s3types.Object) error {
+			idx++
+			n := idx
+			return pool.submit(ctx, func() {
+				downloadObject(n, obj)
+			})
 		})
+		pool.wait()
 		if err != nil {
 			return err
 		}
-		if !verbose && gp != nil {
+		if gp != nil {
 			gp.done()
 		}
-		return nil
+		return berr.ErrorOrNil()
 	}
 
 	// Singolo file
 	key := path
+	if jp != nil {
+		remote := "s3://" + bucket + "/" + key
+		if err := downloadS3Object(s3Client, ctx, bucket, key, localPath, opts, jp.hook(localPath, remote)); err != nil {
+			jp.fail(localPath, remote, err)
+			return fmt.Errorf("S3 download failed: %w", err)
+		}
+		return nil
+	}
 	if verbose {
-		infof("Preparing download s3://%s/%s → %s", bucket, key, displayPath(localPath))
+		infof(opts.Logger, i18n.MsgDownloadPreparing, bucket, key, displayPath(localPath))
 		hook := &config.ProgressHook{
 			OnStart: func(k string, total int64) {
 				if total > 0 {
-					fmt.Fprintf(os.Stderr, "   size: %.2f MB\n", float64(total)/(1024*1024))
+					fmt.Fprintf(os.Stderr, "   %s\n", i18n.Messagef(i18n.MsgTransferSize, humanize.Bytes(total)))
 				}
 			},
 			OnProgress: func(k string, written, total int64) {
-				if total <= 0 {
+				if total <= 0 || !progressInteractive() {
 					return
 				}
 				pct := float64(written) / float64(total) * 100
-				fmt.Fprintf(os.Stderr, "\r   downloading: %6.2f%%", pct)
+				fmt.Fprintf(os.Stderr, "\r   %s", i18n.Messagef(i18n.MsgDownloading, pct))
 			},
 			OnDone: func(k string, total int64, took time.Duration) {
 				if total > 0 {
-					fmt.Fprintf(os.Stderr, "\r   done:        100.00%% in %s\n", took.Truncate(100*time.Millisecond))
+					progressDone("   ", i18n.Messagef(i18n.MsgDownloadDone, humanize.Duration(took)), true)
 				} else {
-					fmt.Fprintf(os.Stderr, "   done in %s\n", took.Truncate(100*time.Millisecond))
+					progressDone("   ", i18n.Messagef(i18n.MsgTransferDoneIn, humanize.Duration(took)), false)
 				}
 			},
 		}
-		if err := s3Client.DownloadFileWithProgress(ctx, bucket, key, localPath, hook); err != nil {
+		if err := downloadS3Object(s3Client, ctx, bucket, key, localPath, opts, hook); err != nil {
 			return fmt.Errorf("S3 download failed: %w", err)
 		}
 		return nil
 	}
 
 	// non-verbose: banner minimo + progress globale su una riga
-	infof("Preparing download s3://%s/%s → %s", bucket, key, displayPath(localPath))
+	infof(opts.Logger, i18n.MsgDownloadPreparing, bucket, key, displayPath(localPath))
 	var gp globalProgress
 	var prevWritten int64
 	hook := &config.ProgressHook{
@@ -264,7 +442,7 @@ func DownloadS3FileOrDir(
 			gp.done()
 		},
 	}
-	if err := s3Client.DownloadFileWithProgress(ctx, bucket, key, localPath, hook); err != nil {
+	if err := downloadS3Object(s3Client, ctx, bucket, key, localPath, opts, hook); err != nil {
 		return fmt.Errorf("S3 download failed: %w", err)
 	}
 	return nil
@@ -272,15 +450,34 @@ func DownloadS3FileOrDir(
 
 /* ------------ helpers ------------ */
 
+// downloadS3Object downloads one object, in parallel ranges when
+// opts.Concurrency asks for it.
+func downloadS3Object(s3Client *config.S3Client, ctx context.Context, bucket, key, localPath string, opts DownloadOptions, hook *config.ProgressHook) error {
+	if opts.Concurrency > 1 {
+		return s3Client.DownloadFileRanged(ctx, bucket, key, localPath, hook, config.RangeOptions{
+			PartSize:    opts.PartSize,
+			Concurrency: opts.Concurrency,
+		})
+	}
+	return s3Client.DownloadFileWithProgress(ctx, bucket, key, localPath, hook)
+}
+
 // Rimuove l’ultimo segmento dal path locale in modo che i file della “cartella” S3
 // vengano salvati senza includere il prefisso root.
+// Uses filepath.Dir so volume names ("C:\"), UNC/long paths ("\\?\C:\...")
+// and absolute roots are preserved; a bare name yields "" (current directory).
 func cleanLocalPath(path string) string {
-	clean := filepath.Clean(path)
-	parts := strings.Split(clean, string(os.PathSeparator))
-	if len(parts) == 1 {
+	return LocalParentDir(path)
+}
+
+// LocalParentDir returns the parent directory of a local path, or "" when the
+// path has no directory component.
+func LocalParentDir(path string) string {
+	parent := filepath.Dir(filepath.Clean(path))
+	if parent == "." {
 		return ""
 	}
-	return filepath.Join(parts[:len(parts)-1]...)
+	return parent
 }
 
 // per stampare cartelle vuote come "." invece di stringa vuota
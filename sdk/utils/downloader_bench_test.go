@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+// go test ./sdk/utils -run ^$ -bench HTTPDownload -benchmem
+func BenchmarkHTTPDownloadBufferSize(b *testing.B) {
+	const size = 32 << 20
+	payload := make([]byte, size)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer srv.Close()
+
+	for _, buf := range []int{32 << 10, 128 << 10, 1 << 20} {
+		b.Run(fmt.Sprintf("buffer=%dKB", buf>>10), func(b *testing.B) {
+			target := filepath.Join(b.TempDir(), "blob")
+			opts := DownloadOptions{BufferSize: buf, ProgressFormat: ProgressJSON, ProgressOutput: io.Discard}
+			b.SetBytes(size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := DownloadHTTPFileWithOptions(context.Background(), srv.URL, target, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
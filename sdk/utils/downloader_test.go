@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestCleanLocalPath(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{in: "data", want: ""},
+		{in: filepath.FromSlash("out/data"), want: "out"},
+		{in: filepath.FromSlash("out/nested/data"), want: filepath.FromSlash("out/nested")},
+		{in: filepath.FromSlash("out/data/"), want: "out"},
+		{in: filepath.FromSlash("./data"), want: ""},
+		// absolute paths must keep their root
+		{in: filepath.FromSlash("/tmp/out/data"), want: filepath.FromSlash("/tmp/out")},
+	}
+	for _, c := range cases {
+		if got := cleanLocalPath(c.in); got != c.want {
+			t.Errorf("cleanLocalPath(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestParsePathLocalAndURI(t *testing.T) {
+	cases := []struct {
+		in       string
+		scheme   string
+		host     string
+		path     string
+		filename string
+	}{
+		{in: "s3://datalake/proj/artifacts/id/file.csv", scheme: "s3", host: "datalake", path: "proj/artifacts/id/file.csv", filename: "file.csv"},
+		{in: "https://example.com/a/b.txt", scheme: "https", host: "example.com", path: "a/b.txt", filename: "b.txt"},
+		{in: "relative/file.csv", scheme: "file", path: "relative/file.csv", filename: "file.csv"},
+		{in: `C:\data\file.csv`, scheme: "file", path: `C:\data\file.csv`},
+		{in: "d:/data/file.csv", scheme: "file", path: "d:/data/file.csv", filename: "file.csv"},
+	}
+	for _, c := range cases {
+		pp, err := ParsePath(c.in)
+		if err != nil {
+			t.Fatalf("ParsePath(%q): %v", c.in, err)
+		}
+		if pp.Scheme != c.scheme || pp.Host != c.host || pp.Path != c.path {
+			t.Errorf("ParsePath(%q) = %+v", c.in, pp)
+		}
+		if c.filename != "" && pp.Filename != c.filename {
+			t.Errorf("ParsePath(%q).Filename = %q, want %q", c.in, pp.Filename, c.filename)
+		}
+	}
+}
+
+func TestDownloadHTTPResumesPartialFile(t *testing.T) {
+	SetProgressMode(ProgressSilent)
+	defer SetProgressMode(ProgressAuto)
+
+	payload := bytes.Repeat([]byte("0123456789"), 10_000)
+	sum := md5.Sum(payload)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	modified := time.Now().Add(-time.Minute)
+	var ranges, ifRanges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		ifRanges = append(ifRanges, r.Header.Get("If-Range"))
+		if r.URL.Path != "/" {
+			w.Header().Set("ETag", etag)
+		}
+		if r.URL.Path == "/cut" {
+			// the connection drops after 10000 bytes
+			w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+			_, _ = w.Write(payload[:10_000])
+			return
+		}
+		http.ServeContent(w, r, "data.bin", modified, bytes.NewReader(payload))
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "data.bin")
+	part := dest + config.PartSuffix
+	partial := func(data []byte, validator string) {
+		t.Helper()
+		ranges, ifRanges = nil, nil
+		if err := os.WriteFile(part, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_ = os.Remove(part + config.PartETagSuffix)
+		if validator != "" {
+			if err := os.WriteFile(part+config.PartETagSuffix, []byte(validator), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	check := func(wantRange string) {
+		t.Helper()
+		if got, _ := os.ReadFile(dest); !bytes.Equal(got, payload) {
+			t.Fatal("downloaded file mismatch")
+		}
+		if len(ranges) != 1 || ranges[0] != wantRange {
+			t.Fatalf("expected a single request with range %q, got %q", wantRange, ranges)
+		}
+		for _, p := range []string{part, part + config.PartETagSuffix} {
+			if _, err := os.Stat(p); !os.IsNotExist(err) {
+				t.Fatalf("%s left behind (%v)", p, err)
+			}
+		}
+	}
+
+	// resumed with If-Range on the ETag of the first response
+	partial(payload[:30_000], etag)
+	if err := DownloadHTTPFile(srv.URL+"/etag", dest); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	check("bytes=30000-")
+	if ifRanges[0] != etag {
+		t.Fatalf("expected If-Range %s, got %q", etag, ifRanges[0])
+	}
+
+	// or on Last-Modified when there is no ETag
+	partial(payload[:30_000], modified.UTC().Format(http.TimeFormat))
+	if err := DownloadHTTPFile(srv.URL, dest); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	check("bytes=30000-")
+
+	// a resource changed since the partial file starts over
+	partial(make([]byte, 30_000), `"0123"`)
+	if err := DownloadHTTPFile(srv.URL+"/etag", dest); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	check("bytes=30000-")
+
+	// without a validator the partial file can't be trusted
+	partial(make([]byte, 30_000), "")
+	if err := DownloadHTTPFile(srv.URL+"/etag", dest); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	check("")
+
+	// a corrupted partial file fails the MD5 check of the ETag
+	partial(make([]byte, 30_000), etag)
+	var cerr *ChecksumError
+	if err := DownloadHTTPFile(srv.URL+"/etag", dest); !errors.As(err, &cerr) {
+		t.Fatalf("expected a checksum error, got %v", err)
+	}
+	if _, err := os.Stat(part); !os.IsNotExist(err) {
+		t.Fatalf("corrupted partial file kept (%v)", err)
+	}
+
+	// an interrupted download keeps the validator for the next one
+	if err := DownloadHTTPFile(srv.URL+"/cut", dest); err == nil {
+		t.Fatal("expected the interrupted download to fail")
+	}
+	if b, _ := os.ReadFile(part + config.PartETagSuffix); string(b) != etag {
+		t.Fatalf("expected the ETag kept next to the partial file, got %q", b)
+	}
+	ranges = nil
+	if err := DownloadHTTPFile(srv.URL+"/etag", dest); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	check("bytes=10000-")
+}
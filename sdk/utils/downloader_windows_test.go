@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package utils
+
+import "testing"
+
+func TestCleanLocalPathWindows(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{in: `C:\out\data`, want: `C:\out`},
+		{in: `C:\data`, want: `C:\`},
+		{in: `C:/out/nested/data`, want: `C:\out\nested`},
+		{in: `\\?\C:\very\long\out\data`, want: `\\?\C:\very\long\out`},
+		{in: `\\server\share\out\data`, want: `\\server\share\out`},
+		{in: `out\data`, want: `out`},
+	}
+	for _, c := range cases {
+		if got := cleanLocalPath(c.in); got != c.want {
+			t.Errorf("cleanLocalPath(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+
+	pp, err := ParsePath(`C:\data\file.csv`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pp.Scheme != "file" || pp.Filename != "file.csv" {
+		t.Errorf("ParsePath drive letter = %+v", pp)
+	}
+}
@@ -5,94 +5,185 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/i18n"
 )
 
-// CheckUpdateEnvironment decides whether to refresh the environment:
-// - missing/empty timestamp -> update
-// - invalid timestamp       -> update
-// - older than TTL          -> update
-func CheckUpdateEnvironment() {
-	const key = UpdatedEnvKey
+// DefaultEnvironmentTTL is how long the well-known metadata of an
+// environment is fresh when environment_ttl is not set.
+const DefaultEnvironmentTTL = time.Hour
+
+// ErrNoEndpoint is returned by RefreshEnvironment when dhcore_endpoint is
+// empty.
+var ErrNoEndpoint = errors.New("dhcore_endpoint is empty")
+
+// RefreshOptions tunes RefreshEnvironment.
+type RefreshOptions struct {
+	// Force fetches the metadata even when it is still fresh.
+	Force bool
+	// TTL overrides environment_ttl (DHCORE_ENVIRONMENT_TTL).
+	TTL time.Duration
+	// NoPersist keeps the fetched metadata in Viper only.
+	NoPersist bool
+}
 
-	if viper.IsSet(IniSource) && viper.GetString(IniSource) == "env" {
-		fmt.Printf("INI file has been created from enviromental variables...skip update\n")
-		return
-	}
+// Environment describes the current CLI environment, as loaded in Viper.
+type Environment struct {
+	Name        string
+	Endpoint    string
+	APIVersion  string
+	APILevel    string
+	CoreVersion string
+	Issuer      string
+	// Source is "env" when the environment was built from environment
+	// variables instead of the INI
+	Source string
+	// UpdatedAt is when the well-known metadata was last fetched; zero if
+	// unknown
+	UpdatedAt time.Time
+	TTL       time.Duration
+}
 
-	val := viper.GetString(key)
-	isSet := viper.IsSet(key)
-	fmt.Printf("Config freshness (%s): isSet=%v value=%q\n", key, isSet, val)
+// Stale reports whether the metadata is older than TTL, or its age is
+// unknown.
+func (e Environment) Stale() bool {
+	return e.UpdatedAt.IsZero() || time.Since(e.UpdatedAt) >= e.TTL
+}
 
-	if !isSet || val == "" {
-		fmt.Println("Update: no timestamp.")
-		updateEnvironment()
-		return
+// EnvironmentTTL returns environment_ttl (a duration such as "30m" or
+// "24h"), or DefaultEnvironmentTTL when unset or invalid.
+func EnvironmentTTL() time.Duration {
+	if d, err := time.ParseDuration(viper.GetString(EnvironmentTTLKey)); err == nil && d > 0 {
+		return d
 	}
+	return DefaultEnvironmentTTL
+}
 
-	t, err := time.Parse(time.RFC3339, val)
-	if err != nil {
-		fmt.Printf("Update: invalid timestamp (%v).\n", err)
-		updateEnvironment()
-		return
+// EnvironmentInfo returns the current environment; it doesn't fetch
+// anything.
+func EnvironmentInfo() Environment {
+	e := Environment{
+		Name:        viper.GetString(CurrentEnvironment),
+		Endpoint:    viper.GetString(DhCoreEndpoint),
+		APIVersion:  viper.GetString(DhCoreApiVersion),
+		APILevel:    viper.GetString(ApiLevelKey),
+		CoreVersion: viper.GetString("dhcore_version"),
+		Issuer:      viper.GetString(DhCoreIssuer),
+		Source:      viper.GetString(IniSource),
+		TTL:         EnvironmentTTL(),
 	}
-
-	now := time.Now().UTC()
-	age := now.Sub(t.UTC())
-	ttl := time.Duration(outdatedAfterHours) * time.Hour
-
-	if age >= ttl {
-		fmt.Printf("Update: outdated (age %s ≥ TTL %s).\n", age, ttl)
-		updateEnvironment()
-		return
+	if t, err := time.Parse(time.RFC3339, viper.GetString(UpdatedEnvKey)); err == nil {
+		e.UpdatedAt = t
 	}
-
-	fmt.Printf("Fresh: age %s < TTL %s.\n", age, ttl)
+	return e
 }
 
-// Fetch well-known, update Viper, bump timestamp, persist allowlisted keys.
-func updateEnvironment() {
-	fmt.Println("Updating environment…")
-	baseEndpoint := viper.GetString(DhCoreEndpoint)
-	if baseEndpoint == "" {
-		fmt.Println("Skip: dhcore_endpoint is empty.")
-		return
+// RefreshEnvironment fetches the well-known configuration and OpenID
+// metadata of the current environment into Viper, and persists them to the
+// INI, when they are stale (see Environment.Stale) or opts.Force is set.
+// Environments built from environment variables are only refreshed with
+// Force, and never persisted. In offline mode (see Offline) nothing is
+// fetched, and Force fails with a *config.OfflineError. It returns the
+// resulting environment.
+func RefreshEnvironment(ctx context.Context, opts RefreshOptions) (Environment, error) {
+	env := EnvironmentInfo()
+	if opts.TTL > 0 {
+		env.TTL = opts.TTL
+	}
+	fromEnv := env.Source == "env"
+	if Offline() && !opts.Force {
+		return env, nil
+	}
+	if !opts.Force {
+		if fromEnv {
+			config.DefaultLogger().Info(i18n.Message(i18n.MsgEnvFromVariables))
+			return env, nil
+		}
+		config.DefaultLogger().Debug(i18n.Messagef(i18n.MsgEnvFreshness, UpdatedEnvKey, !env.UpdatedAt.IsZero(), viper.GetString(UpdatedEnvKey)))
+		if !env.Stale() {
+			config.DefaultLogger().Debug(i18n.Messagef(i18n.MsgEnvFresh, time.Since(env.UpdatedAt), env.TTL))
+			return env, nil
+		}
+		if raw := viper.GetString(UpdatedEnvKey); raw == "" {
+			config.DefaultLogger().Info(i18n.Message(i18n.MsgEnvNoTimestamp))
+		} else if _, err := time.Parse(time.RFC3339, raw); err != nil {
+			config.DefaultLogger().Warn(i18n.Messagef(i18n.MsgEnvInvalidTimestamp, err))
+		} else {
+			config.DefaultLogger().Info(i18n.Messagef(i18n.MsgEnvOutdated, time.Since(env.UpdatedAt), env.TTL))
+		}
 	}
 
-	cfg, err := FetchConfig(baseEndpoint + "/.well-known/configuration")
+	config.DefaultLogger().Info(i18n.Message(i18n.MsgEnvUpdating))
+	if env.Endpoint == "" {
+		return env, ErrNoEndpoint
+	}
+	cfg, err := FetchConfigContext(ctx, env.Endpoint+"/.well-known/configuration")
+	if err != nil {
+		return env, fmt.Errorf("config fetch failed: %w", err)
+	}
+	oidc, err := FetchConfigContext(ctx, env.Endpoint+"/.well-known/openid-configuration")
 	if err != nil {
-		fmt.Printf("Config fetch failed: %v\n", err)
-		return
+		return env, fmt.Errorf("openid fetch failed: %w", err)
 	}
 	for k, v := range cfg {
 		viper.Set(k, ReflectValue(v))
 	}
-
-	oidc, err := FetchConfig(baseEndpoint + "/.well-known/openid-configuration")
-	if err != nil {
-		fmt.Printf("OpenID fetch failed: %v\n", err)
-		return
-	}
 	for k, v := range oidc {
 		viper.Set(k, ReflectValue(v))
 	}
 
 	ts := time.Now().UTC().Format(time.RFC3339)
 	viper.Set(UpdatedEnvKey, ts)
-	fmt.Printf("Set %s=%s\n", UpdatedEnvKey, ts)
+	config.DefaultLogger().Debug(i18n.Messagef(i18n.MsgEnvTimestampSet, UpdatedEnvKey, ts))
 
-	env := viper.GetString(CurrentEnvironment)
-	if env == "" {
-		env = resolveEnvName()
+	ttl := env.TTL
+	env = EnvironmentInfo()
+	env.TTL = ttl
+	if opts.NoPersist || fromEnv {
+		return env, nil
+	}
+	name := env.Name
+	if name == "" {
+		name = resolveEnvName()
 	}
-	if err := UpdateIniFromStruct(getIniPath(), env); err != nil {
-		fmt.Printf("Persist failed: %v\n", err)
-		return
+	if err := UpdateIniFromStruct(writableIniPath(), name); err != nil {
+		return env, fmt.Errorf("persist failed: %w", err)
+	}
+	config.DefaultLogger().Info(i18n.Messagef(i18n.MsgEnvPersisted, name))
+	return env, nil
+}
+
+// CheckUpdateEnvironment refreshes the environment when its metadata is
+// missing, invalid or older than environment_ttl; failures are logged.
+//
+// Deprecated: use RefreshEnvironment, which returns the error.
+func CheckUpdateEnvironment() {
+	CheckUpdateEnvironmentContext(context.Background())
+}
+
+// CheckUpdateEnvironmentContext is CheckUpdateEnvironment with the well-known
+// fetches bound to ctx.
+//
+// Deprecated: use RefreshEnvironment, which returns the error.
+func CheckUpdateEnvironmentContext(ctx context.Context) {
+	if _, err := RefreshEnvironment(ctx, RefreshOptions{}); err != nil {
+		config.DefaultLogger().Warn(err.Error())
 	}
-	fmt.Printf("Persisted to [%s].\n", env)
+}
+
+// Offline reports offline mode, set with dhcore_offline (DHCORE_OFFLINE) or
+// WithOffline: well-known metadata is never fetched and FetchConfig fails
+// with a *config.OfflineError. Pass it to CoreConfig.Offline so Core calls
+// fail the same way.
+func Offline() bool {
+	return viper.GetBool(OfflineKey)
 }
 
 // Backward-compat wrapper.
@@ -101,9 +192,9 @@ func UpdateIniSectionFromViper(_ []string) error {
 	if env == "" {
 		env = resolveEnvName()
 	}
-	if err := UpdateIniFromStruct(getIniPath(), env); err != nil {
+	if err := UpdateIniFromStruct(writableIniPath(), env); err != nil {
 		return fmt.Errorf("failed to save ini: %w", err)
 	}
-	fmt.Printf("Updated section [%s] in %s\n", env, getIniPath())
+	config.DefaultLogger().Info(i18n.Messagef(i18n.MsgIniSectionUpdated, env, getIniPath()))
 	return nil
 }
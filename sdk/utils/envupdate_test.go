@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/ini.v1"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+func TestRefreshEnvironment(t *testing.T) {
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		switch r.URL.Path {
+		case "/.well-known/configuration":
+			fmt.Fprint(w, `{"dhcore_version":"0.11.0","dhcore_api_level":"12"}`)
+		case "/.well-known/openid-configuration":
+			fmt.Fprint(w, `{"issuer":"https://issuer.example"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	iniPath := filepath.Join(t.TempDir(), "config.ini")
+	t.Setenv(ConfigPathEnv, iniPath)
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set(CurrentEnvironment, "test")
+	viper.Set(DhCoreEndpoint, srv.URL)
+	viper.Set(UpdatedEnvKey, time.Now().Add(-2*time.Hour).UTC().Format(time.RFC3339))
+
+	// fresh with a longer TTL: nothing is fetched
+	viper.Set(EnvironmentTTLKey, "3h")
+	env, err := RefreshEnvironment(context.Background(), RefreshOptions{})
+	if err != nil || env.Stale() || fetches.Load() != 0 {
+		t.Fatalf("unexpected refresh: %v, %+v, %d fetches", err, env, fetches.Load())
+	}
+
+	// stale with the default TTL
+	viper.Set(EnvironmentTTLKey, "")
+	env, err = RefreshEnvironment(context.Background(), RefreshOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetches.Load() != 2 || env.CoreVersion != "0.11.0" || env.APILevel != "12" || env.Stale() {
+		t.Fatalf("unexpected environment %+v", env)
+	}
+	cfg, err := ini.Load(iniPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Section("test").Key("dhcore_version").String() != "0.11.0" {
+		t.Fatal("metadata not persisted")
+	}
+
+	// offline: nothing is fetched, forcing fails
+	viper.Set(OfflineKey, true)
+	viper.Set(UpdatedEnvKey, "")
+	if _, err := RefreshEnvironment(context.Background(), RefreshOptions{}); err != nil || fetches.Load() != 2 {
+		t.Fatalf("offline refresh: %v, %d fetches", err, fetches.Load())
+	}
+	if _, err := RefreshEnvironment(context.Background(), RefreshOptions{Force: true}); !config.IsOffline(err) || fetches.Load() != 2 {
+		t.Fatalf("offline forced refresh: %v, %d fetches", err, fetches.Load())
+	}
+	viper.Set(OfflineKey, false)
+
+	// forced, without persisting
+	if _, err := RefreshEnvironment(context.Background(), RefreshOptions{Force: true, NoPersist: true}); err != nil || fetches.Load() != 4 {
+		t.Fatalf("forced refresh: %v, %d fetches", err, fetches.Load())
+	}
+}
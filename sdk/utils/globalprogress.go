@@ -6,45 +6,129 @@ package utils
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/humanize"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/i18n"
+)
+
+/* ------------ progress mode (terminal vs logs) ------------ */
+
+// ProgressMode controls how transfer progress is rendered on stderr.
+type ProgressMode int32
+
+const (
+	// ProgressAuto redraws a single line on a terminal and falls back to
+	// ProgressPlain when stderr is piped (CI logs, files).
+	ProgressAuto ProgressMode = iota
+	// ProgressFrames always redraws a single line with carriage returns.
+	ProgressFrames
+	// ProgressPlain prints a regular log line every plainProgressInterval.
+	ProgressPlain
+	// ProgressSilent disables progress output.
+	ProgressSilent
+)
+
+// plainProgressInterval is the minimum delay between two plain progress lines.
+const plainProgressInterval = 5 * time.Second
+
+var (
+	progressMode atomic.Int32
+
+	// progressOut and stderrIsTerminal are variables so tests can replace them.
+	progressOut      io.Writer = os.Stderr
+	stderrIsTerminal           = sync.OnceValue(func() bool { return isTerminal(os.Stderr) })
 )
 
+// SetProgressMode selects the progress rendering; the default is ProgressAuto.
+func SetProgressMode(mode ProgressMode) {
+	progressMode.Store(int32(mode))
+}
+
+// effectiveProgressMode resolves ProgressAuto against the current stderr.
+func effectiveProgressMode() ProgressMode {
+	mode := ProgressMode(progressMode.Load())
+	if mode != ProgressAuto {
+		return mode
+	}
+	if stderrIsTerminal() {
+		return ProgressFrames
+	}
+	return ProgressPlain
+}
+
+// progressInteractive reports whether carriage-return frames can be drawn.
+func progressInteractive() bool {
+	return effectiveProgressMode() == ProgressFrames
+}
+
+// progressDone prints the closing line of the verbose progress of a file,
+// over its frame when framed (and frames are drawn); nothing with
+// ProgressSilent.
+func progressDone(prefix, msg string, framed bool) {
+	switch effectiveProgressMode() {
+	case ProgressSilent:
+		return
+	case ProgressFrames:
+		if framed {
+			prefix = "\r" + prefix
+		}
+	}
+	fmt.Fprintf(progressOut, "%s%s\n", prefix, msg)
+}
+
+// isTerminal reports whether f is a character device (a console on Windows).
+func isTerminal(f *os.File) bool {
+	st, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return st.Mode()&os.ModeCharDevice != 0
+}
+
 /* ------------ tiny UI helpers for single-line progress ------------ */
 
+// globalProgress renders the bytes of a whole transfer on one line; add,
+// render and done are safe for concurrent use, the totals must be set
+// before the transfer starts.
 type globalProgress struct {
-	totalKnown bool
-	totalBytes int64
-	doneBytes  int64
-	spinIdx    int
-	lastTick   time.Time
+	mu          sync.Mutex
+	totalKnown  bool
+	totalBytes  int64
+	doneBytes   int64
+	spinIdx     int
+	lastTick    time.Time
+	lastPrinted int64
+	printed     bool
 }
 
 var spinner = []rune{'|', '/', '-', '\\'}
 
 func (gp *globalProgress) add(delta int64) {
+	gp.mu.Lock()
+	defer gp.mu.Unlock()
 	gp.doneBytes += delta
 }
 
-func (gp *globalProgress) human(n int64) string {
-	const (
-		KB = 1024
-		MB = 1024 * KB
-		GB = 1024 * MB
-	)
-	switch {
-	case n >= GB:
-		return fmt.Sprintf("%.2f GB", float64(n)/float64(GB))
-	case n >= MB:
-		return fmt.Sprintf("%.2f MB", float64(n)/float64(MB))
-	case n >= KB:
-		return fmt.Sprintf("%.2f KB", float64(n)/float64(KB))
-	default:
-		return fmt.Sprintf("%d B", n)
-	}
+func (gp *globalProgress) render(force bool) {
+	gp.mu.Lock()
+	defer gp.mu.Unlock()
+	gp.renderLocked(force)
 }
 
-func (gp *globalProgress) render(force bool) {
+func (gp *globalProgress) renderLocked(force bool) {
+	switch effectiveProgressMode() {
+	case ProgressSilent:
+		return
+	case ProgressPlain:
+		gp.renderPlain(force)
+		return
+	}
+
 	// throttling: update ~10 times each seconds to avoid “spamming”
 	if !force && time.Since(gp.lastTick) < 100*time.Millisecond {
 		return
@@ -52,21 +136,49 @@ func (gp *globalProgress) render(force bool) {
 	gp.lastTick = time.Now()
 
 	if gp.totalKnown && gp.totalBytes > 0 {
-		pct := float64(gp.doneBytes) / float64(gp.totalBytes) * 100
-		if gp.doneBytes > gp.totalBytes {
-			gp.doneBytes = gp.totalBytes
-			pct = 100
-		}
-		fmt.Fprintf(os.Stderr, "\rProgress: %6.2f%% (%s / %s)   ",
-			pct, gp.human(gp.doneBytes), gp.human(gp.totalBytes))
+		pct := gp.percent()
+		fmt.Fprintf(progressOut, "\r%s   ",
+			i18n.Messagef(i18n.MsgProgress, pct, humanize.Bytes(gp.doneBytes), humanize.Bytes(gp.totalBytes)))
 	} else {
 		ch := spinner[gp.spinIdx%len(spinner)]
 		gp.spinIdx++
-		fmt.Fprintf(os.Stderr, "\rProgress: [%c] %s downloaded   ", ch, gp.human(gp.doneBytes))
+		fmt.Fprintf(progressOut, "\r%s   ", i18n.Messagef(i18n.MsgProgressUnknownTotal, ch, humanize.Bytes(gp.doneBytes)))
+	}
+}
+
+// renderPlain prints one complete line at most every plainProgressInterval,
+// and never twice for the same amount of bytes.
+func (gp *globalProgress) renderPlain(force bool) {
+	if !force && time.Since(gp.lastTick) < plainProgressInterval {
+		return
+	}
+	if gp.printed && gp.lastPrinted == gp.doneBytes {
+		return
+	}
+	gp.lastTick = time.Now()
+	gp.lastPrinted = gp.doneBytes
+	gp.printed = true
+
+	if gp.totalKnown && gp.totalBytes > 0 {
+		fmt.Fprintln(progressOut,
+			i18n.Messagef(i18n.MsgProgress, gp.percent(), humanize.Bytes(gp.doneBytes), humanize.Bytes(gp.totalBytes)))
+	} else {
+		fmt.Fprintln(progressOut, i18n.Messagef(i18n.MsgProgressTransferred, humanize.Bytes(gp.doneBytes)))
+	}
+}
+
+func (gp *globalProgress) percent() float64 {
+	if gp.doneBytes > gp.totalBytes {
+		gp.doneBytes = gp.totalBytes
 	}
+	return humanize.Ratio(gp.doneBytes, gp.totalBytes)
 }
 
 func (gp *globalProgress) done() {
-	gp.render(true)
-	fmt.Fprintln(os.Stderr)
+	gp.mu.Lock()
+	defer gp.mu.Unlock()
+	gp.renderLocked(true)
+	if progressInteractive() {
+		fmt.Fprintln(progressOut)
+	}
 }
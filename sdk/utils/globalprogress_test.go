@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func withProgressOutput(t *testing.T, mode ProgressMode, terminal bool) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prevOut, prevTerm := progressOut, stderrIsTerminal
+	progressOut = &buf
+	stderrIsTerminal = func() bool { return terminal }
+	SetProgressMode(mode)
+	t.Cleanup(func() {
+		progressOut, stderrIsTerminal = prevOut, prevTerm
+		SetProgressMode(ProgressAuto)
+	})
+	return &buf
+}
+
+func TestGlobalProgressPipedPrintsPlainLines(t *testing.T) {
+	buf := withProgressOutput(t, ProgressAuto, false)
+
+	gp := &globalProgress{totalKnown: true, totalBytes: 1000}
+	for i := 0; i < 10; i++ {
+		gp.add(100)
+		gp.render(false)
+	}
+	gp.render(true)
+	gp.done()
+
+	out := buf.String()
+	if strings.Contains(out, "\r") {
+		t.Fatalf("carriage returns in piped output: %q", out)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	// first tick + final line; the final render/done pair must not duplicate it
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), out)
+	}
+	if !strings.Contains(lines[1], "100.00%") {
+		t.Fatalf("last line should report completion: %q", lines[1])
+	}
+}
+
+func TestGlobalProgressTerminalDrawsFrames(t *testing.T) {
+	buf := withProgressOutput(t, ProgressAuto, true)
+
+	gp := &globalProgress{}
+	gp.add(2048)
+	gp.done()
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "\r") || !strings.HasSuffix(out, "\n") {
+		t.Fatalf("unexpected frame output: %q", out)
+	}
+}
+
+func TestGlobalProgressSilent(t *testing.T) {
+	buf := withProgressOutput(t, ProgressSilent, true)
+
+	gp := &globalProgress{totalKnown: true, totalBytes: 10}
+	gp.add(10)
+	gp.render(true)
+	gp.done()
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output, got %q", buf.String())
+	}
+}
+
+func TestProgressDone(t *testing.T) {
+	cases := []struct {
+		mode     ProgressMode
+		terminal bool
+		want     string
+	}{
+		{ProgressAuto, true, "\r   done\n"},
+		{ProgressAuto, false, "   done\n"},
+		{ProgressPlain, true, "   done\n"},
+		{ProgressSilent, true, ""},
+	}
+	for _, c := range cases {
+		buf := withProgressOutput(t, c.mode, c.terminal)
+		progressDone("   ", "done", true)
+		if buf.String() != c.want {
+			t.Errorf("mode %d, terminal %v: got %q, want %q", c.mode, c.terminal, buf.String(), c.want)
+		}
+	}
+	buf := withProgressOutput(t, ProgressFrames, true)
+	progressDone("   ", "done", false)
+	if buf.String() != "   done\n" {
+		t.Errorf("unframed line redrawn: %q", buf.String())
+	}
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Environment variables holding the passphrase, or the path of a file
+// containing it, used to encrypt the secrets of the INI.
+const (
+	IniPassphraseEnv = "DHCORE_INI_PASSPHRASE"
+	IniKeyFileEnv    = "DHCORE_INI_KEY_FILE"
+)
+
+// encryptedPrefix marks encrypted INI values: enc:v1:base64(salt|nonce|ciphertext).
+const encryptedPrefix = "enc:v1:"
+
+const (
+	saltSize         = 16
+	pbkdf2Iterations = 100_000
+)
+
+var (
+	iniCryptMu    sync.Mutex
+	iniPass       []byte
+	iniSalt       []byte                // salt of the values encrypted by this process
+	iniDerivedKey = map[string][]byte{} // passphrase hash and salt -> key
+)
+
+// ErrIniPassphrase is returned when encrypted INI values can't be decrypted.
+var ErrIniPassphrase = errors.New("missing or wrong passphrase for the encrypted INI secrets")
+
+// SetIniPassphrase sets the passphrase encrypting the secrets of the INI,
+// overriding DHCORE_INI_PASSPHRASE and DHCORE_INI_KEY_FILE; nil restores
+// them. It has no effect on secrets kept in a CredentialStore.
+func SetIniPassphrase(p []byte) {
+	iniCryptMu.Lock()
+	defer iniCryptMu.Unlock()
+	iniPass = bytes.Clone(p)
+	iniSalt = nil
+	clear(iniDerivedKey)
+}
+
+// iniPassphrase returns the configured passphrase; nil when secrets are
+// stored in plaintext.
+func iniPassphrase() ([]byte, error) {
+	iniCryptMu.Lock()
+	p := iniPass
+	iniCryptMu.Unlock()
+	if p != nil {
+		return p, nil
+	}
+	if v := os.Getenv(IniPassphraseEnv); v != "" {
+		return []byte(v), nil
+	}
+	if path := os.Getenv(IniKeyFileEnv); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read INI key file: %w", err)
+		}
+		if b = bytes.TrimSpace(b); len(b) == 0 {
+			return nil, errors.New("empty INI key file")
+		}
+		return b, nil
+	}
+	return nil, nil
+}
+
+// iniKey derives the AES-256 key of pass and salt, once per process.
+func iniKey(pass, salt []byte) ([]byte, error) {
+	iniCryptMu.Lock()
+	defer iniCryptMu.Unlock()
+	id := fmt.Sprintf("%x:%x", sha256.Sum256(pass), salt)
+	if k, ok := iniDerivedKey[id]; ok {
+		return k, nil
+	}
+	k, err := pbkdf2.Key(sha256.New, string(pass), salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	iniDerivedKey[id] = k
+	return k, nil
+}
+
+func encryptSecret(pass []byte, plain string) (string, error) {
+	iniCryptMu.Lock()
+	if iniSalt == nil {
+		iniSalt = make([]byte, saltSize)
+		_, _ = rand.Read(iniSalt)
+	}
+	salt := iniSalt
+	iniCryptMu.Unlock()
+
+	gcm, err := iniCipher(pass, salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, _ = rand.Read(nonce)
+	out := append(append(bytes.Clone(salt), nonce...), gcm.Seal(nil, nonce, []byte(plain), nil)...)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(out), nil
+}
+
+func decryptSecret(pass []byte, v string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v, encryptedPrefix))
+	if err != nil || len(raw) < saltSize {
+		return "", errors.New("malformed encrypted value")
+	}
+	gcm, err := iniCipher(pass, raw[:saltSize])
+	if err != nil {
+		return "", err
+	}
+	raw = raw[saltSize:]
+	if len(raw) < gcm.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+	plain, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrIniPassphrase
+	}
+	return string(plain), nil
+}
+
+func iniCipher(pass, salt []byte) (cipher.AEAD, error) {
+	key, err := iniKey(pass, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// decryptSecrets decrypts in place the encrypted values read from the INI.
+func decryptSecrets(values map[string]string) error {
+	var pass []byte
+	for k, v := range values {
+		if !strings.HasPrefix(v, encryptedPrefix) {
+			continue
+		}
+		if pass == nil {
+			var err error
+			if pass, err = iniPassphrase(); err != nil {
+				return err
+			}
+			if pass == nil {
+				return fmt.Errorf("%w: set %s or %s", ErrIniPassphrase, IniPassphraseEnv, IniKeyFileEnv)
+			}
+		}
+		plain, err := decryptSecret(pass, v)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", k, err)
+		}
+		values[k] = plain
+	}
+	return nil
+}
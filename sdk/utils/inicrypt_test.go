@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"gopkg.in/ini.v1"
+)
+
+func TestEncryptedIniSecrets(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte("correct horse\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(IniKeyFileEnv, keyFile)
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	iniPath := filepath.Join(t.TempDir(), IniName)
+	viper.Set(DhCoreEndpoint, "https://core.example")
+	viper.Set(DhCoreRefreshToken, "rt-secret")
+	if err := WriteIniFromStruct(iniPath, "prod"); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(iniPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "rt-secret") || !strings.Contains(string(raw), encryptedPrefix) {
+		t.Fatalf("refresh token not encrypted:\n%s", raw)
+	}
+
+	load := func() error {
+		viper.Reset()
+		cfg, err := ini.Load(iniPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return loadIniSectionIntoViper(cfg, "prod")
+	}
+	if err := load(); err != nil {
+		t.Fatal(err)
+	}
+	if viper.GetString(DhCoreRefreshToken) != "rt-secret" || viper.GetString(DhCoreEndpoint) != "https://core.example" {
+		t.Fatalf("unexpected values after decryption")
+	}
+
+	SetIniPassphrase([]byte("wrong"))
+	t.Cleanup(func() { SetIniPassphrase(nil) })
+	if err := load(); !errors.Is(err, ErrIniPassphrase) {
+		t.Fatalf("expected passphrase error, got %v", err)
+	}
+}
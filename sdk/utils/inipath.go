@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/viper"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/i18n"
+)
+
+const (
+	// ConfigPathEnv overrides the location of the INI file.
+	ConfigPathEnv = config.IniPathEnv
+	// IniDirName and IniFileName locate the INI file under the XDG config
+	// home; IniName is the legacy file in the home directory.
+	IniDirName  = config.IniDirName
+	IniFileName = config.IniFileName
+)
+
+var (
+	iniPathMu       sync.RWMutex
+	iniPathOverride string
+)
+
+// SetIniPath sets the INI file used by the package, taking precedence over
+// DHCORE_CONFIG; "" restores the default lookup.
+func SetIniPath(path string) {
+	iniPathMu.Lock()
+	defer iniPathMu.Unlock()
+	iniPathOverride = path
+}
+
+// IniPath returns the INI file used by the package: the one set with
+// SetIniPath (or WithIniPath), else config.DefaultIniPath ($DHCORE_CONFIG,
+// else $XDG_CONFIG_HOME/dhcore/config.ini, or a legacy ~/.dhcore.ini while
+// only that one exists). The first write of a legacy file copies it to the
+// XDG location, which is used from then on; the legacy file is left in
+// place, and written in place when it can't be copied (e.g. in a read-only
+// home).
+func IniPath() string {
+	return getIniPath()
+}
+
+func getIniPath() string {
+	if p := explicitIniPath(); p != "" {
+		return p
+	}
+	return config.DefaultIniPath()
+}
+
+// explicitIniPath returns the INI file set with SetIniPath or DHCORE_CONFIG.
+func explicitIniPath() string {
+	iniPathMu.RLock()
+	defer iniPathMu.RUnlock()
+	if iniPathOverride != "" {
+		return iniPathOverride
+	}
+	return os.Getenv(ConfigPathEnv)
+}
+
+// writableIniPath is getIniPath for the writes of the INI: a legacy file
+// found by the default lookup is first copied to the XDG location.
+func writableIniPath() string {
+	path := getIniPath()
+	legacy := config.LegacyIniPath()
+	if explicitIniPath() != "" || path != legacy {
+		return path
+	}
+	xdg := config.XDGIniPath()
+	if err := copyIniFile(legacy, xdg); err != nil {
+		return legacy
+	}
+	config.DefaultLogger().Info(i18n.Messagef(i18n.MsgIniMigrated, legacy, xdg))
+	return xdg
+}
+
+// copyIniFile copies the INI from to, which must not exist yet.
+func copyIniFile(from, to string) error {
+	if err := ensureIniDir(to); err != nil {
+		return err
+	}
+	b, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return errors.Join(err, os.Remove(to))
+	}
+	if err := f.Close(); err != nil {
+		return errors.Join(err, os.Remove(to))
+	}
+	return nil
+}
+
+// ensureIniDir creates the directory of the INI file before it is written.
+func ensureIniDir(iniPath string) error {
+	return os.MkdirAll(filepath.Dir(iniPath), 0o700)
+}
+
+// IniOption configures RegisterIniCfgWithViperOptions.
+type IniOption func(*iniOptions)
+
+type iniOptions struct {
+	path    string
+	env     []string
+	offline *bool
+}
+
+// WithIniPath uses path as the INI file (see SetIniPath).
+func WithIniPath(path string) IniOption {
+	return func(o *iniOptions) { o.path = path }
+}
+
+// WithEnvironment selects the environment to load, like the optionalEnv
+// argument of RegisterIniCfgWithViper.
+func WithEnvironment(env string) IniOption {
+	return func(o *iniOptions) {
+		if env != "" {
+			o.env = []string{env}
+		}
+	}
+}
+
+// WithOffline sets offline mode (see Offline) for the process.
+func WithOffline(offline bool) IniOption {
+	return func(o *iniOptions) { o.offline = &offline }
+}
+
+// RegisterIniCfgWithViperOptions is RegisterIniCfgWithViperContext
+// configured with options.
+func RegisterIniCfgWithViperOptions(ctx context.Context, opts ...IniOption) error {
+	var o iniOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.path != "" {
+		SetIniPath(o.path)
+	}
+	if o.offline != nil {
+		viper.Set(OfflineKey, *o.offline)
+	}
+	return RegisterIniCfgWithViperContext(ctx, o.env...)
+}
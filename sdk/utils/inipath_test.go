@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIniPathLookup(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(ConfigPathEnv, "")
+
+	xdg := filepath.Join(home, ".config", IniDirName, IniFileName)
+	if got := IniPath(); got != xdg {
+		t.Fatalf("default: got %s", got)
+	}
+
+	// the legacy file is read in place, and copied on the first write
+	legacy := filepath.Join(home, IniName)
+	if err := os.WriteFile(legacy, []byte("[DEFAULT]\ncurrent_environment = prod\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := IniPath(); got != legacy {
+		t.Fatalf("legacy: got %s", got)
+	}
+	if _, err := os.Stat(xdg); !os.IsNotExist(err) {
+		t.Fatalf("reading must not migrate: %v", err)
+	}
+	if got := writableIniPath(); got != xdg {
+		t.Fatalf("migrated: got %s", got)
+	}
+	if b, err := os.ReadFile(xdg); err != nil || len(b) == 0 {
+		t.Fatalf("migrated file: %q, %v", b, err)
+	}
+	if _, err := os.Stat(legacy); err != nil {
+		t.Fatalf("legacy file must be kept: %v", err)
+	}
+	if got := IniPath(); got != xdg {
+		t.Fatalf("after migration: got %s", got)
+	}
+
+	custom := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", custom)
+	if got := IniPath(); got != legacy {
+		t.Fatalf("legacy without an xdg file: got %s", got)
+	}
+	if err := os.Remove(legacy); err != nil {
+		t.Fatal(err)
+	}
+	if got := IniPath(); got != filepath.Join(custom, IniDirName, IniFileName) {
+		t.Fatalf("xdg: got %s", got)
+	}
+	t.Setenv(ConfigPathEnv, "/etc/dhcore.ini")
+	if got := IniPath(); got != "/etc/dhcore.ini" {
+		t.Fatalf("env: got %s", got)
+	}
+	SetIniPath("/run/dhcore.ini")
+	t.Cleanup(func() { SetIniPath("") })
+	if got := IniPath(); got != "/run/dhcore.ini" {
+		t.Fatalf("override: got %s", got)
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+)
+
+/* ------------ machine-readable progress (JSON lines) ------------ */
+
+// ProgressFormat selects how transfers report progress.
+type ProgressFormat int
+
+const (
+	// ProgressText prints human-readable banners and progress on stderr.
+	ProgressText ProgressFormat = iota
+	// ProgressJSON writes one ProgressEvent per line and nothing else.
+	ProgressJSON
+)
+
+// Progress event phases.
+const (
+	PhaseStart    = "start"
+	PhaseProgress = "progress"
+	PhaseDone     = "done"
+	PhaseError    = "error"
+)
+
+// ProgressEvent is a single JSON-lines progress update.
+type ProgressEvent struct {
+	Op        string `json:"op"` // "upload" | "download"
+	Phase     string `json:"phase"`
+	File      string `json:"file"`             // local path
+	Remote    string `json:"remote,omitempty"` // s3://bucket/key or URL
+	Bytes     int64  `json:"bytes"`
+	Total     int64  `json:"total"` // 0 when unknown
+	ElapsedMs int64  `json:"elapsed_ms,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// jsonProgress serializes events on a shared writer; safe for concurrent use.
+type jsonProgress struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	op  string
+}
+
+func newJSONProgress(w io.Writer, op string) *jsonProgress {
+	if w == nil {
+		w = os.Stderr
+	}
+	return &jsonProgress{enc: json.NewEncoder(w), op: op}
+}
+
+func (p *jsonProgress) emit(ev ProgressEvent) {
+	ev.Op = p.op
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_ = p.enc.Encode(ev)
+}
+
+// fail reports a failed transfer of a single file.
+func (p *jsonProgress) fail(file, remote string, err error) {
+	p.emit(ProgressEvent{Phase: PhaseError, File: file, Remote: remote, Error: err.Error()})
+}
+
+// hook builds a ProgressHook emitting start/progress/done events for one file.
+func (p *jsonProgress) hook(file, remote string) *config.ProgressHook {
+	return &config.ProgressHook{
+		OnStart: func(k string, total int64) {
+			p.emit(ProgressEvent{Phase: PhaseStart, File: file, Remote: remote, Total: total})
+		},
+		OnProgress: func(k string, written, total int64) {
+			p.emit(ProgressEvent{Phase: PhaseProgress, File: file, Remote: remote, Bytes: written, Total: total})
+		},
+		OnDone: func(k string, total int64, took time.Duration) {
+			p.emit(ProgressEvent{Phase: PhaseDone, File: file, Remote: remote, Bytes: total, Total: total, ElapsedMs: took.Milliseconds()})
+		},
+	}
+}
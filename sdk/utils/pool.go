@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+	"sync"
+)
+
+/* ------------ bounded worker pool for directory transfers ------------ */
+
+// DefaultParallelism is the number of files a directory transfer moves at
+// once when its options leave Parallelism at 0.
+const DefaultParallelism = 4
+
+// workerPool runs jobs on a fixed number of goroutines. submit blocks while
+// all of them are busy, so walking the source never gets far ahead of the
+// transfers.
+type workerPool struct {
+	jobs chan func()
+	wg   sync.WaitGroup
+}
+
+func newWorkerPool(n int) *workerPool {
+	if n <= 0 {
+		n = DefaultParallelism
+	}
+	p := &workerPool{jobs: make(chan func())}
+	for i := 0; i < n; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for job := range p.jobs {
+				job()
+			}
+		}()
+	}
+	return p
+}
+
+// submit queues job, or returns the error of ctx if it ends first.
+func (p *workerPool) submit(ctx context.Context, job func()) error {
+	select {
+	case p.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wait stops accepting jobs and waits for the running ones.
+func (p *workerPool) wait() {
+	close(p.jobs)
+	p.wg.Wait()
+}
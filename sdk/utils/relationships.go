@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Relationship types of metadata.relationships.
+const (
+	RelationshipProducedBy  = "produced_by"
+	RelationshipConsumes    = "consumes"
+	RelationshipConsumedBy  = "consumed_by"
+	RelationshipDerivedFrom = "derived_from"
+	RelationshipRunOf       = "run_of"
+	RelationshipStepOf      = "step_of"
+	RelationshipRetryOf     = "retry_of"
+)
+
+// RelationshipTypes are the types ValidateRelationship accepts.
+var RelationshipTypes = []string{
+	RelationshipProducedBy, RelationshipConsumes, RelationshipConsumedBy,
+	RelationshipDerivedFrom, RelationshipRunOf, RelationshipStepOf, RelationshipRetryOf,
+}
+
+// Relationship links an entity to the entity with key Dest, e.g. an
+// artifact produced_by a run.
+type Relationship struct {
+	Type string `json:"type"`
+	Dest string `json:"dest"`
+}
+
+// ValidateRelationship checks that r has a known type and that Dest is an
+// entity key (store://... or kind://...).
+func ValidateRelationship(r Relationship) error {
+	if !slices.Contains(RelationshipTypes, r.Type) {
+		return fmt.Errorf("invalid relationship type %q (expected one of %s)", r.Type, strings.Join(RelationshipTypes, ", "))
+	}
+	if scheme, rest, ok := strings.Cut(r.Dest, "://"); !ok || scheme == "" || !strings.Contains(rest, "/") {
+		return fmt.Errorf("invalid relationship dest %q: not an entity key", r.Dest)
+	}
+	return nil
+}
+
+// EntityRelationships returns metadata.relationships of entity.
+func EntityRelationships(entity map[string]interface{}) []Relationship {
+	meta, _ := entity["metadata"].(map[string]interface{})
+	var out []Relationship
+	switch raw := meta["relationships"].(type) {
+	case []interface{}:
+		for _, it := range raw {
+			if m, ok := it.(map[string]interface{}); ok {
+				out = append(out, relationshipOf(m))
+			}
+		}
+	case []map[string]interface{}:
+		for _, m := range raw {
+			out = append(out, relationshipOf(m))
+		}
+	}
+	return out
+}
+
+func relationshipOf(m map[string]interface{}) Relationship {
+	r := Relationship{}
+	r.Type, _ = m["type"].(string)
+	r.Dest, _ = m["dest"].(string)
+	return r
+}
+
+// SetRelationships replaces metadata.relationships of entity.
+func SetRelationships(entity map[string]interface{}, rels []Relationship) {
+	meta, ok := entity["metadata"].(map[string]interface{})
+	if !ok {
+		meta = map[string]interface{}{}
+		entity["metadata"] = meta
+	}
+	list := make([]interface{}, len(rels))
+	for i, r := range rels {
+		list[i] = map[string]interface{}{"type": r.Type, "dest": r.Dest}
+	}
+	meta["relationships"] = list
+}
+
+// AddRelationship validates r and adds it to metadata.relationships of
+// entity, unless already there; it reports whether entity changed.
+func AddRelationship(entity map[string]interface{}, r Relationship) (bool, error) {
+	if err := ValidateRelationship(r); err != nil {
+		return false, err
+	}
+	rels := EntityRelationships(entity)
+	if slices.Contains(rels, r) {
+		return false, nil
+	}
+	SetRelationships(entity, append(rels, r))
+	return true, nil
+}
+
+// RemoveRelationships removes from metadata.relationships of entity those
+// matching r: same type and, when r.Dest is set, same dest. It returns how
+// many were removed.
+func RemoveRelationships(entity map[string]interface{}, r Relationship) int {
+	rels := EntityRelationships(entity)
+	kept := slices.DeleteFunc(slices.Clone(rels), func(x Relationship) bool {
+		return x.Type == r.Type && (r.Dest == "" || x.Dest == r.Dest)
+	})
+	if n := len(rels) - len(kept); n > 0 {
+		SetRelationships(entity, kept)
+		return n
+	}
+	return 0
+}
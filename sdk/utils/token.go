@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/i18n"
+	"github.com/spf13/viper"
+	"gopkg.in/ini.v1"
+)
+
+// NewTokenSourceFromViper builds the token source of the current environment
+// (dhcore_access_token, dhcore_refresh_token, oauth2_token_endpoint and
+// dhcore_client_id). Refreshed tokens are written back to Viper and to the
+// environment section of the INI. It returns nil when there is no refresh
+// token: the static access token is then the only option.
+func NewTokenSourceFromViper() *config.RefreshTokenSource {
+	refresh := viper.GetString(DhCoreRefreshToken)
+	if refresh == "" {
+		return nil
+	}
+	ts := config.NewRefreshTokenSource(viper.GetString(Oauth2TokenEndpoint), viper.GetString(DhCoreClientId), config.Token{
+		AccessToken:  viper.GetString(DhCoreAccessToken),
+		RefreshToken: refresh,
+	})
+	ts.OnRefresh = func(t config.Token) {
+		if err := PersistToken(t); err != nil {
+			config.DefaultLogger().Warn(i18n.Messagef(i18n.MsgTokenPersistFailed, err))
+		}
+	}
+	return ts
+}
+
+// PersistToken stores t in Viper and in the INI section of the current
+// environment, leaving the other keys untouched.
+func PersistToken(t config.Token) error {
+	values := map[string]string{
+		DhCoreAccessToken:  t.AccessToken,
+		DhCoreRefreshToken: t.RefreshToken,
+	}
+	if !t.Expiry.IsZero() {
+		values[DhCoreExpiresIn] = strconv.Itoa(int(time.Until(t.Expiry).Seconds()))
+	}
+	return persistValues(values)
+}
+
+// NewS3ConfigFromViper returns the S3 settings of the current environment.
+// Credentials are renewed from Core when they expire and the new ones are
+// persisted like tokens.
+func NewS3ConfigFromViper() config.S3Config {
+	exp, _ := config.ParseCredentialsExpiration(viper.GetString(AwsCredentialsExpiration))
+	return config.S3Config{
+		AccessKey:       viper.GetString(AwsAccessKeyId),
+		SecretKey:       viper.GetString(AwsSecretAccessKey),
+		AccessToken:     viper.GetString(AwsSessionToken),
+		Region:          viper.GetString(AwsRegion),
+		EndpointURL:     viper.GetString(AwsEndpointURL),
+		Expiration:      exp,
+		CoreCredentials: true,
+		OnCredentialsRefresh: func(c config.S3Credentials) {
+			if err := PersistS3Credentials(c); err != nil {
+				config.DefaultLogger().Warn(i18n.Messagef(i18n.MsgTokenPersistFailed, err))
+			}
+		},
+	}
+}
+
+// PersistS3Credentials stores temporary S3 credentials like PersistToken.
+func PersistS3Credentials(c config.S3Credentials) error {
+	values := map[string]string{
+		AwsAccessKeyId:     c.AccessKey,
+		AwsSecretAccessKey: c.SecretKey,
+		AwsSessionToken:    c.SessionToken,
+	}
+	if !c.Expiration.IsZero() {
+		values[AwsCredentialsExpiration] = c.Expiration.UTC().Format(time.RFC3339)
+	}
+	return persistValues(values)
+}
+
+// persistValues sets values in Viper and in the INI section of the current
+// environment.
+func persistValues(values map[string]string) error {
+	for k, v := range values {
+		viper.Set(k, v)
+	}
+
+	env := viper.GetString(CurrentEnvironment)
+	if env == "" {
+		env = resolveEnvName()
+	}
+	path := writableIniPath()
+	cfg, err := ini.Load(path)
+	if err != nil {
+		return fmt.Errorf("failed to read ini: %w", err)
+	}
+	sec := cfg.Section(env)
+	for k, v := range values {
+		sec.Key(k).SetValue(v)
+	}
+	if err := protectSecrets(cfg); err != nil {
+		return err
+	}
+	if err := cfg.SaveTo(path); err != nil {
+		return fmt.Errorf("failed to save ini: %w", err)
+	}
+	return nil
+}
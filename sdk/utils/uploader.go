@@ -5,79 +5,123 @@
 package utils
 
 import (
+	"bufio"
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
 
 	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/humanize"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/i18n"
 
 	"fmt"
 	"io"
+	"io/fs"
+	"log/slog"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
-/* ------------ logging helpers (stderr) ------------ */
+/* ------------ logging helpers (config.LoggerOr) ------------ */
 
-func upInfof(format string, a ...any) {
-	fmt.Fprintf(os.Stderr, "[INFO] "+format+"\n", a...)
+func upInfof(l *slog.Logger, id i18n.MessageID, a ...any) {
+	config.LoggerOr(l).Info(i18n.Messagef(id, a...))
 }
-func upWarnf(format string, a ...any) {
-	fmt.Fprintf(os.Stderr, "[WARN] "+format+"\n", a...)
+
+// UploadOptions tunes UploadS3FileWithOptions / UploadS3DirWithOptions.
+type UploadOptions struct {
+	Verbose bool
+	// ContentTypes overrides content type detection per extension
+	// (e.g. ".parquet" -> "application/vnd.apache.parquet").
+	ContentTypes map[string]string
+	// ProgressFormat selects text (default) or JSON-lines progress.
+	ProgressFormat ProgressFormat
+	// ProgressOutput receives JSON-lines events; nil means stderr.
+	ProgressOutput io.Writer
+	// NoPreScan skips counting files and bytes before a directory upload:
+	// the upload starts immediately but totals are unknown.
+	NoPreScan bool
+	// Logger receives the info lines; nil means config.DefaultLogger.
+	Logger *slog.Logger
+	// Parallelism is the number of files of a directory uploaded at once
+	// (default DefaultParallelism); per-file progress bars of verbose mode
+	// are drawn only when it is 1.
+	Parallelism int
+	// NoChecksums skips the SHA-256 and MD5 recorded in the file infos
+	// ("hash", "md5"), which cost a second read of each file.
+	NoChecksums bool
 }
 
 /* ------------ FILE SINGOLO ------------ */
 
 func UploadS3File(client *config.S3Client, ctx context.Context, bucket, key, localPath string, verbose bool) (map[string]interface{}, []map[string]interface{}, error) {
+	return UploadS3FileWithOptions(client, ctx, bucket, key, localPath, UploadOptions{Verbose: verbose})
+}
+
+func UploadS3FileWithOptions(client *config.S3Client, ctx context.Context, bucket, key, localPath string, opts UploadOptions) (map[string]interface{}, []map[string]interface{}, error) {
+	verbose := opts.Verbose
+
 	file, err := os.Open(localPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to open local file: %w", err)
 	}
 	defer file.Close()
 
-	// Detect content-type
-	header := make([]byte, 512)
-	n, _ := file.Read(header)
-	contentType := http.DetectContentType(header[:n])
-	if _, err := file.Seek(0, io.SeekStart); err != nil {
-		return nil, nil, fmt.Errorf("seek error: %w", err)
+	// Detect content-type (extension first, sniffing only as fallback)
+	contentType, err := config.DetectContentType(file, opts.ContentTypes)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	jsonMode := opts.ProgressFormat == ProgressJSON
+
 	// Banner (uguale per verbose / non-verbose)
-	upInfof("Preparing upload %s → s3://%s/%s", displayPathUpload(localPath), bucket, key)
+	if !jsonMode {
+		upInfof(opts.Logger, i18n.MsgUploadPreparing, displayPathUpload(localPath), bucket, key)
+	}
 
 	// Upload
 	var output interface{}
-	if verbose {
+	if jsonMode {
+		jp := newJSONProgress(opts.ProgressOutput, "upload")
+		remote := "s3://" + bucket + "/" + key
+		output, err = client.UploadFileWithContentType(ctx, bucket, key, file, contentType, jp.hook(localPath, remote))
+		if err != nil {
+			jp.fail(localPath, remote, err)
+			return nil, nil, fmt.Errorf("upload error: %w", err)
+		}
+	} else if verbose {
 		hook := &config.ProgressHook{
 			OnStart: func(k string, total int64) {
 				if total > 0 {
-					fmt.Fprintf(os.Stderr, "   size: %.2f MB\n", float64(total)/(1024*1024))
+					fmt.Fprintf(os.Stderr, "   %s\n", i18n.Messagef(i18n.MsgTransferSize, humanize.Bytes(total)))
 				}
 			},
 			OnProgress: func(k string, written, total int64) {
-				if total <= 0 {
+				if total <= 0 || !progressInteractive() {
 					return
 				}
 				pct := float64(written) / float64(total) * 100
-				fmt.Fprintf(os.Stderr, "\r   uploading: %6.2f%%", pct)
+				fmt.Fprintf(os.Stderr, "\r   %s", i18n.Messagef(i18n.MsgUploading, pct))
 			},
 			OnDone: func(k string, total int64, took time.Duration) {
 				if total > 0 {
-					fmt.Fprintf(os.Stderr, "\r   done:      100.00%% in %s\n", took.Truncate(100*time.Millisecond))
+					progressDone("   ", i18n.Messagef(i18n.MsgUploadDone, humanize.Duration(took)), true)
 				} else {
-					fmt.Fprintf(os.Stderr, "   done in %s\n", took.Truncate(100*time.Millisecond))
+					progressDone("   ", i18n.Messagef(i18n.MsgTransferDoneIn, humanize.Duration(took)), false)
 				}
 			},
 		}
-		// rewind per sicurezza
-		if _, err := file.Seek(0, io.SeekStart); err != nil {
-			return nil, nil, fmt.Errorf("seek error: %w", err)
-		}
-		output, err = client.UploadFileWithProgress(ctx, bucket, key, file, hook)
+		output, err = client.UploadFileWithContentType(ctx, bucket, key, file, contentType, hook)
 		if err != nil {
 			return nil, nil, fmt.Errorf("upload error: %w", err)
 		}
@@ -118,10 +162,7 @@ func UploadS3File(client *config.S3Client, ctx context.Context, bucket, key, loc
 				gp.done()
 			},
 		}
-		if _, err := file.Seek(0, io.SeekStart); err != nil {
-			return nil, nil, fmt.Errorf("seek error: %w", err)
-		}
-		output, err = client.UploadFileWithProgress(ctx, bucket, key, file, hook)
+		output, err = client.UploadFileWithContentType(ctx, bucket, key, file, contentType, hook)
 		if err != nil {
 			return nil, nil, fmt.Errorf("upload error: %w", err)
 		}
@@ -145,116 +186,210 @@ func UploadS3File(client *config.S3Client, ctx context.Context, bucket, key, loc
 			"size":          info.Size(),
 		},
 	}
+	if !opts.NoChecksums {
+		sums, err := FileChecksums(localPath)
+		if err != nil {
+			return result, files, err
+		}
+		addChecksums(files[0], sums)
+	}
 
 	return result, files, nil
 }
 
+/* ------------ READER ------------ */
+
+// UploadS3Reader uploads the content of r to bucket/key, for data that isn't
+// in a local file. size is the length of r, 0 or negative when unknown (see
+// config.S3Client.UploadReader). An empty contentType is resolved from the
+// name of the key, then by sniffing the first bytes. The file info returned
+// describes what was actually read, checksums included unless
+// opts.NoChecksums.
+func UploadS3Reader(client *config.S3Client, ctx context.Context, bucket, key string, r io.Reader, size int64, contentType string, opts UploadOptions) (map[string]interface{}, []map[string]interface{}, error) {
+	name := path.Base(key)
+	br := bufio.NewReader(r)
+	if contentType == "" {
+		contentType = config.ContentTypeByName(name, opts.ContentTypes)
+	}
+	if contentType == "" {
+		header, err := br.Peek(512)
+		if err != nil && err != io.EOF {
+			return nil, nil, fmt.Errorf("read error: %w", err)
+		}
+		contentType = http.DetectContentType(header)
+	}
+
+	// conteggio e checksum calcolati durante l'upload, senza rileggere
+	sha, md := sha256.New(), md5.New()
+	var read int64
+	var w io.Writer = writerFunc(func(p []byte) (int, error) {
+		read += int64(len(p))
+		return len(p), nil
+	})
+	if !opts.NoChecksums {
+		w = io.MultiWriter(w, sha, md)
+	}
+	body := io.TeeReader(br, w)
+
+	remote := "s3://" + bucket + "/" + key
+	var hook *config.ProgressHook
+	var jp *jsonProgress
+	if opts.ProgressFormat == ProgressJSON {
+		jp = newJSONProgress(opts.ProgressOutput, "upload")
+		hook = jp.hook(name, remote)
+	} else {
+		upInfof(opts.Logger, i18n.MsgUploadPreparing, name, bucket, key)
+	}
+	output, err := client.UploadReader(ctx, bucket, key, body, size, contentType, hook)
+	if err != nil {
+		if jp != nil {
+			jp.fail(name, remote, err)
+		}
+		return nil, nil, fmt.Errorf("upload error: %w", err)
+	}
+
+	files := []map[string]interface{}{
+		{
+			"path":          "",
+			"name":          name,
+			"content_type":  contentType,
+			"last_modified": time.Now().UTC().Format(time.RFC1123),
+			"size":          read,
+		},
+	}
+	if !opts.NoChecksums {
+		addChecksums(files[0], Checksums{SHA256: hex.EncodeToString(sha.Sum(nil)), MD5: hex.EncodeToString(md.Sum(nil))})
+	}
+	return normalizeUploadResult(output), files, nil
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
 /* ------------ DIRECTORY ------------ */
 
 func UploadS3Dir(client *config.S3Client, ctx context.Context, parsedPath *ParsedPath, localPath string, verbose bool) ([]map[string]interface{}, []map[string]interface{}, error) {
+	return UploadS3DirWithOptions(client, ctx, parsedPath, localPath, UploadOptions{Verbose: verbose})
+}
+
+func UploadS3DirWithOptions(client *config.S3Client, ctx context.Context, parsedPath *ParsedPath, localPath string, opts UploadOptions) ([]map[string]interface{}, []map[string]interface{}, error) {
+	verbose := opts.Verbose
 	bucket := parsedPath.Host
 	prefix := parsedPath.Path
+	contentTypes := config.NormalizeContentTypes(opts.ContentTypes)
 
-	// Enumerazione file locali (per stampare [i/N] e calcolare totals)
-	var localFiles []string
+	// Pre-scan opzionale (solo conteggi, per stampare [i/N] e calcolare totals);
+	// i file vengono poi enumerati in streaming durante l'upload
+	total := -1
 	var totalBytes int64
-	err := filepath.Walk(localPath, func(path string, info os.FileInfo, walkErr error) error {
-		if walkErr != nil {
-			return fmt.Errorf("walk error: %w", walkErr)
-		}
-		if info.IsDir() {
-			return nil
+	if !opts.NoPreScan {
+		n, size, err := scanDir(ctx, localPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to enumerate local directory: %w", err)
 		}
-		localFiles = append(localFiles, path)
-		totalBytes += info.Size()
-		return nil
-	})
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to enumerate local directory: %w", err)
+		total, totalBytes = n, size
 	}
 
-	total := len(localFiles)
-	if verbose {
-		upInfof("Preparing upload directory %s → s3://%s/%s (%d files, %.2f MB)",
-			displayPathUpload(localPath), bucket, prefix, total, float64(totalBytes)/(1024*1024))
+	jsonMode := opts.ProgressFormat == ProgressJSON
+	var jp *jsonProgress
+	if jsonMode {
+		jp = newJSONProgress(opts.ProgressOutput, "upload")
+	} else if verbose && total >= 0 {
+		upInfof(opts.Logger, i18n.MsgUploadDirPreparingTotals,
+			displayPathUpload(localPath), bucket, prefix, total, humanize.Bytes(totalBytes))
 	} else {
-		upInfof("Preparing upload directory %s → s3://%s/%s", displayPathUpload(localPath), bucket, prefix)
+		upInfof(opts.Logger, i18n.MsgUploadDirPreparing, displayPathUpload(localPath), bucket, prefix)
 	}
 
-	var results []map[string]interface{}
-	var fileInfos []map[string]interface{}
+	// i file sono caricati da un pool di worker: i risultati sono raccolti
+	// con il loro indice e riordinati come nel walk
+	type uploaded struct {
+		idx    int
+		result map[string]interface{}
+		info   map[string]interface{}
+	}
+	var (
+		mu   sync.Mutex
+		done []uploaded
+	)
+	frames := opts.Parallelism == 1
 
 	// Progress globale per modalità non-verbose
 	var gp *globalProgress
-	if !verbose {
+	if !verbose && !jsonMode {
 		gp = &globalProgress{
 			totalKnown: totalBytes > 0,
 			totalBytes: totalBytes,
 		}
 	}
 
-	for i, path := range localFiles {
-		info, err := os.Stat(path)
+	// un file fallito non interrompe gli altri: gli errori sono raccolti in berr
+	berr := NewBatchError("upload")
+	i := 0
+	uploadDirEntry := func(n int, path string) {
+		relPath, err := filepath.Rel(localPath, path)
 		if err != nil {
-			return nil, nil, fmt.Errorf("stat error on %s: %w", path, err)
+			berr.Add(path, fmt.Errorf("relative path error: %w", err))
+			return
 		}
-		relPath, err := filepath.Rel(localPath, path)
+		item := filepath.ToSlash(relPath)
+		info, err := os.Stat(path)
 		if err != nil {
-			return nil, nil, fmt.Errorf("relative path error: %w", err)
+			berr.Add(item, fmt.Errorf("stat error: %w", err))
+			return
 		}
 		s3Key := filepath.ToSlash(filepath.Join(prefix, relPath))
+		remote := "s3://" + bucket + "/" + s3Key
 
 		file, err := os.Open(path)
 		if err != nil {
-			return nil, nil, fmt.Errorf("open file error: %w", err)
+			berr.Add(item, fmt.Errorf("open file error: %w", err))
+			return
 		}
 
 		// MIME
-		header := make([]byte, 512)
-		n, _ := file.Read(header)
-		contentType := http.DetectContentType(header[:n])
-		if _, err := file.Seek(0, io.SeekStart); err != nil {
+		contentType, err := config.DetectContentType(file, contentTypes)
+		if err != nil {
 			_ = file.Close()
-			return nil, nil, fmt.Errorf("seek error: %w", err)
+			berr.Add(item, err)
+			return
 		}
 
-		if verbose {
-			fmt.Fprintf(os.Stderr, "   [%d/%d] %s → s3://%s/%s\n", i+1, total, relPath, bucket, s3Key)
-			hook := &config.ProgressHook{
+		var hook *config.ProgressHook
+		if jsonMode {
+			hook = jp.hook(path, remote)
+		} else if verbose {
+			if total >= 0 {
+				fmt.Fprintf(os.Stderr, "   [%d/%d] %s → %s\n", n, total, relPath, remote)
+			} else {
+				fmt.Fprintf(os.Stderr, "   [%d] %s → %s\n", n, relPath, remote)
+			}
+			hook = &config.ProgressHook{
 				OnStart: func(k string, total int64) {
 					if total > 0 {
-						fmt.Fprintf(os.Stderr, "      └─ size: %.2f MB\n", float64(total)/(1024*1024))
+						fmt.Fprintf(os.Stderr, "      └─ %s\n", i18n.Messagef(i18n.MsgTransferSize, humanize.Bytes(total)))
 					}
 				},
 				OnProgress: func(k string, written, total int64) {
-					if total <= 0 {
+					if total <= 0 || !frames || !progressInteractive() {
 						return
 					}
 					pct := float64(written) / float64(total) * 100
-					fmt.Fprintf(os.Stderr, "\r      └─ uploading: %6.2f%%", pct)
+					fmt.Fprintf(os.Stderr, "\r      └─ %s", i18n.Messagef(i18n.MsgUploading, pct))
 				},
 				OnDone: func(k string, total int64, took time.Duration) {
 					if total > 0 {
-						fmt.Fprintf(os.Stderr, "\r      └─ done:      100.00%% in %s\n", took.Truncate(100*time.Millisecond))
+						progressDone("      └─ ", i18n.Messagef(i18n.MsgUploadDone, humanize.Duration(took)), frames)
 					} else {
-						fmt.Fprintf(os.Stderr, "      └─ done in %s\n", took.Truncate(100*time.Millisecond))
+						progressDone("      └─ ", i18n.Messagef(i18n.MsgTransferDoneIn, humanize.Duration(took)), false)
 					}
 				},
 			}
-			if _, err := file.Seek(0, io.SeekStart); err != nil {
-				_ = file.Close()
-				return nil, nil, fmt.Errorf("seek error: %w", err)
-			}
-			out, upErr := client.UploadFileWithProgress(ctx, bucket, s3Key, file, hook)
-			_ = file.Close()
-			if upErr != nil {
-				return nil, nil, fmt.Errorf("upload error (%s): %w", path, upErr)
-			}
-			results = append(results, normalizeUploadResult(out))
 		} else {
 			// non-verbose: aggiorna la progress BAR GLOBALE con un hook per-file
 			var prevWritten int64
-			hook := &config.ProgressHook{
+			hook = &config.ProgressHook{
 				OnProgress: func(k string, written, total int64) {
 					delta := written - prevWritten
 					if delta > 0 && gp != nil {
@@ -270,17 +405,17 @@ func UploadS3Dir(client *config.S3Client, ctx context.Context, parsedPath *Parse
 					}
 				},
 			}
-			if _, err := file.Seek(0, io.SeekStart); err != nil {
-				_ = file.Close()
-				return nil, nil, fmt.Errorf("seek error: %w", err)
-			}
-			out, upErr := client.UploadFileWithProgress(ctx, bucket, s3Key, file, hook)
-			_ = file.Close()
-			if upErr != nil {
-				return nil, nil, fmt.Errorf("upload error (%s): %w", path, upErr)
+		}
+		out, upErr := client.UploadFileWithContentType(ctx, bucket, s3Key, file, contentType, hook)
+		_ = file.Close()
+		if upErr != nil {
+			if jp != nil {
+				jp.fail(path, remote, upErr)
 			}
-			results = append(results, normalizeUploadResult(out))
+			berr.Add(item, fmt.Errorf("upload error: %w", upErr))
+			return
 		}
+		result := normalizeUploadResult(out)
 
 		// Accumula info file per status
 		dirPath := filepath.Dir(relPath)
@@ -288,19 +423,53 @@ func UploadS3Dir(client *config.S3Client, ctx context.Context, parsedPath *Parse
 		if dirPath != "." {
 			normalizedPath = filepath.ToSlash(dirPath + "/" + info.Name())
 		}
-		fileInfos = append(fileInfos, map[string]interface{}{
+		fi := map[string]interface{}{
 			"path":          normalizedPath,
 			"name":          info.Name(),
 			"content_type":  contentType,
 			"last_modified": info.ModTime().UTC().Format(http.TimeFormat),
 			"size":          info.Size(),
+		}
+		if !opts.NoChecksums {
+			sums, err := FileChecksums(path)
+			if err != nil {
+				berr.Add(item, err)
+				return
+			}
+			addChecksums(fi, sums)
+		}
+		mu.Lock()
+		done = append(done, uploaded{idx: n, result: result, info: fi})
+		mu.Unlock()
+	}
+
+	pool := newWorkerPool(opts.Parallelism)
+	err := walkFiles(ctx, localPath, func(path string, _ fs.DirEntry) error {
+		i++
+		n := i
+		return pool.submit(ctx, func() {
+			uploadDirEntry(n, path)
 		})
+	})
+	pool.wait()
+
+	sort.Slice(done, func(a, b int) bool { return done[a].idx < done[b].idx })
+	var results, fileInfos []map[string]interface{}
+	for _, u := range done {
+		results = append(results, u.result)
+		fileInfos = append(fileInfos, u.info)
 	}
 
-	if !verbose && gp != nil {
+	if gp != nil {
 		gp.done()
 	}
-	return results, fileInfos, nil
+	if err != nil {
+		if ctx.Err() != nil {
+			return results, fileInfos, ctx.Err()
+		}
+		berr.Add(localPath, fmt.Errorf("failed to enumerate local directory: %w", err))
+	}
+	return results, fileInfos, berr.ErrorOrNil()
 }
 
 /* ------------ helpers ------------ */
@@ -322,6 +491,13 @@ func normalizeUploadResult(output interface{}) map[string]interface{} {
 	return result
 }
 
+// addChecksums records sums in a file info, as "hash" (sha256:<hex>) and
+// "md5".
+func addChecksums(info map[string]interface{}, sums Checksums) {
+	info["hash"] = sums.Hash()
+	info["md5"] = sums.MD5
+}
+
 func displayPathUpload(p string) string {
 	if p == "" {
 		return "."
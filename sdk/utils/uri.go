@@ -16,21 +16,24 @@ type ParsedPath struct {
 
 // ParsePath parses any kind of path: S3, HTTP, local (absolute or relative)
 func ParsePath(input string) (*ParsedPath, error) {
-	// Try parsing as URI
-	parsed, err := url.Parse(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse path: %w", err)
-	}
-
 	result := &ParsedPath{}
 
-	// If there's a scheme (e.g. s3, https), treat it as URI
-	if parsed.Scheme != "" {
-		result.Scheme = parsed.Scheme
-		result.Host = parsed.Host
-		result.Path = strings.TrimPrefix(parsed.Path, "/")
-		result.Filename = filepath.Base(parsed.Path)
-		return result, nil
+	// A Windows drive letter (C:\data, C:/data) would be parsed as a URI scheme
+	if !hasDriveLetter(input) {
+		// Try parsing as URI
+		parsed, err := url.Parse(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse path: %w", err)
+		}
+
+		// If there's a scheme (e.g. s3, https), treat it as URI
+		if parsed.Scheme != "" {
+			result.Scheme = parsed.Scheme
+			result.Host = parsed.Host
+			result.Path = strings.TrimPrefix(parsed.Path, "/")
+			result.Filename = filepath.Base(parsed.Path)
+			return result, nil
+		}
 	}
 
 	// Else, it's a local path
@@ -41,3 +44,11 @@ func ParsePath(input string) (*ParsedPath, error) {
 
 	return result, nil
 }
+
+func hasDriveLetter(input string) bool {
+	if len(input) < 2 || input[1] != ':' {
+		return false
+	}
+	c := input[0]
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
@@ -6,6 +6,7 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"reflect"
@@ -14,6 +15,9 @@ import (
 
 	"github.com/spf13/viper"
 	"gopkg.in/ini.v1"
+
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/config"
+	"github.com/scc-digitalhub/digitalhub-cli-sdk/sdk/i18n"
 )
 
 // EnvDumpPrefix: optional prefix for env lookup (e.g., "DHCORE")
@@ -24,7 +28,7 @@ const EnvDumpPrefix = ""
 // - env: canonical env name (UPPER_SNAKE). If empty, derived from vkey
 // - persist: "true" to write the key into the INI
 // - default: optional default to set if key is unset
-// - secret: "true" if sensitive (not used here, but handy for logging)
+// - secret: "true" if sensitive (redacted in Core debug logs, see SecretKeys)
 // - bind: "false" to NOT bind from env (we still can set defaults)
 type Config struct {
 	AuthorizationEndpoint             string `vkey:"authorization_endpoint"               env:"AUTHORIZATION_ENDPOINT"               persist:"true"`
@@ -68,6 +72,9 @@ type Config struct {
 	TokenEndpoint                     string `vkey:"token_endpoint"                       env:"TOKEN_ENDPOINT"                       persist:"true"`
 	TokenEndpointAuthMethodsSupported string `vkey:"token_endpoint_auth_methods_supported" env:"TOKEN_ENDPOINT_AUTH_METHODS_SUPPORTED" persist:"true"`
 	UserinfoEndpoint                  string `vkey:"userinfo_endpoint"                    env:"USERINFO_ENDPOINT"                    persist:"true"`
+	CredentialStore                   string `vkey:"credential_store"                     env:"DHCORE_CREDENTIAL_STORE"              persist:"true"`
+	EnvironmentTTL                    string `vkey:"environment_ttl"                      env:"DHCORE_ENVIRONMENT_TTL"               persist:"true"`
+	Offline                           string `vkey:"dhcore_offline"                       env:"DHCORE_OFFLINE"                       persist:"true"`
 	IniSource                         string `vkey:"ini_source"               env:"INI_SOURCE"               persist:"true"`
 	UpdatedEnvironment                string `vkey:"updated_environment" env:"UPDATED_ENVIRONMENT" persist:"true" bind:"false"`
 	CurrentEnvironment                string `vkey:"current_environment" env:"CURRENT_ENVIRONMENT" persist:"false"`
@@ -85,6 +92,23 @@ type Config struct {
 	RunId                                   string `vkey:"run_id" env:"RUN_ID" persist:"false"`
 }
 
+// SecretKeys returns the Viper keys tagged secret:"true".
+func SecretKeys() []string {
+	var keys []string
+	rt := reflect.TypeOf(Config{})
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.Tag.Get("secret") == "true" && f.Tag.Get("vkey") != "" {
+			keys = append(keys, f.Tag.Get("vkey"))
+		}
+	}
+	return keys
+}
+
+func init() {
+	config.RegisterSecretKeys(SecretKeys()...)
+}
+
 // resolveEnvName: --env > "default"
 func resolveEnvName(optionalEnv ...string) string {
 	if len(optionalEnv) > 0 && optionalEnv[0] != "" && strings.ToLower(optionalEnv[0]) != "null" {
@@ -172,6 +196,12 @@ func WriteIniFromStruct(iniPath, envName string) error {
 		sec.Key(key).SetValue(val)
 	}
 
+	if err := protectSecrets(cfg); err != nil {
+		return err
+	}
+	if err := ensureIniDir(iniPath); err != nil {
+		return err
+	}
 	return cfg.SaveTo(iniPath)
 }
 
@@ -204,6 +234,9 @@ func UpdateIniFromStruct(iniPath, envName string) error {
 		cfg.Section("DEFAULT").Key("current_environment").SetValue(envName)
 	}
 	sec.Key(UpdatedEnvKey).SetValue(time.Now().UTC().Format(time.RFC3339))
+	if err := protectSecrets(cfg); err != nil {
+		return err
+	}
 	return cfg.SaveTo(iniPath)
 }
 
@@ -213,11 +246,11 @@ func loadIniSectionIntoViper(cfg *ini.File, env string) error {
 	selected := def
 	if env != "" && cfg.HasSection(env) {
 		selected = cfg.Section(env)
-		fmt.Printf("Using env: [%s]\n", env)
+		config.DefaultLogger().Info(i18n.Messagef(i18n.MsgEnvUsing, env))
 	} else if env == "" || strings.EqualFold(env, "DEFAULT") {
-		fmt.Println("Using env: [DEFAULT]")
+		config.DefaultLogger().Info(i18n.Message(i18n.MsgEnvUsingDefault))
 	} else {
-		fmt.Println("Env not found, falling back to [DEFAULT]")
+		config.DefaultLogger().Warn(i18n.Message(i18n.MsgEnvFallbackDefault))
 	}
 
 	merged := make(map[string]string)
@@ -229,6 +262,9 @@ func loadIniSectionIntoViper(cfg *ini.File, env string) error {
 			merged[k.Name()] = k.Value()
 		}
 	}
+	if err := ResolveSecrets(selected.Name(), merged); err != nil {
+		return err
+	}
 
 	var buf bytes.Buffer
 	for k, v := range merged {
@@ -244,16 +280,29 @@ func loadIniSectionIntoViper(cfg *ini.File, env string) error {
 // 2) load INI or lazy-bootstraps it from well-known (writes only target env)
 // 3) load active section into Viper and set current_environment
 func RegisterIniCfgWithViper(optionalEnv ...string) error {
+	return RegisterIniCfgWithViperContext(context.Background(), optionalEnv...)
+}
+
+// RegisterIniCfgWithViperContext is RegisterIniCfgWithViper returning ctx.Err()
+// instead of bootstrapping the INI file once ctx is done.
+func RegisterIniCfgWithViperContext(ctx context.Context, optionalEnv ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	iniPath := getIniPath()
 
 	BindEnvFromStruct(EnvDumpPrefix)
+	useCredentialStore(viper.GetString(CredentialStoreKey))
 
 	cfg, err := ini.Load(iniPath)
 	if err != nil {
-		fmt.Println("INI not found; Get information from Env variables")
+		config.DefaultLogger().Info(i18n.Message(i18n.MsgIniNotFound))
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		envName, bootErr := bootstrapFromEnv(iniPath, optionalEnv...)
 		if bootErr != nil {
-			fmt.Printf("Bootstrap failed: %v\n", bootErr)
+			config.DefaultLogger().Warn(i18n.Messagef(i18n.MsgIniBootstrapFailed, bootErr))
 			if envName == "" {
 				envName = resolveEnvName(optionalEnv...)
 			}
@@ -262,7 +311,7 @@ func RegisterIniCfgWithViper(optionalEnv ...string) error {
 		}
 		cfg, err = ini.Load(iniPath)
 		if err != nil {
-			fmt.Printf("INI written but cannot reload: %v (ENV-only mode)\n", err)
+			config.DefaultLogger().Warn(i18n.Messagef(i18n.MsgIniReloadFailed, err))
 			viper.Set(CurrentEnvironment, viper.GetString(CurrentEnvironment))
 			return nil
 		}
@@ -276,6 +325,8 @@ func RegisterIniCfgWithViper(optionalEnv ...string) error {
 		}
 	}
 
+	useCredentialStore(cfg.Section(env).Key(CredentialStoreKey).String())
+	useCredentialStore(cfg.Section("DEFAULT").Key(CredentialStoreKey).String())
 	if err := loadIniSectionIntoViper(cfg, env); err != nil {
 		return fmt.Errorf("failed to load INI into viper: %w", err)
 	}
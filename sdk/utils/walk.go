@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+/* ------------ streaming directory walk ------------ */
+
+// walkBatch is the number of directory entries read at a time.
+const walkBatch = 256
+
+// walkFiles calls fn for every non-directory entry under root, reading
+// directories in batches instead of loading (and sorting) them whole like
+// filepath.Walk, so memory does not grow with the number of files.
+// Entries come in directory order. An error from fn or ctx stops the walk.
+func walkFiles(ctx context.Context, root string, fn func(path string, d fs.DirEntry) error) error {
+	return WalkFiles(ctx, root, fn, nil)
+}
+
+// WalkFiles is the streaming walk of the uploads, for other packages: it
+// calls fn for every non-directory entry under root (root itself when it is
+// not a directory) and, when emptyDir is not nil, emptyDir for every
+// directory with no entries, root included.
+func WalkFiles(ctx context.Context, root string, fn func(path string, d fs.DirEntry) error, emptyDir func(path string) error) error {
+	st, err := os.Lstat(root)
+	if err != nil {
+		return err
+	}
+	if !st.IsDir() {
+		return fn(root, fs.FileInfoToDirEntry(st))
+	}
+	return walkDir(ctx, root, fn, emptyDir)
+}
+
+func walkDir(ctx context.Context, dir string, fn func(path string, d fs.DirEntry) error, emptyDir func(path string) error) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	empty := true
+	for {
+		entries, err := f.ReadDir(walkBatch)
+		for _, e := range entries {
+			empty = false
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			path := filepath.Join(dir, e.Name())
+			if e.IsDir() {
+				if err := walkDir(ctx, path, fn, emptyDir); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := fn(path, e); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			if empty && emptyDir != nil {
+				return emptyDir(dir)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// scanDir counts the files under root and their total size, without keeping
+// the list in memory.
+func scanDir(ctx context.Context, root string) (files int, bytes int64, err error) {
+	err = walkFiles(ctx, root, func(path string, d fs.DirEntry) error {
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files++
+		bytes += info.Size()
+		return nil
+	})
+	return files, bytes, err
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestWalkFilesStreamsAllFiles(t *testing.T) {
+	root := t.TempDir()
+	var want []string
+	// more entries than a single ReadDir batch
+	for i := 0; i < walkBatch+10; i++ {
+		want = append(want, fmt.Sprintf("f%03d.txt", i))
+	}
+	want = append(want, "sub/a.txt", "sub/deep/b.txt")
+	for _, rel := range want {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("xy"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	err := walkFiles(context.Background(), root, func(path string, d fs.DirEntry) error {
+		rel, _ := filepath.Rel(root, path)
+		got = append(got, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("walk returned %d files, want %d", len(got), len(want))
+	}
+
+	n, size, err := scanDir(context.Background(), root)
+	if err != nil || n != len(want) || size != int64(2*len(want)) {
+		t.Fatalf("scan: %d files, %d bytes, %v", n, size, err)
+	}
+
+	var empty []string
+	err = WalkFiles(context.Background(), root, func(string, fs.DirEntry) error { return nil }, func(path string) error {
+		empty = append(empty, path)
+		return nil
+	})
+	if err != nil || fmt.Sprint(empty) != fmt.Sprint([]string{filepath.Join(root, "empty")}) {
+		t.Fatalf("unexpected empty dirs %v (%v)", empty, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := scanDir(ctx, root); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}